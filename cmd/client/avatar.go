@@ -0,0 +1,87 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	pb "example.com/user/proto"
+	"github.com/spf13/cobra"
+)
+
+func uploadAvatarCommand(opts *options) *cobra.Command {
+	var contentType string
+	cmd := &cobra.Command{
+		Use:   "upload-avatar ID FILE",
+		Short: "Set the avatar of a user to a PNG, JPEG, GIF or WebP image",
+		Long: "Set the avatar of a user to the image in FILE, or stdin when FILE is -, and print\n" +
+			"the stored avatar. The server detects the image type unless --content-type is given.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			in := os.Stdin
+			if args[1] != "-" {
+				f, err := os.Open(args[1])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				in = f
+			}
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			avatar, err := c.UploadAvatar(ctx, id, contentType, in)
+			if err != nil {
+				return err
+			}
+			return printJSON(avatar)
+		},
+	}
+	cmd.Flags().StringVar(&contentType, "content-type", "", "type of the image, checked by the server (default: detected)")
+	return cmd
+}
+
+func getAvatarCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-avatar ID FILE",
+		Short: "Write the avatar of a user to FILE, or stdout when FILE is -",
+		Long: "Write the avatar of a user to FILE and print its description, or write it to stdout\n" +
+			"alone when FILE is -. FILE is only replaced once the whole image has arrived.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			if args[1] == "-" {
+				_, err := c.GetAvatar(ctx, id, os.Stdout)
+				return err
+			}
+			var avatar *pb.Avatar
+			err = writeAtomically(args[1], func(w io.Writer) error {
+				avatar, err = c.GetAvatar(ctx, id, w)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return printJSON(avatar)
+		},
+	}
+}
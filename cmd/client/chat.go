@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	pb "example.com/user/proto"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func chatCommand(opts *options) *cobra.Command {
+	var from, to, room string
+	cmd := &cobra.Command{
+		Use:   "chat [MESSAGE...]",
+		Short: "Chat over the bidirectional stream",
+		Long: "Send each MESSAGE over the chat stream and print the messages coming back.\n\n" +
+			"Without MESSAGE, chat interactively: every line read from stdin is sent as a message\n" +
+			"and incoming messages are printed as they arrive, until the end of input (Ctrl-D) or\n" +
+			"Ctrl-C. --timeout then only applies when set explicitly; --deadline always does.\n\n" +
+			"Messages go to every other client connected, to the user named by --to only, or with\n" +
+			"--room to the other members of the room, which is joined first. --to Server has the\n" +
+			"server echo them back.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			interactive := len(args) == 0
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			if opts.credentials && !cmd.Flags().Changed("from") {
+				// The server names authenticated callers itself
+				from = ""
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			ctx, cancel := opts.bound(ctx, !interactive || cmd.Flags().Changed("timeout"))
+			defer cancel()
+
+			stream, err := c.Chat(ctx)
+			if err != nil {
+				return err
+			}
+			go func() {
+				defer stream.CloseSend()
+				send := func(text string) bool {
+					return stream.Send(&pb.ChatMessage{
+						From:      from,
+						To:        to,
+						Message:   text,
+						Timestamp: timestamppb.Now(),
+						Type:      pb.MessageType_MESSAGE_TYPE_TEXT,
+						Room:      room,
+					}) == nil
+				}
+				// Joining first registers the stream under from, so it is sent messages to
+				// it even before sending any
+				join := &pb.ChatMessage{From: from, Room: room, Type: pb.MessageType_MESSAGE_TYPE_JOIN}
+				if stream.Send(join) != nil {
+					return
+				}
+				if !interactive {
+					for _, text := range args {
+						if !send(text) {
+							return
+						}
+					}
+					return
+				}
+
+				if isTerminal(os.Stdin) {
+					as := from
+					if as == "" {
+						as = "the authenticated caller"
+					}
+					fmt.Fprintf(os.Stderr, "Chatting as %s; type a message per line, Ctrl-D to leave\n", as)
+				}
+				scanner := bufio.NewScanner(os.Stdin)
+				for scanner.Scan() {
+					if text := strings.TrimSpace(scanner.Text()); text != "" && !send(text) {
+						return
+					}
+				}
+			}()
+
+			// Messages are printed as they arrive until the server ends the stream, which it
+			// does once the messages sent are handled
+			for {
+				msg, err := stream.Recv()
+				switch {
+				case errors.Is(err, io.EOF):
+					return nil
+				case err != nil && interactive && status.Code(err) == codes.Canceled:
+					// Ctrl-C
+					return nil
+				case err != nil:
+					return err
+				}
+				printChatMessage(msg)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", defaultChatName(), "name to chat as, unless the client authenticates")
+	cmd.Flags().StringVar(&to, "to", "", "recipient of the messages")
+	cmd.Flags().StringVar(&room, "room", "", "room to join and send the messages to")
+	return cmd
+}
+
+func chatHistoryCommand(opts *options) *cobra.Command {
+	req := &pb.ChatHistoryRequest{}
+	var from string
+	cmd := &cobra.Command{
+		Use:   "chat-history",
+		Short: "Print the latest chat messages of a room, with a user or sent to everyone",
+		Long: "Print the latest chat messages sent to --room, between --from and --peer, or else to\n" +
+			"everyone, oldest first. When older ones remain, the --before giving them is printed\n" +
+			"to stderr. A room's history is only for those in it; a client with credentials reads\n" +
+			"as the caller they authenticate, and only admins may read as another --from.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			// The server reads as authenticated callers itself, and only lets admins name
+			// someone else
+			if (req.Room != "" || req.Peer != "") && (!opts.credentials || cmd.Flags().Changed("from")) {
+				req.User = from
+			}
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			res, err := c.GetChatHistory(ctx, req)
+			if err != nil {
+				return err
+			}
+			for _, msg := range res.Messages {
+				printChatMessage(msg)
+			}
+			if res.HasMore {
+				fmt.Fprintf(os.Stderr, "Older messages remain: --before %d\n", res.Messages[0].Id)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&req.Room, "room", "", "room whose messages to print")
+	cmd.Flags().StringVar(&req.Peer, "peer", "", "user whose direct messages with --from to print")
+	cmd.Flags().StringVar(&from, "from", defaultChatName(), "name chatted as, unless the client authenticates")
+	cmd.Flags().Int64Var(&req.Before, "before", 0, "only messages older than this ID")
+	cmd.Flags().Int32Var(&req.Limit, "limit", 0, "messages to print (0 for the server's default of 50)")
+	return cmd
+}
+
+// printChatMessage prints msg on a line, with the user or room it was sent to
+func printChatMessage(msg *pb.ChatMessage) {
+	at := msg.Timestamp.AsTime().Local().Format(time.TimeOnly)
+	switch {
+	case msg.Type == pb.MessageType_MESSAGE_TYPE_JOIN || msg.Type == pb.MessageType_MESSAGE_TYPE_LEAVE:
+		fmt.Printf("%s * %s\n", at, msg.Message)
+	case msg.To != "" && msg.From != "Server":
+		fmt.Printf("%s %s (to %s): %s\n", at, msg.From, msg.To, msg.Message)
+	case msg.Room != "":
+		fmt.Printf("%s [%s] %s: %s\n", at, msg.Room, msg.From, msg.Message)
+	default:
+		fmt.Printf("%s %s: %s\n", at, msg.From, msg.Message)
+	}
+}
+
+// defaultChatName is the login name of the user running the client
+func defaultChatName() string {
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "cli"
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,489 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"iter"
+	"os"
+	"strconv"
+	"strings"
+
+	"example.com/user/pkg/userclient"
+	pb "example.com/user/proto"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func getCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get ID",
+		Short: "Print a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			user, err := c.GetUser(ctx, id)
+			if err != nil {
+				return err
+			}
+			return printJSON(user)
+		},
+	}
+}
+
+func batchGetCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "batch-get ID...",
+		Short: "Print several users, and the IDs without a user",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids := make([]int32, len(args))
+			for i, arg := range args {
+				id, err := parseID(arg)
+				if err != nil {
+					return err
+				}
+				ids[i] = id
+			}
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			res, err := c.BatchGetUsers(ctx, ids)
+			if err != nil {
+				return err
+			}
+			return printJSON(res)
+		},
+	}
+}
+
+func createCommand(opts *options) *cobra.Command {
+	req := &pb.CreateUserRequest{}
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a user and print it",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			user, err := c.CreateUser(ctx, req)
+			if err != nil {
+				return err
+			}
+			return printJSON(user)
+		},
+	}
+	cmd.Flags().StringVar(&req.Name, "name", "", "name (required)")
+	cmd.Flags().StringVar(&req.Email, "email", "", "email (required)")
+	cmd.Flags().StringVar(&req.Role, "role", "", `"user" (the default), "admin" or a custom role such as "auditor"`)
+	cmd.Flags().StringVar(&req.Password, "password", "", "password")
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("email")
+	return cmd
+}
+
+func validateCommand(opts *options) *cobra.Command {
+	req := &pb.CreateUserRequest{}
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check a new user as create would, without creating it, and print what is wrong",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			res, err := c.ValidateUser(ctx, req)
+			if err != nil {
+				return err
+			}
+			return printJSON(res)
+		},
+	}
+	cmd.Flags().StringVar(&req.Name, "name", "", "name")
+	cmd.Flags().StringVar(&req.Email, "email", "", "email")
+	cmd.Flags().StringVar(&req.Role, "role", "", `"user" (the default), "admin" or a custom role such as "auditor"`)
+	return cmd
+}
+
+func updateCommand(opts *options) *cobra.Command {
+	req := &pb.UpdateUserRequest{}
+	var etag string
+	cmd := &cobra.Command{
+		Use:   "update ID",
+		Short: "Change a user and print it; fields left out stay unchanged",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			req.Id = id
+			// Name exactly the fields given, so one given empty is cleared rather than ignored
+			req.UpdateMask = nil
+			for _, field := range []string{"name", "email", "role"} {
+				if cmd.Flags().Changed(field) {
+					if req.UpdateMask == nil {
+						req.UpdateMask = &fieldmaskpb.FieldMask{}
+					}
+					req.UpdateMask.Paths = append(req.UpdateMask.Paths, field)
+				}
+			}
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+			if etag != "" {
+				ctx = userclient.IfMatch(ctx, etag)
+			}
+
+			user, err := c.UpdateUser(ctx, req)
+			if err != nil {
+				return err
+			}
+			return printJSON(user)
+		},
+	}
+	cmd.Flags().StringVar(&req.Name, "name", "", "new name")
+	cmd.Flags().StringVar(&req.Email, "email", "", "new email")
+	cmd.Flags().StringVar(&req.Role, "role", "", `new role, "user", "admin" or a custom role; empty resets it to "user"`)
+	cmd.Flags().Int64Var(&req.Version, "version", 0, "fail unless the user is still at this version")
+	cmd.Flags().StringVar(&etag, "if-match", "", "fail unless the user still has this etag")
+	return cmd
+}
+
+func deleteCommand(opts *options) *cobra.Command {
+	var etag string
+	cmd := &cobra.Command{
+		Use:   "delete ID",
+		Short: "Delete a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+			if etag != "" {
+				ctx = userclient.IfMatch(ctx, etag)
+			}
+
+			if err := c.DeleteUser(ctx, id); err != nil {
+				return err
+			}
+			fmt.Printf("Deleted user %d\n", id)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&etag, "if-match", "", "fail unless the user still has this etag")
+	return cmd
+}
+
+func suspendCommand(opts *options) *cobra.Command {
+	return statusCommand(opts, "suspend ID", "Suspend a user, rejecting its credentials until reactivated", (*userclient.Client).SuspendUser)
+}
+
+func activateCommand(opts *options) *cobra.Command {
+	return statusCommand(opts, "activate ID", "Reactivate a suspended user", (*userclient.Client).ActivateUser)
+}
+
+// statusCommand runs change on the user given by ID and prints the result
+func statusCommand(opts *options, use, short string, change func(*userclient.Client, context.Context, int32) (*pb.UserResponse, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			user, err := change(c, ctx, id)
+			if err != nil {
+				return err
+			}
+			return printJSON(user)
+		},
+	}
+}
+
+func listCommand(opts *options) *cobra.Command {
+	filter := &pb.UserFilter{}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Print matching users, one JSON object per line",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			for user, err := range c.StreamUsers(ctx, filter) {
+				if err != nil {
+					return err
+				}
+				line, err := protojson.Marshal(user)
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(line))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&filter.Keyword, "keyword", "", "only users whose name or email contains this, ignoring case")
+	cmd.Flags().StringVar(&filter.NameContains, "name", "", "only users whose name contains this, ignoring case")
+	cmd.Flags().StringVar(&filter.EmailContains, "email", "", "only users whose email contains this, ignoring case")
+	cmd.Flags().StringSliceVar(&filter.Roles, "role", nil, "only users with one of these roles")
+	cmd.Flags().StringVar(&filter.OrderBy, "order-by", "", `sort order, e.g. "created_at desc"`)
+	cmd.Flags().BoolVar(&filter.IncludeDeleted, "include-deleted", false, "also list soft-deleted users")
+	cmd.Flags().Int32Var(&filter.Limit, "limit", 0, "at most this many users (0 for all)")
+	cmd.Flags().Int32Var(&filter.PageSize, "page-size", 100, "users fetched per page")
+	return cmd
+}
+
+func pageCommand(opts *options) *cobra.Command {
+	req := &pb.ListUsersRequest{}
+	cmd := &cobra.Command{
+		Use:   "page",
+		Short: "Print one page of matching users, with the next page token and the total",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			res, err := c.ListUsers(ctx, req)
+			if err != nil {
+				return err
+			}
+			return printJSON(res)
+		},
+	}
+	cmd.Flags().StringVar(&req.Keyword, "keyword", "", "only users whose name or email contains this, ignoring case")
+	cmd.Flags().StringVar(&req.EmailContains, "email", "", "only users whose email contains this, ignoring case")
+	cmd.Flags().StringSliceVar(&req.Roles, "role", nil, "only users with one of these roles")
+	cmd.Flags().StringVar(&req.OrderBy, "order-by", "", `sort order, e.g. "created_at desc"`)
+	cmd.Flags().BoolVar(&req.IncludeDeleted, "include-deleted", false, "also list soft-deleted users")
+	cmd.Flags().Int32Var(&req.PageSize, "page-size", 0, "users per page (0 for the server's default of 50)")
+	cmd.Flags().StringVar(&req.PageToken, "page-token", "", "next page token printed by the previous page")
+	return cmd
+}
+
+func searchCommand(opts *options) *cobra.Command {
+	req := &pb.SearchUsersRequest{}
+	cmd := &cobra.Command{
+		Use:   "search QUERY",
+		Short: "Print one page of the users matching a query, such as 'role:admin name~john'",
+		Long: "Print one page of the users matching QUERY, with the next page token and the total.\n" +
+			"QUERY holds terms that must all hold: role:admin, name~john (or just john),\n" +
+			`created>2024-01-01 (also >=, <, <= and :) and deleted:true; quote values with spaces.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req.Query = args[0]
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			res, err := c.SearchUsers(ctx, req)
+			if err != nil {
+				return err
+			}
+			return printJSON(res)
+		},
+	}
+	cmd.Flags().StringVar(&req.OrderBy, "order-by", "", `sort order, e.g. "created_at desc"`)
+	cmd.Flags().Int32Var(&req.PageSize, "page-size", 0, "users per page (0 for the server's default of 50)")
+	cmd.Flags().StringVar(&req.PageToken, "page-token", "", "next page token printed by the previous page")
+	return cmd
+}
+
+func historyCommand(opts *options) *cobra.Command {
+	req := &pb.UserHistoryRequest{}
+	cmd := &cobra.Command{
+		Use:   "history ID",
+		Short: "Print one page of the changes made to a user, newest first (admins only)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			req.UserId = id
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			res, err := c.GetUserHistory(ctx, req)
+			if err != nil {
+				return err
+			}
+			return printJSON(res)
+		},
+	}
+	cmd.Flags().Int32Var(&req.PageSize, "page-size", 0, "changes per page (0 for the server's default of 50)")
+	cmd.Flags().StringVar(&req.PageToken, "page-token", "", "next page token printed by the previous page")
+	return cmd
+}
+
+func bulkCreateCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "bulk-create [FILE]",
+		Short: "Create users read from FILE or stdin, one JSON object per line",
+		Long: "Create users read from FILE, or stdin when FILE is - or left out, one JSON object\n" +
+			`per line such as {"name": "Ada", "email": "ada@example.com"}, and print the result.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBulk(opts, cmd, args, func() *pb.CreateUserRequest { return &pb.CreateUserRequest{} }, (*userclient.Client).CreateUsers)
+		},
+	}
+}
+
+func bulkUpdateCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "bulk-update [FILE]",
+		Short: "Apply updates read from FILE or stdin, one JSON object per line",
+		Long: "Apply updates read from FILE, or stdin when FILE is - or left out, one JSON object\n" +
+			`per line such as {"id": 2, "role": "admin", "version": 3}, and print the result.` + "\n" +
+			"Each update is applied on its own; those rejected are listed with their reason.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBulk(opts, cmd, args, func() *pb.UpdateUserRequest { return &pb.UpdateUserRequest{} }, (*userclient.Client).BulkUpdateUsers)
+		},
+	}
+}
+
+// runBulk sends the requests read from the file in args, or stdin, one JSON object per
+// line, on a client stream, and prints the result
+func runBulk[Req, Res proto.Message](opts *options, cmd *cobra.Command, args []string, newReq func() Req, send func(*userclient.Client, context.Context, iter.Seq[Req]) (Res, error)) error {
+	in := os.Stdin
+	if len(args) == 1 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	c, err := opts.connect(cmd)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx, cancel := opts.context()
+	defer cancel()
+
+	// A line that can't be read abandons the call, so no request is applied
+	var readErr error
+	requests := func(yield func(Req) bool) {
+		scanner := bufio.NewScanner(in)
+		for n := 1; scanner.Scan(); n++ {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			req := newReq()
+			if err := protojson.Unmarshal([]byte(line), req); err != nil {
+				readErr = fmt.Errorf("line %d: %w", n, err)
+				cancel()
+				return
+			}
+			if !yield(req) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			readErr = err
+			cancel()
+		}
+	}
+
+	res, err := send(c, ctx, requests)
+	if readErr != nil {
+		return readErr
+	}
+	if err != nil {
+		return err
+	}
+	return printJSON(res)
+}
+
+// parseID reads a user ID argument
+func parseID(arg string) (int32, error) {
+	id, err := strconv.ParseInt(arg, 10, 32)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid user ID %q", arg)
+	}
+	return int32(id), nil
+}
+
+// printJSON prints m as indented JSON
+func printJSON(m proto.Message) error {
+	out, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(m)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
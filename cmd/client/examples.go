@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"example.com/user/pkg/userclient"
+	pb "example.com/user/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// callContext returns the context of an example's calls and its cancel func
+type callContext func() (context.Context, context.CancelFunc)
+
+// runExamples demonstrates all gRPC patterns, each bounded by a context from newContext
+func runExamples(c *userclient.Client, newContext callContext) error {
+	slog.Info("🎯 Starting gRPC client examples")
+
+	if err := unaryExample(c, newContext); err != nil {
+		return fmt.Errorf("unary example failed: %w", err)
+	}
+
+	if err := serverStreamingExample(c, newContext); err != nil {
+		return fmt.Errorf("server streaming example failed: %w", err)
+	}
+
+	if err := clientStreamingExample(c, newContext); err != nil {
+		return fmt.Errorf("client streaming example failed: %w", err)
+	}
+
+	if err := bidirectionalStreamingExample(c, newContext); err != nil {
+		return fmt.Errorf("bidirectional streaming example failed: %w", err)
+	}
+
+	slog.Info("✅ All examples completed successfully")
+	return nil
+}
+
+// unaryExample demonstrates unary RPC calls
+func unaryExample(c *userclient.Client, newContext callContext) error {
+	slog.Info("=== Unary RPC example ===")
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	// Test GetUser
+	res, err := c.GetUser(ctx, 1)
+	if err != nil {
+		return fmt.Errorf("GetUser failed: %w", err)
+	}
+
+	slog.Info("✅ Got user", "name", res.Name, "email", res.Email, "role", res.Role)
+
+	// Test CreateUser
+	createRes, err := c.CreateUser(ctx, &pb.CreateUserRequest{
+		Name:  "Test User",
+		Email: "test@example.com",
+		Role:  "user",
+	})
+	if err != nil {
+		return fmt.Errorf("CreateUser failed: %w", err)
+	}
+
+	slog.Info("✅ Created user", "name", createRes.Name, "user_id", createRes.Id)
+	return nil
+}
+
+// serverStreamingExample demonstrates server streaming RPC
+func serverStreamingExample(c *userclient.Client, newContext callContext) error {
+	slog.Info("=== Server streaming RPC example ===")
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	filter := &pb.UserFilter{
+		Keyword:  "John",
+		PageSize: 1,
+	}
+
+	// The client follows the pages of results, one user per page here
+	count := 0
+	for user, err := range c.StreamUsers(ctx, filter) {
+		if err != nil {
+			return fmt.Errorf("StreamUsers failed: %w", err)
+		}
+
+		slog.Info("📨 Streamed user", "name", user.Name, "email", user.Email)
+		count++
+	}
+
+	slog.Info("✅ Stream completed", "users", count)
+	return nil
+}
+
+// clientStreamingExample demonstrates client streaming RPC
+func clientStreamingExample(c *userclient.Client, newContext callContext) error {
+	slog.Info("=== Client streaming RPC example ===")
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	// Send bulk users
+	users := []*pb.CreateUserRequest{
+		{Name: "Alice Johnson", Email: "alice@example.com", Role: "user"},
+		{Name: "Charlie Brown", Email: "charlie@example.com", Role: "user"},
+		{Name: "David Wilson", Email: "david@example.com", Role: "admin"},
+	}
+
+	send := func(yield func(*pb.CreateUserRequest) bool) {
+		for _, user := range users {
+			slog.Info("📤 Sending user", "email", user.Email)
+			if !yield(user) {
+				return
+			}
+		}
+	}
+	result, err := c.CreateUsers(ctx, send)
+	if err != nil {
+		return fmt.Errorf("CreateUsers failed: %w", err)
+	}
+
+	slog.Info("✅ Bulk create finished", "created", result.CreatedCount, "errors", len(result.Errors))
+
+	for _, errMsg := range result.Errors {
+		slog.Error("Bulk create rejected a user", "error", errMsg)
+	}
+
+	return nil
+}
+
+// bidirectionalStreamingExample demonstrates bidirectional streaming RPC
+func bidirectionalStreamingExample(c *userclient.Client, newContext callContext) error {
+	slog.Info("=== Bidirectional streaming RPC example ===")
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	stream, err := c.Chat(ctx)
+	if err != nil {
+		return fmt.Errorf("Chat failed: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	// Message sending goroutine
+	go func() {
+		defer wg.Done()
+		defer stream.CloseSend()
+
+		for i := 0; i < 5; i++ {
+			msg := &pb.ChatMessage{
+				From:      "Client",
+				To:        "Server",
+				Message:   fmt.Sprintf("Message %d", i+1),
+				Timestamp: timestamppb.New(time.Now()),
+				Type:      pb.MessageType_MESSAGE_TYPE_TEXT,
+			}
+
+			if err := stream.Send(msg); err != nil {
+				slog.Error("Chat send failed", "error", err)
+				return
+			}
+
+			slog.Info("📤 Sent message", "message", msg.Message)
+			time.Sleep(1 * time.Second)
+		}
+	}()
+
+	// Message receiving goroutine
+	go func() {
+		defer wg.Done()
+
+		for {
+			msg, err := stream.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				slog.Error("Chat receive failed", "error", err)
+				return
+			}
+
+			slog.Info("📥 Received message", "from", msg.From, "to", msg.To, "message", msg.Message)
+		}
+	}()
+
+	wg.Wait()
+	slog.Info("✅ Chat completed")
+	return nil
+}
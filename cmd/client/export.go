@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pb "example.com/user/proto"
+	"github.com/spf13/cobra"
+)
+
+// fileFormats maps --format values onto file formats
+var fileFormats = map[string]pb.FileFormat{
+	"csv":    pb.FileFormat_FILE_FORMAT_CSV,
+	"ndjson": pb.FileFormat_FILE_FORMAT_NDJSON,
+	"json":   pb.FileFormat_FILE_FORMAT_JSON,
+}
+
+// formatFor returns the file format named by --format, or else by the extension of path,
+// defaulting to CSV
+func formatFor(name, path string) (pb.FileFormat, error) {
+	if name == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".ndjson", ".jsonl":
+			return pb.FileFormat_FILE_FORMAT_NDJSON, nil
+		case ".json":
+			return pb.FileFormat_FILE_FORMAT_JSON, nil
+		}
+		return pb.FileFormat_FILE_FORMAT_CSV, nil
+	}
+	format, ok := fileFormats[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("invalid format %q: expected csv, ndjson or json", name)
+	}
+	return format, nil
+}
+
+func exportCommand(opts *options) *cobra.Command {
+	req := &pb.ExportUsersRequest{}
+	var format string
+	cmd := &cobra.Command{
+		Use:   "export [FILE]",
+		Short: "Write the matching users to FILE or stdout as CSV, NDJSON or JSON",
+		Long: "Write the matching users to FILE, or stdout when FILE is - or left out. The format\n" +
+			"is --format, or else follows the extension of FILE (.csv, .ndjson, .jsonl or .json),\n" +
+			"defaulting to CSV. FILE is only replaced once the whole export has arrived.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "-"
+			if len(args) == 1 {
+				path = args[0]
+			}
+			var err error
+			if req.Format, err = formatFor(format, path); err != nil {
+				return err
+			}
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			if path == "-" {
+				return c.ExportUsers(ctx, req, os.Stdout)
+			}
+			return writeAtomically(path, func(w io.Writer) error {
+				return c.ExportUsers(ctx, req, w)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "", "csv, ndjson or json (default: from the extension of FILE, else csv)")
+	cmd.Flags().StringVar(&req.Keyword, "keyword", "", "only users whose name or email contains this, ignoring case")
+	cmd.Flags().StringVar(&req.EmailContains, "email", "", "only users whose email contains this, ignoring case")
+	cmd.Flags().StringSliceVar(&req.Roles, "role", nil, "only users with one of these roles")
+	cmd.Flags().StringVar(&req.OrderBy, "order-by", "", `sort order, e.g. "created_at desc"`)
+	cmd.Flags().BoolVar(&req.IncludeDeleted, "include-deleted", false, "also export soft-deleted users")
+	return cmd
+}
+
+func importCommand(opts *options) *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "import [FILE]",
+		Short: "Create the users of FILE or stdin, a file as export writes it",
+		Long: "Create the users of FILE, or stdin when FILE is - or left out, and print the result.\n" +
+			"The format is read as for export. The import is atomic: if any user is rejected,\n" +
+			"the errors are listed by line and no user is created. Users get new IDs, and\n" +
+			"soft-deleted ones are skipped.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "-"
+			if len(args) == 1 {
+				path = args[0]
+			}
+			fileFormat, err := formatFor(format, path)
+			if err != nil {
+				return err
+			}
+			in := os.Stdin
+			if path != "-" {
+				f, err := os.Open(path)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				in = f
+			}
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			res, err := c.ImportUsers(ctx, fileFormat, in)
+			if err != nil {
+				return err
+			}
+			return printJSON(res)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "", "csv, ndjson or json (default: from the extension of FILE, else csv)")
+	return cmd
+}
+
+// writeAtomically writes path through a temporary file in the same directory, renamed over
+// path only when write succeeds, so a failed write leaves path as it was
+func writeAtomically(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
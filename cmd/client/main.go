@@ -1,14 +1,273 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
 
-	"example.com/user/internal/client"
+	"example.com/user/internal/config"
+	"example.com/user/internal/logging"
+	"example.com/user/internal/tracing"
+	"example.com/user/pkg/userclient"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
 )
 
+// options holds the configuration and the flags shared by every command
+type options struct {
+	cfg *config.Config
+
+	configFile string
+	profile    string
+	server     string
+	insecure   bool
+	caFile     string
+	certFile   string
+	keyFile    string
+	serverName string
+	timeout    time.Duration
+	deadline   string
+	// deadlineAt is --deadline parsed; zero when unset
+	deadlineAt time.Time
+
+	// shutdownTracing flushes client spans; nil when tracing is off
+	shutdownTracing func(context.Context) error
+	// credentials tells whether connect set the client up to authenticate
+	credentials bool
+}
+
 func main() {
-	c := client.New()
-	if err := c.RunExamples(); err != nil {
-		log.Fatalf("Client examples failed: %v", err)
+	cfg := config.Load()
+	if err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		logging.Fatal("Invalid logging configuration", "error", err)
+	}
+
+	opts := options{cfg: cfg}
+	root := &cobra.Command{
+		Use:   "client",
+		Short: "Call the user service",
+		Long: "Call the user service. Without a command, runs examples of every RPC pattern.\n\n" +
+			"Settings come from the environment, then the chosen profile of the config file\n" +
+			"(~/.userctl.yaml), then flags, each overriding the ones before.",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.parseDeadline()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			return runExamples(c, opts.context)
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.configFile, "config", config.DefaultProfilePath(), "client config file holding server profiles")
+	flags.StringVar(&opts.profile, "profile", "", "profile of the config file to use (default: its current_profile)")
+	flags.StringVarP(&opts.server, "server", "s", "", "server address, e.g. localhost:50051 (GRPC_SERVER_ADDRESS)")
+	flags.BoolVar(&opts.insecure, "insecure", false, "connect in plaintext (GRPC_INSECURE)")
+	flags.StringVar(&opts.caFile, "ca-file", "", "PEM CA bundle verifying the server (TLS_CA_FILE)")
+	flags.StringVar(&opts.certFile, "cert-file", "", "PEM client certificate for mutual TLS (TLS_CLIENT_CERT_FILE)")
+	flags.StringVar(&opts.keyFile, "key-file", "", "PEM private key of --cert-file (TLS_CLIENT_KEY_FILE)")
+	flags.StringVar(&opts.serverName, "server-name", "", "name to verify in the server certificate (TLS_SERVER_NAME)")
+	flags.DurationVar(&opts.timeout, "timeout", 10*time.Second, "how long each call may take (0 for no limit)")
+	flags.StringVar(&opts.deadline, "deadline", "", `time by which every call must end, as RFC 3339 or "15:04[:05]" today`)
+
+	root.AddCommand(
+		getCommand(&opts),
+		batchGetCommand(&opts),
+		createCommand(&opts),
+		validateCommand(&opts),
+		updateCommand(&opts),
+		deleteCommand(&opts),
+		suspendCommand(&opts),
+		activateCommand(&opts),
+		preferencesCommand(&opts),
+		setPreferencesCommand(&opts),
+		listCommand(&opts),
+		pageCommand(&opts),
+		searchCommand(&opts),
+		historyCommand(&opts),
+		bulkCreateCommand(&opts),
+		bulkUpdateCommand(&opts),
+		exportCommand(&opts),
+		importCommand(&opts),
+		uploadAvatarCommand(&opts),
+		getAvatarCommand(&opts),
+		watchCommand(&opts),
+		chatCommand(&opts),
+		chatHistoryCommand(&opts),
+	)
+
+	err := root.Execute()
+	opts.flushTraces()
+	if err != nil {
+		logging.Fatal("Client failed", "error", err)
+	}
+}
+
+// connect applies the chosen profile, then the flags set on cmd, over the configuration
+// and connects to the server
+func (o *options) connect(cmd *cobra.Command) (*userclient.Client, error) {
+	cfg := o.cfg
+	profile, err := config.LoadProfile(o.configFile, o.profile)
+	if err != nil {
+		return nil, err
+	}
+	profile.Apply(&cfg.Client)
+	if err := cfg.ResolveSecrets(context.Background()); err != nil {
+		return nil, fmt.Errorf("load secrets: %w", err)
+	}
+
+	flags := cmd.Flags()
+	if flags.Changed("server") {
+		cfg.Client.ServerAddress = o.server
+	}
+	if flags.Changed("insecure") {
+		cfg.Client.Insecure = o.insecure
+	}
+	if flags.Changed("ca-file") {
+		cfg.Client.TLSCAFile = o.caFile
+	}
+	if flags.Changed("cert-file") {
+		cfg.Client.TLSCertFile = o.certFile
+	}
+	if flags.Changed("key-file") {
+		cfg.Client.TLSKeyFile = o.keyFile
+	}
+	if flags.Changed("server-name") {
+		cfg.Client.TLSServerName = o.serverName
+	}
+	// TLS flags win over a GRPC_INSECURE set in the environment, as by the Makefile
+	tlsFlags := flags.Changed("ca-file") || flags.Changed("cert-file") || flags.Changed("key-file") || flags.Changed("server-name")
+	if tlsFlags && !flags.Changed("insecure") {
+		cfg.Client.Insecure = false
+	}
+
+	o.credentials = cfg.Client.AuthToken != "" || cfg.Client.APIKey != "" || cfg.Client.SigningKey != "" || cfg.Client.TLSCertFile != ""
+	clientOpts, err := clientOptions(cfg.Client)
+	if err != nil {
+		return nil, err
+	}
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, "user-client")
+	if err != nil {
+		return nil, fmt.Errorf("set up tracing: %w", err)
+	}
+	if shutdownTracing != nil {
+		o.shutdownTracing = shutdownTracing
+		clientOpts = append(clientOpts, userclient.WithDialOptions(grpc.WithStatsHandler(tracing.ClientHandler())))
+	}
+	return userclient.New(cfg.Client.ServerAddress, clientOpts...)
+}
+
+// flushTraces sends the spans not yet exported when tracing is on
+func (o *options) flushTraces() {
+	if o.shutdownTracing == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := o.shutdownTracing(ctx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
+}
+
+// clientOptions translates the client configuration into options of userclient.New
+func clientOptions(cfg config.ClientConfig) ([]userclient.Option, error) {
+	opts := []userclient.Option{
+		userclient.WithConnectTimeout(cfg.ConnectionTimeout),
+		userclient.WithLoadBalancing(cfg.LBPolicy),
+		userclient.WithAuthToken(cfg.AuthToken),
+		userclient.WithAPIKey(cfg.APIKey),
+		userclient.WithCAFile(cfg.TLSCAFile),
+		userclient.WithClientCertificate(cfg.TLSCertFile, cfg.TLSKeyFile),
+		userclient.WithServerName(cfg.TLSServerName),
+		userclient.WithRetry(cfg.RetryMaxAttempts, cfg.RetryInitialBackoff, cfg.RetryMaxBackoff),
+		userclient.WithHedging(cfg.HedgeDelay),
+		userclient.WithRateLimit(cfg.RateLimit, cfg.RateLimitBurst),
+		userclient.WithCircuitBreaker(cfg.BreakerFailures, cfg.BreakerCooldown),
+	}
+	if cfg.Insecure {
+		opts = append(opts, userclient.WithInsecure())
+	}
+	if cfg.SigningKey != "" {
+		keyID, secret, found := strings.Cut(cfg.SigningKey, ":")
+		if !found || keyID == "" || secret == "" {
+			return nil, errors.New(`invalid client credentials: SIGNING_KEY must be "keyID:secret"`)
+		}
+		opts = append(opts, userclient.WithSigningKey(keyID, []byte(secret)))
+	}
+	if len(cfg.RetryCodes) > 0 {
+		retryable := make([]codes.Code, 0, len(cfg.RetryCodes))
+		for _, name := range cfg.RetryCodes {
+			var code codes.Code
+			if err := code.UnmarshalJSON([]byte(strconv.Quote(name))); err != nil {
+				return nil, fmt.Errorf("RETRY_CODES: unknown status code %q", name)
+			}
+			retryable = append(retryable, code)
+		}
+		opts = append(opts, userclient.WithRetryCodes(retryable...))
+	}
+	if ka := cfg.Keepalive; ka.Time > 0 {
+		opts = append(opts, userclient.WithKeepalive(keepalive.ClientParameters{
+			Time:                ka.Time,
+			Timeout:             ka.Timeout,
+			PermitWithoutStream: ka.PermitWithoutStream,
+		}))
+	}
+	return opts, nil
+}
+
+// parseDeadline reads --deadline, which must not have passed already
+func (o *options) parseDeadline() error {
+	if o.deadline == "" {
+		return nil
+	}
+	deadline, err := time.Parse(time.RFC3339, o.deadline)
+	if err != nil {
+		clock, cerr := time.ParseInLocation(time.TimeOnly, o.deadline, time.Local)
+		if cerr != nil {
+			clock, cerr = time.ParseInLocation("15:04", o.deadline, time.Local)
+		}
+		if cerr != nil {
+			return fmt.Errorf(`invalid --deadline %q: expected RFC 3339 such as 2026-01-02T15:04:05Z, or "15:04[:05]"`, o.deadline)
+		}
+		y, m, d := time.Now().Date()
+		deadline = time.Date(y, m, d, clock.Hour(), clock.Minute(), clock.Second(), 0, time.Local)
+	}
+	if !deadline.After(time.Now()) {
+		return fmt.Errorf("--deadline %s has already passed", deadline.Format(time.RFC3339))
+	}
+	o.deadlineAt = deadline
+	return nil
+}
+
+// context returns the context of a call, bounded by --timeout and --deadline
+func (o *options) context() (context.Context, context.CancelFunc) {
+	return o.bound(context.Background(), true)
+}
+
+// bound derives a context from parent ending at --deadline, or after --timeout when
+// withTimeout is set, whichever comes first
+func (o *options) bound(parent context.Context, withTimeout bool) (context.Context, context.CancelFunc) {
+	deadline := o.deadlineAt
+	if withTimeout && o.timeout > 0 {
+		if end := time.Now().Add(o.timeout); deadline.IsZero() || end.Before(deadline) {
+			deadline = end
+		}
+	}
+	if deadline.IsZero() {
+		return context.WithCancel(parent)
 	}
-}
\ No newline at end of file
+	return context.WithDeadline(parent, deadline)
+}
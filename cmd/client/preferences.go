@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+
+	pb "example.com/user/proto"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func preferencesCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "preferences ID",
+		Short: "Print the preferences of a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			prefs, err := c.GetPreferences(ctx, id)
+			if err != nil {
+				return err
+			}
+			return printJSON(prefs)
+		},
+	}
+}
+
+// preferenceFlags are the flags of set-preferences and the update_mask paths they change
+var preferenceFlags = []struct{ flag, path string }{
+	{"locale", "locale"},
+	{"timezone", "timezone"},
+	{"email", "notifications.email"},
+	{"push", "notifications.push"},
+	{"weekly-digest", "notifications.weekly_digest"},
+}
+
+func setPreferencesCommand(opts *options) *cobra.Command {
+	req := &pb.UpdatePreferencesRequest{Notifications: &pb.NotificationSettings{}}
+	cmd := &cobra.Command{
+		Use:   "set-preferences ID",
+		Short: "Change the preferences of a user and print them; those left out stay unchanged",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			req.UserId = id
+			// Name exactly the fields given, so that --push=false turns push off
+			req.UpdateMask = &fieldmaskpb.FieldMask{}
+			for _, f := range preferenceFlags {
+				if cmd.Flags().Changed(f.flag) {
+					req.UpdateMask.Paths = append(req.UpdateMask.Paths, f.path)
+				}
+			}
+			if len(req.UpdateMask.Paths) == 0 {
+				return errors.New("nothing to change: give --locale, --timezone, --email, --push or --weekly-digest")
+			}
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			ctx, cancel := opts.context()
+			defer cancel()
+
+			prefs, err := c.UpdatePreferences(ctx, req)
+			if err != nil {
+				return err
+			}
+			return printJSON(prefs)
+		},
+	}
+	cmd.Flags().StringVar(&req.Locale, "locale", "", "language tag such as fr-FR; empty resets it to en-US")
+	cmd.Flags().StringVar(&req.Timezone, "timezone", "", "IANA time zone such as Europe/Paris; empty resets it to UTC")
+	cmd.Flags().BoolVar(&req.Notifications.Email, "email", false, "receive email notifications")
+	cmd.Flags().BoolVar(&req.Notifications.Push, "push", false, "receive push notifications")
+	cmd.Flags().BoolVar(&req.Notifications.WeeklyDigest, "weekly-digest", false, "receive a weekly digest")
+	return cmd
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	pb "example.com/user/proto"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func watchCommand(opts *options) *cobra.Command {
+	var ids []int32
+	var types []string
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Print changes to users as they happen, one JSON object per line",
+		Long: "Print every change to users as the server commits it, one JSON object per line,\n" +
+			"until Ctrl-C. --timeout only applies when set explicitly; --deadline always does.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &pb.WatchUsersRequest{UserIds: ids}
+			for _, name := range types {
+				t, ok := pb.UserEventType_value["USER_EVENT_TYPE_"+strings.ToUpper(name)]
+				if !ok || t == 0 {
+					return fmt.Errorf("invalid event type %q: expected created, updated, deleted or undeleted", name)
+				}
+				req.Types = append(req.Types, pb.UserEventType(t))
+			}
+			c, err := opts.connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			ctx, cancel := opts.bound(ctx, cmd.Flags().Changed("timeout"))
+			defer cancel()
+
+			for event, err := range c.WatchUsers(ctx, req) {
+				if err != nil {
+					// Ctrl-C ends the watch
+					if errors.Is(ctx.Err(), context.Canceled) {
+						return nil
+					}
+					return err
+				}
+				line, err := protojson.Marshal(event)
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(line))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Int32SliceVar(&ids, "user", nil, "only changes to these user IDs")
+	cmd.Flags().StringSliceVar(&types, "type", nil, "only these changes: created, updated, deleted or undeleted")
+	return cmd
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"example.com/user/internal/config"
+	"example.com/user/internal/logging"
+	"example.com/user/internal/repository"
+)
+
+func main() {
+	steps := flag.Int("steps", 1, "number of migrations to roll back with down")
+	timeout := flag.Duration("timeout", 5*time.Minute, "overall migration timeout")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] up|down|status\n\nFlags:\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+	if err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		logging.Fatal("Invalid logging configuration", "error", err)
+	}
+	if err := cfg.ResolveSecrets(context.Background()); err != nil {
+		logging.Fatal("Failed to load secrets", "error", err)
+	}
+	migrator, closeDB, err := repository.OpenMigrator(cfg.Storage)
+	if err != nil {
+		logging.Fatal("Failed to open storage", "backend", cfg.Storage.Backend, "error", err)
+	}
+	defer closeDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	switch flag.Arg(0) {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			logging.Fatal("Migration failed", "error", err)
+		}
+		slog.Info("✅ Applied migrations", "count", applied)
+
+	case "down":
+		reverted, err := migrator.Down(ctx, *steps)
+		if err != nil {
+			logging.Fatal("Rollback failed", "error", err)
+		}
+		slog.Info("✅ Reverted migrations", "count", reverted)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			logging.Fatal("Failed to read migration status", "error", err)
+		}
+		for _, st := range statuses {
+			state := "pending"
+			if st.Applied {
+				state = "applied " + st.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d  %-30s %s\n", st.Version, st.Name, state)
+		}
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
@@ -1,14 +1,41 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
+	"example.com/user/internal/logging"
 	"example.com/user/internal/server"
 )
 
 func main() {
-	srv := server.New()
-	if err := srv.Start(); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	srv, err := server.New()
+	if err != nil {
+		logging.Fatal("Failed to create server", "error", err)
 	}
-}
\ No newline at end of file
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Start() }()
+
+	select {
+	case err := <-errc:
+		if err != nil {
+			logging.Fatal("Failed to start server", "error", err)
+		}
+	case <-ctx.Done():
+		// Restore default handling so a second signal kills the process at once
+		stop()
+		slog.Info("📥 Received shutdown signal")
+		srv.Stop()
+		if err := <-errc; err != nil {
+			slog.Error("Server stopped with error", "error", err)
+		}
+		slog.Info("👋 Server stopped")
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"example.com/user/internal/auth"
+	"example.com/user/internal/config"
+	"example.com/user/internal/logging"
+)
+
+// token mints a JWT signed with the server's JWT_SECRET, for development and testing
+func main() {
+	subject := flag.String("sub", "1", "subject (user ID) of the token")
+	role := flag.String("role", "user", "role claim")
+	email := flag.String("email", "", "email claim")
+	ttl := flag.Duration("ttl", time.Hour, "token lifetime")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags]\n\nPrints a token for AUTH_TOKEN, signed with JWT_SECRET.\n\nFlags:\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cfg := config.Load()
+	if err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		logging.Fatal("Invalid logging configuration", "error", err)
+	}
+	if err := cfg.ResolveSecrets(context.Background()); err != nil {
+		logging.Fatal("Failed to load secrets", "error", err)
+	}
+	authn, err := auth.NewJWTAuthenticator(cfg.Auth.JWTSecret, cfg.Auth.JWTIssuer, cfg.Auth.JWTAudience)
+	if err != nil {
+		logging.Fatal("Invalid JWT configuration", "error", err)
+	}
+
+	token, err := authn.Issue(*subject, *role, *email, *ttl)
+	if err != nil {
+		logging.Fatal("Failed to sign token", "error", err)
+	}
+	fmt.Println(token)
+}
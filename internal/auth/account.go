@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// AccountChecker vets the account behind an authenticated Principal, returning a gRPC
+// status error when it may no longer be used, e.g. because it was suspended. Valid
+// credentials outlive such changes, so they are checked on every call.
+type AccountChecker func(ctx context.Context, p *Principal) error
+
+// RoleResolver returns the current role of the account behind p, which may have changed
+// since the credentials carrying p.Role were issued
+type RoleResolver func(ctx context.Context, p *Principal) (string, error)
+
+// UnaryAccountInterceptor rejects calls whose Principal check refuses. It must run after
+// the authentication interceptors; calls without a Principal, to public methods, pass.
+func UnaryAccountInterceptor(check AccountChecker) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if p, ok := PrincipalFromContext(ctx); ok {
+			if err := check(ctx, p); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAccountInterceptor is the streaming counterpart of UnaryAccountInterceptor
+func StreamAccountInterceptor(check AccountChecker) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if p, ok := PrincipalFromContext(ss.Context()); ok {
+			if err := check(ss.Context(), p); err != nil {
+				return err
+			}
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// APIKeyHeader is the metadata key carrying an API key
+const APIKeyHeader = "x-api-key"
+
+// minAPIKeyLength rejects keys short enough to be guessed
+const minAPIKeyLength = 16
+
+// APIKeyAuthenticator authenticates callers by a static API key. Keys are kept as
+// SHA-256 digests so lookups don't leak key contents through timing.
+type APIKeyAuthenticator struct {
+	keys map[[sha256.Size]byte]*Principal
+}
+
+// NewAPIKeyAuthenticator parses key entries of the form "subject:role:key"
+func NewAPIKeyAuthenticator(entries []string) (*APIKeyAuthenticator, error) {
+	a := &APIKeyAuthenticator{keys: make(map[[sha256.Size]byte]*Principal, len(entries))}
+	for i, entry := range entries {
+		subject, role, key, err := parseAPIKeyEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("API key %d: %w", i+1, err)
+		}
+
+		digest := sha256.Sum256([]byte(key))
+		if _, dup := a.keys[digest]; dup {
+			return nil, fmt.Errorf("API key %d (%s): duplicate key", i+1, subject)
+		}
+		a.keys[digest] = &Principal{Subject: subject, Role: role}
+	}
+
+	if len(a.keys) == 0 {
+		return nil, errors.New("no API keys configured")
+	}
+	return a, nil
+}
+
+// ReadAPIKeys reads key entries from r, one per line; blank lines and lines starting
+// with # are ignored
+func ReadAPIKeys(r io.Reader) ([]string, error) {
+	var entries []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	return entries, scanner.Err()
+}
+
+// LoadAPIKeysFile reads key entries from the file at path
+func LoadAPIKeysFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open API keys file: %w", err)
+	}
+	defer f.Close()
+
+	return ReadAPIKeys(f)
+}
+
+// Authenticate implements Authenticator for the key in the x-api-key header
+func (a *APIKeyAuthenticator) Authenticate(md Metadata) (*Principal, error) {
+	values := metadata.MD(md).Get(APIKeyHeader)
+	if len(values) == 0 {
+		return nil, errNoCredentials
+	}
+
+	principal, ok := a.keys[sha256.Sum256([]byte(values[0]))]
+	if !ok {
+		return nil, errors.New("invalid API key")
+	}
+
+	p := *principal
+	return &p, nil
+}
+
+func parseAPIKeyEntry(entry string) (subject, role, key string, err error) {
+	parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+	if len(parts) != 3 {
+		return "", "", "", errors.New(`expected "subject:role:key"`)
+	}
+
+	subject, role, key = parts[0], parts[1], parts[2]
+	if subject == "" || role == "" {
+		return "", "", "", errors.New("subject and role are required")
+	}
+	if len(key) < minAPIKeyLength {
+		return "", "", "", fmt.Errorf("key of %s must be at least %d characters", subject, minAPIKeyLength)
+	}
+	return subject, role, key, nil
+}
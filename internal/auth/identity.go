@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"net/url"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientIdentity describes the verified certificate a client presented over mutual TLS
+type ClientIdentity struct {
+	CommonName   string
+	Organization []string
+	DNSNames     []string
+	URIs         []string
+	SerialNumber string
+}
+
+type clientIdentityKey struct{}
+
+// WithClientIdentity returns a copy of ctx carrying id
+func WithClientIdentity(ctx context.Context, id *ClientIdentity) context.Context {
+	return context.WithValue(ctx, clientIdentityKey{}, id)
+}
+
+// ClientIdentityFromContext returns the identity attached by the mTLS interceptors
+func ClientIdentityFromContext(ctx context.Context) (*ClientIdentity, bool) {
+	id, ok := ctx.Value(clientIdentityKey{}).(*ClientIdentity)
+	return id, ok
+}
+
+// MTLSUnaryInterceptor attaches the caller's ClientIdentity to the handler context.
+// Certificates are verified during the TLS handshake, so calls without one never get here
+// when the server requires client certificates.
+func MTLSUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withPeerIdentity(ctx), req)
+	}
+}
+
+// MTLSStreamInterceptor is the streaming counterpart of MTLSUnaryInterceptor
+func MTLSStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withPeerIdentity(ss.Context())})
+	}
+}
+
+// withPeerIdentity attaches the identity of the verified peer certificate, if any
+func withPeerIdentity(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ctx
+	}
+
+	return WithClientIdentity(ctx, identityFromCert(tlsInfo.State.VerifiedChains[0][0]))
+}
+
+func identityFromCert(cert *x509.Certificate) *ClientIdentity {
+	return &ClientIdentity{
+		CommonName:   cert.Subject.CommonName,
+		Organization: cert.Subject.Organization,
+		DNSNames:     cert.DNSNames,
+		URIs:         uriStrings(cert.URIs),
+		SerialNumber: cert.SerialNumber.String(),
+	}
+}
+
+func uriStrings(uris []*url.URL) []string {
+	var out []string
+	for _, u := range uris {
+		out = append(out, u.String())
+	}
+	return out
+}
+
+// contextStream overrides the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
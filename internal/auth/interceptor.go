@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// errNoCredentials is returned when a request carries no credentials at all
+var errNoCredentials = errors.New("missing credentials")
+
+// Metadata is the incoming request metadata handed to an Authenticator
+type Metadata metadata.MD
+
+// bearerToken extracts the token of an "authorization: Bearer <token>" header
+func (md Metadata) bearerToken() (string, error) {
+	values := metadata.MD(md).Get("authorization")
+	if len(values) == 0 {
+		return "", errNoCredentials
+	}
+
+	scheme, token, found := strings.Cut(values[0], " ")
+	if !found || !strings.EqualFold(scheme, "bearer") || strings.TrimSpace(token) == "" {
+		return "", errors.New("authorization header must be \"Bearer <token>\"")
+	}
+	return strings.TrimSpace(token), nil
+}
+
+// Authenticator identifies the caller of a request from its metadata
+type Authenticator interface {
+	Authenticate(md Metadata) (*Principal, error)
+}
+
+// RequestAuthenticator is an Authenticator whose credentials cover the request itself,
+// such as a request signature. The interceptors hand it the method and, for unary calls,
+// the request message; streaming calls are authenticated with a nil request.
+type RequestAuthenticator interface {
+	Authenticator
+	AuthenticateRequest(md Metadata, method string, req any) (*Principal, error)
+}
+
+// UnaryServerInterceptor rejects calls that authn can't authenticate with Unauthenticated
+// and attaches the Principal to the handler context. Methods listed in public (full
+// names such as "/user.UserService/GetUser") skip authentication.
+func UnaryServerInterceptor(authn Authenticator, public ...string) grpc.UnaryServerInterceptor {
+	skip := methodSet(public)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if skip[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticate(ctx, authn, info.FullMethod, req)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor
+func StreamServerInterceptor(authn Authenticator, public ...string) grpc.StreamServerInterceptor {
+	skip := methodSet(public)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if skip[info.FullMethod] {
+			return handler(srv, ss)
+		}
+
+		ctx, err := authenticate(ss.Context(), authn, info.FullMethod, nil)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, authn Authenticator, method string, req any) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var principal *Principal
+	var err error
+	if ra, ok := authn.(RequestAuthenticator); ok {
+		principal, err = ra.AuthenticateRequest(Metadata(md), method, req)
+	} else {
+		principal, err = authn.Authenticate(Metadata(md))
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return WithPrincipal(ctx, principal), nil
+}
+
+func methodSet(methods []string) map[string]bool {
+	set := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		set[m] = true
+	}
+	return set
+}
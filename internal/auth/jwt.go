@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minSecretLength is the shortest HMAC secret accepted, matching the HS256 output size
+const minSecretLength = 32
+
+// Claims are the JWT claims the service issues and accepts
+type Claims struct {
+	jwt.RegisteredClaims
+	Role  string `json:"role,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// JWTAuthenticator verifies HS256 bearer tokens
+type JWTAuthenticator struct {
+	secret   []byte
+	issuer   string
+	audience string
+}
+
+// NewJWTAuthenticator creates an authenticator for tokens signed with secret. Issuer
+// and audience are enforced when non-empty.
+func NewJWTAuthenticator(secret, issuer, audience string) (*JWTAuthenticator, error) {
+	if len(secret) < minSecretLength {
+		return nil, fmt.Errorf("JWT secret must be at least %d bytes", minSecretLength)
+	}
+	return &JWTAuthenticator{secret: []byte(secret), issuer: issuer, audience: audience}, nil
+}
+
+// Verify parses token and checks its signature, expiry, issuer and audience
+func (a *JWTAuthenticator) Verify(token string) (*Claims, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if a.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(a.issuer))
+	}
+	if a.audience != "" {
+		opts = append(opts, jwt.WithAudience(a.audience))
+	}
+
+	claims := &Claims{}
+	if _, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) { return a.secret, nil }, opts...); err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+	return claims, nil
+}
+
+// Issue signs a token for subject valid for ttl, stamped with the configured issuer and audience
+func (a *JWTAuthenticator) Issue(subject, role, email string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			Issuer:    a.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		Role:  role,
+		Email: email,
+	}
+	if a.audience != "" {
+		claims.Audience = jwt.ClaimStrings{a.audience}
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(a.secret)
+}
+
+// Authenticate implements Authenticator for the bearer token in the request metadata
+func (a *JWTAuthenticator) Authenticate(md Metadata) (*Principal, error) {
+	token, err := md.bearerToken()
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := a.Verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bearer token: %w", err)
+	}
+	return &Principal{Subject: claims.Subject, Role: claims.Role, Claims: claims}, nil
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func newTestJWTAuthenticator(t *testing.T, secret, issuer, audience string) *JWTAuthenticator {
+	t.Helper()
+
+	a, err := NewJWTAuthenticator(secret, issuer, audience)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestNewJWTAuthenticatorSecretLength(t *testing.T) {
+	if _, err := NewJWTAuthenticator(testSecret[:minSecretLength-1], "", ""); err == nil {
+		t.Error("NewJWTAuthenticator with a short secret = nil error, want one")
+	}
+}
+
+func TestJWTAuthenticate(t *testing.T) {
+	verifier := newTestJWTAuthenticator(t, testSecret, "users", "api")
+
+	issue := func(a *JWTAuthenticator, subject string, ttl time.Duration) string {
+		token, err := a.Issue(subject, RoleAdmin, "ada@example.com", ttl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	valid := issue(verifier, "1", time.Minute)
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "1", Issuer: "users", Audience: jwt.ClaimStrings{"api"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+		Role: RoleAdmin,
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string // empty sends no authorization header
+		wantErr       string
+	}{
+		{"valid", "Bearer " + valid, ""},
+		{"lowercase scheme", "bearer " + valid, ""},
+		{"no header", "", "missing credentials"},
+		{"other scheme", "Basic " + valid, "Bearer <token>"},
+		{"no token", "Bearer  ", "Bearer <token>"},
+		{"garbled", "Bearer not.a.token", "invalid bearer token"},
+		{"expired", "Bearer " + issue(verifier, "1", -time.Minute), "expired"},
+		{"other secret", "Bearer " + issue(newTestJWTAuthenticator(t, strings.Repeat("s", 32), "users", "api"), "1", time.Minute),
+			"signature is invalid"},
+		{"other issuer", "Bearer " + issue(newTestJWTAuthenticator(t, testSecret, "elsewhere", "api"), "1", time.Minute),
+			"invalid issuer"},
+		{"other audience", "Bearer " + issue(newTestJWTAuthenticator(t, testSecret, "users", "web"), "1", time.Minute),
+			"invalid audience"},
+		{"no subject", "Bearer " + issue(verifier, "", time.Minute), "token has no subject"},
+		{"unsigned", "Bearer " + unsigned, "invalid bearer token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := Metadata{}
+			if tt.authorization != "" {
+				md["authorization"] = []string{tt.authorization}
+			}
+			principal, err := verifier.Authenticate(md)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Authenticate() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if principal.Subject != "1" || principal.Role != RoleAdmin || principal.Claims.Email != "ada@example.com" {
+				t.Errorf("Authenticate() = %+v, want subject 1 as admin", principal)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	const public = "/grpc.health.v1.Health/Check"
+	verifier := newTestJWTAuthenticator(t, testSecret, "", "")
+	interceptor := UnaryServerInterceptor(verifier, public)
+	token, err := verifier.Issue("2", RoleUser, "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		want          codes.Code
+		wantPrincipal bool
+	}{
+		{"authenticated", getUser, "Bearer " + token, codes.OK, true},
+		{"anonymous", getUser, "", codes.Unauthenticated, false},
+		{"bad token", getUser, "Bearer nope", codes.Unauthenticated, false},
+		{"anonymous on public method", public, "", codes.OK, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.MD{}
+			if tt.authorization != "" {
+				md.Set("authorization", tt.authorization)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+
+			var principal *Principal
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, req any) (any, error) {
+					principal, _ = PrincipalFromContext(ctx)
+					return nil, nil
+				})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("code = %v (%v), want %v", got, err, tt.want)
+			}
+			if (principal != nil) != tt.wantPrincipal {
+				t.Errorf("handler principal = %+v, want one: %v", principal, tt.wantPrincipal)
+			}
+			if principal != nil && principal.Subject != "2" {
+				t.Errorf("handler principal subject = %q, want 2", principal.Subject)
+			}
+		})
+	}
+}
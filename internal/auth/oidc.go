@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// oidcTimeout bounds each request to the identity provider
+	oidcTimeout = 10 * time.Second
+	// oidcLeeway tolerates clock skew between the provider and this server
+	oidcLeeway = 30 * time.Second
+)
+
+// oidcSigningMethods are the asymmetric algorithms accepted from an identity provider
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// OIDCAuthenticator verifies bearer tokens issued by an OpenID Connect provider such as
+// Keycloak or Auth0, using the signing keys the provider publishes at its JWKS endpoint
+type OIDCAuthenticator struct {
+	issuer    string
+	audience  string
+	roleClaim string
+	keys      *jwksCache
+}
+
+// NewOIDCAuthenticator discovers the provider at issuerURL and fetches its signing keys.
+// Tokens must carry issuerURL as iss and, when audience is set, list it in aud. The
+// caller's role is read from roleClaim, a dotted path such as "realm_access.roles".
+func NewOIDCAuthenticator(ctx context.Context, issuerURL, audience, roleClaim string) (*OIDCAuthenticator, error) {
+	if issuerURL == "" {
+		return nil, errors.New("OIDC issuer URL is required")
+	}
+	client := &http.Client{Timeout: oidcTimeout}
+
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	wellKnown := strings.TrimSuffix(issuerURL, "/") + "/.well-known/openid-configuration"
+	if err := getJSON(ctx, client, wellKnown, &discovery); err != nil {
+		return nil, fmt.Errorf("OIDC discovery: %w", err)
+	}
+	// OpenID Connect Discovery 1.0 §4.3: the document must be for the configured issuer
+	if discovery.Issuer != issuerURL {
+		return nil, fmt.Errorf("OIDC discovery: provider reports issuer %q, expected %q", discovery.Issuer, issuerURL)
+	}
+	if discovery.JWKSURI == "" {
+		return nil, errors.New("OIDC discovery: provider publishes no jwks_uri")
+	}
+
+	a := &OIDCAuthenticator{
+		issuer:    issuerURL,
+		audience:  audience,
+		roleClaim: roleClaim,
+		keys:      newJWKSCache(discovery.JWKSURI, client),
+	}
+	a.keys.mu.Lock()
+	err := a.keys.refresh(ctx)
+	a.keys.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Verify parses token and checks its signature against the provider's keys, its expiry,
+// issuer and audience
+func (a *OIDCAuthenticator) Verify(token string) (*Claims, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(oidcSigningMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer(a.issuer),
+		jwt.WithLeeway(oidcLeeway),
+	}
+	if a.audience != "" {
+		opts = append(opts, jwt.WithAudience(a.audience))
+	}
+
+	claims := &oidcClaims{}
+	keyfunc := func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		ctx, cancel := context.WithTimeout(context.Background(), oidcTimeout)
+		defer cancel()
+		return a.keys.key(ctx, kid)
+	}
+	if _, err := jwt.ParseWithClaims(token, claims, keyfunc, opts...); err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+
+	claims.Role = roleFromClaim(lookupClaim(claims.raw, a.roleClaim))
+	return &claims.Claims, nil
+}
+
+// Authenticate implements Authenticator for the bearer token in the request metadata
+func (a *OIDCAuthenticator) Authenticate(md Metadata) (*Principal, error) {
+	token, err := md.bearerToken()
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := a.Verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bearer token: %w", err)
+	}
+	return &Principal{Subject: claims.Subject, Role: claims.Role, Claims: claims}, nil
+}
+
+// oidcClaims decodes the standard claims and keeps the full payload, since providers put
+// roles in claims of their own (Keycloak's realm_access.roles, Auth0's namespaced claims)
+type oidcClaims struct {
+	Claims
+	raw map[string]any
+}
+
+func (c *oidcClaims) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &c.Claims); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &c.raw)
+}
+
+// lookupClaim follows a dotted path through nested claim objects. A claim whose own name
+// contains dots, like Auth0's "https://example.com/roles", matches as a whole first.
+func lookupClaim(claims map[string]any, path string) any {
+	if v, ok := claims[path]; ok {
+		return v
+	}
+	head, rest, found := strings.Cut(path, ".")
+	if !found {
+		return nil
+	}
+	nested, ok := claims[head].(map[string]any)
+	if !ok {
+		return nil
+	}
+	return lookupClaim(nested, rest)
+}
+
+// roleFromClaim maps a role claim to a single role. Providers usually send a list of
+// roles, in which case admin wins, then user, then whatever comes first.
+func roleFromClaim(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []any:
+		var roles []string
+		for _, r := range v {
+			if s, ok := r.(string); ok {
+				roles = append(roles, s)
+			}
+		}
+		for _, preferred := range []string{RoleAdmin, RoleUser} {
+			for _, r := range roles {
+				if r == preferred {
+					return r
+				}
+			}
+		}
+		if len(roles) > 0 {
+			return roles[0]
+		}
+	}
+	return ""
+}
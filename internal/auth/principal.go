@@ -0,0 +1,34 @@
+package auth
+
+import "context"
+
+// Principal is the authenticated caller of an RPC
+type Principal struct {
+	// Subject identifies the caller, e.g. the user ID of a JWT's sub claim
+	Subject string
+	Role    string
+	// Claims holds the verified token when the caller authenticated with a JWT
+	Claims *Claims
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the caller attached by the authentication interceptors
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// ClaimsFromContext returns the verified JWT claims of the caller, if it sent a token
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	p, ok := PrincipalFromContext(ctx)
+	if !ok || p.Claims == nil {
+		return nil, false
+	}
+	return p.Claims, true
+}
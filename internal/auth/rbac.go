@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Common roles carried by principals
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Policy maps full gRPC method names (e.g. "/user.UserService/DeleteUser"), or path.Match
+// patterns of them (e.g. "/user.UserService/Admin*"), to the roles allowed to call them.
+// A method matched by several entries needs a role allowed by each of them. Methods
+// without a matching entry are open to every authenticated caller.
+type Policy map[string][]string
+
+// Validate reports malformed patterns
+func (p Policy) Validate() error {
+	for pattern := range p {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid method pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Allows reports whether a caller with role may invoke method
+func (p Policy) Allows(method, role string) bool {
+	for pattern, roles := range p {
+		if matchMethod(pattern, method) && !slices.Contains(roles, role) {
+			return false
+		}
+	}
+	return true
+}
+
+// restricts reports whether any entry applies to method
+func (p Policy) restricts(method string) bool {
+	for pattern := range p {
+		if matchMethod(pattern, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchMethod(pattern, method string) bool {
+	if pattern == method {
+		return true
+	}
+	matched, _ := path.Match(pattern, method)
+	return matched
+}
+
+// RequestRule restricts the requests of a method that Matches to Roles, for methods
+// whose sensitivity depends on what is asked rather than on the method alone
+type RequestRule struct {
+	Roles   []string
+	Matches func(req any) bool
+}
+
+// RequestPolicy maps full gRPC method names to the rule checked against each request
+// message. Streaming methods are checked as each message is received.
+type RequestPolicy map[string]RequestRule
+
+// UnaryAuthorizationInterceptor enforces policy and requests against the role of the
+// Principal put in the context by the authentication interceptors, which must run first
+func UnaryAuthorizationInterceptor(policy Policy, requests RequestPolicy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := authorize(ctx, policy, info.FullMethod); err != nil {
+			return nil, err
+		}
+		if rule, ok := requests[info.FullMethod]; ok {
+			if err := authorizeRequest(ctx, rule, info.FullMethod, req); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthorizationInterceptor is the streaming counterpart of UnaryAuthorizationInterceptor
+func StreamAuthorizationInterceptor(policy Policy, requests RequestPolicy) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(ss.Context(), policy, info.FullMethod); err != nil {
+			return err
+		}
+		if rule, ok := requests[info.FullMethod]; ok {
+			ss = &authorizingStream{ServerStream: ss, rule: rule, method: info.FullMethod}
+		}
+		return handler(srv, ss)
+	}
+}
+
+// authorizingStream checks every received message against a RequestRule
+type authorizingStream struct {
+	grpc.ServerStream
+	rule   RequestRule
+	method string
+}
+
+func (s *authorizingStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return authorizeRequest(s.Context(), s.rule, s.method, m)
+}
+
+func authorize(ctx context.Context, policy Policy, method string) error {
+	if !policy.restricts(method) {
+		return nil
+	}
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+	if !policy.Allows(method, principal.Role) {
+		return status.Errorf(codes.PermissionDenied, "role %q may not call %s", principal.Role, method)
+	}
+	return nil
+}
+
+func authorizeRequest(ctx context.Context, rule RequestRule, method string, req any) error {
+	if !rule.Matches(req) {
+		return nil
+	}
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+	if !slices.Contains(rule.Roles, principal.Role) {
+		return status.Errorf(codes.PermissionDenied, "role %q may not make this request to %s", principal.Role, method)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	deleteUser = "/user.UserService/DeleteUser"
+	getUser    = "/user.UserService/GetUser"
+	setLevel   = "/user.AdminService/SetLogLevel"
+)
+
+func TestPolicyAllows(t *testing.T) {
+	policy := Policy{
+		deleteUser:             {RoleAdmin},
+		"/user.AdminService/*": {RoleAdmin, "operator"},
+		"/user.*/Set*":         {RoleAdmin},
+	}
+
+	tests := []struct {
+		method string
+		role   string
+		want   bool
+	}{
+		{deleteUser, RoleAdmin, true},
+		{deleteUser, RoleUser, false},
+		{deleteUser, "", false},
+		{getUser, RoleUser, true},
+		{getUser, "", true},
+		{"/user.AdminService/Drain", "operator", true},
+		{"/user.AdminService/Drain", RoleUser, false},
+		// Every matching entry must allow the role
+		{setLevel, RoleAdmin, true},
+		{setLevel, "operator", false},
+	}
+	for _, tt := range tests {
+		if got := policy.Allows(tt.method, tt.role); got != tt.want {
+			t.Errorf("Allows(%s, %q) = %v, want %v", tt.method, tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	if err := (Policy{deleteUser: {RoleAdmin}, "/user.AdminService/*": {RoleAdmin}}).Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	if err := (Policy{"/user.AdminService/[": {RoleAdmin}}).Validate(); err == nil {
+		t.Error("Validate() of an unclosed bracket = nil, want an error")
+	}
+}
+
+// requestingOne keeps requests of the int 1 to admins
+var requestingOne = RequestRule{
+	Roles:   []string{RoleAdmin},
+	Matches: func(req any) bool { return req == 1 },
+}
+
+func TestUnaryAuthorizationInterceptor(t *testing.T) {
+	interceptor := UnaryAuthorizationInterceptor(Policy{deleteUser: {RoleAdmin}}, RequestPolicy{getUser: requestingOne})
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		method    string
+		req       any
+		want      codes.Code
+	}{
+		{"admin", &Principal{Role: RoleAdmin}, deleteUser, 0, codes.OK},
+		{"user", &Principal{Role: RoleUser}, deleteUser, 0, codes.PermissionDenied},
+		{"anonymous", nil, deleteUser, 0, codes.Unauthenticated},
+		{"anonymous on open method", nil, getUser, 0, codes.OK},
+		{"user on unmatched request", &Principal{Role: RoleUser}, getUser, 2, codes.OK},
+		{"user on matched request", &Principal{Role: RoleUser}, getUser, 1, codes.PermissionDenied},
+		{"admin on matched request", &Principal{Role: RoleAdmin}, getUser, 1, codes.OK},
+		{"anonymous on matched request", nil, getUser, 1, codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = WithPrincipal(ctx, tt.principal)
+			}
+			called := false
+			_, err := interceptor(ctx, tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, req any) (any, error) {
+					called = true
+					return nil, nil
+				})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("code = %v, want %v", got, tt.want)
+			}
+			if called != (tt.want == codes.OK) {
+				t.Errorf("handler called = %v with code %v", called, tt.want)
+			}
+		})
+	}
+}
+
+// recvStream is a server stream receiving the ints of msgs in turn
+type recvStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	msgs []int
+}
+
+func (s *recvStream) Context() context.Context { return s.ctx }
+
+func (s *recvStream) RecvMsg(m any) error {
+	*m.(*int) = s.msgs[0]
+	s.msgs = s.msgs[1:]
+	return nil
+}
+
+func TestStreamAuthorizationInterceptor(t *testing.T) {
+	// Streams are checked against each message RecvMsg fills in
+	receivingOne := RequestRule{
+		Roles:   []string{RoleAdmin},
+		Matches: func(req any) bool { return *req.(*int) == 1 },
+	}
+	interceptor := StreamAuthorizationInterceptor(Policy{deleteUser: {RoleAdmin}}, RequestPolicy{getUser: receivingOne})
+
+	tests := []struct {
+		name   string
+		role   string
+		method string
+		msgs   []int
+		want   codes.Code
+		// wantRecv holds the code of each RecvMsg when the handler runs
+		wantRecv []codes.Code
+	}{
+		{"user on restricted method", RoleUser, deleteUser, []int{1}, codes.PermissionDenied, nil},
+		{"admin on restricted method", RoleAdmin, deleteUser, []int{1}, codes.OK, []codes.Code{codes.OK}},
+		{"user on unmatched messages", RoleUser, getUser, []int{2, 3}, codes.OK, []codes.Code{codes.OK, codes.OK}},
+		{"user on a matched message", RoleUser, getUser, []int{2, 1, 3}, codes.OK,
+			[]codes.Code{codes.OK, codes.PermissionDenied, codes.OK}},
+		{"admin on a matched message", RoleAdmin, getUser, []int{1}, codes.OK, []codes.Code{codes.OK}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &recvStream{ctx: WithPrincipal(context.Background(), &Principal{Role: tt.role}), msgs: tt.msgs}
+			var gotRecv []codes.Code
+			err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: tt.method},
+				func(srv any, ss grpc.ServerStream) error {
+					for range tt.msgs {
+						var m int
+						gotRecv = append(gotRecv, status.Code(ss.RecvMsg(&m)))
+					}
+					return nil
+				})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("code = %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(gotRecv, tt.wantRecv) {
+				t.Errorf("RecvMsg codes = %v, want %v", gotRecv, tt.wantRecv)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Package blob stores binary content such as avatars under string keys, in memory or in
+// a directory, behind the Store interface.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"example.com/user/internal/config"
+)
+
+// Supported values for BLOB_BACKEND
+const (
+	BackendMemory = "memory"
+	BackendFile   = "file"
+)
+
+// ErrNotFound is returned by Get for a key holding no blob
+var ErrNotFound = errors.New("blob not found")
+
+// Info describes a stored blob
+type Info struct {
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+}
+
+// Store keeps blobs under keys such as "avatars/42"
+type Store interface {
+	// Put stores what is read from r under key, replacing any blob there only once r
+	// has been read to its end; when reading r fails, the stored blob is left as it was
+	Put(ctx context.Context, key, contentType string, r io.Reader) (Info, error)
+	// Get opens the blob under key, or returns ErrNotFound; the caller closes it
+	Get(ctx context.Context, key string) (io.ReadCloser, Info, error)
+}
+
+// Open creates the Store of the configured BLOB_BACKEND
+func Open(cfg config.BlobConfig) (Store, error) {
+	switch cfg.Backend {
+	case BackendMemory, "":
+		return NewMemoryStore(), nil
+	case BackendFile:
+		return NewFileStore(cfg.Dir)
+	}
+	return nil, fmt.Errorf("unknown blob backend %q (expected %s or %s)", cfg.Backend, BackendMemory, BackendFile)
+}
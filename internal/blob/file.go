@@ -0,0 +1,124 @@
+package blob
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileStore keeps each blob in a file under a directory, named by its key. The first line
+// of the file holds the content type, so a blob and its type are replaced together.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a FileStore under dir, creating dir if needed
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		return nil, errors.New("BLOB_DIR is required by the file blob backend")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// path returns the file of key, refusing keys that would leave the directory
+func (s *FileStore) path(key string) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+func (s *FileStore) Put(ctx context.Context, key, contentType string, r io.Reader) (Info, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return Info{}, err
+	}
+	if strings.ContainsAny(contentType, "\r\n") {
+		return Info{}, fmt.Errorf("invalid content type %q", contentType)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return Info{}, err
+	}
+
+	// Write a temporary file beside the blob, renamed over it once complete
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return Info{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := writeBlob(tmp, contentType, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return Info{}, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return Info{}, err
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{ContentType: contentType, Size: size, ModTime: stat.ModTime()}, nil
+}
+
+// writeBlob writes the content type line and then r to w, returning the size of r
+func writeBlob(w io.Writer, contentType string, r io.Reader) (int64, error) {
+	if _, err := io.WriteString(w, contentType+"\n"); err != nil {
+		return 0, err
+	}
+	return io.Copy(w, r)
+}
+
+func (s *FileStore) Get(ctx context.Context, key string) (io.ReadCloser, Info, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, Info{}, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, Info{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, Info{}, err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, Info{}, err
+	}
+	r := bufio.NewReader(f)
+	line, err := r.ReadString('\n')
+	if err != nil {
+		f.Close()
+		return nil, Info{}, fmt.Errorf("read blob %s: %w", key, err)
+	}
+	info := Info{
+		ContentType: strings.TrimSuffix(line, "\n"),
+		Size:        stat.Size() - int64(len(line)),
+		ModTime:     stat.ModTime(),
+	}
+	return fileBlob{Reader: r, Closer: f}, info, nil
+}
+
+// fileBlob reads the content of a blob file past its content type line
+type fileBlob struct {
+	io.Reader
+	io.Closer
+}
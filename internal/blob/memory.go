@@ -0,0 +1,52 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps blobs in memory; they are lost when the process exits
+type MemoryStore struct {
+	mu    sync.RWMutex
+	blobs map[string]memoryBlob
+}
+
+type memoryBlob struct {
+	data []byte
+	info Info
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{blobs: make(map[string]memoryBlob)}
+}
+
+func (s *MemoryStore) Put(ctx context.Context, key, contentType string, r io.Reader) (Info, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Info{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return Info{}, err
+	}
+
+	info := Info{ContentType: contentType, Size: int64(len(data)), ModTime: time.Now()}
+	s.mu.Lock()
+	s.blobs[key] = memoryBlob{data: data, info: info}
+	s.mu.Unlock()
+	return info, nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (io.ReadCloser, Info, error) {
+	s.mu.RLock()
+	blob, ok := s.blobs[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, Info{}, ErrNotFound
+	}
+	// Blobs are replaced rather than modified, so readers can share the data
+	return io.NopCloser(bytes.NewReader(blob.data)), blob.info, nil
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU[string, int](2, 0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Set("c", 3)
+
+	tests := []struct {
+		key    string
+		want   int
+		wantOK bool
+	}{
+		{"a", 1, true},
+		{"b", 0, false},
+		{"c", 3, true},
+	}
+	for _, tt := range tests {
+		if got, ok := c.Get(tt.key); got != tt.want || ok != tt.wantOK {
+			t.Errorf("Get(%q) = %d, %v; want %d, %v", tt.key, got, ok, tt.want, tt.wantOK)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+
+	// Replacing a value doesn't evict anything
+	c.Set("c", 4)
+	if got, _ := c.Get("c"); got != 4 || c.Len() != 2 {
+		t.Errorf("after replacing c: Get(c) = %d, Len() = %d; want 4, 2", got, c.Len())
+	}
+}
+
+func TestLRUExpires(t *testing.T) {
+	c := NewLRU[string, int](4, 20*time.Millisecond)
+	c.Set("a", 1)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("Get right after Set missed")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get after the TTL hit")
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len() after an expired Get = %d, want 0", c.Len())
+	}
+}
+
+func TestLRUDeleteAndPurge(t *testing.T) {
+	c := NewLRU[string, int](0, 0)
+	c.Set("a", 1)
+	c.Delete("a")
+	c.Delete("missing")
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get after Delete hit")
+	}
+
+	// A size below 1 still holds one entry
+	c.Set("b", 2)
+	c.Purge()
+	if c.Len() != 0 {
+		t.Errorf("Len() after Purge = %d, want 0", c.Len())
+	}
+}
@@ -0,0 +1,199 @@
+// Package chat delivers the messages of Chat streams between the members connected to
+// a server: members register under a user name and join rooms, and what one sends to a
+// name, a room or everyone reaches the members there.
+package chat
+
+import (
+	"sync"
+
+	pb "example.com/user/proto"
+)
+
+// OutboxSize is how many messages a member may fall behind by before it is cut off
+const OutboxSize = 256
+
+// Member is one connected Chat stream. Messages delivered to it are queued in its
+// outbox until the stream sends them.
+type Member struct {
+	outbox     chan *pb.ChatMessage
+	overflowed chan struct{}
+	overflow   sync.Once
+	// Guarded by the mutex of the hub
+	name   string
+	closed bool
+}
+
+// NewMember creates a member without a name, in no room
+func NewMember() *Member {
+	return &Member{
+		outbox:     make(chan *pb.ChatMessage, OutboxSize),
+		overflowed: make(chan struct{}),
+	}
+}
+
+// Outbox yields the messages delivered to the member, in order
+func (m *Member) Outbox() <-chan *pb.ChatMessage {
+	return m.outbox
+}
+
+// Overflowed is closed once a message was dropped because the outbox was full; the
+// stream should then end rather than carry on with a gap
+func (m *Member) Overflowed() <-chan struct{} {
+	return m.overflowed
+}
+
+// Deliver queues msg without waiting, so one slow member can't hold the others back
+func (m *Member) Deliver(msg *pb.ChatMessage) {
+	select {
+	case m.outbox <- msg:
+	default:
+		m.overflow.Do(func() { close(m.overflowed) })
+	}
+}
+
+// Hub keeps the members of a server by name and the rooms they are in. Messages given
+// to it must not be changed afterwards, as every recipient shares them.
+type Hub struct {
+	mu    sync.RWMutex
+	users map[string]map[*Member]bool
+	rooms map[string]map[*Member]bool
+}
+
+// NewHub creates a hub without members
+func NewHub() *Hub {
+	return &Hub{
+		users: make(map[string]map[*Member]bool),
+		rooms: make(map[string]map[*Member]bool),
+	}
+}
+
+// Register adds m under name, which other members can then send to; several members
+// may share a name, as one user may chat from several clients, so name must be one the
+// stream has a right to. A member registers once, and not at all once closed.
+func (h *Hub) Register(name string, m *Member) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if m.closed || m.name != "" {
+		return
+	}
+	m.name = name
+	add(h.users, name, m)
+}
+
+// Join adds m to room, creating the room if needed; false when m is already in it, or
+// isn't registered
+func (h *Hub) Join(room string, m *Member) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if m.name == "" || h.rooms[room][m] {
+		return false
+	}
+	add(h.rooms, room, m)
+	return true
+}
+
+// Leave removes m from room, dropping the room once empty; false when m wasn't in it
+func (h *Hub) Leave(room string, m *Member) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return remove(h.rooms, room, m)
+}
+
+// Close unregisters m, removing it from every room, and returns the name it had and the
+// rooms it left. The stream of m must close it once it ends.
+func (h *Hub) Close(m *Member) (string, []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	m.closed = true
+	remove(h.users, m.name, m)
+	var left []string
+	for room := range h.rooms {
+		if remove(h.rooms, room, m) {
+			left = append(left, room)
+		}
+	}
+	return m.name, left
+}
+
+// InRoom reports whether m is in room
+func (h *Hub) InRoom(room string, m *Member) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.rooms[room][m]
+}
+
+// Present reports whether a member registered as name is in room
+func (h *Hub) Present(room, name string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for m := range h.rooms[room] {
+		if m.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Send delivers msg to every member registered as name; false when there is none
+func (h *Hub) Send(name string, msg *pb.ChatMessage) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for m := range h.users[name] {
+		m.Deliver(msg)
+	}
+	return len(h.users[name]) > 0
+}
+
+// Broadcast delivers msg to every member of room but except, which may be nil
+func (h *Hub) Broadcast(room string, msg *pb.ChatMessage, except *Member) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	deliver(h.rooms[room], msg, except)
+}
+
+// BroadcastAll delivers msg to every registered member but except, which may be nil
+func (h *Hub) BroadcastAll(msg *pb.ChatMessage, except *Member) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, members := range h.users {
+		deliver(members, msg, except)
+	}
+}
+
+func deliver(members map[*Member]bool, msg *pb.ChatMessage, except *Member) {
+	for m := range members {
+		if m != except {
+			m.Deliver(msg)
+		}
+	}
+}
+
+// add puts m in the set of key, creating it if needed
+func add(sets map[string]map[*Member]bool, key string, m *Member) {
+	members := sets[key]
+	if members == nil {
+		members = make(map[*Member]bool)
+		sets[key] = members
+	}
+	members[m] = true
+}
+
+// remove takes m out of the set of key, dropping the set once empty; false when m
+// wasn't in it
+func remove(sets map[string]map[*Member]bool, key string, m *Member) bool {
+	members := sets[key]
+	if !members[m] {
+		return false
+	}
+	delete(members, m)
+	if len(members) == 0 {
+		delete(sets, key)
+	}
+	return true
+}
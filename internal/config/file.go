@@ -0,0 +1,96 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// configFileEnv names the variable holding the path of the config file
+const configFileEnv = "CONFIG_FILE"
+
+// fileEnv tracks the variables set from the config file, so applying it again can undo
+// those it no longer sets
+var fileEnv struct {
+	sync.Mutex
+	// original holds what each variable was before the file set it; nil when unset
+	original map[string]*string
+}
+
+// ApplyFile sets the variables of the file named by CONFIG_FILE, when set, in the
+// environment for Load to read, overriding those set otherwise. The file holds KEY=VALUE
+// lines in the format of .env.example. Variables an earlier call set that the file no
+// longer has go back to what they were, so the file can be applied again after edits.
+func ApplyFile() error {
+	path := os.Getenv(configFileEnv)
+	if path == "" {
+		return nil
+	}
+	vars, err := readEnvFile(path)
+	if err != nil {
+		return err
+	}
+
+	fileEnv.Lock()
+	defer fileEnv.Unlock()
+	if fileEnv.original == nil {
+		fileEnv.original = make(map[string]*string)
+	}
+	for key, original := range fileEnv.original {
+		if _, ok := vars[key]; ok {
+			continue
+		}
+		if original == nil {
+			os.Unsetenv(key)
+		} else {
+			os.Setenv(key, *original)
+		}
+		delete(fileEnv.original, key)
+	}
+	for key, value := range vars {
+		if _, ok := fileEnv.original[key]; !ok {
+			var original *string
+			if v, ok := os.LookupEnv(key); ok {
+				original = &v
+			}
+			fileEnv.original[key] = original
+		}
+		os.Setenv(key, value)
+	}
+	return nil
+}
+
+// readEnvFile parses KEY=VALUE lines, skipping blank lines and # comments; values may be
+// quoted
+func readEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+	defer f.Close()
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("config file %s line %d: expected KEY=VALUE", path, n)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+	return vars, nil
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileFileName is the client config file looked for in the home directory
+const ProfileFileName = ".userctl.yaml"
+
+// profileFile is the layout of the client config file
+type profileFile struct {
+	// CurrentProfile is used when no profile is asked for
+	CurrentProfile string                   `yaml:"current_profile"`
+	Profiles       map[string]ClientProfile `yaml:"profiles"`
+}
+
+// ClientProfile holds the settings of one server in the client config file. Settings
+// left out keep the values of the environment.
+type ClientProfile struct {
+	Address    string `yaml:"address"`
+	Insecure   *bool  `yaml:"insecure"`
+	CAFile     string `yaml:"ca_file"`
+	CertFile   string `yaml:"cert_file"`
+	KeyFile    string `yaml:"key_file"`
+	ServerName string `yaml:"server_name"`
+	AuthToken  string `yaml:"auth_token"` // may be a Vault reference, as AUTH_TOKEN
+	APIKey     string `yaml:"api_key"`
+	SigningKey string `yaml:"signing_key"`
+}
+
+// DefaultProfilePath returns the client config file in the home directory, or "" when
+// there is no home directory
+func DefaultProfilePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ProfileFileName)
+}
+
+// LoadProfile reads the profile called name from the client config file at path, or its
+// current_profile when name is empty. A missing file gives an empty profile unless a
+// profile was asked for by name.
+func LoadProfile(path, name string) (ClientProfile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && name == "" {
+		return ClientProfile{}, nil
+	}
+	if err != nil {
+		return ClientProfile{}, fmt.Errorf("read client config file: %w", err)
+	}
+
+	var file profileFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return ClientProfile{}, fmt.Errorf("parse client config file %s: %w", path, err)
+	}
+	if name == "" {
+		name = file.CurrentProfile
+		if name == "" {
+			return ClientProfile{}, nil
+		}
+	}
+	profile, ok := file.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(file.Profiles))
+		for n := range file.Profiles {
+			names = append(names, n)
+		}
+		slices.Sort(names)
+		return ClientProfile{}, fmt.Errorf("client config file %s has no profile %q (profiles: %s)", path, name, strings.Join(names, ", "))
+	}
+
+	// Relative file paths are taken from the directory of the config file, and ~ is the
+	// home directory
+	for _, p := range []*string{&profile.CAFile, &profile.CertFile, &profile.KeyFile} {
+		*p = resolveProfilePath(filepath.Dir(path), *p)
+	}
+	return profile, nil
+}
+
+// Apply sets the settings of p over c. TLS settings turn GRPC_INSECURE off unless the
+// profile sets insecure itself, so a profile for a TLS server works wherever it is used.
+func (p ClientProfile) Apply(c *ClientConfig) {
+	set := func(dst *string, value string) {
+		if value != "" {
+			*dst = value
+		}
+	}
+	set(&c.ServerAddress, p.Address)
+	set(&c.TLSCAFile, p.CAFile)
+	set(&c.TLSCertFile, p.CertFile)
+	set(&c.TLSKeyFile, p.KeyFile)
+	set(&c.TLSServerName, p.ServerName)
+	set(&c.AuthToken, p.AuthToken)
+	set(&c.APIKey, p.APIKey)
+	set(&c.SigningKey, p.SigningKey)
+
+	switch {
+	case p.Insecure != nil:
+		c.Insecure = *p.Insecure
+	case p.CAFile != "" || p.CertFile != "" || p.KeyFile != "" || p.ServerName != "":
+		c.Insecure = false
+	}
+}
+
+func resolveProfilePath(dir, path string) string {
+	switch {
+	case path == "":
+		return ""
+	case path == "~" || strings.HasPrefix(path, "~/"):
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+		return path
+	case filepath.IsAbs(path):
+		return path
+	default:
+		return filepath.Join(dir, path)
+	}
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// vaultRefPrefix marks a setting whose value lives in Vault, written as
+// "vault:<mount>/<path>#<field>" for a KV version 2 secret
+const vaultRefPrefix = "vault:"
+
+// vaultTimeout bounds each request to Vault
+const vaultTimeout = 10 * time.Second
+
+// VaultConfig holds how to reach HashiCorp Vault for secret references
+type VaultConfig struct {
+	Addr      string // e.g. https://vault.example.com:8200
+	Token     string
+	TokenFile string // read instead of Token when set, e.g. a file written by Vault Agent
+	Namespace string // Vault Enterprise namespace
+}
+
+// ResolveSecrets replaces secret settings holding a Vault reference with the secret
+// itself: JWT_SECRET, API_KEYS, SIGNING_KEYS, POSTGRES_DSN, REDIS_PASSWORD, TLS_KEY,
+// LOG_REDACT_KEY, SENTRY_DSN, AUTH_TOKEN, API_KEY and SIGNING_KEY. Settings holding plain values are left alone, and Vault is
+// only contacted when at least one reference is present.
+func (c *Config) ResolveSecrets(ctx context.Context) error {
+	type setting struct {
+		name  string
+		value *string
+	}
+	secrets := []setting{
+		{"JWT_SECRET", &c.Auth.JWTSecret},
+		{"POSTGRES_DSN", &c.Storage.PostgresDSN},
+		{"REDIS_PASSWORD", &c.Storage.RedisPassword},
+		{"TLS_KEY", &c.Server.TLSKey},
+		{"LOG_REDACT_KEY", &c.Log.RedactKey},
+		{"SENTRY_DSN", &c.Errors.SentryDSN},
+		{"AUTH_TOKEN", &c.Client.AuthToken},
+		{"API_KEY", &c.Client.APIKey},
+		{"SIGNING_KEY", &c.Client.SigningKey},
+	}
+	for i := range c.Auth.APIKeys {
+		secrets = append(secrets, setting{fmt.Sprintf("API_KEYS[%d]", i), &c.Auth.APIKeys[i]})
+	}
+	for i := range c.Auth.SigningKeys {
+		secrets = append(secrets, setting{fmt.Sprintf("SIGNING_KEYS[%d]", i), &c.Auth.SigningKeys[i]})
+	}
+
+	var vault *vaultClient
+	var problems []error
+	for _, s := range secrets {
+		name, value := s.name, s.value
+		ref, ok := strings.CutPrefix(*value, vaultRefPrefix)
+		if !ok {
+			continue
+		}
+		if vault == nil {
+			var err error
+			if vault, err = newVaultClient(c.Vault); err != nil {
+				return fmt.Errorf("resolve %s: %w", name, err)
+			}
+		}
+
+		secret, err := vault.read(ctx, ref)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("resolve %s: %w", name, err))
+			continue
+		}
+		*value = secret
+	}
+	return errors.Join(problems...)
+}
+
+// vaultClient reads KV version 2 secrets, fetching each secret path once
+type vaultClient struct {
+	cfg    VaultConfig
+	client *http.Client
+	cache  map[string]map[string]any
+}
+
+func newVaultClient(cfg VaultConfig) (*vaultClient, error) {
+	if cfg.Addr == "" {
+		return nil, errors.New("VAULT_ADDR is not set")
+	}
+	if cfg.TokenFile != "" {
+		token, err := os.ReadFile(cfg.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("read VAULT_TOKEN_FILE: %w", err)
+		}
+		cfg.Token = strings.TrimSpace(string(token))
+	}
+	if cfg.Token == "" {
+		return nil, errors.New("VAULT_TOKEN or VAULT_TOKEN_FILE is not set")
+	}
+
+	return &vaultClient{
+		cfg:    cfg,
+		client: &http.Client{Timeout: vaultTimeout},
+		cache:  make(map[string]map[string]any),
+	}, nil
+}
+
+// read resolves a "<mount>/<path>#<field>" reference
+func (v *vaultClient) read(ctx context.Context, ref string) (string, error) {
+	path, field, found := strings.Cut(ref, "#")
+	mount, secretPath, hasPath := strings.Cut(path, "/")
+	if !found || field == "" || !hasPath || mount == "" || secretPath == "" {
+		return "", fmt.Errorf("invalid Vault reference %q (expected vault:<mount>/<path>#<field>)", vaultRefPrefix+ref)
+	}
+
+	data, ok := v.cache[path]
+	if !ok {
+		var err error
+		if data, err = v.fetch(ctx, mount, secretPath); err != nil {
+			return "", err
+		}
+		v.cache[path] = data
+	}
+
+	value, ok := data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("Vault secret %s has no string field %q", path, field)
+	}
+	return value, nil
+}
+
+// fetch reads the latest version of a KV version 2 secret
+func (v *vaultClient) fetch(ctx context.Context, mount, secretPath string) (map[string]any, error) {
+	url := strings.TrimSuffix(v.cfg.Addr, "/") + "/v1/" + mount + "/data/" + secretPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.cfg.Token)
+	if v.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.cfg.Namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("read Vault secret %s/%s: %w", mount, secretPath, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("Vault secret %s/%s not found", mount, secretPath)
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("Vault token may not read %s/%s", mount, secretPath)
+	default:
+		return nil, fmt.Errorf("read Vault secret %s/%s: %s", mount, secretPath, resp.Status)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("parse Vault secret %s/%s: %w", mount, secretPath, err)
+	}
+	if body.Data.Data == nil {
+		// KV v2 reports a deleted latest version with null data
+		return nil, fmt.Errorf("Vault secret %s/%s has no data", mount, secretPath)
+	}
+	return body.Data.Data, nil
+}
@@ -0,0 +1,137 @@
+// Package deadline bounds how long calls may run on the server when callers don't.
+package deadline
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Policy sets the deadlines of calls
+type Policy struct {
+	// Unary is the timeout of unary calls sent without a deadline; 0 leaves them unbounded
+	Unary time.Duration
+	// Stream is the timeout of streaming calls sent without a deadline; 0 leaves them unbounded
+	Stream time.Duration
+	// Min is the least time a caller's deadline must leave; calls arriving with less are
+	// rejected rather than started with no chance to finish. 0 accepts any deadline.
+	Min time.Duration
+	// Methods caps how long calls of a method may run, whatever deadline they were sent
+	// with, in place of Unary or Stream. A timeout of 0 leaves the method unbounded.
+	Methods map[string]time.Duration
+}
+
+// ParseMethodTimeouts parses "Method=timeout" entries. Method is a full method name such
+// as "/user.UserService/StreamUsers" or just "StreamUsers" to match it in any service.
+func ParseMethodTimeouts(entries []string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(entries))
+	for _, entry := range entries {
+		method, spec, found := strings.Cut(entry, "=")
+		method = strings.TrimSpace(method)
+		if !found || method == "" {
+			return nil, fmt.Errorf("method timeout %q: expected Method=timeout", entry)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(spec))
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("method timeout %q: invalid timeout %q", entry, spec)
+		}
+		timeouts[method] = timeout
+	}
+	return timeouts, nil
+}
+
+// Enforcer applies the Policy in force to calls as they start
+type Enforcer struct {
+	policy atomic.Pointer[Policy]
+}
+
+// NewEnforcer creates an Enforcer applying p
+func NewEnforcer(p Policy) *Enforcer {
+	e := &Enforcer{}
+	e.Set(p)
+	return e
+}
+
+// Set replaces the policy for calls started from now on; running calls keep their deadline
+func (e *Enforcer) Set(p Policy) {
+	e.policy.Store(&p)
+}
+
+// UnaryServerInterceptor applies the policy to every unary call
+func (e *Enforcer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		p := e.policy.Load()
+		ctx, cancel, err := p.apply(ctx, info.FullMethod, p.Unary)
+		if err != nil {
+			return nil, err
+		}
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor applies the policy to every streaming call. The deadline ends a
+// stream through its context, so handlers must return once Context() is done.
+func (e *Enforcer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		p := e.policy.Load()
+		ctx, cancel, err := p.apply(ss.Context(), info.FullMethod, p.Stream)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+		return handler(srv, &deadlineStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// apply checks the caller's deadline against p.Min, then bounds the call by the timeout of
+// fullMethod, or by timeout when the method has none and the caller set no deadline
+func (p Policy) apply(ctx context.Context, fullMethod string, timeout time.Duration) (context.Context, context.CancelFunc, error) {
+	d, hasDeadline := ctx.Deadline()
+	if hasDeadline {
+		if left := time.Until(d); p.Min > 0 && left < p.Min {
+			return nil, nil, status.Errorf(codes.DeadlineExceeded,
+				"Deadline leaves %s, less than the %s needed", left.Round(time.Millisecond), p.Min)
+		}
+	}
+
+	methodTimeout, capped := p.methodTimeout(fullMethod)
+	if capped {
+		timeout = methodTimeout
+	}
+	if timeout <= 0 || (hasDeadline && !capped) {
+		return ctx, func() {}, nil
+	}
+	// An earlier deadline from the caller still applies
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, nil
+}
+
+// methodTimeout returns the timeout set for fullMethod, by full or bare method name
+func (p Policy) methodTimeout(fullMethod string) (time.Duration, bool) {
+	if timeout, ok := p.Methods[fullMethod]; ok {
+		return timeout, true
+	}
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		if timeout, ok := p.Methods[fullMethod[i+1:]]; ok {
+			return timeout, true
+		}
+	}
+	return 0, false
+}
+
+// deadlineStream carries the call's context with the applied deadline
+type deadlineStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *deadlineStream) Context() context.Context {
+	return s.ctx
+}
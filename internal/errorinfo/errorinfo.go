@@ -0,0 +1,94 @@
+// Package errorinfo gives the errors the server returns a google.rpc.ErrorInfo detail,
+// so clients can branch on a stable reason, and learn whether trying again may help,
+// instead of matching messages. The reasons are those of package usererrors.
+package errorinfo
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"example.com/user/pkg/usererrors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// retryableCodes are the codes of calls that may succeed if tried again as they are
+var retryableCodes = []codes.Code{codes.Unavailable, codes.ResourceExhausted, codes.Aborted}
+
+// New returns the ErrorInfo detail of reason, for statuses carrying more details
+func New(reason string) *errdetails.ErrorInfo {
+	return &errdetails.ErrorInfo{Reason: reason, Domain: usererrors.Domain}
+}
+
+// Errorf returns an error with code and a formatted message, whose ErrorInfo has reason
+func Errorf(c codes.Code, reason, format string, args ...any) error {
+	st := status.Newf(c, format, args...)
+	if detailed, err := st.WithDetails(New(reason)); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// Attach returns err with an ErrorInfo detail telling whether the call is retryable. An
+// error without one is given its code as reason, such as "NOT_FOUND"; nil is returned
+// as it is.
+func Attach(err error) error {
+	if err == nil {
+		return nil
+	}
+	st := status.Convert(err)
+	if st.Code() == codes.OK {
+		return err
+	}
+
+	p := st.Proto()
+	var info *errdetails.ErrorInfo
+	slot, retryInfo := len(p.Details), false
+	for i, detail := range p.Details {
+		switch {
+		case detail.MessageIs(&errdetails.ErrorInfo{}):
+			info = &errdetails.ErrorInfo{}
+			if detail.UnmarshalTo(info) != nil {
+				return err
+			}
+			slot = i
+		case detail.MessageIs(&errdetails.RetryInfo{}):
+			retryInfo = true
+		}
+	}
+	if info == nil {
+		info = New(reason(st.Code()))
+		p.Details = append(p.Details, nil)
+	}
+	if _, set := info.Metadata[usererrors.MetadataRetryable]; set {
+		return err
+	}
+	if info.Metadata == nil {
+		info.Metadata = make(map[string]string, 1)
+	}
+	retryable := retryInfo || slices.Contains(retryableCodes, st.Code())
+	info.Metadata[usererrors.MetadataRetryable] = strconv.FormatBool(retryable)
+
+	detail, marshalErr := anypb.New(info)
+	if marshalErr != nil {
+		return err
+	}
+	p.Details[slot] = detail
+	return status.FromProto(p).Err()
+}
+
+// reason spells c in upper snake case, as google.rpc.Code does
+func reason(c codes.Code) string {
+	var b strings.Builder
+	for i, r := range c.String() {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
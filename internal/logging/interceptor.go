@@ -0,0 +1,174 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// RequestIDHeader is the metadata key carrying a call's request ID. Callers may send
+// their own; otherwise the server generates one and returns it in the response header.
+const RequestIDHeader = "x-request-id"
+
+// maxRequestIDLength bounds caller-supplied request IDs so they can't bloat every log line
+const maxRequestIDLength = 128
+
+// UnaryServerInterceptor tags the context of every unary call with its method, request
+// ID and target user, and logs the call once it finishes, to access too unless it is nil
+func UnaryServerInterceptor(access *AccessLog) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		id := requestID(ctx)
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id))
+		ctx = callContext(ctx, info.FullMethod, id, req)
+
+		resp, err := handler(ctx, req)
+		requestBytes, responseBytes := messageSize(req), messageSize(resp)
+		logCall(ctx, start, err,
+			"request_bytes", requestBytes,
+			"response_bytes", responseBytes,
+		)
+		if access != nil {
+			entry := &accessEntry{Method: info.FullMethod, RequestID: id, MessagesReceived: 1, BytesReceived: int64(requestBytes)}
+			if err == nil {
+				entry.MessagesSent, entry.BytesSent = 1, int64(responseBytes)
+			}
+			access.record(ctx, start, err, entry)
+		}
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor tags the context of every streaming call with its method and
+// request ID, and logs the call with the messages it carried once it finishes, to access
+// too unless it is nil
+func StreamServerInterceptor(access *AccessLog) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		id := requestID(ss.Context())
+		ss.SetHeader(metadata.Pairs(RequestIDHeader, id))
+		stream := &loggingStream{ServerStream: ss, ctx: callContext(ss.Context(), info.FullMethod, id, nil)}
+
+		err := handler(srv, stream)
+		logCall(stream.ctx, start, err,
+			"messages_received", stream.received.Load(), "received_bytes", stream.receivedBytes.Load(),
+			"messages_sent", stream.sent.Load(), "sent_bytes", stream.sentBytes.Load(),
+		)
+		if access != nil {
+			access.record(stream.ctx, start, err, &accessEntry{
+				Method:           info.FullMethod,
+				RequestID:        id,
+				MessagesReceived: stream.received.Load(),
+				MessagesSent:     stream.sent.Load(),
+				BytesReceived:    stream.receivedBytes.Load(),
+				BytesSent:        stream.sentBytes.Load(),
+			})
+		}
+		return err
+	}
+}
+
+// callContext adds the fields identifying a call to ctx
+func callContext(ctx context.Context, method, id string, req any) context.Context {
+	args := []any{"method", method, "request_id", id}
+	// Requests addressing a single user identify it by ID
+	if r, ok := req.(interface{ GetId() int32 }); ok {
+		args = append(args, "user_id", r.GetId())
+	}
+	return With(ctx, args...)
+}
+
+// logCall logs a finished call at a level matching its status
+func logCall(ctx context.Context, start time.Time, err error, args ...any) {
+	code := status.Code(err)
+	args = append(args, "code", code.String(), "duration", time.Since(start))
+	if addr := peerAddr(ctx); addr != "" {
+		args = append(args, "peer", addr)
+	}
+	if err != nil {
+		args = append(args, "error", status.Convert(err).Message())
+	}
+	slog.Log(ctx, codeLevel(code), "RPC finished", args...)
+}
+
+// peerAddr returns the address of the caller, or "" when unknown
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// codeLevel maps a status code to a log level: caller mistakes are routine, server
+// failures are errors and everything in between deserves a look
+func codeLevel(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.Unauthenticated:
+		return slog.LevelInfo
+	case codes.Unknown, codes.Unimplemented, codes.Internal, codes.DataLoss:
+		return slog.LevelError
+	default:
+		return slog.LevelWarn
+	}
+}
+
+// messageSize returns the encoded size of m, or 0 when it is not a protobuf message
+func messageSize(m any) int {
+	if msg, ok := m.(proto.Message); ok {
+		return proto.Size(msg)
+	}
+	return 0
+}
+
+// loggingStream carries the call's tagged context and counts the messages that pass
+// through; handlers may send from several goroutines, so the counts are atomic
+type loggingStream struct {
+	grpc.ServerStream
+	ctx                      context.Context
+	received, sent           atomic.Int64
+	receivedBytes, sentBytes atomic.Int64
+}
+
+func (s *loggingStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *loggingStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received.Add(1)
+		s.receivedBytes.Add(int64(messageSize(m)))
+	}
+	return err
+}
+
+func (s *loggingStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent.Add(1)
+		s.sentBytes.Add(int64(messageSize(m)))
+	}
+	return err
+}
+
+// requestID returns the ID the caller sent, or a new random one
+func requestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if ids := md.Get(RequestIDHeader); len(ids) > 0 && ids[0] != "" && len(ids[0]) <= maxRequestIDLength {
+		return ids[0]
+	}
+
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
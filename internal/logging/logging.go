@@ -0,0 +1,113 @@
+// Package logging configures structured logging with log/slog and keeps personal data
+// out of the logs.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Supported output formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// level is the minimum level of the default logger
+var level = new(slog.LevelVar)
+
+// Setup makes a logger writing to stderr at lvl (debug, info, warn or error) in format
+// the slog default, which also routes the standard log package through it
+func Setup(lvl, format string) error {
+	logger, err := New(os.Stderr, lvl, format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
+}
+
+// New creates a logger writing to w that adds the fields stored in a call's context
+func New(w io.Writer, lvl, format string) (*slog.Logger, error) {
+	if err := SetLevel(lvl); err != nil {
+		return nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case FormatText:
+		h = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q (expected %s or %s)", format, FormatText, FormatJSON)
+	}
+	return slog.New(contextHandler{h}), nil
+}
+
+// SetLevel changes the minimum level of every logger made by New, taking effect at once
+func SetLevel(lvl string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(lvl)); err != nil {
+		return fmt.Errorf("invalid log level %q (expected debug, info, warn or error)", lvl)
+	}
+	level.Set(l)
+	return nil
+}
+
+// Level returns the current minimum level, e.g. "debug"
+func Level() string {
+	return strings.ToLower(level.Level().String())
+}
+
+// Fatal logs msg at error level and exits, like log.Fatal
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+type fieldsKey struct{}
+
+// With returns a copy of ctx whose log records carry args as extra fields, in the
+// key-value form of slog.Logger.With
+func With(ctx context.Context, args ...any) context.Context {
+	fields, _ := ctx.Value(fieldsKey{}).([]any)
+	return context.WithValue(ctx, fieldsKey{}, append(fields[:len(fields):len(fields)], args...))
+}
+
+// Fields returns the fields stored in ctx by With, in key-value form
+func Fields(ctx context.Context) []any {
+	fields, _ := ctx.Value(fieldsKey{}).([]any)
+	return fields[:len(fields):len(fields)]
+}
+
+// contextHandler adds the fields stored by With, and the ID of the active trace, to
+// records logged with a context
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if fields, ok := ctx.Value(fieldsKey{}).([]any); ok {
+		r.Add(fields...)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Supported redaction modes
+const (
+	// ModeMask replaces values with a fixed placeholder
+	ModeMask = "mask"
+	// ModeHash replaces values with a keyed hash, so log lines about the same value can
+	// still be correlated without revealing it
+	ModeHash = "hash"
+)
+
+// masked replaces values in ModeMask
+const masked = "[REDACTED]"
+
+// Redactor rewrites the values of configured PII fields before they are logged
+type Redactor struct {
+	fields map[string]bool
+	mode   string
+	key    []byte
+}
+
+// NewRedactor creates a Redactor for fields, matched case-insensitively against the
+// names given to Value and the proto field names walked by Proto. In ModeHash, values
+// are hashed with key; an empty key uses a random one, so hashes are only stable for
+// the lifetime of the process.
+func NewRedactor(fields []string, mode, key string) (*Redactor, error) {
+	r := &Redactor{fields: make(map[string]bool, len(fields)), mode: mode}
+	for _, f := range fields {
+		r.fields[strings.ToLower(strings.TrimSpace(f))] = true
+	}
+
+	switch mode {
+	case ModeMask:
+	case ModeHash:
+		r.key = []byte(key)
+		if key == "" {
+			r.key = make([]byte, 32)
+			if _, err := rand.Read(r.key); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown redaction mode %q (expected %s or %s)", mode, ModeMask, ModeHash)
+	}
+	return r, nil
+}
+
+// Value returns v redacted if field is a PII field, or unchanged otherwise
+func (r *Redactor) Value(field, v string) string {
+	if r == nil || v == "" || !r.fields[strings.ToLower(field)] {
+		return v
+	}
+	if r.mode == ModeMask {
+		return masked
+	}
+
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(v))
+	return "[sha256:" + hex.EncodeToString(mac.Sum(nil))[:12] + "]"
+}
+
+// Proto returns a copy of m whose PII string fields, in nested messages too, are redacted
+func (r *Redactor) Proto(m proto.Message) proto.Message {
+	if r == nil || m == nil || len(r.fields) == 0 {
+		return m
+	}
+	clone := proto.Clone(m)
+	r.redactMessage(clone.ProtoReflect())
+	return clone
+}
+
+func (r *Redactor) redactMessage(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			// Map keys and values have no field name of their own to match against
+		case fd.Kind() == protoreflect.StringKind && r.fields[strings.ToLower(string(fd.Name()))]:
+			if fd.IsList() {
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					list.Set(i, protoreflect.ValueOfString(r.Value(string(fd.Name()), list.Get(i).String())))
+				}
+			} else {
+				m.Set(fd, protoreflect.ValueOfString(r.Value(string(fd.Name()), v.String())))
+			}
+		case fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind:
+			if fd.IsList() {
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					r.redactMessage(list.Get(i).Message())
+				}
+			} else {
+				r.redactMessage(v.Message())
+			}
+		}
+		return true
+	})
+}
+
+// current is the Redactor used by the package-level helpers; nil logs values as they are
+var current atomic.Pointer[Redactor]
+
+// SetRedactor installs r for Redact and RedactProto
+func SetRedactor(r *Redactor) {
+	current.Store(r)
+}
+
+// Redact redacts v with the installed Redactor if field is a PII field
+func Redact(field, v string) string {
+	return current.Load().Value(field, v)
+}
+
+// RedactProto returns m with its PII fields redacted by the installed Redactor
+func RedactProto(m proto.Message) proto.Message {
+	return current.Load().Proto(m)
+}
@@ -0,0 +1,158 @@
+// Package maintenance turns away new calls while operators drain a server, letting calls
+// already in flight finish.
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/user/internal/errorinfo"
+	"example.com/user/pkg/usererrors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// DefaultRetryAfter is how long rejected callers are asked to wait when no delay is given
+const DefaultRetryAfter = 30 * time.Second
+
+// State describes maintenance mode
+type State struct {
+	Enabled bool
+	// Reason is told to rejected callers
+	Reason string
+	// RetryAfter is how long rejected callers should wait before retrying
+	RetryAfter time.Duration
+	// Since is when maintenance mode was turned on
+	Since time.Time
+}
+
+// Mode holds whether the server is in maintenance mode. Calls to exempt services, such as
+// the one controlling the mode, are always let through.
+type Mode struct {
+	exempt []string
+
+	mu       sync.RWMutex
+	state    State
+	onChange []func()
+}
+
+// New creates a Mode, initially off, that never rejects calls to the exempt services
+func New(exemptServices ...string) *Mode {
+	return &Mode{exempt: exemptServices}
+}
+
+// State returns the current state
+func (m *Mode) State() State {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.state
+}
+
+// Enable turns maintenance mode on, or updates its reason and delay when it is already
+// on. A retryAfter of 0 uses DefaultRetryAfter.
+func (m *Mode) Enable(reason string, retryAfter time.Duration) State {
+	if retryAfter <= 0 {
+		retryAfter = DefaultRetryAfter
+	}
+
+	m.mu.Lock()
+	since := m.state.Since
+	if !m.state.Enabled {
+		since = time.Now()
+	}
+	m.state = State{Enabled: true, Reason: reason, RetryAfter: retryAfter, Since: since}
+	state, onChange := m.state, m.onChange
+	m.mu.Unlock()
+
+	for _, f := range onChange {
+		f()
+	}
+	return state
+}
+
+// Disable turns maintenance mode off
+func (m *Mode) Disable() State {
+	m.mu.Lock()
+	m.state = State{}
+	onChange := m.onChange
+	m.mu.Unlock()
+
+	for _, f := range onChange {
+		f()
+	}
+	return State{}
+}
+
+// OnChange registers f to be called after every change of the mode
+func (m *Mode) OnChange(f func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onChange = append(m.onChange, f)
+}
+
+// Err returns why the server isn't ready while maintenance mode is on, or nil
+func (m *Mode) Err() error {
+	state := m.State()
+	switch {
+	case !state.Enabled:
+		return nil
+	case state.Reason == "":
+		return errors.New("maintenance mode")
+	default:
+		return fmt.Errorf("maintenance mode: %s", state.Reason)
+	}
+}
+
+// UnaryServerInterceptor rejects new unary calls while maintenance mode is on
+func (m *Mode) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := m.check(info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects new streams while maintenance mode is on; streams
+// opened before it was turned on carry on until they end
+func (m *Mode) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := m.check(info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// check returns UNAVAILABLE with ErrorInfo and RetryInfo details for calls to fullMethod that maintenance
+// mode turns away
+func (m *Mode) check(fullMethod string) error {
+	state := m.State()
+	if !state.Enabled || m.isExempt(fullMethod) {
+		return nil
+	}
+
+	msg := "Server is in maintenance"
+	if state.Reason != "" {
+		msg += ": " + state.Reason
+	}
+	st := status.Newf(codes.Unavailable, "%s, retry in %s", msg, state.RetryAfter)
+	if detailed, err := st.WithDetails(errorinfo.New(usererrors.ReasonMaintenance), &errdetails.RetryInfo{RetryDelay: durationpb.New(state.RetryAfter)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// isExempt reports whether fullMethod, "/service/method", belongs to an exempt service
+func (m *Mode) isExempt(fullMethod string) bool {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return slices.Contains(m.exempt, service)
+}
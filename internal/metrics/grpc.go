@@ -0,0 +1,219 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// RPC types used as the grpc_type label
+const (
+	unary        = "unary"
+	clientStream = "client_stream"
+	serverStream = "server_stream"
+	bidiStream   = "bidi_stream"
+)
+
+// GRPC records the RPCs handled by a server, labelled by service, method and RPC type
+// with the same metric names as go-grpc-prometheus so existing dashboards apply. Latency
+// is also labelled by status code, so per-method SLOs can leave out failed calls.
+type GRPC struct {
+	started  *prometheus.CounterVec
+	handled  *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	received *prometheus.CounterVec
+	sent     *prometheus.CounterVec
+	// Streams get metrics of their own since they can stay open far longer than unary
+	// calls and carry any number of messages
+	streamsActive  *prometheus.GaugeVec
+	streamDuration *prometheus.HistogramVec
+	streamMessages *prometheus.HistogramVec
+}
+
+// NewGRPC creates the gRPC server metrics and registers them with reg. The latency
+// histogram has buckets with the given upper bounds in seconds, or prometheus.DefBuckets
+// when there are none.
+func NewGRPC(reg prometheus.Registerer, buckets []float64) *GRPC {
+	if len(buckets) == 0 {
+		buckets = prometheus.DefBuckets
+	}
+
+	labels := []string{"grpc_type", "grpc_service", "grpc_method"}
+	m := &GRPC{
+		started: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_started_total",
+			Help: "RPCs started on the server.",
+		}, labels),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "RPCs completed on the server, by status code.",
+		}, append(labels, "grpc_code")),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Time from the start of an RPC until the server finished handling it, by status code.",
+			Buckets: buckets,
+		}, append(labels, "grpc_code")),
+		received: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_received_total",
+			Help: "Stream messages received from clients.",
+		}, labels),
+		sent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_sent_total",
+			Help: "Stream messages sent to clients.",
+		}, labels),
+		streamsActive: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "grpc_server_streams_active",
+			Help: "Streaming RPCs currently open on the server.",
+		}, labels),
+		streamDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_stream_duration_seconds",
+			Help:    "Time streaming RPCs stayed open, by status code.",
+			Buckets: []float64{.01, .1, 1, 10, 30, 60, 300, 900, 1800, 3600},
+		}, append(labels, "grpc_code")),
+		streamMessages: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_stream_messages",
+			Help:    "Messages carried by a streaming RPC, by direction (received or sent).",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 8),
+		}, append(labels, "direction")),
+	}
+	reg.MustRegister(m.started, m.handled, m.latency, m.received, m.sent,
+		m.streamsActive, m.streamDuration, m.streamMessages)
+
+	return m
+}
+
+// Initialize creates the series of every method registered on srv so they are
+// exported as zero before the first call
+func (m *GRPC) Initialize(srv *grpc.Server) {
+	for service, info := range srv.GetServiceInfo() {
+		for _, method := range info.Methods {
+			typ := rpcType(method.IsClientStream, method.IsServerStream)
+			m.started.WithLabelValues(typ, service, method.Name)
+			m.latency.WithLabelValues(typ, service, method.Name, "OK")
+			m.handled.WithLabelValues(typ, service, method.Name, "OK")
+			if typ != unary {
+				m.streamsActive.WithLabelValues(typ, service, method.Name)
+			}
+		}
+	}
+}
+
+// UnaryServerInterceptor records every unary call
+func (m *GRPC) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		service, method := splitMethod(info.FullMethod)
+		start := m.start(unary, service, method)
+		resp, err := handler(ctx, req)
+		m.finish(unary, service, method, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records every streaming call, how long it stays open and the
+// messages it carries
+func (m *GRPC) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		service, method := splitMethod(info.FullMethod)
+		typ := rpcType(info.IsClientStream, info.IsServerStream)
+		active := m.streamsActive.WithLabelValues(typ, service, method)
+		active.Inc()
+		defer active.Dec()
+
+		start := m.start(typ, service, method)
+		stream := &countingStream{
+			ServerStream: ss,
+			received:     m.received.WithLabelValues(typ, service, method),
+			sent:         m.sent.WithLabelValues(typ, service, method),
+		}
+		err := handler(srv, stream)
+		m.finish(typ, service, method, start, err)
+
+		m.streamDuration.WithLabelValues(typ, service, method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+		m.streamMessages.WithLabelValues(typ, service, method, "received").Observe(float64(stream.receivedCount.Load()))
+		m.streamMessages.WithLabelValues(typ, service, method, "sent").Observe(float64(stream.sentCount.Load()))
+		return err
+	}
+}
+
+func (m *GRPC) start(typ, service, method string) time.Time {
+	m.started.WithLabelValues(typ, service, method).Inc()
+	return time.Now()
+}
+
+func (m *GRPC) finish(typ, service, method string, start time.Time, err error) {
+	code := status.Code(err).String()
+	m.latency.WithLabelValues(typ, service, method, code).Observe(time.Since(start).Seconds())
+	m.handled.WithLabelValues(typ, service, method, code).Inc()
+}
+
+// countingStream counts the messages that pass through a server stream, in total and for
+// this stream; handlers may send from several goroutines, so the stream's counts are atomic
+type countingStream struct {
+	grpc.ServerStream
+	received, sent           prometheus.Counter
+	receivedCount, sentCount atomic.Int64
+}
+
+func (s *countingStream) RecvMsg(msg any) error {
+	err := s.ServerStream.RecvMsg(msg)
+	if err == nil {
+		s.received.Inc()
+		s.receivedCount.Add(1)
+	}
+	return err
+}
+
+func (s *countingStream) SendMsg(msg any) error {
+	err := s.ServerStream.SendMsg(msg)
+	if err == nil {
+		s.sent.Inc()
+		s.sentCount.Add(1)
+	}
+	return err
+}
+
+// ParseBuckets parses histogram bucket upper bounds in seconds, such as "0.01" or "2.5",
+// which must be positive and increasing
+func ParseBuckets(specs []string) ([]float64, error) {
+	buckets := make([]float64, 0, len(specs))
+	for _, spec := range specs {
+		b, err := strconv.ParseFloat(spec, 64)
+		if err != nil || b <= 0 {
+			return nil, fmt.Errorf("invalid histogram bucket %q: must be a positive number of seconds", spec)
+		}
+		if n := len(buckets); n > 0 && b <= buckets[n-1] {
+			return nil, fmt.Errorf("histogram buckets must be increasing, got %g after %g", b, buckets[n-1])
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, nil
+}
+
+// splitMethod splits "/user.UserService/GetUser" into its service and method names
+func splitMethod(fullMethod string) (string, string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "unknown", "unknown"
+	}
+	return service, method
+}
+
+func rpcType(clientStreaming, serverStreaming bool) string {
+	switch {
+	case clientStreaming && serverStreaming:
+		return bidiStream
+	case clientStreaming:
+		return clientStream
+	case serverStreaming:
+		return serverStream
+	default:
+		return unary
+	}
+}
@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCClient records the RPCs made by a client, with the metric names of
+// go-grpc-prometheus and the labels of GRPC. A call is recorded once, as the caller saw
+// it, however many attempts its retries took.
+type GRPCClient struct {
+	started *prometheus.CounterVec
+	handled *prometheus.CounterVec
+	latency *prometheus.HistogramVec
+	retries *prometheus.CounterVec
+	hedges  *prometheus.CounterVec
+}
+
+// NewGRPCClient creates the gRPC client metrics and registers them with reg
+func NewGRPCClient(reg prometheus.Registerer) *GRPCClient {
+	labels := []string{"grpc_type", "grpc_service", "grpc_method"}
+	m := &GRPCClient{
+		started: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_client_started_total",
+			Help: "RPCs started by the client.",
+		}, labels),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_client_handled_total",
+			Help: "RPCs completed by the client, by status code.",
+		}, append(labels, "grpc_code")),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_client_handling_seconds",
+			Help:    "Time from the start of an RPC until the client received its status, retries included, by status code.",
+			Buckets: prometheus.DefBuckets,
+		}, append(labels, "grpc_code")),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_client_retries_total",
+			Help: "Attempts the client made again after an RPC failed.",
+		}, []string{"grpc_service", "grpc_method"}),
+		hedges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_client_hedges_total",
+			Help: "Second attempts the client sent while an RPC's first attempt was still waiting for an answer.",
+		}, []string{"grpc_service", "grpc_method"}),
+	}
+	reg.MustRegister(m.started, m.handled, m.latency, m.retries, m.hedges)
+
+	return m
+}
+
+// Retried counts a retry of fullMethod
+func (m *GRPCClient) Retried(fullMethod string) {
+	service, method := splitMethod(fullMethod)
+	m.retries.WithLabelValues(service, method).Inc()
+}
+
+// Hedged counts a hedged attempt of fullMethod
+func (m *GRPCClient) Hedged(fullMethod string) {
+	service, method := splitMethod(fullMethod)
+	m.hedges.WithLabelValues(service, method).Inc()
+}
+
+// UnaryClientInterceptor records every unary call
+func (m *GRPCClient) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, fullMethod string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		service, method := splitMethod(fullMethod)
+		start := m.start(unary, service, method)
+		err := invoker(ctx, fullMethod, req, reply, cc, opts...)
+		m.finish(unary, service, method, start, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor records every streaming call once its status arrives
+func (m *GRPCClient) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, fullMethod string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		service, method := splitMethod(fullMethod)
+		typ := rpcType(desc.ClientStreams, desc.ServerStreams)
+		start := m.start(typ, service, method)
+		stream, err := streamer(ctx, desc, cc, fullMethod, opts...)
+		if err != nil {
+			m.finish(typ, service, method, start, err)
+			return nil, err
+		}
+		return &monitoredStream{
+			ClientStream: stream,
+			// Without server streaming, the single response ends the call
+			endsOnResponse: !desc.ServerStreams,
+			finish:         func(err error) { m.finish(typ, service, method, start, err) },
+		}, nil
+	}
+}
+
+func (m *GRPCClient) start(typ, service, method string) time.Time {
+	m.started.WithLabelValues(typ, service, method).Inc()
+	return time.Now()
+}
+
+func (m *GRPCClient) finish(typ, service, method string, start time.Time, err error) {
+	code := status.Code(err).String()
+	m.latency.WithLabelValues(typ, service, method, code).Observe(time.Since(start).Seconds())
+	m.handled.WithLabelValues(typ, service, method, code).Inc()
+}
+
+// monitoredStream records a client stream when RecvMsg reports its end
+type monitoredStream struct {
+	grpc.ClientStream
+	endsOnResponse bool
+	finish         func(error)
+	once           sync.Once
+}
+
+func (s *monitoredStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil || s.endsOnResponse {
+		s.once.Do(func() {
+			outcome := err
+			if errors.Is(outcome, io.EOF) {
+				outcome = nil
+			}
+			s.finish(outcome)
+		})
+	}
+	return err
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Path is where the metrics endpoint serves the Prometheus exposition format
+const Path = "/metrics"
+
+// NewRegistry creates a registry preloaded with Go runtime and process collectors
+func NewRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return reg
+}
+
+// Handler serves reg in the Prometheus exposition format, to be mounted at Path
+func Handler(reg *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg})
+}
+
+// Repository records repository calls per backend and operation
+type Repository struct {
+	operations *prometheus.CounterVec
+	errors     *prometheus.CounterVec
+	latency    *prometheus.HistogramVec
+}
+
+// NewRepository creates the repository metrics and registers them with reg
+func NewRepository(reg prometheus.Registerer) *Repository {
+	m := &Repository{
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "user_repository_operations_total",
+			Help: "Repository calls, by backend and operation.",
+		}, []string{"backend", "operation"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "user_repository_errors_total",
+			Help: "Repository calls that returned an error, by backend and operation.",
+		}, []string{"backend", "operation"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "user_repository_operation_duration_seconds",
+			Help:    "Repository call latency, by backend and operation.",
+			Buckets: []float64{.0001, .0005, .001, .005, .01, .025, .05, .1, .25, .5, 1, 5},
+		}, []string{"backend", "operation"}),
+	}
+	reg.MustRegister(m.operations, m.errors, m.latency)
+
+	return m
+}
+
+// Observe records one call to operation on backend that started at start and returned err
+func (m *Repository) Observe(backend, operation string, start time.Time, err error) {
+	m.operations.WithLabelValues(backend, operation).Inc()
+	m.latency.WithLabelValues(backend, operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.errors.WithLabelValues(backend, operation).Inc()
+	}
+}
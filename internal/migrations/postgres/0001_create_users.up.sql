@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS users (
+	id         SERIAL PRIMARY KEY,
+	name       TEXT NOT NULL,
+	email      TEXT NOT NULL UNIQUE,
+	role       TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
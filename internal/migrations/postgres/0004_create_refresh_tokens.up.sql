@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+	id         BIGSERIAL PRIMARY KEY,
+	token_hash TEXT NOT NULL UNIQUE,
+	family_id  TEXT NOT NULL,
+	subject    TEXT NOT NULL,
+	role       TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL,
+	revoked_at TIMESTAMPTZ NULL
+);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_family_id_idx ON refresh_tokens (family_id);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS user_preferences (
+	user_id             INTEGER PRIMARY KEY,
+	locale              TEXT NOT NULL,
+	timezone            TEXT NOT NULL,
+	email_notifications BOOLEAN NOT NULL,
+	push_notifications  BOOLEAN NOT NULL,
+	weekly_digest       BOOLEAN NOT NULL,
+	updated_at          TIMESTAMPTZ NOT NULL
+);
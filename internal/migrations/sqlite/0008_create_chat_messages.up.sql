@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS chat_messages (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	sender     TEXT NOT NULL,
+	recipient  TEXT NOT NULL,
+	room       TEXT NOT NULL,
+	message    TEXT NOT NULL,
+	type       INTEGER NOT NULL,
+	created_at TIMESTAMP NOT NULL
+);
+
+-- Each conversation is read newest first: a room, two users, or messages to everyone
+CREATE INDEX IF NOT EXISTS chat_messages_room_idx ON chat_messages (room, recipient, id);
+CREATE INDEX IF NOT EXISTS chat_messages_direct_idx ON chat_messages (sender, recipient, id);
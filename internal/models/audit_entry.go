@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	pb "example.com/user/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AuditEntry records one change made to a user through the API
+type AuditEntry struct {
+	ID        int64
+	Actor     string
+	Method    string
+	UserID    int32
+	OldValue  *User // nil for creations and restores
+	NewValue  *User // nil for deletions
+	CreatedAt time.Time
+}
+
+// EntityID returns the entry's ID for generic repositories
+func (e *AuditEntry) EntityID() int64 {
+	return e.ID
+}
+
+// SetEntityID assigns the ID chosen by a generic repository
+func (e *AuditEntry) SetEntityID(id int64) {
+	e.ID = id
+}
+
+// ToProto converts the entry to its protobuf form
+func (e *AuditEntry) ToProto() *pb.AuditEntry {
+	res := &pb.AuditEntry{
+		Id:            e.ID,
+		Timestamp:     timestamppb.New(e.CreatedAt),
+		Actor:         e.Actor,
+		Method:        e.Method,
+		UserId:        e.UserID,
+		ChangedFields: e.ChangedFields(),
+	}
+	if e.OldValue != nil {
+		res.OldValue = e.OldValue.ToProto()
+	}
+	if e.NewValue != nil {
+		res.NewValue = e.NewValue.ToProto()
+	}
+	return res
+}
+
+// ChangedFields lists the fields that differ between the old and new values: name, email,
+// role and status, and deleted_at for deletions and restores; nil for creations
+func (e *AuditEntry) ChangedFields() []string {
+	switch {
+	case e.Method == "DeleteUser" || e.Method == "UndeleteUser":
+		// Deletions record no new value and restores no old one
+		return []string{"deleted_at"}
+	case e.OldValue == nil || e.NewValue == nil:
+		return nil
+	}
+
+	var fields []string
+	for _, f := range []struct {
+		name     string
+		old, new string
+	}{
+		{"name", e.OldValue.Name, e.NewValue.Name},
+		{"email", e.OldValue.Email, e.NewValue.Email},
+		{"role", e.OldValue.Role, e.NewValue.Role},
+		{"status", e.OldValue.Status, e.NewValue.Status},
+	} {
+		if f.old != f.new {
+			fields = append(fields, f.name)
+		}
+	}
+	return fields
+}
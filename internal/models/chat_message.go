@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	pb "example.com/user/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ChatMessage is a message sent over Chat, kept in the chat history
+type ChatMessage struct {
+	ID        int64
+	From      string
+	To        string // empty for messages to a room or to everyone
+	Room      string // empty for direct messages and messages to everyone
+	Message   string
+	Type      pb.MessageType
+	CreatedAt time.Time
+}
+
+// ChatMessageFromProto returns the message to keep of msg, sent at sentAt
+func ChatMessageFromProto(msg *pb.ChatMessage, sentAt time.Time) *ChatMessage {
+	return &ChatMessage{
+		From:      msg.From,
+		To:        msg.To,
+		Room:      msg.Room,
+		Message:   msg.Message,
+		Type:      msg.Type,
+		CreatedAt: sentAt,
+	}
+}
+
+// EntityID returns the message's ID for generic repositories
+func (m *ChatMessage) EntityID() int64 {
+	return m.ID
+}
+
+// SetEntityID assigns the ID chosen by a generic repository
+func (m *ChatMessage) SetEntityID(id int64) {
+	m.ID = id
+}
+
+// ToProto converts the message to its protobuf form
+func (m *ChatMessage) ToProto() *pb.ChatMessage {
+	return &pb.ChatMessage{
+		Id:        m.ID,
+		From:      m.From,
+		To:        m.To,
+		Room:      m.Room,
+		Message:   m.Message,
+		Type:      m.Type,
+		Timestamp: timestamppb.New(m.CreatedAt),
+	}
+}
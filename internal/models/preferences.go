@@ -0,0 +1,110 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	pb "example.com/user/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Defaults of the preferences of a user who has set none
+const (
+	DefaultLocale   = "en-US"
+	DefaultTimezone = "UTC"
+)
+
+// PreferenceFields are the fields the update_mask of an UpdatePreferencesRequest may name,
+// besides "*" for all of them
+var PreferenceFields = []string{
+	"locale", "timezone", "notifications",
+	"notifications.email", "notifications.push", "notifications.weekly_digest",
+}
+
+// Preferences are the settings a user keeps for themselves
+type Preferences struct {
+	UserID             int32
+	Locale             string
+	Timezone           string
+	EmailNotifications bool
+	PushNotifications  bool
+	WeeklyDigest       bool
+	UpdatedAt          time.Time // zero until first saved
+}
+
+// DefaultPreferences returns the preferences of user id before it sets any
+func DefaultPreferences(id int32) *Preferences {
+	return &Preferences{
+		UserID:             id,
+		Locale:             DefaultLocale,
+		Timezone:           DefaultTimezone,
+		EmailNotifications: true,
+	}
+}
+
+// ToProto converts Preferences to their protobuf form
+func (p *Preferences) ToProto() *pb.UserPreferences {
+	prefs := &pb.UserPreferences{
+		UserId:   p.UserID,
+		Locale:   p.Locale,
+		Timezone: p.Timezone,
+		Notifications: &pb.NotificationSettings{
+			Email:        p.EmailNotifications,
+			Push:         p.PushNotifications,
+			WeeklyDigest: p.WeeklyDigest,
+		},
+	}
+	if !p.UpdatedAt.IsZero() {
+		prefs.UpdatedAt = timestamppb.New(p.UpdatedAt)
+	}
+	return prefs
+}
+
+// Update modifies preferences from an UpdatePreferencesRequest: the fields its update_mask
+// names, even to empty values, or without a mask locale and timezone when not empty and
+// the notifications when set
+func (p *Preferences) Update(req *pb.UpdatePreferencesRequest) {
+	masked := len(req.GetUpdateMask().GetPaths()) > 0
+	setString := func(field, value, fallback string, dst *string) {
+		switch {
+		case masked && PreferencesMaskNames(req, field) && value == "":
+			*dst = fallback
+		case masked && PreferencesMaskNames(req, field) || !masked && value != "":
+			*dst = value
+		}
+	}
+	setString("locale", req.Locale, DefaultLocale, &p.Locale)
+	setString("timezone", req.Timezone, DefaultTimezone, &p.Timezone)
+
+	notifications := req.GetNotifications()
+	setBool := func(field string, value bool, dst *bool) {
+		if masked && PreferencesMaskNames(req, "notifications."+field) || !masked && notifications != nil {
+			*dst = value
+		}
+	}
+	setBool("email", notifications.GetEmail(), &p.EmailNotifications)
+	setBool("push", notifications.GetPush(), &p.PushNotifications)
+	setBool("weekly_digest", notifications.GetWeeklyDigest(), &p.WeeklyDigest)
+	p.UpdatedAt = time.Now()
+}
+
+// PreferencesMaskNames reports whether the update_mask of req names field, directly,
+// through the message holding it or with "*"
+func PreferencesMaskNames(req *pb.UpdatePreferencesRequest, field string) bool {
+	paths := req.GetUpdateMask().GetPaths()
+	if parent, _, nested := strings.Cut(field, "."); nested && slices.Contains(paths, parent) {
+		return true
+	}
+	return slices.Contains(paths, field) || slices.Contains(paths, "*")
+}
+
+// EntityID returns the preferences' user ID for generic repositories
+func (p *Preferences) EntityID() int32 {
+	return p.UserID
+}
+
+// SetEntityID assigns the ID chosen by a generic repository
+func (p *Preferences) SetEntityID(id int32) {
+	p.UserID = id
+}
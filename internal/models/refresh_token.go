@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// RefreshToken is a stored, single-use refresh token. Only a hash of the token is kept.
+type RefreshToken struct {
+	ID        int64
+	TokenHash string
+	// FamilyID groups the tokens of one session, each rotation producing the next member
+	FamilyID  string
+	Subject   string
+	Role      string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RevokedAt *time.Time // set once the token has been used or its session revoked
+}
+
+// IsRevoked reports whether the token can no longer be used
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// IsExpired reports whether the token expired before now
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// EntityID returns the token's ID for generic repositories
+func (t *RefreshToken) EntityID() int64 {
+	return t.ID
+}
+
+// SetEntityID assigns the ID chosen by a generic repository
+func (t *RefreshToken) SetEntityID(id int64) {
+	t.ID = id
+}
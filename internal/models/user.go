@@ -1,40 +1,108 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
 	"time"
 
 	pb "example.com/user/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// Built-in roles; every other role name is a custom role
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// roleTypes maps the built-in role names onto their Role
+var roleTypes = map[string]pb.Role{
+	RoleUser:  pb.Role_ROLE_USER,
+	RoleAdmin: pb.Role_ROLE_ADMIN,
+}
+
+// customRolePattern is what the name of a custom role looks like
+var customRolePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+// Statuses of a user account
+const (
+	StatusActive    = "active"
+	StatusSuspended = "suspended"
+)
+
 // User represents the internal user model
 type User struct {
 	ID        int32
 	Name      string
 	Email     string
 	Role      string
+	Status    string // StatusActive or StatusSuspended
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Version   int64
+	DeletedAt *time.Time // non-nil while the user is soft-deleted
+}
+
+// IsDeleted reports whether the user has been soft-deleted
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// IsSuspended reports whether the user's account has been suspended
+func (u *User) IsSuspended() bool {
+	return u.Status == StatusSuspended
+}
+
+// EntityID returns the user's ID for generic repositories
+func (u *User) EntityID() int32 {
+	return u.ID
+}
+
+// SetEntityID assigns the ID chosen by a generic repository
+func (u *User) SetEntityID(id int32) {
+	u.ID = id
 }
 
 // ToProto converts internal User model to protobuf UserResponse
 func (u *User) ToProto() *pb.UserResponse {
-	return &pb.UserResponse{
+	res := &pb.UserResponse{
 		Id:        u.ID,
 		Name:      u.Name,
 		Email:     u.Email,
 		Role:      u.Role,
+		RoleType:  RoleType(u.Role),
 		CreatedAt: timestamppb.New(u.CreatedAt),
 		UpdatedAt: timestamppb.New(u.UpdatedAt),
+		Version:   u.Version,
+		Status:    pb.UserStatus_USER_STATUS_ACTIVE,
+		Etag:      u.ETag(),
+	}
+	if u.IsSuspended() {
+		res.Status = pb.UserStatus_USER_STATUS_SUSPENDED
 	}
+	if u.DeletedAt != nil {
+		res.DeletedAt = timestamppb.New(*u.DeletedAt)
+	}
+	return res
+}
+
+// ETag hashes the state of the user. Timestamps are left out, as backends store them
+// with different precisions, but every change increments the version.
+func (u *User) ETag() string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%d %d %q %q %q %q %t",
+		u.ID, u.Version, u.Name, u.Email, u.Role, u.Status, u.IsDeleted()))
+	return hex.EncodeToString(sum[:8])
 }
 
 // FromCreateRequest creates a User from CreateUserRequest
 func FromCreateRequest(req *pb.CreateUserRequest, id int32) *User {
 	now := time.Now()
-	role := req.Role
+	role := RoleName(req.RoleType, req.Role)
 	if role == "" {
-		role = "user"
+		role = RoleUser
 	}
 	
 	return &User{
@@ -42,21 +110,63 @@ func FromCreateRequest(req *pb.CreateUserRequest, id int32) *User {
 		Name:      req.Name,
 		Email:     req.Email,
 		Role:      role,
+		Status:    StatusActive,
 		CreatedAt: now,
 		UpdatedAt: now,
+		Version:   1,
 	}
 }
 
-// Update modifies user fields from UpdateUserRequest
+// UpdatableFields are the fields the update_mask of an UpdateUserRequest may name, besides
+// "*" for all of them
+var UpdatableFields = []string{"name", "email", "role"}
+
+// Update modifies user fields from UpdateUserRequest: those its update_mask names, even to
+// empty values, or without a mask those it sets to non-empty values
 func (u *User) Update(req *pb.UpdateUserRequest) {
-	if req.Name != "" {
-		u.Name = req.Name
+	masked := len(req.GetUpdateMask().GetPaths()) > 0
+	set := func(field, value string, dst *string) {
+		if masked && MaskNames(req, field) || !masked && value != "" {
+			*dst = value
+		}
 	}
-	if req.Email != "" {
-		u.Email = req.Email
-	}
-	if req.Role != "" {
-		u.Role = req.Role
+	set("name", req.Name, &u.Name)
+	set("email", req.Email, &u.Email)
+	set("role", RoleName(req.RoleType, req.Role), &u.Role)
+	// A cleared role falls back to the default, as on creation
+	if u.Role == "" {
+		u.Role = RoleUser
 	}
 	u.UpdatedAt = time.Now()
-}
\ No newline at end of file
+}
+
+// MaskNames reports whether the update_mask of req names field, directly or with "*"
+func MaskNames(req *pb.UpdateUserRequest, field string) bool {
+	paths := req.GetUpdateMask().GetPaths()
+	return slices.Contains(paths, field) || slices.Contains(paths, "*")
+}
+
+// RoleName resolves the role a request gives as a Role, by name as older clients do, or
+// both, into the name stored; empty when it gives none
+func RoleName(t pb.Role, name string) string {
+	for builtin, bt := range roleTypes {
+		if t == bt {
+			return builtin
+		}
+	}
+	return name
+}
+
+// RoleType returns the Role a stored role name stands for
+func RoleType(name string) pb.Role {
+	if t, ok := roleTypes[name]; ok {
+		return t
+	}
+	return pb.Role_ROLE_CUSTOM
+}
+
+// ValidRoleName reports whether name is a built-in role or a well-formed custom one
+func ValidRoleName(name string) bool {
+	_, builtin := roleTypes[name]
+	return builtin || customRolePattern.MatchString(name)
+}
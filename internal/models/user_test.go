@@ -0,0 +1,153 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	pb "example.com/user/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestUserUpdate(t *testing.T) {
+	mask := func(paths ...string) *fieldmaskpb.FieldMask { return &fieldmaskpb.FieldMask{Paths: paths} }
+
+	tests := []struct {
+		name string
+		req  *pb.UpdateUserRequest
+		want User
+	}{
+		{"no mask sets non-empty fields", &pb.UpdateUserRequest{Name: "Grace"},
+			User{Name: "Grace", Email: "ada@example.com", Role: RoleAdmin}},
+		{"no mask leaves empty fields", &pb.UpdateUserRequest{},
+			User{Name: "Ada", Email: "ada@example.com", Role: RoleAdmin}},
+		{"mask sets only its fields", &pb.UpdateUserRequest{Name: "Grace", Email: "grace@example.com", UpdateMask: mask("email")},
+			User{Name: "Ada", Email: "grace@example.com", Role: RoleAdmin}},
+		{"mask sets empty fields", &pb.UpdateUserRequest{UpdateMask: mask("name")},
+			User{Name: "", Email: "ada@example.com", Role: RoleAdmin}},
+		{"masked empty role resets to user", &pb.UpdateUserRequest{UpdateMask: mask("role")},
+			User{Name: "Ada", Email: "ada@example.com", Role: RoleUser}},
+		{"star mask sets everything", &pb.UpdateUserRequest{Name: "Grace", Email: "grace@example.com", UpdateMask: mask("*")},
+			User{Name: "Grace", Email: "grace@example.com", Role: RoleUser}},
+		{"role by type", &pb.UpdateUserRequest{RoleType: pb.Role_ROLE_USER},
+			User{Name: "Ada", Email: "ada@example.com", Role: RoleUser}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &User{ID: 1, Name: "Ada", Email: "ada@example.com", Role: RoleAdmin, Version: 3}
+			u.Update(tt.req)
+			if u.Name != tt.want.Name || u.Email != tt.want.Email || u.Role != tt.want.Role {
+				t.Errorf("after Update: name %q, email %q, role %q; want %q, %q, %q",
+					u.Name, u.Email, u.Role, tt.want.Name, tt.want.Email, tt.want.Role)
+			}
+			if u.ID != 1 || u.Version != 3 {
+				t.Errorf("Update changed ID or version: %d, %d", u.ID, u.Version)
+			}
+		})
+	}
+}
+
+func TestMaskNames(t *testing.T) {
+	tests := []struct {
+		paths []string
+		field string
+		want  bool
+	}{
+		{nil, "name", false},
+		{[]string{"name"}, "name", true},
+		{[]string{"email"}, "name", false},
+		{[]string{"email", "role"}, "role", true},
+		{[]string{"*"}, "email", true},
+	}
+	for _, tt := range tests {
+		req := &pb.UpdateUserRequest{UpdateMask: &fieldmaskpb.FieldMask{Paths: tt.paths}}
+		if got := MaskNames(req, tt.field); got != tt.want {
+			t.Errorf("MaskNames(%v, %q) = %v, want %v", tt.paths, tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestRoleName(t *testing.T) {
+	tests := []struct {
+		roleType pb.Role
+		name     string
+		want     string
+	}{
+		{pb.Role_ROLE_UNKNOWN, "", ""},
+		{pb.Role_ROLE_UNKNOWN, "auditor", "auditor"},
+		{pb.Role_ROLE_USER, "", RoleUser},
+		{pb.Role_ROLE_ADMIN, "", RoleAdmin},
+		// A built-in type wins over the name
+		{pb.Role_ROLE_ADMIN, "auditor", RoleAdmin},
+		{pb.Role_ROLE_CUSTOM, "auditor", "auditor"},
+	}
+	for _, tt := range tests {
+		if got := RoleName(tt.roleType, tt.name); got != tt.want {
+			t.Errorf("RoleName(%v, %q) = %q, want %q", tt.roleType, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRoleType(t *testing.T) {
+	tests := []struct {
+		name string
+		want pb.Role
+	}{
+		{RoleUser, pb.Role_ROLE_USER},
+		{RoleAdmin, pb.Role_ROLE_ADMIN},
+		{"auditor", pb.Role_ROLE_CUSTOM},
+	}
+	for _, tt := range tests {
+		if got := RoleType(tt.name); got != tt.want {
+			t.Errorf("RoleType(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidRoleName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{RoleUser, true},
+		{RoleAdmin, true},
+		{"auditor", true},
+		{"team-lead_2", true},
+		{"", false},
+		{"Admin", false},
+		{"2fa", false},
+		{"has space", false},
+		{"a" + strings.Repeat("b", 31), true},
+		{"a" + strings.Repeat("b", 32), false},
+	}
+	for _, tt := range tests {
+		if got := ValidRoleName(tt.name); got != tt.want {
+			t.Errorf("ValidRoleName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFromCreateRequestRole(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.CreateUserRequest
+		want string
+	}{
+		{"defaults to user", &pb.CreateUserRequest{}, RoleUser},
+		{"by name", &pb.CreateUserRequest{Role: "auditor"}, "auditor"},
+		{"by type", &pb.CreateUserRequest{RoleType: pb.Role_ROLE_ADMIN}, RoleAdmin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := FromCreateRequest(tt.req, 7)
+			if u.Role != tt.want {
+				t.Errorf("role = %q, want %q", u.Role, tt.want)
+			}
+			if u.ID != 7 || u.Status != StatusActive || u.Version != 1 {
+				t.Errorf("FromCreateRequest = %+v, want ID 7, active, at version 1", u)
+			}
+			if u.ToProto().RoleType != RoleType(tt.want) {
+				t.Errorf("ToProto().RoleType = %v, want %v", u.ToProto().RoleType, RoleType(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"example.com/user/internal/errorinfo"
+	"example.com/user/pkg/usererrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// InFlight caps how many calls the server handles at once across all clients, shedding
+// the rest rather than letting them queue on shared locks such as the store's
+type InFlight struct {
+	max     int64
+	current atomic.Int64
+	exempt  []string
+}
+
+// NewInFlight creates an InFlight allowing max calls at once; calls to the exempt
+// services are neither counted nor shed
+func NewInFlight(max int, exemptServices ...string) *InFlight {
+	return &InFlight{max: int64(max), exempt: exemptServices}
+}
+
+// UnaryServerInterceptor rejects calls over the limit with ResourceExhausted
+func (f *InFlight) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		release, err := f.acquire(info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects streams over the limit with ResourceExhausted. A stream
+// counts against the limit until it ends.
+func (f *InFlight) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, err := f.acquire(info.FullMethod)
+		if err != nil {
+			return err
+		}
+		defer release()
+		return handler(srv, ss)
+	}
+}
+
+// acquire takes a slot for a call to fullMethod, returning the func giving it back
+func (f *InFlight) acquire(fullMethod string) (func(), error) {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if slices.Contains(f.exempt, service) {
+		return func() {}, nil
+	}
+	if f.current.Add(1) > f.max {
+		f.current.Add(-1)
+		return nil, errorinfo.Errorf(codes.ResourceExhausted, usererrors.ReasonServerBusy, "Server is busy handling %d calls, retry later", f.max)
+	}
+	return func() { f.current.Add(-1) }, nil
+}
@@ -0,0 +1,225 @@
+// Package ratelimit throttles RPCs per client with token buckets.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/user/internal/auth"
+	"example.com/user/internal/errorinfo"
+	"example.com/user/pkg/usererrors"
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// idleTimeout is how long a client's buckets are kept after its last request
+	idleTimeout = 10 * time.Minute
+	// inprocessNetwork is the network of the in-memory connection frontends call through
+	inprocessNetwork = "bufconn"
+)
+
+// ClientAddressKey is the metadata key under which in-process frontends, such as the
+// REST gateway, pass on the address of the HTTP client a call is made for. It is only
+// trusted on in-process connections, whose peer address is that of the frontend.
+const ClientAddressKey = "x-client-address"
+
+// Limit is a token bucket refilled at Rate tokens per second and holding up to Burst.
+// A zero Rate means unlimited.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// ParseMethodLimits parses "Method=rate[:burst]" entries. Method is a full method name
+// such as "/user.UserService/CreateUser" or just "CreateUser" to match it in any service.
+// A rate of 0 exempts the method; burst defaults to the rate rounded up.
+func ParseMethodLimits(entries []string) (map[string]Limit, error) {
+	limits := make(map[string]Limit, len(entries))
+	for _, entry := range entries {
+		method, spec, found := strings.Cut(entry, "=")
+		method = strings.TrimSpace(method)
+		if !found || method == "" {
+			return nil, fmt.Errorf("rate limit %q: expected Method=rate[:burst]", entry)
+		}
+
+		rateSpec, burstSpec, hasBurst := strings.Cut(spec, ":")
+		r, err := strconv.ParseFloat(strings.TrimSpace(rateSpec), 64)
+		if err != nil || r < 0 || math.IsInf(r, 0) || math.IsNaN(r) {
+			return nil, fmt.Errorf("rate limit %q: invalid rate %q", entry, rateSpec)
+		}
+		limit := Limit{Rate: r, Burst: int(math.Ceil(r))}
+		if hasBurst {
+			if limit.Burst, err = strconv.Atoi(strings.TrimSpace(burstSpec)); err != nil || limit.Burst < 1 {
+				return nil, fmt.Errorf("rate limit %q: invalid burst %q", entry, burstSpec)
+			}
+		}
+		limits[method] = limit
+	}
+	return limits, nil
+}
+
+// Limiter holds a token bucket per client for the default limit, plus one per client
+// and method for methods with a limit of their own
+type Limiter struct {
+	mu        sync.Mutex
+	fallback  Limit
+	methods   map[string]Limit
+	buckets   map[bucketKey]*bucket
+	lastSweep time.Time
+}
+
+type bucketKey struct {
+	client string
+	// method is empty for the bucket shared by every method under the default limit
+	method string
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// New creates a Limiter applying fallback to methods missing from methods
+func New(fallback Limit, methods map[string]Limit) *Limiter {
+	return &Limiter{
+		fallback:  fallback,
+		methods:   methods,
+		buckets:   make(map[bucketKey]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// SetLimits replaces the limits. Buckets start over, full, under the new limits.
+func (l *Limiter) SetLimits(fallback Limit, methods map[string]Limit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fallback = fallback
+	l.methods = methods
+	l.buckets = make(map[bucketKey]*bucket)
+}
+
+// Allow takes a token from client's bucket for fullMethod. When none is left it returns
+// false and how long until one is.
+func (l *Limiter) Allow(client, fullMethod string) (bool, time.Duration) {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit, key := l.limitFor(client, fullMethod)
+	if limit.Rate == 0 {
+		return true, 0
+	}
+
+	l.sweep(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(limit.Rate), max(limit.Burst, 1))}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	if b.limiter.AllowN(now, 1) {
+		return true, 0
+	}
+	r := b.limiter.ReserveN(now, 1)
+	wait := r.DelayFrom(now)
+	r.CancelAt(now)
+	return false, wait
+}
+
+// limitFor returns the limit of fullMethod and the bucket it draws from; l.mu must be held
+func (l *Limiter) limitFor(client, fullMethod string) (Limit, bucketKey) {
+	if limit, ok := l.methods[fullMethod]; ok {
+		return limit, bucketKey{client: client, method: fullMethod}
+	}
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		if limit, ok := l.methods[fullMethod[i+1:]]; ok {
+			return limit, bucketKey{client: client, method: fullMethod}
+		}
+	}
+	return l.fallback, bucketKey{client: client}
+}
+
+// sweep drops buckets of clients that have been idle, at most once per idleTimeout;
+// l.mu must be held
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleTimeout {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) >= idleTimeout {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// UnaryServerInterceptor rejects calls over the limit with ResourceExhausted
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := l.check(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor. A
+// stream takes one token when it is opened, whatever the number of messages.
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.check(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// check returns RESOURCE_EXHAUSTED, with ErrorInfo and RetryInfo details, for calls over
+// the limit
+func (l *Limiter) check(ctx context.Context, fullMethod string) error {
+	allowed, wait := l.Allow(clientKey(ctx), fullMethod)
+	if allowed {
+		return nil
+	}
+	st := status.Newf(codes.ResourceExhausted, "Rate limit exceeded for %s, retry in %s", fullMethod, wait.Round(time.Millisecond))
+	if detailed, err := st.WithDetails(errorinfo.New(usererrors.ReasonRateLimited), &errdetails.RetryInfo{RetryDelay: durationpb.New(wait)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// clientKey identifies the caller: its authenticated subject when there is one, else
+// its peer IP address, so one host can't dodge the limit by opening new connections.
+// Calls of in-process frontends are told apart by the address of their HTTP client.
+func clientKey(ctx context.Context) string {
+	if p, ok := auth.PrincipalFromContext(ctx); ok && p.Subject != "" {
+		return "principal:" + p.Subject
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr := p.Addr.String()
+		if p.Addr.Network() == inprocessNetwork {
+			if values := metadata.ValueFromIncomingContext(ctx, ClientAddressKey); len(values) == 1 && values[0] != "" {
+				addr = values[0]
+			}
+		}
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			addr = host
+		}
+		return "peer:" + addr
+	}
+	return "unknown"
+}
@@ -0,0 +1,172 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"example.com/user/internal/auth"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	createUser = "/user.UserService/CreateUser"
+	getUser    = "/user.UserService/GetUser"
+)
+
+func TestParseMethodLimits(t *testing.T) {
+	tests := []struct {
+		entry   string
+		want    Limit
+		wantErr bool
+	}{
+		{"CreateUser=2", Limit{Rate: 2, Burst: 2}, false},
+		{" CreateUser = 0.5 ", Limit{Rate: 0.5, Burst: 1}, false},
+		{"CreateUser=2:10", Limit{Rate: 2, Burst: 10}, false},
+		{"CreateUser=0", Limit{}, false},
+		{"CreateUser", Limit{}, true},
+		{"=2", Limit{}, true},
+		{"CreateUser=fast", Limit{}, true},
+		{"CreateUser=-1", Limit{}, true},
+		{"CreateUser=Inf", Limit{}, true},
+		{"CreateUser=NaN", Limit{}, true},
+		{"CreateUser=2:0", Limit{}, true},
+		{"CreateUser=2:lots", Limit{}, true},
+	}
+	for _, tt := range tests {
+		limits, err := ParseMethodLimits([]string{tt.entry})
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMethodLimits(%q) = %v, want an error", tt.entry, limits)
+			}
+			continue
+		}
+		if err != nil || len(limits) != 1 || limits["CreateUser"] != tt.want {
+			t.Errorf("ParseMethodLimits(%q) = %v, %v; want CreateUser at %+v", tt.entry, limits, err, tt.want)
+		}
+	}
+}
+
+func TestAllow(t *testing.T) {
+	// Slow enough that no token comes back while the test runs
+	limiter := New(Limit{Rate: 0.01, Burst: 2}, map[string]Limit{
+		createUser: {Rate: 0.01, Burst: 1},
+		"Export":   {Rate: 0.01, Burst: 1},
+		"Health":   {},
+	})
+
+	calls := []struct {
+		client string
+		method string
+		want   bool
+	}{
+		// Methods under the default limit share one bucket per client
+		{"a", getUser, true},
+		{"a", "/user.UserService/ListUsers", true},
+		{"a", getUser, false},
+		{"b", getUser, true},
+		// Methods with a limit of their own have their own buckets
+		{"a", createUser, true},
+		{"a", createUser, false},
+		{"b", createUser, true},
+		// Matched by method name in any service, with a bucket per full method
+		{"a", "/user.UserService/Export", true},
+		{"a", "/user.UserService/Export", false},
+		{"a", "/user.AdminService/Export", true},
+		// A zero rate is unlimited
+		{"a", "/grpc.health.v1/Health", true},
+		{"a", "/grpc.health.v1/Health", true},
+	}
+	for i, c := range calls {
+		allowed, wait := limiter.Allow(c.client, c.method)
+		if allowed != c.want {
+			t.Fatalf("call %d: Allow(%s, %s) = %v, want %v", i, c.client, c.method, allowed, c.want)
+		}
+		if !allowed && wait <= 0 {
+			t.Errorf("call %d: denied with a wait of %v", i, wait)
+		}
+	}
+
+	// New limits start with full buckets
+	limiter.SetLimits(Limit{Rate: 0.01, Burst: 1}, nil)
+	if allowed, _ := limiter.Allow("a", getUser); !allowed {
+		t.Error("Allow after SetLimits = false, want true")
+	}
+}
+
+// inprocessAddr is the address of a peer on an in-memory connection
+type inprocessAddr struct{}
+
+func (inprocessAddr) Network() string { return inprocessNetwork }
+func (inprocessAddr) String() string  { return inprocessNetwork }
+
+func TestClientKey(t *testing.T) {
+	tcp := &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 4321}
+	forwarded := metadata.Pairs(ClientAddressKey, "198.51.100.7:5555")
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		addr      net.Addr
+		md        metadata.MD
+		want      string
+	}{
+		{"authenticated", &auth.Principal{Subject: "2"}, tcp, nil, "principal:2"},
+		{"without subject", &auth.Principal{}, tcp, nil, "peer:192.0.2.1"},
+		{"tcp peer", nil, tcp, nil, "peer:192.0.2.1"},
+		{"ipv6 peer", nil, &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 1}, nil, "peer:2001:db8::1"},
+		// Only in-process frontends are trusted to name the client
+		{"forwarded over tcp", nil, tcp, forwarded, "peer:192.0.2.1"},
+		{"forwarded in process", nil, inprocessAddr{}, forwarded, "peer:198.51.100.7"},
+		{"in process", nil, inprocessAddr{}, nil, "peer:bufconn"},
+		{"no peer", nil, nil, nil, "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = auth.WithPrincipal(ctx, tt.principal)
+			}
+			if tt.addr != nil {
+				ctx = peer.NewContext(ctx, &peer.Peer{Addr: tt.addr})
+			}
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			if got := clientKey(ctx); got != tt.want {
+				t.Errorf("clientKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := New(Limit{Rate: 0.01, Burst: 1}, nil).UnaryServerInterceptor()
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1}})
+	info := &grpc.UnaryServerInfo{FullMethod: getUser}
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+
+	if _, err := interceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("first call error = %v", err)
+	}
+	_, err := interceptor(ctx, nil, info, handler)
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("second call code = %v, want ResourceExhausted", st.Code())
+	}
+	var retry *errdetails.RetryInfo
+	for _, detail := range st.Details() {
+		if r, ok := detail.(*errdetails.RetryInfo); ok {
+			retry = r
+		}
+	}
+	if retry == nil || retry.RetryDelay.AsDuration() <= 0 || retry.RetryDelay.AsDuration() > 100*time.Second {
+		t.Errorf("RetryInfo = %v, want a delay of up to 100s", retry)
+	}
+}
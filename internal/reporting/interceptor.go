@@ -0,0 +1,64 @@
+package reporting
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errPanic is returned to callers in place of a handler panic, which may reveal internals
+var errPanic = status.Error(codes.Internal, "internal error")
+
+// UnaryServerInterceptor turns handler panics into Internal errors instead of crashing
+// the server, and reports them and every other Internal error to r unless it is nil
+func UnaryServerInterceptor(r Reporter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(ctx, r, info.FullMethod, p)
+			}
+		}()
+
+		resp, err = handler(ctx, req)
+		report(ctx, r, info.FullMethod, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor turns handler panics into Internal errors instead of crashing
+// the server, and reports them and every other Internal error to r unless it is nil
+func StreamServerInterceptor(r Reporter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(ss.Context(), r, info.FullMethod, p)
+			}
+		}()
+
+		err = handler(srv, ss)
+		report(ss.Context(), r, info.FullMethod, err)
+		return err
+	}
+}
+
+// recovered logs and reports the panic p, returning the error sent to the caller. It runs
+// in the deferred call, so the panicking frames are still on the stack.
+func recovered(ctx context.Context, r Reporter, method string, p any) error {
+	stack := debug.Stack()
+	slog.ErrorContext(ctx, "Recovered from panic", "panic", p, "stack", string(stack))
+	if r != nil {
+		r.Report(ctx, Event{Method: method, Err: errPanic, Panic: p, Stack: stack})
+	}
+	return errPanic
+}
+
+// report sends err to r if it is an Internal error
+func report(ctx context.Context, r Reporter, method string, err error) {
+	if r != nil && status.Code(err) == codes.Internal {
+		r.Report(ctx, Event{Method: method, Err: err})
+	}
+}
@@ -0,0 +1,49 @@
+// Package reporting sends server failures, Internal errors and recovered panics, to an
+// error tracking service such as Sentry.
+package reporting
+
+import (
+	"context"
+	"fmt"
+
+	"example.com/user/internal/config"
+)
+
+// Reporters accepted by ERROR_REPORTER
+const (
+	ReporterNone   = "none"
+	ReporterSentry = "sentry"
+)
+
+// Event describes a call that failed on the server side
+type Event struct {
+	// Method is the full gRPC method name
+	Method string
+	// Err is the Internal status error returned to the caller
+	Err error
+	// Panic is the value the handler panicked with; nil unless it panicked
+	Panic any
+	// Stack is the stack of the panicking goroutine; nil unless the handler panicked
+	Stack []byte
+}
+
+// Reporter sends Events to an error tracking service. Report is called while the call is
+// being handled, so it must not block on the network.
+type Reporter interface {
+	Report(ctx context.Context, e Event)
+	// Flush sends the Events still buffered, giving up when ctx is done
+	Flush(ctx context.Context) error
+}
+
+// New creates the Reporter configured by cfg, tagging reports with service; it returns
+// nil when ERROR_REPORTER=none
+func New(cfg config.ErrorReportingConfig, service string) (Reporter, error) {
+	switch cfg.Reporter {
+	case ReporterNone, "":
+		return nil, nil
+	case ReporterSentry:
+		return NewSentry(cfg, service)
+	default:
+		return nil, fmt.Errorf("unknown ERROR_REPORTER %q (expected %s or %s)", cfg.Reporter, ReporterNone, ReporterSentry)
+	}
+}
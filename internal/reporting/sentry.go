@@ -0,0 +1,78 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"example.com/user/internal/config"
+	"example.com/user/internal/logging"
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/status"
+)
+
+// Sentry reports Events to Sentry. Events are sent in the background and tagged with the
+// call's method, request ID and trace ID; request and response messages are never sent.
+type Sentry struct {
+	client  *sentry.Client
+	service string
+}
+
+// NewSentry creates a Sentry reporter for the project of cfg.SentryDSN. The release is
+// taken from SENTRY_RELEASE or, failing that, the VCS revision the binary was built from.
+func NewSentry(cfg config.ErrorReportingConfig, service string) (*Sentry, error) {
+	if cfg.SentryDSN == "" {
+		return nil, errors.New("SENTRY_DSN is required when ERROR_REPORTER=sentry")
+	}
+	if cfg.SampleRate <= 0 || cfg.SampleRate > 1 {
+		return nil, fmt.Errorf("SENTRY_SAMPLE_RATE must be above 0 and at most 1, got %g", cfg.SampleRate)
+	}
+
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:         cfg.SentryDSN,
+		Environment: cfg.Environment,
+		SampleRate:  cfg.SampleRate,
+		// Panics with a string or other non-error value only carry a stack with this set
+		AttachStacktrace: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create Sentry client: %w", err)
+	}
+	return &Sentry{client: client, service: service}, nil
+}
+
+// Report queues e for sending. Panics are reported from the recovering goroutine, whose
+// stack still holds the panicking frames.
+func (s *Sentry) Report(ctx context.Context, e Event) {
+	scope := sentry.NewScope()
+	scope.SetTag("service", s.service)
+	scope.SetTag("grpc.method", e.Method)
+	scope.SetTag("grpc.code", status.Code(e.Err).String())
+	fields := logging.Fields(ctx)
+	for i := 0; i+1 < len(fields); i += 2 {
+		if key, ok := fields[i].(string); ok && key != "method" {
+			scope.SetTag(key, fmt.Sprint(fields[i+1]))
+		}
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		scope.SetTag("trace_id", sc.TraceID().String())
+	}
+
+	hub := sentry.NewHub(s.client, scope)
+	if e.Panic != nil {
+		hub.RecoverWithContext(ctx, e.Panic)
+		return
+	}
+	// Internal errors carry no stack, so group them by method and message
+	scope.SetFingerprint([]string{e.Method, status.Convert(e.Err).Message()})
+	hub.CaptureException(e.Err)
+}
+
+// Flush sends the queued reports
+func (s *Sentry) Flush(ctx context.Context) error {
+	if !s.client.FlushWithContext(ctx) {
+		return errors.New("timed out sending error reports to Sentry")
+	}
+	return nil
+}
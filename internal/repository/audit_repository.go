@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	"example.com/user/internal/models"
+)
+
+// defaultAuditPageSize applies when an AuditFilter sets no page size
+const defaultAuditPageSize = 50
+
+// AuditFilter selects audit entries; zero fields match everything
+type AuditFilter struct {
+	UserID    int32
+	Actor     string
+	PageSize  int
+	PageToken string
+}
+
+// AuditRepository is an append-only log of user changes. Entries can't be modified or
+// removed through it; the SQL backends also reject UPDATE and DELETE on the table.
+type AuditRepository interface {
+	// Append stores entry, assigning its ID
+	Append(entry *models.AuditEntry) error
+	// List returns entries matching filter, newest first, plus the token of the next
+	// page when more remain
+	List(filter AuditFilter) ([]*models.AuditEntry, string, error)
+}
+
+// auditPage is the page size and resume position parsed from an AuditFilter
+type auditPage struct {
+	size int
+	// before is the ID of the last entry already returned, 0 on the first page
+	before int64
+}
+
+func parseAuditFilter(filter AuditFilter) (auditPage, error) {
+	page := auditPage{size: filter.PageSize}
+	if page.size <= 0 {
+		page.size = defaultAuditPageSize
+	}
+	if page.size > maxPageSize {
+		page.size = maxPageSize
+	}
+	if filter.PageToken == "" {
+		return page, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(filter.PageToken)
+	if err != nil {
+		return auditPage{}, ErrInvalidPageToken
+	}
+	page.before, err = strconv.ParseInt(string(data), 10, 64)
+	if err != nil || page.before <= 0 {
+		return auditPage{}, ErrInvalidPageToken
+	}
+	return page, nil
+}
+
+// paginate trims entries fetched with one extra row (size+1) and returns the token of
+// the following page, or an empty token when this is the last page
+func (p auditPage) paginate(entries []*models.AuditEntry) ([]*models.AuditEntry, string) {
+	if len(entries) <= p.size {
+		return entries, ""
+	}
+
+	entries = entries[:p.size]
+	last := entries[p.size-1].ID
+	return entries, base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(last, 10)))
+}
+
+// InMemoryAuditRepository implements AuditRepository on top of the generic MemoryRepository
+type InMemoryAuditRepository struct {
+	store *MemoryRepository[models.AuditEntry, *models.AuditEntry, int64]
+}
+
+// NewInMemoryAuditRepository creates an empty in-memory audit log
+func NewInMemoryAuditRepository() *InMemoryAuditRepository {
+	return &InMemoryAuditRepository{
+		store: NewMemoryRepository[models.AuditEntry, *models.AuditEntry, int64](MemoryOptions[models.AuditEntry]{}),
+	}
+}
+
+func (r *InMemoryAuditRepository) Append(entry *models.AuditEntry) error {
+	if entry.Method == "" || entry.Actor == "" {
+		return ErrInvalidInput
+	}
+
+	entry.ID = 0
+	return r.store.Create(entry)
+}
+
+func (r *InMemoryAuditRepository) List(filter AuditFilter) ([]*models.AuditEntry, string, error) {
+	page, err := parseAuditFilter(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	matches := r.store.Find(func(e *models.AuditEntry) bool {
+		return (filter.UserID == 0 || e.UserID == filter.UserID) &&
+			(filter.Actor == "" || e.Actor == filter.Actor) &&
+			(page.before == 0 || e.ID < page.before)
+	})
+
+	// Find returns entries oldest first
+	entries := make([]*models.AuditEntry, 0, min(len(matches), page.size+1))
+	for i := len(matches) - 1; i >= 0 && len(entries) <= page.size; i-- {
+		entries = append(entries, matches[i])
+	}
+
+	entries, next := page.paginate(entries)
+	return entries, next, nil
+}
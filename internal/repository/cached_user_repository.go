@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"example.com/user/internal/cache"
+	"example.com/user/internal/models"
+	pb "example.com/user/proto"
+)
+
+// CachedUserRepository decorates another UserRepository with an in-process LRU cache
+// of users by ID. Entries expire after the TTL and are invalidated on every write made
+// through this repository; writes by other replicas are only picked up on expiry.
+type CachedUserRepository struct {
+	next  UserRepository
+	users *cache.LRU[int32, models.User]
+	// mu orders fills after invalidations; generation counts the invalidations, so a read
+	// that loaded a user before a write doesn't cache it once the write invalidated it
+	mu         sync.Mutex
+	generation uint64
+}
+
+// NewCachedUserRepository wraps next with a cache of up to size users, each valid for ttl
+func NewCachedUserRepository(next UserRepository, size int, ttl time.Duration) *CachedUserRepository {
+	return &CachedUserRepository{
+		next:  next,
+		users: cache.NewLRU[int32, models.User](size, ttl),
+	}
+}
+
+func (r *CachedUserRepository) GetByID(id int32) (*models.User, error) {
+	if user, ok := r.users.Get(id); ok {
+		return &user, nil
+	}
+
+	r.mu.Lock()
+	generation := r.generation
+	r.mu.Unlock()
+
+	user, err := r.next.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.generation == generation {
+		r.users.Set(id, *user)
+	}
+	return user, nil
+}
+
+func (r *CachedUserRepository) Create(user *models.User) error {
+	return r.next.Create(user)
+}
+
+func (r *CachedUserRepository) CreateMany(users []*models.User) error {
+	return r.next.CreateMany(users)
+}
+
+func (r *CachedUserRepository) Update(user *models.User) error {
+	// Invalidate even on failure: a version conflict means the cached copy is stale
+	defer r.invalidate(user.ID)
+	return r.next.Update(user)
+}
+
+func (r *CachedUserRepository) Delete(id int32) error {
+	defer r.invalidate(id)
+	return r.next.Delete(id)
+}
+
+func (r *CachedUserRepository) Undelete(id int32) error {
+	defer r.invalidate(id)
+	return r.next.Undelete(id)
+}
+
+func (r *CachedUserRepository) DeleteMany(ids []int32) error {
+	defer func() {
+		for _, id := range ids {
+			r.invalidate(id)
+		}
+	}()
+	return r.next.DeleteMany(ids)
+}
+
+func (r *CachedUserRepository) List(filter *pb.UserFilter) ([]*models.User, string, error) {
+	return r.next.List(filter)
+}
+
+func (r *CachedUserRepository) Count(filter *pb.UserFilter) (int, error) {
+	return r.next.Count(filter)
+}
+
+func (r *CachedUserRepository) EmailExists(email string) bool {
+	return r.next.EmailExists(email)
+}
+
+// WithTx bypasses the cache inside the transaction, then invalidates every user
+// updated or deleted inside it
+func (r *CachedUserRepository) WithTx(ctx context.Context, fn func(repo UserRepository) error) error {
+	var touched []int32
+	err := r.next.WithTx(ctx, func(tx UserRepository) error {
+		return fn(&invalidationRecorder{UserRepository: tx, touched: &touched})
+	})
+
+	for _, id := range touched {
+		r.invalidate(id)
+	}
+	return err
+}
+
+// invalidate drops the cached copy of user id, and keeps reads that loaded it before from
+// caching theirs
+func (r *CachedUserRepository) invalidate(id int32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	r.users.Delete(id)
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"example.com/user/internal/models"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// pausingRepository holds up the next GetByID after loading the user, until resumed, so a
+// write can land between a cache miss and the fill
+type pausingRepository struct {
+	UserRepository
+	loaded chan struct{}
+	resume chan struct{}
+}
+
+func (r *pausingRepository) GetByID(id int32) (*models.User, error) {
+	user, err := r.UserRepository.GetByID(id)
+	select {
+	case r.loaded <- struct{}{}:
+		<-r.resume
+	default:
+	}
+	return user, err
+}
+
+// cachedRepositories builds each cache decorator over next
+var cachedRepositories = map[string]func(t *testing.T, next UserRepository) UserRepository{
+	"lru": func(t *testing.T, next UserRepository) UserRepository {
+		return NewCachedUserRepository(next, 16, time.Hour)
+	},
+	"redis": func(t *testing.T, next UserRepository) UserRepository {
+		client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+		t.Cleanup(func() { client.Close() })
+		// No TTL, so a stale entry would never go away
+		return NewRedisCachedUserRepository(next, client, RedisCacheOptions{})
+	},
+}
+
+func TestCachedReadDuringWrite(t *testing.T) {
+	writes := []struct {
+		name  string
+		write func(repo UserRepository) error
+		check func(t *testing.T, user *models.User, err error)
+	}{
+		{
+			name: "update",
+			write: func(repo UserRepository) error {
+				user, err := repo.GetByID(1)
+				if err != nil {
+					return err
+				}
+				user.Name = "Renamed"
+				return repo.Update(user)
+			},
+			check: func(t *testing.T, user *models.User, err error) {
+				if err != nil || user.Name != "Renamed" || user.Version != 2 {
+					t.Errorf("GetByID after update = %+v, %v; want version 2 named Renamed", user, err)
+				}
+			},
+		},
+		{
+			name:  "delete",
+			write: func(repo UserRepository) error { return repo.Delete(1) },
+			check: func(t *testing.T, user *models.User, err error) {
+				if err != ErrUserNotFound {
+					t.Errorf("GetByID after delete = %+v, %v; want ErrUserNotFound", user, err)
+				}
+			},
+		},
+	}
+	for backend, newCached := range cachedRepositories {
+		for _, tt := range writes {
+			t.Run(backend+"/"+tt.name, func(t *testing.T) {
+				store := NewInMemoryUserRepository(SampleUsers())
+				pausing := &pausingRepository{UserRepository: store, loaded: make(chan struct{}), resume: make(chan struct{})}
+				cached := newCached(t, pausing)
+
+				// A read misses the cache and loads the user as it is before the write...
+				read := make(chan struct{})
+				go func() {
+					defer close(read)
+					cached.GetByID(1)
+				}()
+				<-pausing.loaded
+
+				// ...which then commits and invalidates, before the read fills the cache
+				if err := tt.write(cached); err != nil {
+					t.Fatal(err)
+				}
+				close(pausing.resume)
+				<-read
+
+				user, err := cached.GetByID(1)
+				tt.check(t, user, err)
+			})
+		}
+	}
+}
+
+func TestCachedFailedWriteInvalidates(t *testing.T) {
+	for backend, newCached := range cachedRepositories {
+		t.Run(backend, func(t *testing.T) {
+			store := NewInMemoryUserRepository(SampleUsers())
+			cached := newCached(t, store)
+			if _, err := cached.GetByID(2); err != nil {
+				t.Fatal(err)
+			}
+
+			// Another replica renames the user behind the cache's back
+			user, _ := store.GetByID(2)
+			user.Name = "Elsewhere"
+			if err := store.Update(user); err != nil {
+				t.Fatal(err)
+			}
+
+			stale, _ := cached.GetByID(2)
+			stale.Name = "Here"
+			if err := cached.Update(stale); err != ErrVersionConflict {
+				t.Fatalf("Update from a stale copy = %v, want ErrVersionConflict", err)
+			}
+			fresh, err := cached.GetByID(2)
+			if err != nil || fresh.Name != "Elsewhere" {
+				t.Errorf("GetByID after a conflict = %+v, %v; want the user named Elsewhere", fresh, err)
+			}
+		})
+	}
+}
+
+// countingRepository counts the GetByID calls reaching the repository it wraps
+type countingRepository struct {
+	UserRepository
+	gets int
+}
+
+func (r *countingRepository) GetByID(id int32) (*models.User, error) {
+	r.gets++
+	return r.UserRepository.GetByID(id)
+}
+
+func TestCachedWritesInvalidate(t *testing.T) {
+	writes := []struct {
+		name  string
+		write func(repo UserRepository) error
+	}{
+		{"update", func(repo UserRepository) error {
+			user, err := repo.GetByID(2)
+			if err != nil {
+				return err
+			}
+			user.Name = "Renamed"
+			return repo.Update(user)
+		}},
+		{"delete", func(repo UserRepository) error { return repo.Delete(2) }},
+		{"delete many", func(repo UserRepository) error { return repo.DeleteMany([]int32{3, 2}) }},
+		{"transaction", func(repo UserRepository) error {
+			return repo.WithTx(context.Background(), func(tx UserRepository) error { return tx.Delete(2) })
+		}},
+	}
+	for backend, newCached := range cachedRepositories {
+		for _, tt := range writes {
+			t.Run(backend+"/"+tt.name, func(t *testing.T) {
+				store := &countingRepository{UserRepository: NewInMemoryUserRepository(SampleUsers())}
+				cached := newCached(t, store)
+
+				// A second read is served from the cache
+				cached.GetByID(2)
+				cached.GetByID(2)
+				if store.gets > 1 {
+					t.Fatalf("%d reads reached the repository, want 1", store.gets)
+				}
+
+				if err := tt.write(cached); err != nil {
+					t.Fatal(err)
+				}
+				gets := store.gets
+				cached.GetByID(2)
+				if store.gets != gets+1 {
+					t.Errorf("a read after the %s was served from the cache", tt.name)
+				}
+			})
+		}
+	}
+}
+
+func TestCachedUserExpires(t *testing.T) {
+	store := &countingRepository{UserRepository: NewInMemoryUserRepository(SampleUsers())}
+	cached := NewCachedUserRepository(store, 16, 20*time.Millisecond)
+
+	cached.GetByID(1)
+	cached.GetByID(1)
+	time.Sleep(40 * time.Millisecond)
+	cached.GetByID(1)
+	if store.gets != 2 {
+		t.Errorf("%d reads reached the repository, want 2: the first and the one after expiry", store.gets)
+	}
+}
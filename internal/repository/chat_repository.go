@@ -0,0 +1,121 @@
+package repository
+
+import "example.com/user/internal/models"
+
+// defaultChatHistoryLimit applies when a ChatFilter sets no limit
+const defaultChatHistoryLimit = 50
+
+// ChatFilter selects the messages of one conversation: those sent to Room, else the
+// direct messages between User and Peer both ways, else those sent to everyone
+type ChatFilter struct {
+	Room   string
+	User   string
+	Peer   string
+	Before int64 // Only messages with a lower ID when set
+	Limit  int
+}
+
+// ChatRepository keeps the history of chat messages
+type ChatRepository interface {
+	// Append stores msg, assigning its ID
+	Append(msg *models.ChatMessage) error
+	// History returns the latest messages matching filter, newest first, and whether
+	// older ones remain
+	History(filter ChatFilter) ([]*models.ChatMessage, bool, error)
+}
+
+// limit is the number of messages filter asks for, within bounds
+func (f ChatFilter) limit() int {
+	switch {
+	case f.Limit <= 0:
+		return defaultChatHistoryLimit
+	case f.Limit > maxPageSize:
+		return maxPageSize
+	}
+	return f.Limit
+}
+
+// matches reports whether msg belongs to the conversation of f, before f.Before
+func (f ChatFilter) matches(msg *models.ChatMessage) bool {
+	switch {
+	case f.Before != 0 && msg.ID >= f.Before:
+		return false
+	case f.Room != "":
+		return msg.Room == f.Room
+	case f.Peer != "":
+		return msg.Room == "" &&
+			(msg.From == f.User && msg.To == f.Peer || msg.From == f.Peer && msg.To == f.User)
+	}
+	return msg.Room == "" && msg.To == ""
+}
+
+// whereChat adds the conditions of filter on the chat_messages table
+func (q *sqlQuery) whereChat(filter ChatFilter) {
+	switch {
+	case filter.Room != "":
+		q.where("room = " + q.arg(filter.Room))
+	case filter.Peer != "":
+		q.where("room = ''")
+		q.where("((sender = " + q.arg(filter.User) + " AND recipient = " + q.arg(filter.Peer) + ") OR (sender = " +
+			q.arg(filter.Peer) + " AND recipient = " + q.arg(filter.User) + "))")
+	default:
+		q.where("room = ''")
+		q.where("recipient = ''")
+	}
+	if filter.Before != 0 {
+		q.where("id < " + q.arg(filter.Before))
+	}
+}
+
+// chatHistoryQuery is the query of History on the SQL backends, fetching one message
+// more than asked for to learn whether older ones remain
+func chatHistoryQuery(dialect sqlDialect, filter ChatFilter) (string, []any) {
+	q := sqlQuery{dialect: dialect}
+	q.whereChat(filter)
+	query := "SELECT id, sender, recipient, room, message, type, created_at FROM chat_messages" + q.clause() +
+		" ORDER BY id DESC LIMIT " + q.arg(filter.limit()+1)
+	return query, q.args
+}
+
+// trimHistory drops the extra message fetched by History, reporting whether there was one
+func trimHistory(messages []*models.ChatMessage, filter ChatFilter) ([]*models.ChatMessage, bool) {
+	if len(messages) <= filter.limit() {
+		return messages, false
+	}
+	return messages[:filter.limit()], true
+}
+
+// InMemoryChatRepository implements ChatRepository on top of the generic MemoryRepository
+type InMemoryChatRepository struct {
+	store *MemoryRepository[models.ChatMessage, *models.ChatMessage, int64]
+}
+
+// NewInMemoryChatRepository creates an empty in-memory chat history
+func NewInMemoryChatRepository() *InMemoryChatRepository {
+	return &InMemoryChatRepository{
+		store: NewMemoryRepository[models.ChatMessage, *models.ChatMessage, int64](MemoryOptions[models.ChatMessage]{}),
+	}
+}
+
+func (r *InMemoryChatRepository) Append(msg *models.ChatMessage) error {
+	if msg.From == "" {
+		return ErrInvalidInput
+	}
+
+	msg.ID = 0
+	return r.store.Create(msg)
+}
+
+func (r *InMemoryChatRepository) History(filter ChatFilter) ([]*models.ChatMessage, bool, error) {
+	matches := r.store.Find(filter.matches)
+
+	// Find returns messages oldest first
+	limit := filter.limit()
+	messages := make([]*models.ChatMessage, 0, min(len(matches), limit+1))
+	for i := len(matches) - 1; i >= 0 && len(messages) <= limit; i-- {
+		messages = append(messages, matches[i])
+	}
+
+	messages, more := trimHistory(messages, filter)
+	return messages, more, nil
+}
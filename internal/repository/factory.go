@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"time"
+
+	"example.com/user/internal/config"
+	"example.com/user/internal/metrics"
+	"example.com/user/internal/migrations"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/redis/go-redis/v9"
+)
+
+// Supported values for STORAGE_BACKEND
+const (
+	BackendMemory   = "memory"
+	BackendPostgres = "postgres"
+	BackendSQLite   = "sqlite"
+)
+
+// storageConnectTimeout bounds backend connection and schema bootstrap at startup
+const storageConnectTimeout = 10 * time.Second
+
+// Store bundles the repositories built for the configured storage backend
+type Store struct {
+	Users UserRepository
+	// Tokens stores refresh tokens in the same backend as Users
+	Tokens TokenRepository
+	// Audit is the append-only log of user changes, in the same backend as Users
+	Audit AuditRepository
+	// Preferences stores the preferences of users, in the same backend as Users
+	Preferences PreferencesRepository
+	// Chats keeps the chat history, in the same backend as Users
+	Chats ChatRepository
+	// Hooks lets cross-cutting features subscribe to writes made through Users
+	Hooks   *Hooks
+	closers []func() error
+	// snapshot saves the in-memory backend to SNAPSHOT_FILE; nil when snapshots are off
+	snapshot func() (int, error)
+	// ready checks that the backend can serve requests; nil when it always can
+	ready func(ctx context.Context) error
+}
+
+// Ready reports whether the backend can serve requests: a SQL database must be reachable
+// and have every schema migration applied. The in-memory backend is always ready.
+func (s *Store) Ready(ctx context.Context) error {
+	if s.ready == nil {
+		return nil
+	}
+	return s.ready(ctx)
+}
+
+// Snapshot saves the in-memory backend to the configured SNAPSHOT_FILE and returns the
+// number of users written, or ErrSnapshotDisabled for other configurations
+func (s *Store) Snapshot() (int, error) {
+	if s.snapshot == nil {
+		return 0, ErrSnapshotDisabled
+	}
+	return s.snapshot()
+}
+
+// SnapshotEnabled reports whether Snapshot can be used
+func (s *Store) SnapshotEnabled() bool {
+	return s.snapshot != nil
+}
+
+// Close releases every resource opened by the backend, most recently opened first
+func (s *Store) Close() error {
+	var firstErr error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		if err := s.closers[i](); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	s.closers = nil
+	return firstErr
+}
+
+// readinessCheck returns a check that pings a SQL backend and verifies that its schema is
+// fully migrated, which matters when STORAGE_AUTO_MIGRATE is off
+func readinessCheck(ping func(ctx context.Context) error, db *sql.DB, dialect string) (func(ctx context.Context) error, error) {
+	migrator, err := migrations.New(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context) error {
+		if err := ping(ctx); err != nil {
+			return fmt.Errorf("ping database: %w", err)
+		}
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return fmt.Errorf("read migration status: %w", err)
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d schema migration(s) pending", pending)
+		}
+		return nil
+	}, nil
+}
+
+// Open builds the repositories for the backend selected in cfg, applying pending
+// schema migrations first when cfg.AutoMigrate is set. When m is non-nil, calls
+// reaching the backend (cache misses included, cache hits excluded) are recorded in it.
+func Open(cfg config.StorageConfig, m *metrics.Repository) (*Store, error) {
+	store := &Store{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), storageConnectTimeout)
+	defer cancel()
+
+	if cfg.AutoMigrate && (cfg.Backend == BackendPostgres || cfg.Backend == BackendSQLite) {
+		if err := migrateUp(ctx, cfg); err != nil {
+			return nil, fmt.Errorf("migrate schema: %w", err)
+		}
+	}
+
+	seed := SampleUsers()
+	if cfg.SeedFile != "" {
+		var err error
+		if seed, err = LoadSeedFile(cfg.SeedFile); err != nil {
+			return nil, err
+		}
+	}
+
+	switch cfg.Backend {
+	case BackendMemory, "":
+		cfg.Backend = BackendMemory
+		repo := NewInMemoryUserRepository(seed)
+		if cfg.SnapshotFile != "" {
+			// A missing snapshot just means a first start, served from the seed
+			n, err := repo.LoadSnapshot(cfg.SnapshotFile)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+			if err == nil {
+				slog.Info("Restored users from snapshot", "count", n, "file", cfg.SnapshotFile)
+			}
+			store.snapshot = func() (int, error) { return repo.SaveSnapshot(cfg.SnapshotFile) }
+		}
+		store.Users = repo
+		store.Tokens = NewInMemoryTokenRepository()
+		store.Audit = NewInMemoryAuditRepository()
+		store.Preferences = NewInMemoryPreferencesRepository()
+		store.Chats = NewInMemoryChatRepository()
+
+	case BackendPostgres:
+		repo, err := NewPostgresUserRepository(ctx, cfg.PostgresDSN)
+		if err != nil {
+			return nil, err
+		}
+		store.Users = repo
+		store.Tokens = NewPostgresTokenRepository(repo)
+		store.Audit = NewPostgresAuditRepository(repo)
+		store.Preferences = NewPostgresPreferencesRepository(repo)
+		store.Chats = NewPostgresChatRepository(repo)
+		store.closers = append(store.closers, func() error {
+			repo.Close()
+			return nil
+		})
+		// Migration status is read through database/sql, sharing the repository's pool
+		db := stdlib.OpenDBFromPool(repo.pool)
+		store.closers = append(store.closers, db.Close)
+		if store.ready, err = readinessCheck(repo.pool.Ping, db, migrations.DialectPostgres); err != nil {
+			store.Close()
+			return nil, err
+		}
+
+	case BackendSQLite:
+		repo, err := NewSQLiteUserRepository(cfg.SQLitePath)
+		if err != nil {
+			return nil, err
+		}
+		store.Users = repo
+		store.Tokens = NewSQLiteTokenRepository(repo)
+		store.Audit = NewSQLiteAuditRepository(repo)
+		store.Preferences = NewSQLitePreferencesRepository(repo)
+		store.Chats = NewSQLiteChatRepository(repo)
+		store.closers = append(store.closers, repo.Close)
+		if store.ready, err = readinessCheck(repo.conn.PingContext, repo.conn, migrations.DialectSQLite); err != nil {
+			store.Close()
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (expected %s, %s or %s)",
+			cfg.Backend, BackendMemory, BackendPostgres, BackendSQLite)
+	}
+
+	// SQL backends are only seeded from an explicit file, and only when empty
+	if cfg.SeedFile != "" && cfg.Backend != BackendMemory {
+		seeded, err := seedIfEmpty(store.Users, seed)
+		if err != nil {
+			store.Close()
+			return nil, fmt.Errorf("seed %s storage: %w", cfg.Backend, err)
+		}
+		if seeded {
+			slog.Info("Seeded users", "count", len(seed), "file", cfg.SeedFile)
+		}
+	}
+
+	if m != nil {
+		store.Users = NewInstrumentedUserRepository(store.Users, cfg.Backend, m)
+	}
+
+	if cfg.RedisAddr != "" {
+		client := redis.NewClient(&redis.Options{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		})
+		cached := NewRedisCachedUserRepository(store.Users, client, RedisCacheOptions{TTL: cfg.RedisCacheTTL})
+		store.Users = cached
+		store.closers = append(store.closers, cached.Close)
+	}
+
+	if cfg.CacheSize > 0 {
+		store.Users = NewCachedUserRepository(store.Users, cfg.CacheSize, cfg.CacheTTL)
+	}
+
+	store.Hooks = NewHooks()
+	store.Users = NewHookedUserRepository(store.Users, store.Hooks)
+
+	return store, nil
+}
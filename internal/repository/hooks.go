@@ -0,0 +1,312 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"example.com/user/internal/models"
+	pb "example.com/user/proto"
+)
+
+// EventType identifies the kind of change an Event reports
+type EventType int
+
+const (
+	EventCreated EventType = iota + 1
+	EventUpdated
+	EventDeleted
+	EventUndeleted
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventCreated:
+		return "created"
+	case EventUpdated:
+		return "updated"
+	case EventDeleted:
+		return "deleted"
+	case EventUndeleted:
+		return "undeleted"
+	}
+	return "unknown"
+}
+
+// Event describes a committed change to a user
+type Event struct {
+	Type EventType
+	// User is the state after the change
+	User *models.User
+	// Previous is the state before the change; nil for creations
+	Previous *models.User
+	At       time.Time
+}
+
+// Hook receives repository events. Hooks run synchronously after the change is
+// committed, so they should be quick and hand slow work off to a goroutine.
+type Hook func(Event)
+
+// Hooks is a registry of subscribers to repository events
+type Hooks struct {
+	mu     sync.RWMutex
+	hooks  map[EventType][]subscription
+	nextID uint64
+}
+
+// subscription is a hook with the ID that unsubscribes it
+type subscription struct {
+	id uint64
+	fn Hook
+}
+
+// NewHooks creates an empty hook registry
+func NewHooks() *Hooks {
+	return &Hooks{hooks: make(map[EventType][]subscription)}
+}
+
+// OnCreate subscribes fn to user creations, until the returned func is called
+func (h *Hooks) OnCreate(fn Hook) (unsubscribe func()) {
+	return h.subscribe(fn, EventCreated)
+}
+
+// OnUpdate subscribes fn to user updates, including restores by Undelete, until the
+// returned func is called
+func (h *Hooks) OnUpdate(fn Hook) (unsubscribe func()) {
+	return h.subscribe(fn, EventUpdated, EventUndeleted)
+}
+
+// OnDelete subscribes fn to user deletions, until the returned func is called
+func (h *Hooks) OnDelete(fn Hook) (unsubscribe func()) {
+	return h.subscribe(fn, EventDeleted)
+}
+
+// Subscribe subscribes fn to every event, until the returned func is called
+func (h *Hooks) Subscribe(fn Hook) (unsubscribe func()) {
+	return h.subscribe(fn, EventCreated, EventUpdated, EventDeleted, EventUndeleted)
+}
+
+func (h *Hooks) subscribe(fn Hook, types ...EventType) func() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.nextID++
+	id := h.nextID
+	for _, t := range types {
+		h.hooks[t] = append(h.hooks[t], subscription{id: id, fn: fn})
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { h.unsubscribe(id, types) })
+	}
+}
+
+func (h *Hooks) unsubscribe(id uint64, types []EventType) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, t := range types {
+		// Copy rather than edit in place, as emit may be ranging over the old slice
+		h.hooks[t] = slices.DeleteFunc(slices.Clone(h.hooks[t]), func(s subscription) bool { return s.id == id })
+	}
+}
+
+// has reports whether anyone listens to t, so callers can skip building the event
+func (h *Hooks) has(t EventType) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.hooks[t]) > 0
+}
+
+// emit delivers e to its subscribers, isolating the caller from hook panics
+func (h *Hooks) emit(e Event) {
+	h.mu.RLock()
+	hooks := h.hooks[e.Type]
+	h.mu.RUnlock()
+
+	for _, hook := range hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					slog.Error("Repository hook panicked", "event", e.Type.String(), "user_id", e.User.ID, "panic", r)
+				}
+			}()
+			hook.fn(e)
+		}()
+	}
+}
+
+// HookedUserRepository decorates a UserRepository, publishing an Event to its Hooks
+// after every successful write. Inside WithTx, events are held back until commit.
+type HookedUserRepository struct {
+	next  UserRepository
+	hooks *Hooks
+	// pending collects events raised inside a transaction; nil outside one
+	pending *[]Event
+}
+
+// NewHookedUserRepository wraps next so that writes are published to hooks
+func NewHookedUserRepository(next UserRepository, hooks *Hooks) *HookedUserRepository {
+	return &HookedUserRepository{next: next, hooks: hooks}
+}
+
+func (r *HookedUserRepository) GetByID(id int32) (*models.User, error) {
+	return r.next.GetByID(id)
+}
+
+func (r *HookedUserRepository) Create(user *models.User) error {
+	if err := r.next.Create(user); err != nil {
+		return err
+	}
+
+	if r.hooks.has(EventCreated) {
+		created := *user
+		r.publish(Event{Type: EventCreated, User: &created})
+	}
+	return nil
+}
+
+func (r *HookedUserRepository) CreateMany(users []*models.User) error {
+	if err := r.next.CreateMany(users); err != nil {
+		return err
+	}
+
+	if r.hooks.has(EventCreated) {
+		for _, user := range users {
+			created := *user
+			r.publish(Event{Type: EventCreated, User: &created})
+		}
+	}
+	return nil
+}
+
+func (r *HookedUserRepository) Update(user *models.User) error {
+	previous := r.previous(EventUpdated, user.ID)
+
+	if err := r.next.Update(user); err != nil {
+		return err
+	}
+
+	if r.hooks.has(EventUpdated) {
+		updated := *user
+		r.publish(Event{Type: EventUpdated, User: &updated, Previous: previous})
+	}
+	return nil
+}
+
+func (r *HookedUserRepository) Delete(id int32) error {
+	previous := r.previous(EventDeleted, id)
+
+	if err := r.next.Delete(id); err != nil {
+		return err
+	}
+
+	if previous != nil {
+		deleted := *previous
+		now := time.Now()
+		deleted.DeletedAt = &now
+		r.publish(Event{Type: EventDeleted, User: &deleted, Previous: previous})
+	}
+	return nil
+}
+
+func (r *HookedUserRepository) DeleteMany(ids []int32) error {
+	previous := make(map[int32]*models.User, len(ids))
+	for _, id := range ids {
+		if user := r.previous(EventDeleted, id); user != nil {
+			previous[id] = user
+		}
+	}
+
+	if err := r.next.DeleteMany(ids); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, id := range ids {
+		user, ok := previous[id]
+		if !ok {
+			continue
+		}
+		// Repeated IDs are only deleted, and published, once
+		delete(previous, id)
+
+		deleted := *user
+		deleted.DeletedAt = &now
+		r.publish(Event{Type: EventDeleted, User: &deleted, Previous: user})
+	}
+	return nil
+}
+
+func (r *HookedUserRepository) Undelete(id int32) error {
+	if err := r.next.Undelete(id); err != nil {
+		return err
+	}
+
+	if r.hooks.has(EventUndeleted) {
+		if restored, err := r.next.GetByID(id); err == nil {
+			r.publish(Event{Type: EventUndeleted, User: restored})
+		}
+	}
+	return nil
+}
+
+func (r *HookedUserRepository) List(filter *pb.UserFilter) ([]*models.User, string, error) {
+	return r.next.List(filter)
+}
+
+func (r *HookedUserRepository) Count(filter *pb.UserFilter) (int, error) {
+	return r.next.Count(filter)
+}
+
+func (r *HookedUserRepository) EmailExists(email string) bool {
+	return r.next.EmailExists(email)
+}
+
+func (r *HookedUserRepository) WithTx(ctx context.Context, fn func(repo UserRepository) error) error {
+	// Nested transactions share the outermost buffer
+	if r.pending != nil {
+		return r.next.WithTx(ctx, func(tx UserRepository) error {
+			return fn(&HookedUserRepository{next: tx, hooks: r.hooks, pending: r.pending})
+		})
+	}
+
+	var pending []Event
+	err := r.next.WithTx(ctx, func(tx UserRepository) error {
+		return fn(&HookedUserRepository{next: tx, hooks: r.hooks, pending: &pending})
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, e := range pending {
+		r.hooks.emit(e)
+	}
+	return nil
+}
+
+// previous loads the state of a user before a change, only when someone will see it
+func (r *HookedUserRepository) previous(t EventType, id int32) *models.User {
+	if !r.hooks.has(t) {
+		return nil
+	}
+	user, err := r.next.GetByID(id)
+	if err != nil {
+		return nil
+	}
+	return user
+}
+
+// publish emits e now, or queues it until the enclosing transaction commits
+func (r *HookedUserRepository) publish(e Event) {
+	e.At = time.Now()
+	if r.pending != nil {
+		*r.pending = append(*r.pending, e)
+		return
+	}
+	r.hooks.emit(e)
+}
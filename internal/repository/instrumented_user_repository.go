@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"example.com/user/internal/metrics"
+	"example.com/user/internal/models"
+	pb "example.com/user/proto"
+)
+
+// InstrumentedUserRepository decorates a UserRepository, recording the count, errors
+// and latency of every call under the backend's name
+type InstrumentedUserRepository struct {
+	next    UserRepository
+	backend string
+	metrics *metrics.Repository
+}
+
+// NewInstrumentedUserRepository wraps next so its calls are recorded in m
+func NewInstrumentedUserRepository(next UserRepository, backend string, m *metrics.Repository) *InstrumentedUserRepository {
+	return &InstrumentedUserRepository{next: next, backend: backend, metrics: m}
+}
+
+func (r *InstrumentedUserRepository) GetByID(id int32) (user *models.User, err error) {
+	defer r.observe("get_by_id", time.Now(), &err)
+	return r.next.GetByID(id)
+}
+
+func (r *InstrumentedUserRepository) Create(user *models.User) (err error) {
+	defer r.observe("create", time.Now(), &err)
+	return r.next.Create(user)
+}
+
+func (r *InstrumentedUserRepository) CreateMany(users []*models.User) (err error) {
+	defer r.observe("create_many", time.Now(), &err)
+	return r.next.CreateMany(users)
+}
+
+func (r *InstrumentedUserRepository) Update(user *models.User) (err error) {
+	defer r.observe("update", time.Now(), &err)
+	return r.next.Update(user)
+}
+
+func (r *InstrumentedUserRepository) Delete(id int32) (err error) {
+	defer r.observe("delete", time.Now(), &err)
+	return r.next.Delete(id)
+}
+
+func (r *InstrumentedUserRepository) Undelete(id int32) (err error) {
+	defer r.observe("undelete", time.Now(), &err)
+	return r.next.Undelete(id)
+}
+
+func (r *InstrumentedUserRepository) DeleteMany(ids []int32) (err error) {
+	defer r.observe("delete_many", time.Now(), &err)
+	return r.next.DeleteMany(ids)
+}
+
+func (r *InstrumentedUserRepository) List(filter *pb.UserFilter) (users []*models.User, next string, err error) {
+	defer r.observe("list", time.Now(), &err)
+	return r.next.List(filter)
+}
+
+func (r *InstrumentedUserRepository) Count(filter *pb.UserFilter) (n int, err error) {
+	defer r.observe("count", time.Now(), &err)
+	return r.next.Count(filter)
+}
+
+func (r *InstrumentedUserRepository) EmailExists(email string) bool {
+	defer r.observe("email_exists", time.Now(), new(error))
+	return r.next.EmailExists(email)
+}
+
+// WithTx records the whole transaction as well as each call made inside it
+func (r *InstrumentedUserRepository) WithTx(ctx context.Context, fn func(repo UserRepository) error) (err error) {
+	defer r.observe("with_tx", time.Now(), &err)
+	return r.next.WithTx(ctx, func(tx UserRepository) error {
+		return fn(&InstrumentedUserRepository{next: tx, backend: r.backend, metrics: r.metrics})
+	})
+}
+
+// observe is deferred with a pointer to the named result so it sees the final error
+func (r *InstrumentedUserRepository) observe(operation string, start time.Time, err *error) {
+	r.metrics.Observe(r.backend, operation, start, *err)
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"example.com/user/internal/config"
+	"example.com/user/internal/migrations"
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" database/sql driver
+)
+
+// OpenMigrator connects to the SQL backend selected in cfg and returns a schema migrator
+// for it, together with a function that closes the connection
+func OpenMigrator(cfg config.StorageConfig) (*migrations.Migrator, func() error, error) {
+	var db *sql.DB
+	var dialect string
+	var err error
+
+	switch cfg.Backend {
+	case BackendPostgres:
+		dialect = migrations.DialectPostgres
+		db, err = sql.Open("pgx", cfg.PostgresDSN)
+	case BackendSQLite:
+		dialect = migrations.DialectSQLite
+		db, err = openSQLite(cfg.SQLitePath)
+	default:
+		return nil, nil, fmt.Errorf("storage backend %q has no schema migrations", cfg.Backend)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	migrator, err := migrations.New(db, dialect)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+
+	return migrator, db.Close, nil
+}
+
+// migrateUp applies pending migrations for SQL backends before their repositories are opened
+func migrateUp(ctx context.Context, cfg config.StorageConfig) error {
+	migrator, closeDB, err := OpenMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return err
+	}
+	if applied > 0 {
+		slog.Info("Applied schema migrations", "count", applied, "backend", cfg.Backend)
+	}
+
+	return nil
+}
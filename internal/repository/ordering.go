@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"cmp"
+	"strings"
+	"time"
+
+	"example.com/user/internal/models"
+)
+
+// Sortable user fields accepted in UserFilter.order_by
+const (
+	OrderByID        = "id"
+	OrderByName      = "name"
+	OrderByEmail     = "email"
+	OrderByCreatedAt = "created_at"
+)
+
+// userOrder is a parsed UserFilter.order_by. Ties are always broken by ID in the
+// same direction, which keeps the ordering total and page cursors stable.
+type userOrder struct {
+	Field string
+	Desc  bool
+}
+
+// parseOrderBy parses "field [asc|desc]", defaulting to ascending ID order
+func parseOrderBy(orderBy string) (userOrder, error) {
+	parts := strings.Fields(strings.ToLower(orderBy))
+	if len(parts) == 0 {
+		return userOrder{Field: OrderByID}, nil
+	}
+	if len(parts) > 2 {
+		return userOrder{}, ErrInvalidOrderBy
+	}
+
+	order := userOrder{Field: parts[0]}
+	switch order.Field {
+	case OrderByID, OrderByName, OrderByEmail, OrderByCreatedAt:
+	default:
+		return userOrder{}, ErrInvalidOrderBy
+	}
+
+	if len(parts) == 2 {
+		switch parts[1] {
+		case "asc":
+		case "desc":
+			order.Desc = true
+		default:
+			return userOrder{}, ErrInvalidOrderBy
+		}
+	}
+
+	return order, nil
+}
+
+// String renders the canonical form, used to tie page tokens to their ordering
+func (o userOrder) String() string {
+	if o.Desc {
+		return o.Field + " desc"
+	}
+	return o.Field + " asc"
+}
+
+// compare orders two users, returning a negative number when a sorts first
+func (o userOrder) compare(a, b *models.User) int {
+	var c int
+	switch o.Field {
+	case OrderByName:
+		c = strings.Compare(a.Name, b.Name)
+	case OrderByEmail:
+		c = strings.Compare(a.Email, b.Email)
+	case OrderByCreatedAt:
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	}
+	if c == 0 {
+		c = cmp.Compare(a.ID, b.ID)
+	}
+	if o.Desc {
+		c = -c
+	}
+	return c
+}
+
+// value returns the sort key of user as carried in page tokens
+func (o userOrder) value(user *models.User) string {
+	switch o.Field {
+	case OrderByName:
+		return user.Name
+	case OrderByEmail:
+		return user.Email
+	case OrderByCreatedAt:
+		return user.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return ""
+}
+
+// cursorUser rebuilds the sort key of the last user of a page so it can be compared
+func (o userOrder) cursorUser(c pageCursor) (*models.User, error) {
+	user := &models.User{ID: c.LastID}
+	switch o.Field {
+	case OrderByName:
+		user.Name = c.LastValue
+	case OrderByEmail:
+		user.Email = c.LastValue
+	case OrderByCreatedAt:
+		createdAt, err := time.Parse(time.RFC3339Nano, c.LastValue)
+		if err != nil {
+			return nil, ErrInvalidPageToken
+		}
+		user.CreatedAt = createdAt
+	}
+	return user, nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"example.com/user/internal/migrations"
+	"example.com/user/internal/models"
+	pb "example.com/user/proto"
+)
+
+// orderingUsers are numbered 1 to 4, with names, emails and creation times each sorting
+// differently from the IDs, and a name shared by two users to exercise the ID tie-break
+func orderingUsers() []*models.User {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []*models.User{
+		{Name: "Carol", Email: "b@example.com", Role: models.RoleUser, CreatedAt: base.Add(2 * time.Hour), Version: 1},
+		{Name: "Alice", Email: "d@example.com", Role: models.RoleUser, CreatedAt: base.Add(3 * time.Hour), Version: 1},
+		{Name: "Bob", Email: "a@example.com", Role: models.RoleUser, CreatedAt: base, Version: 1},
+		{Name: "Alice", Email: "c@example.com", Role: models.RoleUser, CreatedAt: base.Add(time.Hour), Version: 1},
+	}
+}
+
+// listRepositories builds each backend whose List orders and pages on its own, holding users
+var listRepositories = map[string]func(t *testing.T, users []*models.User) UserRepository{
+	"memory": func(t *testing.T, users []*models.User) UserRepository {
+		return NewInMemoryUserRepository(users)
+	},
+	"sqlite": func(t *testing.T, users []*models.User) UserRepository {
+		repo, err := NewSQLiteUserRepository(filepath.Join(t.TempDir(), "users.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { repo.Close() })
+
+		migrator, err := migrations.New(repo.conn, migrations.DialectSQLite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := migrator.Up(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		for _, user := range users {
+			if err := repo.Create(user); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return repo
+	},
+}
+
+func ids(users []*models.User) []int32 {
+	var ids []int32
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
+
+func TestParseOrderBy(t *testing.T) {
+	tests := []struct {
+		orderBy string
+		want    userOrder
+		wantErr bool
+	}{
+		{"", userOrder{Field: OrderByID}, false},
+		{"   ", userOrder{Field: OrderByID}, false},
+		{"name", userOrder{Field: OrderByName}, false},
+		{"NAME DESC", userOrder{Field: OrderByName, Desc: true}, false},
+		{"email asc", userOrder{Field: OrderByEmail}, false},
+		{" created_at  desc ", userOrder{Field: OrderByCreatedAt, Desc: true}, false},
+		{"role", userOrder{}, true},
+		{"name up", userOrder{}, true},
+		{"name asc id", userOrder{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseOrderBy(tt.orderBy)
+		if tt.wantErr {
+			if err != ErrInvalidOrderBy {
+				t.Errorf("parseOrderBy(%q) error = %v, want ErrInvalidOrderBy", tt.orderBy, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseOrderBy(%q) = %+v, %v; want %+v", tt.orderBy, got, err, tt.want)
+		}
+	}
+}
+
+func TestListOrdering(t *testing.T) {
+	tests := []struct {
+		orderBy string
+		want    []int32
+	}{
+		{"", []int32{1, 2, 3, 4}},
+		{"id desc", []int32{4, 3, 2, 1}},
+		{"name", []int32{2, 4, 3, 1}},
+		{"name desc", []int32{1, 3, 4, 2}},
+		{"email", []int32{3, 1, 4, 2}},
+		{"created_at", []int32{3, 4, 1, 2}},
+		{"created_at desc", []int32{2, 1, 4, 3}},
+	}
+	for backend, newRepo := range listRepositories {
+		repo := newRepo(t, orderingUsers())
+		for _, tt := range tests {
+			t.Run(backend+"/"+tt.orderBy, func(t *testing.T) {
+				users, _, err := repo.List(&pb.UserFilter{OrderBy: tt.orderBy})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := ids(users); !slices.Equal(got, tt.want) {
+					t.Errorf("List order = %v, want %v", got, tt.want)
+				}
+			})
+		}
+
+		if _, _, err := repo.List(&pb.UserFilter{OrderBy: "role"}); err != ErrInvalidOrderBy {
+			t.Errorf("%s: List(order_by role) error = %v, want ErrInvalidOrderBy", backend, err)
+		}
+	}
+}
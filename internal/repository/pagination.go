@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"example.com/user/internal/models"
+	pb "example.com/user/proto"
+)
+
+// maxPageSize caps page_size so a single page can't pull the whole table
+const maxPageSize = 1000
+
+// pageCursor is the position a page token resumes from: the sort key of the last
+// user returned, under the ordering the token was issued for
+type pageCursor struct {
+	Order     string `json:"o"`
+	LastID    int32  `json:"id"`
+	LastValue string `json:"v,omitempty"`
+}
+
+// listParams holds the ordering, page size and resume position parsed from a UserFilter
+type listParams struct {
+	order userOrder
+	size  int
+	// after is the sort key of the last user already returned, nil on the first page
+	after *models.User
+}
+
+// parseListParams validates the ordering and page token of filter
+func parseListParams(filter *pb.UserFilter) (listParams, error) {
+	order, err := parseOrderBy(filter.GetOrderBy())
+	if err != nil {
+		return listParams{}, err
+	}
+
+	params := listParams{order: order, size: pageSize(filter)}
+	if filter.GetPageToken() == "" {
+		return params, nil
+	}
+
+	cursor, err := decodePageToken(filter.GetPageToken())
+	if err != nil {
+		return listParams{}, err
+	}
+	// A token only makes sense under the ordering it was issued for
+	if cursor.Order != order.String() {
+		return listParams{}, ErrInvalidPageToken
+	}
+
+	params.after, err = order.cursorUser(cursor)
+	if err != nil {
+		return listParams{}, err
+	}
+
+	return params, nil
+}
+
+// encodePageToken renders a cursor as an opaque, URL-safe token
+func encodePageToken(c pageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodePageToken parses a token produced by encodePageToken
+func decodePageToken(token string) (pageCursor, error) {
+	var c pageCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, ErrInvalidPageToken
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.LastID <= 0 {
+		return pageCursor{}, ErrInvalidPageToken
+	}
+
+	return c, nil
+}
+
+// pageSize returns the effective page size of a filter, or 0 when paging is not requested
+func pageSize(filter *pb.UserFilter) int {
+	size := int(filter.GetPageSize())
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+	if size < 0 {
+		size = 0
+	}
+	return size
+}
+
+// paginate trims a result fetched with one extra row (size+1) and returns the token of
+// the following page, or an empty token when this is the last page
+func (p listParams) paginate(users []*models.User) ([]*models.User, string) {
+	if p.size <= 0 || len(users) <= p.size {
+		return users, ""
+	}
+
+	users = users[:p.size]
+	last := users[p.size-1]
+	return users, encodePageToken(pageCursor{
+		Order:     p.order.String(),
+		LastID:    last.ID,
+		LastValue: p.order.value(last),
+	})
+}
+
+// ResumePageToken returns the page token continuing a listing in orderBy order after the
+// user with id, such as the last one a dropped stream delivered. get looks the user up
+// when the ordering needs more than its ID, so it resumes from where the user sorts now.
+func ResumePageToken(orderBy string, id int32, get func(id int32) (*models.User, error)) (string, error) {
+	order, err := parseOrderBy(orderBy)
+	if err != nil {
+		return "", err
+	}
+
+	last := &models.User{ID: id}
+	if order.Field != OrderByID {
+		if last, err = get(id); err != nil {
+			return "", err
+		}
+	}
+	return encodePageToken(pageCursor{
+		Order:     order.String(),
+		LastID:    id,
+		LastValue: order.value(last),
+	}), nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"example.com/user/internal/models"
+	pb "example.com/user/proto"
+)
+
+// listAll walks every page of filter, returning the IDs in the order they came
+func listAll(t *testing.T, repo UserRepository, filter *pb.UserFilter) []int32 {
+	t.Helper()
+
+	var all []int32
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatalf("still paging after %d pages: %v", pages, all)
+		}
+		users, next, err := repo.List(filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(users) > int(filter.PageSize) {
+			t.Fatalf("page of %d users, want at most %d", len(users), filter.PageSize)
+		}
+		all = append(all, ids(users)...)
+		if next == "" {
+			return all
+		}
+		filter.PageToken = next
+	}
+}
+
+func TestListPages(t *testing.T) {
+	tests := []struct {
+		orderBy string
+		want    []int32
+	}{
+		{"", []int32{1, 2, 3, 4}},
+		{"id desc", []int32{4, 3, 2, 1}},
+		// Users 2 and 4 share a name, so a page boundary between them is resumed by ID
+		{"name", []int32{2, 4, 3, 1}},
+		{"name desc", []int32{1, 3, 4, 2}},
+		{"email", []int32{3, 1, 4, 2}},
+		{"created_at desc", []int32{2, 1, 4, 3}},
+	}
+	for backend, newRepo := range listRepositories {
+		repo := newRepo(t, orderingUsers())
+		for _, tt := range tests {
+			for _, size := range []int32{1, 2, 3, 4, 5} {
+				got := listAll(t, repo, &pb.UserFilter{OrderBy: tt.orderBy, PageSize: size})
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("%s: pages of %d in order %q = %v, want %v", backend, size, tt.orderBy, got, tt.want)
+				}
+			}
+		}
+	}
+}
+
+func TestListPagesAcrossWrites(t *testing.T) {
+	for backend, newRepo := range listRepositories {
+		t.Run(backend, func(t *testing.T) {
+			repo := newRepo(t, orderingUsers())
+			first, next, err := repo.List(&pb.UserFilter{OrderBy: "name", PageSize: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(first); !slices.Equal(got, []int32{2, 4}) {
+				t.Fatalf("first page = %v, want [2 4]", got)
+			}
+
+			// Users added or removed before the cursor shift neither skip nor repeat the rest
+			if err := repo.Create(&models.User{Name: "Aaron", Email: "aaron@example.com", Role: models.RoleUser,
+				CreatedAt: time.Now(), Version: 1}); err != nil {
+				t.Fatal(err)
+			}
+			if err := repo.Delete(2); err != nil {
+				t.Fatal(err)
+			}
+			rest := listAll(t, repo, &pb.UserFilter{OrderBy: "name", PageSize: 2, PageToken: next})
+			if !slices.Equal(rest, []int32{3, 1}) {
+				t.Errorf("pages after the writes = %v, want [3 1]", rest)
+			}
+		})
+	}
+}
+
+func TestListPageTokenErrors(t *testing.T) {
+	_, byName, err := NewInMemoryUserRepository(orderingUsers()).List(&pb.UserFilter{OrderBy: "name", PageSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		filter *pb.UserFilter
+	}{
+		{"not base64", &pb.UserFilter{PageToken: "!!!"}},
+		{"not json", &pb.UserFilter{PageToken: "bm90IGpzb24"}},
+		{"no last id", &pb.UserFilter{PageToken: encodePageToken(pageCursor{Order: "id asc"})}},
+		{"other ordering", &pb.UserFilter{OrderBy: "email", PageToken: byName}},
+		{"other direction", &pb.UserFilter{OrderBy: "name desc", PageToken: byName}},
+		{"bad time", &pb.UserFilter{OrderBy: "created_at",
+			PageToken: encodePageToken(pageCursor{Order: "created_at asc", LastID: 1, LastValue: "yesterday"})}},
+	}
+	for backend, newRepo := range listRepositories {
+		repo := newRepo(t, orderingUsers())
+		for _, tt := range tests {
+			if _, _, err := repo.List(tt.filter); err != ErrInvalidPageToken {
+				t.Errorf("%s: List with %s token error = %v, want ErrInvalidPageToken", backend, tt.name, err)
+			}
+		}
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	tests := []struct {
+		size int32
+		want int
+	}{
+		{0, 0},
+		{-5, 0},
+		{20, 20},
+		{maxPageSize, maxPageSize},
+		{maxPageSize + 1, maxPageSize},
+	}
+	for _, tt := range tests {
+		if got := pageSize(&pb.UserFilter{PageSize: tt.size}); got != tt.want {
+			t.Errorf("pageSize(%d) = %d, want %d", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestResumePageToken(t *testing.T) {
+	repo := NewInMemoryUserRepository(orderingUsers())
+
+	tests := []struct {
+		orderBy string
+		after   int32
+		want    []int32
+	}{
+		{"", 2, []int32{3, 4}},
+		{"id desc", 2, []int32{1}},
+		{"name", 4, []int32{3, 1}},
+		{"created_at", 4, []int32{1, 2}},
+	}
+	for _, tt := range tests {
+		token, err := ResumePageToken(tt.orderBy, tt.after, repo.GetByID)
+		if err != nil {
+			t.Fatalf("ResumePageToken(%q, %d) error = %v", tt.orderBy, tt.after, err)
+		}
+		got := listAll(t, repo, &pb.UserFilter{OrderBy: tt.orderBy, PageSize: 10, PageToken: token})
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("resuming %q after %d = %v, want %v", tt.orderBy, tt.after, got, tt.want)
+		}
+	}
+
+	if _, err := ResumePageToken("name", 99, repo.GetByID); err != ErrUserNotFound {
+		t.Errorf("ResumePageToken after a missing user error = %v, want ErrUserNotFound", err)
+	}
+	if _, err := ResumePageToken("role", 1, repo.GetByID); err != ErrInvalidOrderBy {
+		t.Errorf("ResumePageToken(order_by role) error = %v, want ErrInvalidOrderBy", err)
+	}
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"example.com/user/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// PostgresAuditRepository implements AuditRepository on the audit_log table. Old and new
+// values are stored as JSONB.
+type PostgresAuditRepository struct {
+	db pgExecutor
+}
+
+// NewPostgresAuditRepository stores the audit log through the connection pool of users
+func NewPostgresAuditRepository(users *PostgresUserRepository) *PostgresAuditRepository {
+	return &PostgresAuditRepository{db: users.pool}
+}
+
+func (r *PostgresAuditRepository) Append(entry *models.AuditEntry) error {
+	if entry.Method == "" || entry.Actor == "" {
+		return ErrInvalidInput
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	return r.db.QueryRow(ctx,
+		`INSERT INTO audit_log (actor, method, user_id, old_value, new_value, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		entry.Actor, entry.Method, entry.UserID, entry.OldValue, entry.NewValue, entry.CreatedAt,
+	).Scan(&entry.ID)
+}
+
+func (r *PostgresAuditRepository) List(filter AuditFilter) ([]*models.AuditEntry, string, error) {
+	page, err := parseAuditFilter(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var where []string
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if filter.UserID != 0 {
+		where = append(where, "user_id = "+arg(filter.UserID))
+	}
+	if filter.Actor != "" {
+		where = append(where, "actor = "+arg(filter.Actor))
+	}
+	if page.before != 0 {
+		where = append(where, "id < "+arg(page.before))
+	}
+
+	query := "SELECT id, actor, method, user_id, old_value, new_value, created_at FROM audit_log"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC LIMIT " + arg(page.size+1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	entries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.AuditEntry, error) {
+		var entry models.AuditEntry
+		err := row.Scan(&entry.ID, &entry.Actor, &entry.Method, &entry.UserID, &entry.OldValue, &entry.NewValue, &entry.CreatedAt)
+		return &entry, err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	entries, next := page.paginate(entries)
+	return entries, next, nil
+}
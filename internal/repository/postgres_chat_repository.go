@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"example.com/user/internal/models"
+	pb "example.com/user/proto"
+	"github.com/jackc/pgx/v5"
+)
+
+// PostgresChatRepository implements ChatRepository on the chat_messages table
+type PostgresChatRepository struct {
+	db pgExecutor
+}
+
+// NewPostgresChatRepository stores the chat history through the connection pool of users
+func NewPostgresChatRepository(users *PostgresUserRepository) *PostgresChatRepository {
+	return &PostgresChatRepository{db: users.pool}
+}
+
+func (r *PostgresChatRepository) Append(msg *models.ChatMessage) error {
+	if msg.From == "" {
+		return ErrInvalidInput
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	return r.db.QueryRow(ctx,
+		`INSERT INTO chat_messages (sender, recipient, room, message, type, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		msg.From, msg.To, msg.Room, msg.Message, int32(msg.Type), msg.CreatedAt,
+	).Scan(&msg.ID)
+}
+
+func (r *PostgresChatRepository) History(filter ChatFilter) ([]*models.ChatMessage, bool, error) {
+	query, args := chatHistoryQuery(postgresDialect, filter)
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	messages, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.ChatMessage, error) {
+		var msg models.ChatMessage
+		var msgType int32
+		err := row.Scan(&msg.ID, &msg.From, &msg.To, &msg.Room, &msg.Message, &msgType, &msg.CreatedAt)
+		msg.Type = pb.MessageType(msgType)
+		return &msg, err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	messages, more := trimHistory(messages, filter)
+	return messages, more, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"example.com/user/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const postgresPreferencesColumns = "user_id, locale, timezone, email_notifications, push_notifications, weekly_digest, updated_at"
+
+// PostgresPreferencesRepository implements PreferencesRepository on the user_preferences table
+type PostgresPreferencesRepository struct {
+	db pgExecutor
+}
+
+// NewPostgresPreferencesRepository stores preferences through the connection pool of users
+func NewPostgresPreferencesRepository(users *PostgresUserRepository) *PostgresPreferencesRepository {
+	return &PostgresPreferencesRepository{db: users.pool}
+}
+
+func (r *PostgresPreferencesRepository) GetPreferences(userID int32) (*models.Preferences, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	var prefs models.Preferences
+	err := r.db.QueryRow(ctx, "SELECT "+postgresPreferencesColumns+" FROM user_preferences WHERE user_id = $1", userID).Scan(
+		&prefs.UserID, &prefs.Locale, &prefs.Timezone,
+		&prefs.EmailNotifications, &prefs.PushNotifications, &prefs.WeeklyDigest, &prefs.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPreferencesNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &prefs, nil
+}
+
+func (r *PostgresPreferencesRepository) SavePreferences(prefs *models.Preferences) error {
+	if prefs.UserID <= 0 {
+		return ErrInvalidInput
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO user_preferences (`+postgresPreferencesColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (user_id) DO UPDATE SET locale = EXCLUDED.locale, timezone = EXCLUDED.timezone,
+		 email_notifications = EXCLUDED.email_notifications, push_notifications = EXCLUDED.push_notifications,
+		 weekly_digest = EXCLUDED.weekly_digest, updated_at = EXCLUDED.updated_at`,
+		prefs.UserID, prefs.Locale, prefs.Timezone,
+		prefs.EmailNotifications, prefs.PushNotifications, prefs.WeeklyDigest, prefs.UpdatedAt,
+	)
+	return err
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"example.com/user/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const postgresTokenColumns = "id, token_hash, family_id, subject, role, created_at, expires_at, revoked_at"
+
+// PostgresTokenRepository implements TokenRepository on the refresh_tokens table
+type PostgresTokenRepository struct {
+	db pgExecutor
+}
+
+// NewPostgresTokenRepository stores tokens through the connection pool of users
+func NewPostgresTokenRepository(users *PostgresUserRepository) *PostgresTokenRepository {
+	return &PostgresTokenRepository{db: users.pool}
+}
+
+func (r *PostgresTokenRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	if token.TokenHash == "" || token.FamilyID == "" || token.Subject == "" {
+		return ErrInvalidInput
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	return r.db.QueryRow(ctx,
+		`INSERT INTO refresh_tokens (token_hash, family_id, subject, role, created_at, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		token.TokenHash, token.FamilyID, token.Subject, token.Role, token.CreatedAt, token.ExpiresAt,
+	).Scan(&token.ID)
+}
+
+func (r *PostgresTokenRepository) GetRefreshToken(hash string) (*models.RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	var token models.RefreshToken
+	err := r.db.QueryRow(ctx, "SELECT "+postgresTokenColumns+" FROM refresh_tokens WHERE token_hash = $1", hash).Scan(
+		&token.ID, &token.TokenHash, &token.FamilyID, &token.Subject, &token.Role,
+		&token.CreatedAt, &token.ExpiresAt, &token.RevokedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *PostgresTokenRepository) RevokeRefreshToken(hash string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx, "UPDATE refresh_tokens SET revoked_at = now() WHERE token_hash = $1 AND revoked_at IS NULL", hash)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		// Tell apart an unknown token from one that was already used
+		var exists bool
+		if err := r.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM refresh_tokens WHERE token_hash = $1)", hash).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return ErrTokenRevoked
+		}
+		return ErrTokenNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresTokenRepository) RevokeTokenFamily(familyID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	_, err := r.db.Exec(ctx, "UPDATE refresh_tokens SET revoked_at = now() WHERE family_id = $1 AND revoked_at IS NULL", familyID)
+	return err
+}
@@ -0,0 +1,374 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"example.com/user/internal/models"
+	pb "example.com/user/proto"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// postgresQueryTimeout bounds every statement, since UserRepository methods carry no context
+const postgresQueryTimeout = 5 * time.Second
+
+// pgUniqueViolation is the SQLSTATE reported for unique constraint violations
+const pgUniqueViolation = "23505"
+
+const postgresUserColumns = "id, name, email, role, status, created_at, updated_at, version, deleted_at"
+
+var postgresDialect = sqlDialect{
+	placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+	contains: func(column, param string) string {
+		return fmt.Sprintf("strpos(lower(%s), lower(%s)) > 0", column, param)
+	},
+	timeValue: func(t time.Time) any { return t },
+}
+
+// pgExecutor is satisfied by both the connection pool and an open transaction
+type pgExecutor interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// PostgresUserRepository implements UserRepository using PostgreSQL via a pgx connection pool
+type PostgresUserRepository struct {
+	pool *pgxpool.Pool
+	db   pgExecutor
+	// txCtx is the caller's context while the repository is bound to a transaction
+	txCtx context.Context
+}
+
+// NewPostgresUserRepository connects to PostgreSQL. The schema is managed by internal/migrations.
+// Pool sizing is controlled through the DSN (e.g. pool_max_conns=10).
+func NewPostgresUserRepository(ctx context.Context, dsn string) (*PostgresUserRepository, error) {
+	poolCfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parse postgres dsn: %w", err)
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("connect to postgres: %w", err)
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("ping postgres: %w", err)
+	}
+
+	return &PostgresUserRepository{pool: pool, db: pool}, nil
+}
+
+// Close releases all pooled connections
+func (r *PostgresUserRepository) Close() {
+	r.pool.Close()
+}
+
+// WithTx runs fn against a repository bound to a single transaction, committing if fn
+// returns nil and rolling back otherwise. Nested calls join the enclosing transaction.
+func (r *PostgresUserRepository) WithTx(ctx context.Context, fn func(repo UserRepository) error) error {
+	if r.txCtx != nil {
+		return fn(r)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	if err := fn(&PostgresUserRepository{pool: r.pool, db: tx, txCtx: ctx}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// queryContext derives the per-statement context, scoped to the transaction when there is one
+func (r *PostgresUserRepository) queryContext() (context.Context, context.CancelFunc) {
+	parent := context.Background()
+	if r.txCtx != nil {
+		parent = r.txCtx
+	}
+	return context.WithTimeout(parent, postgresQueryTimeout)
+}
+
+func (r *PostgresUserRepository) GetByID(id int32) (*models.User, error) {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	row := r.db.QueryRow(ctx, "SELECT "+postgresUserColumns+" FROM users WHERE id = $1 AND deleted_at IS NULL", id)
+	user, err := scanPostgresUser(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (r *PostgresUserRepository) Create(user *models.User) error {
+	if user.Name == "" || user.Email == "" {
+		return ErrInvalidInput
+	}
+
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO users (name, email, role, created_at, updated_at, version)
+		 VALUES ($1, $2, $3, $4, $5, 1) RETURNING id`,
+		user.Name, user.Email, user.Role, user.CreatedAt, user.UpdatedAt,
+	).Scan(&user.ID)
+	if err != nil {
+		return translatePostgresError(err)
+	}
+
+	user.Version = 1
+	user.Status = models.StatusActive
+	return nil
+}
+
+// CreateMany inserts users in one round-trip inside a single transaction. Conflicting
+// emails are skipped rather than raised, since an error would abort the transaction
+// before the rest of the batch could be checked.
+func (r *PostgresUserRepository) CreateMany(users []*models.User) error {
+	failed := make(map[int]error)
+	batch := &pgx.Batch{}
+	var queued []int
+	for i, user := range users {
+		if user.Name == "" || user.Email == "" {
+			failed[i] = ErrInvalidInput
+			continue
+		}
+		batch.Queue(
+			`INSERT INTO users (name, email, role, created_at, updated_at, version)
+			 VALUES ($1, $2, $3, $4, $5, 1) ON CONFLICT (email) DO NOTHING RETURNING id`,
+			user.Name, user.Email, user.Role, user.CreatedAt, user.UpdatedAt,
+		)
+		queued = append(queued, i)
+	}
+
+	ids := make([]int32, len(users))
+	err := r.WithTx(context.Background(), func(tx UserRepository) error {
+		pg := tx.(*PostgresUserRepository)
+		ctx, cancel := pg.queryContext()
+		defer cancel()
+
+		results := pg.db.SendBatch(ctx, batch)
+		for _, i := range queued {
+			err := results.QueryRow().Scan(&ids[i])
+			if errors.Is(err, pgx.ErrNoRows) {
+				failed[i] = ErrEmailExists
+				continue
+			}
+			if err != nil {
+				results.Close()
+				return translatePostgresError(err)
+			}
+		}
+		if err := results.Close(); err != nil {
+			return err
+		}
+
+		if len(failed) > 0 {
+			return &BatchError{Errors: failed}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, user := range users {
+		user.ID = ids[i]
+		user.Version = 1
+		user.Status = models.StatusActive
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) Update(user *models.User) error {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx,
+		`UPDATE users SET name = $2, email = $3, role = $4, status = $5, updated_at = $6, version = version + 1
+		 WHERE id = $1 AND version = $7 AND deleted_at IS NULL`,
+		user.ID, user.Name, user.Email, user.Role, user.Status, user.UpdatedAt, user.Version,
+	)
+	if err != nil {
+		return translatePostgresError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		// Either the user is gone or its version moved on
+		var exists bool
+		if err := r.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND deleted_at IS NULL)", user.ID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return ErrVersionConflict
+		}
+		return ErrUserNotFound
+	}
+
+	user.Version++
+	return nil
+}
+
+func (r *PostgresUserRepository) Delete(id int32) error {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx,
+		`UPDATE users SET deleted_at = now(), updated_at = now(), version = version + 1
+		 WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresUserRepository) Undelete(id int32) error {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx,
+		`UPDATE users SET deleted_at = NULL, updated_at = now(), version = version + 1
+		 WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		// Tell apart a missing user from one that isn't deleted
+		var exists bool
+		if err := r.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)", id).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return ErrUserNotDeleted
+		}
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// DeleteMany soft-deletes users with a single statement, rolling back if any is missing
+func (r *PostgresUserRepository) DeleteMany(ids []int32) error {
+	return r.WithTx(context.Background(), func(tx UserRepository) error {
+		pg := tx.(*PostgresUserRepository)
+		ctx, cancel := pg.queryContext()
+		defer cancel()
+
+		rows, err := pg.db.Query(ctx,
+			`UPDATE users SET deleted_at = now(), updated_at = now(), version = version + 1
+			 WHERE id = ANY($1) AND deleted_at IS NULL RETURNING id`, ids)
+		if err != nil {
+			return err
+		}
+		deleted, err := pgx.CollectRows(rows, pgx.RowTo[int32])
+		if err != nil {
+			return err
+		}
+
+		found := make(map[int32]bool, len(deleted))
+		for _, id := range deleted {
+			found[id] = true
+		}
+		failed := make(map[int]error)
+		for i, id := range ids {
+			if !found[id] {
+				failed[i] = ErrUserNotFound
+				// Report a missing ID once, like the other backends
+				found[id] = true
+			}
+		}
+		if len(failed) > 0 {
+			return &BatchError{Errors: failed}
+		}
+		return nil
+	})
+}
+
+func (r *PostgresUserRepository) List(filter *pb.UserFilter) ([]*models.User, string, error) {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	params, err := parseListParams(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query, args := buildUserListQuery(postgresDialect, postgresUserColumns, filter, params)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var result []*models.User
+	for rows.Next() {
+		user, err := scanPostgresUser(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		result = append(result, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	result, nextPageToken := params.paginate(result)
+	return result, nextPageToken, nil
+}
+
+func (r *PostgresUserRepository) Count(filter *pb.UserFilter) (int, error) {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	query, args := buildUserCountQuery(postgresDialect, filter)
+	var n int
+	err := r.db.QueryRow(ctx, query, args...).Scan(&n)
+	return n, err
+}
+
+func (r *PostgresUserRepository) EmailExists(email string) bool {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	var exists bool
+	err := r.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE email = $1)", email).Scan(&exists)
+	return err == nil && exists
+}
+
+// scanPostgresUser reads a single users row in postgresUserColumns order
+func scanPostgresUser(row pgx.Row) (*models.User, error) {
+	var user models.User
+	if err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.Status, &user.CreatedAt, &user.UpdatedAt, &user.Version, &user.DeletedAt); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// translatePostgresError maps driver errors onto repository errors
+func translatePostgresError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return ErrEmailExists
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"errors"
+
+	"example.com/user/internal/models"
+)
+
+var ErrPreferencesNotFound = errors.New("preferences not found")
+
+// PreferencesRepository stores the preferences of users, keyed by user ID
+type PreferencesRepository interface {
+	// GetPreferences returns ErrPreferencesNotFound for a user that has saved none
+	GetPreferences(userID int32) (*models.Preferences, error)
+	// SavePreferences creates or replaces the preferences of prefs.UserID
+	SavePreferences(prefs *models.Preferences) error
+}
+
+// InMemoryPreferencesRepository implements PreferencesRepository on top of the generic
+// MemoryRepository
+type InMemoryPreferencesRepository struct {
+	store *MemoryRepository[models.Preferences, *models.Preferences, int32]
+}
+
+// NewInMemoryPreferencesRepository creates an empty in-memory preferences repository
+func NewInMemoryPreferencesRepository() *InMemoryPreferencesRepository {
+	return &InMemoryPreferencesRepository{
+		store: NewMemoryRepository[models.Preferences, *models.Preferences, int32](MemoryOptions[models.Preferences]{
+			NotFound: ErrPreferencesNotFound,
+		}),
+	}
+}
+
+func (r *InMemoryPreferencesRepository) GetPreferences(userID int32) (*models.Preferences, error) {
+	return r.store.GetByID(userID)
+}
+
+func (r *InMemoryPreferencesRepository) SavePreferences(prefs *models.Preferences) error {
+	if prefs.UserID <= 0 {
+		return ErrInvalidInput
+	}
+
+	// Whichever of two first saves creates the entry, the other replaces it
+	err := r.store.Update(prefs)
+	if errors.Is(err, ErrPreferencesNotFound) {
+		err = r.store.Create(prefs)
+		if errors.Is(err, ErrDuplicateID) {
+			err = r.store.Update(prefs)
+		}
+	}
+	return err
+}
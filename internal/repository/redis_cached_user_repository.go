@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"example.com/user/internal/models"
+	pb "example.com/user/proto"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisOpTimeout bounds every cache round-trip so a slow Redis never stalls the primary store
+const redisOpTimeout = 500 * time.Millisecond
+
+// redisFill caches a user (ARGV[1], for ARGV[3] milliseconds or without expiry when 0)
+// only if its generation (KEYS[2]) is still the one read before loading it (ARGV[2])
+var redisFill = redis.NewScript(`
+if (redis.call('GET', KEYS[2]) or '0') ~= ARGV[2] then
+	return 0
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[3])
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+return 1
+`)
+
+// RedisCacheOptions configures the Redis cache-aside layer
+type RedisCacheOptions struct {
+	// TTL is how long a cached user stays valid; zero disables expiry
+	TTL time.Duration
+	// KeyPrefix namespaces cache keys so several services can share one Redis
+	KeyPrefix string
+}
+
+// RedisCachedUserRepository decorates another UserRepository with a Redis cache-aside layer.
+// Reads by ID are served from Redis when possible; writes go to the wrapped repository and
+// invalidate the cached entry, even when they fail. Every invalidation also bumps a
+// generation counter kept per user, and reads only cache what they loaded if it is
+// unchanged, so a read racing a write, on any replica, can't cache the user as it was
+// before. Cache failures are logged and never fail the request.
+type RedisCachedUserRepository struct {
+	next   UserRepository
+	client *redis.Client
+	opts   RedisCacheOptions
+}
+
+// NewRedisCachedUserRepository wraps next with a Redis cache using the given client
+func NewRedisCachedUserRepository(next UserRepository, client *redis.Client, opts RedisCacheOptions) *RedisCachedUserRepository {
+	if opts.KeyPrefix == "" {
+		opts.KeyPrefix = "user:"
+	}
+
+	return &RedisCachedUserRepository{
+		next:   next,
+		client: client,
+		opts:   opts,
+	}
+}
+
+// Close closes the Redis client; the wrapped repository is owned by the caller
+func (r *RedisCachedUserRepository) Close() error {
+	return r.client.Close()
+}
+
+func (r *RedisCachedUserRepository) GetByID(id int32) (*models.User, error) {
+	if user, ok := r.getCached(id); ok {
+		return user, nil
+	}
+
+	generation, cacheable := r.generation(id)
+	user, err := r.next.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheable {
+		r.setCached(user, generation)
+	}
+	return user, nil
+}
+
+func (r *RedisCachedUserRepository) Create(user *models.User) error {
+	return r.next.Create(user)
+}
+
+func (r *RedisCachedUserRepository) CreateMany(users []*models.User) error {
+	return r.next.CreateMany(users)
+}
+
+func (r *RedisCachedUserRepository) Update(user *models.User) error {
+	// Invalidate even on failure: a version conflict means the cached copy is stale, and
+	// without a TTL it would otherwise never be refreshed
+	defer r.invalidate(user.ID)
+	return r.next.Update(user)
+}
+
+func (r *RedisCachedUserRepository) Delete(id int32) error {
+	defer r.invalidate(id)
+	return r.next.Delete(id)
+}
+
+func (r *RedisCachedUserRepository) Undelete(id int32) error {
+	defer r.invalidate(id)
+	return r.next.Undelete(id)
+}
+
+func (r *RedisCachedUserRepository) DeleteMany(ids []int32) error {
+	defer func() {
+		for _, id := range ids {
+			r.invalidate(id)
+		}
+	}()
+	return r.next.DeleteMany(ids)
+}
+
+func (r *RedisCachedUserRepository) List(filter *pb.UserFilter) ([]*models.User, string, error) {
+	return r.next.List(filter)
+}
+
+func (r *RedisCachedUserRepository) Count(filter *pb.UserFilter) (int, error) {
+	return r.next.Count(filter)
+}
+
+func (r *RedisCachedUserRepository) EmailExists(email string) bool {
+	return r.next.EmailExists(email)
+}
+
+// WithTx runs fn on the wrapped repository's transaction without touching the cache,
+// then invalidates every user updated or deleted inside it
+func (r *RedisCachedUserRepository) WithTx(ctx context.Context, fn func(repo UserRepository) error) error {
+	var touched []int32
+	err := r.next.WithTx(ctx, func(tx UserRepository) error {
+		return fn(&invalidationRecorder{UserRepository: tx, touched: &touched})
+	})
+
+	for _, id := range touched {
+		r.invalidate(id)
+	}
+	return err
+}
+
+func (r *RedisCachedUserRepository) key(id int32) string {
+	return fmt.Sprintf("%s%d", r.opts.KeyPrefix, id)
+}
+
+// generationKey is the key of the invalidation counter of user id, which doesn't expire
+func (r *RedisCachedUserRepository) generationKey(id int32) string {
+	return fmt.Sprintf("%sgeneration:%d", r.opts.KeyPrefix, id)
+}
+
+// generation reads the invalidation counter of user id; false when it can't be read, and
+// what is loaded then mustn't be cached
+func (r *RedisCachedUserRepository) generation(id int32) (string, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisOpTimeout)
+	defer cancel()
+
+	generation, err := r.client.Get(ctx, r.generationKey(id)).Result()
+	switch {
+	case errors.Is(err, redis.Nil):
+		return "0", true
+	case err != nil:
+		slog.Warn("Redis cache read failed", "user_id", id, "error", err)
+		return "", false
+	}
+	return generation, true
+}
+
+func (r *RedisCachedUserRepository) getCached(id int32) (*models.User, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisOpTimeout)
+	defer cancel()
+
+	data, err := r.client.Get(ctx, r.key(id)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			slog.Warn("Redis cache read failed", "user_id", id, "error", err)
+		}
+		return nil, false
+	}
+
+	var user models.User
+	if err := json.Unmarshal(data, &user); err != nil {
+		slog.Warn("Redis cache entry is corrupt", "user_id", id, "error", err)
+		r.invalidate(id)
+		return nil, false
+	}
+
+	return &user, true
+}
+
+// setCached caches user unless it was invalidated since its generation was read
+func (r *RedisCachedUserRepository) setCached(user *models.User, generation string) {
+	data, err := json.Marshal(user)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisOpTimeout)
+	defer cancel()
+
+	// Shorter TTLs would round down to no expiry at all
+	ttl := r.opts.TTL.Milliseconds()
+	if r.opts.TTL > 0 {
+		ttl = max(ttl, 1)
+	}
+	keys := []string{r.key(user.ID), r.generationKey(user.ID)}
+	if err := redisFill.Run(ctx, r.client, keys, data, generation, ttl).Err(); err != nil {
+		slog.Warn("Redis cache write failed", "user_id", user.ID, "error", err)
+	}
+}
+
+// invalidate drops the cached copy of user id and bumps its generation, in one transaction
+func (r *RedisCachedUserRepository) invalidate(id int32) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisOpTimeout)
+	defer cancel()
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, r.generationKey(id))
+		pipe.Del(ctx, r.key(id))
+		return nil
+	})
+	if err != nil {
+		slog.Warn("Redis cache invalidation failed", "user_id", id, "error", err)
+	}
+}
+
+// invalidationRecorder tracks the users modified through a transactional repository
+type invalidationRecorder struct {
+	UserRepository
+	touched *[]int32
+}
+
+func (t *invalidationRecorder) Update(user *models.User) error {
+	*t.touched = append(*t.touched, user.ID)
+	return t.UserRepository.Update(user)
+}
+
+func (t *invalidationRecorder) Delete(id int32) error {
+	*t.touched = append(*t.touched, id)
+	return t.UserRepository.Delete(id)
+}
+
+func (t *invalidationRecorder) Undelete(id int32) error {
+	*t.touched = append(*t.touched, id)
+	return t.UserRepository.Undelete(id)
+}
+
+func (t *invalidationRecorder) DeleteMany(ids []int32) error {
+	*t.touched = append(*t.touched, ids...)
+	return t.UserRepository.DeleteMany(ids)
+}
+
+func (t *invalidationRecorder) WithTx(ctx context.Context, fn func(repo UserRepository) error) error {
+	return t.UserRepository.WithTx(ctx, func(tx UserRepository) error {
+		return fn(&invalidationRecorder{UserRepository: tx, touched: t.touched})
+	})
+}
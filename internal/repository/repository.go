@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var (
+	// ErrNotFound is the default error MemoryRepository returns for unknown IDs
+	ErrNotFound = errors.New("not found")
+	// ErrDuplicateID is returned when Create is given an ID that is already stored
+	ErrDuplicateID = errors.New("id already exists")
+)
+
+// Repository is the CRUD contract shared by entity repositories
+type Repository[T any, ID comparable] interface {
+	GetByID(id ID) (T, error)
+	Create(entity T) error
+	Update(entity T) error
+	Delete(id ID) error
+}
+
+// Sequential constrains IDs that MemoryRepository can allocate itself
+type Sequential interface {
+	~int32 | ~int64
+}
+
+// Entity is implemented by models stored in a MemoryRepository
+type Entity[ID Sequential] interface {
+	EntityID() ID
+	SetEntityID(id ID)
+}
+
+// entityPtr lets MemoryRepository store T by value while calling Entity methods on *T
+type entityPtr[T any, ID Sequential] interface {
+	*T
+	Entity[ID]
+}
+
+// UniqueKey declares a field that must be unique across stored entities
+type UniqueKey[T any] struct {
+	Name string
+	Key  func(entity *T) string
+	// Err is returned when a write would duplicate the key
+	Err error
+}
+
+// MemoryOptions configures a MemoryRepository
+type MemoryOptions[T any] struct {
+	// NotFound is returned for unknown IDs; defaults to ErrNotFound
+	NotFound error
+	Unique   []UniqueKey[T]
+}
+
+// MemoryRepository is a generic, concurrency-safe in-memory Repository. It stores
+// copies of entities (so callers can't mutate stored state), assigns sequential IDs
+// on Create, enforces unique keys through indexes and supports snapshot transactions.
+type MemoryRepository[T any, PT entityPtr[T, ID], ID Sequential] struct {
+	mu      sync.RWMutex
+	items   map[ID]T
+	indexes []memoryIndex[T, ID]
+	nextID  ID
+	opts    MemoryOptions[T]
+}
+
+type memoryIndex[T any, ID Sequential] struct {
+	UniqueKey[T]
+	ids map[string]ID
+}
+
+// NewMemoryRepository creates an empty repository
+func NewMemoryRepository[T any, PT entityPtr[T, ID], ID Sequential](opts MemoryOptions[T]) *MemoryRepository[T, PT, ID] {
+	if opts.NotFound == nil {
+		opts.NotFound = ErrNotFound
+	}
+
+	r := &MemoryRepository[T, PT, ID]{
+		items:  make(map[ID]T),
+		nextID: 1,
+		opts:   opts,
+	}
+	for _, key := range opts.Unique {
+		r.indexes = append(r.indexes, memoryIndex[T, ID]{UniqueKey: key, ids: make(map[string]ID)})
+	}
+
+	return r
+}
+
+func (r *MemoryRepository[T, PT, ID]) GetByID(id ID) (PT, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return nil, r.opts.NotFound
+	}
+
+	return &item, nil
+}
+
+// Create stores a copy of entity, assigning the next ID unless it already carries one
+func (r *MemoryRepository[T, PT, ID]) Create(entity PT) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := entity.EntityID()
+	if id == 0 {
+		id = r.nextID
+	} else if _, exists := r.items[id]; exists {
+		return ErrDuplicateID
+	}
+
+	if err := r.checkUnique(id, entity); err != nil {
+		return err
+	}
+
+	entity.SetEntityID(id)
+	r.nextID = max(r.nextID, id+1)
+	r.store(id, nil, entity)
+	return nil
+}
+
+// Update replaces the stored entity with the same ID
+func (r *MemoryRepository[T, PT, ID]) Update(entity PT) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.replace(entity.EntityID(), entity)
+}
+
+func (r *MemoryRepository[T, PT, ID]) Delete(id ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return r.opts.NotFound
+	}
+
+	for _, idx := range r.indexes {
+		delete(idx.ids, idx.Key(&item))
+	}
+	delete(r.items, id)
+	return nil
+}
+
+// Modify atomically applies fn to a copy of the stored entity and stores the result
+// unless fn fails. The modified copy is returned.
+func (r *MemoryRepository[T, PT, ID]) Modify(id ID, fn func(entity PT) error) (PT, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return nil, r.opts.NotFound
+	}
+
+	if err := fn(&item); err != nil {
+		return nil, err
+	}
+	if err := r.replace(id, &item); err != nil {
+		return nil, err
+	}
+
+	result := item
+	return &result, nil
+}
+
+// Find returns copies of every entity matching match, or all of them when match is nil,
+// in ascending ID order
+func (r *MemoryRepository[T, PT, ID]) Find(match func(entity PT) bool) []PT {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []PT
+	for _, item := range r.items {
+		if match == nil || match(&item) {
+			result = append(result, &item)
+		}
+	}
+	slices.SortFunc(result, func(a, b PT) int { return cmp.Compare(a.EntityID(), b.EntityID()) })
+
+	return result
+}
+
+// Lookup returns the ID holding key in the unique index called name
+func (r *MemoryRepository[T, PT, ID]) Lookup(name, key string) (ID, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, idx := range r.indexes {
+		if idx.Name == name {
+			id, ok := idx.ids[key]
+			return id, ok
+		}
+	}
+	return 0, false
+}
+
+// Snapshot returns copies of every entity in ascending ID order together with the next
+// ID to be allocated, read consistently so it can be handed back to Restore
+func (r *MemoryRepository[T, PT, ID]) Snapshot() ([]PT, ID) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]PT, 0, len(r.items))
+	for _, item := range r.items {
+		items = append(items, &item)
+	}
+	slices.SortFunc(items, func(a, b PT) int { return cmp.Compare(a.EntityID(), b.EntityID()) })
+
+	return items, r.nextID
+}
+
+// Restore replaces the whole content of the repository with items, which must carry
+// their IDs. Nothing changes if an ID is missing or repeated or a unique key clashes.
+func (r *MemoryRepository[T, PT, ID]) Restore(items []PT, nextID ID) error {
+	fresh := NewMemoryRepository[T, PT, ID](r.opts)
+	for _, item := range items {
+		if item.EntityID() <= 0 {
+			return errors.New("restore: entity without an ID")
+		}
+		itemCopy := *item
+		if err := fresh.Create(&itemCopy); err != nil {
+			return fmt.Errorf("restore entity %v: %w", item.EntityID(), err)
+		}
+	}
+	fresh.nextID = max(fresh.nextID, nextID)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items = fresh.items
+	r.indexes = fresh.indexes
+	r.nextID = fresh.nextID
+	return nil
+}
+
+// Len returns the number of stored entities
+func (r *MemoryRepository[T, PT, ID]) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.items)
+}
+
+// WithTx runs fn against a private snapshot and publishes it only if fn succeeds.
+// Writers are blocked for the duration of fn.
+func (r *MemoryRepository[T, PT, ID]) WithTx(ctx context.Context, fn func(tx *MemoryRepository[T, PT, ID]) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Stored entities are values, so copying the maps snapshots them completely
+	tx := &MemoryRepository[T, PT, ID]{
+		items:  make(map[ID]T, len(r.items)),
+		nextID: r.nextID,
+		opts:   r.opts,
+	}
+	for id, item := range r.items {
+		tx.items[id] = item
+	}
+	for _, idx := range r.indexes {
+		ids := make(map[string]ID, len(idx.ids))
+		for key, id := range idx.ids {
+			ids[key] = id
+		}
+		tx.indexes = append(tx.indexes, memoryIndex[T, ID]{UniqueKey: idx.UniqueKey, ids: ids})
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.items = tx.items
+	r.indexes = tx.indexes
+	r.nextID = tx.nextID
+	return nil
+}
+
+// replace swaps the entity stored under id, keeping unique indexes in sync; mu must be held
+func (r *MemoryRepository[T, PT, ID]) replace(id ID, entity PT) error {
+	old, ok := r.items[id]
+	if !ok {
+		return r.opts.NotFound
+	}
+	if err := r.checkUnique(id, entity); err != nil {
+		return err
+	}
+
+	r.store(id, &old, entity)
+	return nil
+}
+
+// checkUnique reports the first unique key of entity already held by another ID; mu must be held
+func (r *MemoryRepository[T, PT, ID]) checkUnique(id ID, entity PT) error {
+	for _, idx := range r.indexes {
+		if owner, taken := idx.ids[idx.Key((*T)(entity))]; taken && owner != id {
+			return idx.Err
+		}
+	}
+	return nil
+}
+
+// store saves a copy of entity and re-indexes it; mu must be held
+func (r *MemoryRepository[T, PT, ID]) store(id ID, old *T, entity PT) {
+	for _, idx := range r.indexes {
+		if old != nil {
+			delete(idx.ids, idx.Key(old))
+		}
+		idx.ids[idx.Key((*T)(entity))] = id
+	}
+	r.items[id] = *entity
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"example.com/user/internal/models"
+	pb "example.com/user/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// seedFile is the document format of SEED_FILE, in JSON or YAML:
+//
+//	users:
+//	  - name: John Doe
+//	    email: john@example.com
+//	    role: admin
+type seedFile struct {
+	Users []seedUser `json:"users" yaml:"users"`
+}
+
+type seedUser struct {
+	Name  string `json:"name" yaml:"name"`
+	Email string `json:"email" yaml:"email"`
+	Role  string `json:"role" yaml:"role"`
+}
+
+// SampleUsers returns the demo users the in-memory backend starts with when no seed file is set
+func SampleUsers() []*models.User {
+	now := time.Now()
+	return []*models.User{
+		{Name: "John Doe", Email: "john@example.com", Role: "admin", CreatedAt: now, UpdatedAt: now, Version: 1},
+		{Name: "Jane Smith", Email: "jane@example.com", Role: "user", CreatedAt: now, UpdatedAt: now, Version: 1},
+		{Name: "Bob Johnson", Email: "bob@example.com", Role: "user", CreatedAt: now, UpdatedAt: now, Version: 1},
+	}
+}
+
+// LoadSeedFile reads initial users from a .json, .yaml or .yml file. Every entry is
+// validated and emails must be unique (case-insensitively); all problems are reported
+// together rather than stopping at the first one.
+func LoadSeedFile(path string) ([]*models.User, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read seed file: %w", err)
+	}
+
+	var doc seedFile
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&doc)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&doc)
+	default:
+		return nil, fmt.Errorf("seed file %s: unsupported extension %q (expected .json, .yaml or .yml)", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse seed file %s: %w", path, err)
+	}
+
+	now := time.Now()
+	users := make([]*models.User, 0, len(doc.Users))
+	seen := make(map[string]int, len(doc.Users))
+	var problems []error
+
+	for i, entry := range doc.Users {
+		if err := validateSeedUser(entry); err != nil {
+			problems = append(problems, fmt.Errorf("user %d: %w", i+1, err))
+			continue
+		}
+
+		key := strings.ToLower(entry.Email)
+		if first, dup := seen[key]; dup {
+			problems = append(problems, fmt.Errorf("user %d: email %s duplicates user %d", i+1, entry.Email, first))
+			continue
+		}
+		seen[key] = i + 1
+
+		role := entry.Role
+		if role == "" {
+			role = models.RoleUser
+		}
+		users = append(users, &models.User{
+			Name:      entry.Name,
+			Email:     entry.Email,
+			Role:      role,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Version:   1,
+		})
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid seed file %s: %w", path, errors.Join(problems...))
+	}
+	return users, nil
+}
+
+func validateSeedUser(entry seedUser) error {
+	if strings.TrimSpace(entry.Name) == "" {
+		return errors.New("name is required")
+	}
+	if entry.Email == "" {
+		return errors.New("email is required")
+	}
+	if addr, err := mail.ParseAddress(entry.Email); err != nil || addr.Address != entry.Email {
+		return fmt.Errorf("invalid email %q", entry.Email)
+	}
+	if entry.Role != "" && !models.ValidRoleName(entry.Role) {
+		return fmt.Errorf("invalid role %q", entry.Role)
+	}
+	return nil
+}
+
+// seedIfEmpty creates users in repo unless it already holds users, deleted ones included,
+// so a persistent backend is only seeded on first start
+func seedIfEmpty(repo UserRepository, users []*models.User) (bool, error) {
+	existing, _, err := repo.List(&pb.UserFilter{Limit: 1, IncludeDeleted: true})
+	if err != nil {
+		return false, err
+	}
+	if len(existing) > 0 || len(users) == 0 {
+		return false, nil
+	}
+
+	return true, repo.CreateMany(users)
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"example.com/user/internal/models"
+)
+
+// snapshotFormat is bumped whenever the snapshot document changes incompatibly
+const snapshotFormat = 1
+
+// ErrSnapshotDisabled is returned by Store.Snapshot unless the memory backend runs with SNAPSHOT_FILE
+var ErrSnapshotDisabled = errors.New("snapshots require the memory backend and SNAPSHOT_FILE")
+
+// snapshot is the on-disk form of an InMemoryUserRepository
+type snapshot struct {
+	Format  int            `json:"format"`
+	TakenAt time.Time      `json:"taken_at"`
+	NextID  int32          `json:"next_id"`
+	Users   []*models.User `json:"users"`
+}
+
+// SaveSnapshot writes every user, deleted ones included, to path. The file is
+// replaced atomically so a crash mid-write never leaves a truncated snapshot.
+func (r *InMemoryUserRepository) SaveSnapshot(path string) (int, error) {
+	users, nextID := r.store.Snapshot()
+	data, err := json.MarshalIndent(snapshot{
+		Format:  snapshotFormat,
+		TakenAt: time.Now().UTC(),
+		NextID:  nextID,
+		Users:   users,
+	}, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("create snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return 0, fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return 0, fmt.Errorf("sync snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, fmt.Errorf("close snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("replace snapshot: %w", err)
+	}
+
+	return len(users), nil
+}
+
+// LoadSnapshot replaces the content of the repository with the snapshot at path
+func (r *InMemoryUserRepository) LoadSnapshot(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return 0, fmt.Errorf("parse snapshot %s: %w", path, err)
+	}
+	if snap.Format != snapshotFormat {
+		return 0, fmt.Errorf("snapshot %s has format %d, expected %d", path, snap.Format, snapshotFormat)
+	}
+
+	if err := r.store.Restore(snap.Users, snap.NextID); err != nil {
+		return 0, fmt.Errorf("load snapshot %s: %w", path, err)
+	}
+	return len(snap.Users), nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"example.com/user/internal/models"
+	pb "example.com/user/proto"
+)
+
+// sqlDialect captures the syntax differences between the SQL backends
+type sqlDialect struct {
+	// placeholder renders the n-th (1-based) positional parameter
+	placeholder func(n int) string
+	// contains renders a case-insensitive substring match of a parameter within a column
+	contains func(column, param string) string
+	// timeValue converts a timestamp into the form the driver compares correctly
+	timeValue func(t time.Time) any
+}
+
+// sqlQuery accumulates WHERE conditions together with their positional arguments
+type sqlQuery struct {
+	dialect    sqlDialect
+	conditions []string
+	args       []any
+}
+
+// arg registers a positional argument and returns its placeholder
+func (q *sqlQuery) arg(value any) string {
+	q.args = append(q.args, value)
+	return q.dialect.placeholder(len(q.args))
+}
+
+// where adds a condition joined with AND
+func (q *sqlQuery) where(condition string) {
+	q.conditions = append(q.conditions, condition)
+}
+
+// clause renders the accumulated conditions as a WHERE clause, or an empty string
+func (q *sqlQuery) clause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// whereFilter adds the deleted, keyword, name, email, role and creation time conditions of
+// filter
+func (q *sqlQuery) whereFilter(filter *pb.UserFilter) {
+	if !filter.GetIncludeDeleted() {
+		q.where("deleted_at IS NULL")
+	}
+
+	// Keyword matches anywhere in the name or email, mirroring the in-memory backend
+	if keyword := filter.GetKeyword(); keyword != "" {
+		q.where("(" + q.dialect.contains("name", q.arg(keyword)) + " OR " + q.dialect.contains("email", q.arg(keyword)) + ")")
+	}
+	if name := filter.GetNameContains(); name != "" {
+		q.where(q.dialect.contains("name", q.arg(name)))
+	}
+	if email := filter.GetEmailContains(); email != "" {
+		q.where(q.dialect.contains("email", q.arg(email)))
+	}
+
+	if roles := filter.GetRoles(); len(roles) > 0 {
+		placeholders := make([]string, len(roles))
+		for i, role := range roles {
+			placeholders[i] = q.arg(role)
+		}
+		q.where("role IN (" + strings.Join(placeholders, ", ") + ")")
+	}
+
+	if after := filter.GetCreatedAfter(); after != nil {
+		q.where("created_at >= " + q.arg(q.dialect.timeValue(after.AsTime())))
+	}
+	if before := filter.GetCreatedBefore(); before != nil {
+		q.where("created_at < " + q.arg(q.dialect.timeValue(before.AsTime())))
+	}
+}
+
+// buildUserListQuery translates a UserFilter into a SELECT over the users table, ordered
+// and resumed according to params. When paging, one extra row is fetched so the caller
+// can tell whether more remain.
+func buildUserListQuery(dialect sqlDialect, columns string, filter *pb.UserFilter, params listParams) (string, []any) {
+	q := &sqlQuery{dialect: dialect}
+	q.whereFilter(filter)
+
+	direction, op := "ASC", ">"
+	if params.order.Desc {
+		direction, op = "DESC", "<"
+	}
+
+	// Keyset condition: strictly after the last (sort key, id) already returned
+	if after := params.after; after != nil {
+		if params.order.Field == OrderByID {
+			q.where(fmt.Sprintf("id %s %s", op, q.arg(after.ID)))
+		} else {
+			column, value := params.order.Field, sortValue(dialect, params.order, after)
+			q.where(fmt.Sprintf("(%s %s %s OR (%s = %s AND id %s %s))",
+				column, op, q.arg(value), column, q.arg(value), op, q.arg(after.ID)))
+		}
+	}
+
+	orderBy := "id " + direction
+	if params.order.Field != OrderByID {
+		orderBy = params.order.Field + " " + direction + ", " + orderBy
+	}
+	query := "SELECT " + columns + " FROM users" + q.clause() + " ORDER BY " + orderBy
+
+	limit := int64(filter.GetLimit())
+	if params.size > 0 {
+		limit = int64(params.size) + 1
+	}
+	if limit > 0 {
+		query += " LIMIT " + q.arg(limit)
+	}
+	if filter.GetOffset() > 0 {
+		if limit <= 0 {
+			// SQLite only accepts OFFSET after a LIMIT, so use an effectively unbounded one
+			query += " LIMIT " + q.arg(int64(1<<62))
+		}
+		query += " OFFSET " + q.arg(filter.GetOffset())
+	}
+
+	return query, q.args
+}
+
+// buildUserCountQuery translates a UserFilter into a count of the users it matches across
+// all pages
+func buildUserCountQuery(dialect sqlDialect, filter *pb.UserFilter) (string, []any) {
+	q := &sqlQuery{dialect: dialect}
+	q.whereFilter(filter)
+	return "SELECT COUNT(*) FROM users" + q.clause(), q.args
+}
+
+// sortValue returns the query argument for the sort key of user
+func sortValue(dialect sqlDialect, order userOrder, user *models.User) any {
+	switch order.Field {
+	case OrderByName:
+		return user.Name
+	case OrderByEmail:
+		return user.Email
+	case OrderByCreatedAt:
+		return dialect.timeValue(user.CreatedAt)
+	}
+	return user.ID
+}
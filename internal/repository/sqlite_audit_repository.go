@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"example.com/user/internal/models"
+)
+
+// SQLiteAuditRepository implements AuditRepository on the audit_log table. Old and new
+// values are stored as JSON.
+type SQLiteAuditRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteAuditRepository stores the audit log in the database file of users
+func NewSQLiteAuditRepository(users *SQLiteUserRepository) *SQLiteAuditRepository {
+	return &SQLiteAuditRepository{db: users.conn}
+}
+
+func (r *SQLiteAuditRepository) Append(entry *models.AuditEntry) error {
+	if entry.Method == "" || entry.Actor == "" {
+		return ErrInvalidInput
+	}
+
+	oldValue, err := marshalAuditValue(entry.OldValue)
+	if err != nil {
+		return err
+	}
+	newValue, err := marshalAuditValue(entry.NewValue)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteQueryTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO audit_log (actor, method, user_id, old_value, new_value, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		entry.Actor, entry.Method, entry.UserID, oldValue, newValue, entry.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	entry.ID, err = res.LastInsertId()
+	return err
+}
+
+func (r *SQLiteAuditRepository) List(filter AuditFilter) ([]*models.AuditEntry, string, error) {
+	page, err := parseAuditFilter(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var where []string
+	var args []any
+	if filter.UserID != 0 {
+		where = append(where, "user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.Actor != "" {
+		where = append(where, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+	if page.before != 0 {
+		where = append(where, "id < ?")
+		args = append(args, page.before)
+	}
+
+	query := "SELECT id, actor, method, user_id, old_value, new_value, created_at FROM audit_log"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, page.size+1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteQueryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var entries []*models.AuditEntry
+	for rows.Next() {
+		var entry models.AuditEntry
+		var oldValue, newValue sql.NullString
+		if err := rows.Scan(&entry.ID, &entry.Actor, &entry.Method, &entry.UserID, &oldValue, &newValue, &entry.CreatedAt); err != nil {
+			return nil, "", err
+		}
+		if entry.OldValue, err = unmarshalAuditValue(oldValue); err != nil {
+			return nil, "", err
+		}
+		if entry.NewValue, err = unmarshalAuditValue(newValue); err != nil {
+			return nil, "", err
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	entries, next := page.paginate(entries)
+	return entries, next, nil
+}
+
+func marshalAuditValue(user *models.User) (sql.NullString, error) {
+	if user == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(user)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func unmarshalAuditValue(value sql.NullString) (*models.User, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+	var user models.User
+	if err := json.Unmarshal([]byte(value.String), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"example.com/user/internal/models"
+	pb "example.com/user/proto"
+)
+
+// SQLiteChatRepository implements ChatRepository on the chat_messages table
+type SQLiteChatRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteChatRepository stores the chat history in the database file of users
+func NewSQLiteChatRepository(users *SQLiteUserRepository) *SQLiteChatRepository {
+	return &SQLiteChatRepository{db: users.conn}
+}
+
+func (r *SQLiteChatRepository) Append(msg *models.ChatMessage) error {
+	if msg.From == "" {
+		return ErrInvalidInput
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteQueryTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO chat_messages (sender, recipient, room, message, type, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		msg.From, msg.To, msg.Room, msg.Message, int32(msg.Type), msg.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	msg.ID, err = res.LastInsertId()
+	return err
+}
+
+func (r *SQLiteChatRepository) History(filter ChatFilter) ([]*models.ChatMessage, bool, error) {
+	query, args := chatHistoryQuery(sqliteDialect, filter)
+
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteQueryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	var messages []*models.ChatMessage
+	for rows.Next() {
+		var msg models.ChatMessage
+		var msgType int32
+		if err := rows.Scan(&msg.ID, &msg.From, &msg.To, &msg.Room, &msg.Message, &msgType, &msg.CreatedAt); err != nil {
+			return nil, false, err
+		}
+		msg.Type = pb.MessageType(msgType)
+		messages = append(messages, &msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	messages, more := trimHistory(messages, filter)
+	return messages, more, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"example.com/user/internal/models"
+)
+
+const sqlitePreferencesColumns = "user_id, locale, timezone, email_notifications, push_notifications, weekly_digest, updated_at"
+
+// SQLitePreferencesRepository implements PreferencesRepository on the user_preferences table
+type SQLitePreferencesRepository struct {
+	db *sql.DB
+}
+
+// NewSQLitePreferencesRepository stores preferences in the database file of users
+func NewSQLitePreferencesRepository(users *SQLiteUserRepository) *SQLitePreferencesRepository {
+	return &SQLitePreferencesRepository{db: users.conn}
+}
+
+func (r *SQLitePreferencesRepository) GetPreferences(userID int32) (*models.Preferences, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteQueryTimeout)
+	defer cancel()
+
+	var prefs models.Preferences
+	err := r.db.QueryRowContext(ctx, "SELECT "+sqlitePreferencesColumns+" FROM user_preferences WHERE user_id = ?", userID).Scan(
+		&prefs.UserID, &prefs.Locale, &prefs.Timezone,
+		&prefs.EmailNotifications, &prefs.PushNotifications, &prefs.WeeklyDigest, &prefs.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPreferencesNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &prefs, nil
+}
+
+func (r *SQLitePreferencesRepository) SavePreferences(prefs *models.Preferences) error {
+	if prefs.UserID <= 0 {
+		return ErrInvalidInput
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteQueryTimeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO user_preferences (`+sqlitePreferencesColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (user_id) DO UPDATE SET locale = excluded.locale, timezone = excluded.timezone,
+		 email_notifications = excluded.email_notifications, push_notifications = excluded.push_notifications,
+		 weekly_digest = excluded.weekly_digest, updated_at = excluded.updated_at`,
+		prefs.UserID, prefs.Locale, prefs.Timezone,
+		prefs.EmailNotifications, prefs.PushNotifications, prefs.WeeklyDigest, prefs.UpdatedAt.UTC(),
+	)
+	return err
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"example.com/user/internal/models"
+)
+
+const sqliteTokenColumns = "id, token_hash, family_id, subject, role, created_at, expires_at, revoked_at"
+
+// SQLiteTokenRepository implements TokenRepository on the refresh_tokens table
+type SQLiteTokenRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteTokenRepository stores tokens in the database file of users
+func NewSQLiteTokenRepository(users *SQLiteUserRepository) *SQLiteTokenRepository {
+	return &SQLiteTokenRepository{db: users.conn}
+}
+
+func (r *SQLiteTokenRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	if token.TokenHash == "" || token.FamilyID == "" || token.Subject == "" {
+		return ErrInvalidInput
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteQueryTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO refresh_tokens (token_hash, family_id, subject, role, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		token.TokenHash, token.FamilyID, token.Subject, token.Role, token.CreatedAt.UTC(), token.ExpiresAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	token.ID, err = res.LastInsertId()
+	return err
+}
+
+func (r *SQLiteTokenRepository) GetRefreshToken(hash string) (*models.RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteQueryTimeout)
+	defer cancel()
+
+	var token models.RefreshToken
+	err := r.db.QueryRowContext(ctx, "SELECT "+sqliteTokenColumns+" FROM refresh_tokens WHERE token_hash = ?", hash).Scan(
+		&token.ID, &token.TokenHash, &token.FamilyID, &token.Subject, &token.Role,
+		&token.CreatedAt, &token.ExpiresAt, &token.RevokedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *SQLiteTokenRepository) RevokeRefreshToken(hash string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteQueryTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = ? WHERE token_hash = ? AND revoked_at IS NULL", time.Now().UTC(), hash)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		// Tell apart an unknown token from one that was already used
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM refresh_tokens WHERE token_hash = ?)", hash).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return ErrTokenRevoked
+		}
+		return ErrTokenNotFound
+	}
+
+	return nil
+}
+
+func (r *SQLiteTokenRepository) RevokeTokenFamily(familyID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteQueryTimeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL", time.Now().UTC(), familyID)
+	return err
+}
@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"example.com/user/internal/models"
+	pb "example.com/user/proto"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqliteQueryTimeout bounds every statement, since UserRepository methods carry no context
+const sqliteQueryTimeout = 5 * time.Second
+
+const sqliteUserColumns = "id, name, email, role, status, created_at, updated_at, version, deleted_at"
+
+var sqliteDialect = sqlDialect{
+	placeholder: func(int) string { return "?" },
+	// SQLite's lower only folds ASCII letters, so other letters must match in case
+	contains: func(column, param string) string {
+		return fmt.Sprintf("instr(lower(%s), lower(%s)) > 0", column, param)
+	},
+	timeValue: func(t time.Time) any { return t.UTC() },
+}
+
+// sqlExecutor is satisfied by both *sql.DB and *sql.Tx
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// SQLiteUserRepository implements UserRepository using an embedded SQLite database file
+type SQLiteUserRepository struct {
+	conn *sql.DB
+	db   sqlExecutor
+	// txCtx is the caller's context while the repository is bound to a transaction
+	txCtx context.Context
+}
+
+// NewSQLiteUserRepository opens (or creates) the database file at path.
+// The schema is managed by internal/migrations.
+func NewSQLiteUserRepository(path string) (*SQLiteUserRepository, error) {
+	db, err := openSQLite(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLiteUserRepository{conn: db, db: db}, nil
+}
+
+// openSQLite opens the database file at path with the pragmas every connection needs
+func openSQLite(path string) (*sql.DB, error) {
+	// Timestamps are written in SQLite's own format (instead of time.Time.String) so they
+	// compare and sort correctly inside queries
+	dsn := "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(ON)&_time_format=sqlite"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite database: %w", err)
+	}
+
+	// SQLite allows a single writer; serializing connections avoids SQLITE_BUSY under load
+	db.SetMaxOpenConns(1)
+
+	return db, nil
+}
+
+// Close closes the underlying database handle
+func (r *SQLiteUserRepository) Close() error {
+	return r.conn.Close()
+}
+
+// WithTx runs fn against a repository bound to a single transaction, committing if fn
+// returns nil and rolling back otherwise. Nested calls join the enclosing transaction.
+// Since SQLite has a single connection, fn must only use the repository it is given.
+func (r *SQLiteUserRepository) WithTx(ctx context.Context, fn func(repo UserRepository) error) error {
+	if r.txCtx != nil {
+		return fn(r)
+	}
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(&SQLiteUserRepository{conn: r.conn, db: tx, txCtx: ctx}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// queryContext derives the per-statement context, scoped to the transaction when there is one
+func (r *SQLiteUserRepository) queryContext() (context.Context, context.CancelFunc) {
+	parent := context.Background()
+	if r.txCtx != nil {
+		parent = r.txCtx
+	}
+	return context.WithTimeout(parent, sqliteQueryTimeout)
+}
+
+func (r *SQLiteUserRepository) GetByID(id int32) (*models.User, error) {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	row := r.db.QueryRowContext(ctx, "SELECT "+sqliteUserColumns+" FROM users WHERE id = ? AND deleted_at IS NULL", id)
+	user, err := scanSQLUser(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (r *SQLiteUserRepository) Create(user *models.User) error {
+	if user.Name == "" || user.Email == "" {
+		return ErrInvalidInput
+	}
+
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO users (name, email, role, created_at, updated_at, version) VALUES (?, ?, ?, ?, ?, 1)`,
+		user.Name, user.Email, user.Role, user.CreatedAt.UTC(), user.UpdatedAt.UTC(),
+	)
+	if err != nil {
+		return translateSQLiteError(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	user.ID = int32(id)
+	user.Version = 1
+	user.Status = models.StatusActive
+
+	return nil
+}
+
+// CreateMany inserts users in a single transaction
+func (r *SQLiteUserRepository) CreateMany(users []*models.User) error {
+	return r.WithTx(context.Background(), func(tx UserRepository) error {
+		return createEach(tx, users)
+	})
+}
+
+func (r *SQLiteUserRepository) Update(user *models.User) error {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET name = ?, email = ?, role = ?, status = ?, updated_at = ?, version = version + 1
+		 WHERE id = ? AND version = ? AND deleted_at IS NULL`,
+		user.Name, user.Email, user.Role, user.Status, user.UpdatedAt.UTC(), user.ID, user.Version,
+	)
+	if err != nil {
+		return translateSQLiteError(err)
+	}
+	if err := requireAffected(res); err != nil {
+		// Either the user is gone or its version moved on
+		var exists bool
+		if scanErr := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE id = ? AND deleted_at IS NULL)", user.ID).Scan(&exists); scanErr != nil {
+			return scanErr
+		}
+		if exists {
+			return ErrVersionConflict
+		}
+		return err
+	}
+
+	user.Version++
+	return nil
+}
+
+func (r *SQLiteUserRepository) Delete(id int32) error {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	now := time.Now().UTC()
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET deleted_at = ?, updated_at = ?, version = version + 1
+		 WHERE id = ? AND deleted_at IS NULL`, now, now, id)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(res)
+}
+
+func (r *SQLiteUserRepository) Undelete(id int32) error {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET deleted_at = NULL, updated_at = ?, version = version + 1
+		 WHERE id = ? AND deleted_at IS NOT NULL`, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(res); err != nil {
+		// Tell apart a missing user from one that isn't deleted
+		var exists bool
+		if scanErr := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)", id).Scan(&exists); scanErr != nil {
+			return scanErr
+		}
+		if exists {
+			return ErrUserNotDeleted
+		}
+		return err
+	}
+
+	return nil
+}
+
+// DeleteMany soft-deletes users in a single transaction
+func (r *SQLiteUserRepository) DeleteMany(ids []int32) error {
+	return r.WithTx(context.Background(), func(tx UserRepository) error {
+		return deleteEach(tx, ids)
+	})
+}
+
+func (r *SQLiteUserRepository) List(filter *pb.UserFilter) ([]*models.User, string, error) {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	params, err := parseListParams(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query, args := buildUserListQuery(sqliteDialect, sqliteUserColumns, filter, params)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var result []*models.User
+	for rows.Next() {
+		user, err := scanSQLUser(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		result = append(result, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	result, nextPageToken := params.paginate(result)
+	return result, nextPageToken, nil
+}
+
+func (r *SQLiteUserRepository) Count(filter *pb.UserFilter) (int, error) {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	query, args := buildUserCountQuery(sqliteDialect, filter)
+	var n int
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&n)
+	return n, err
+}
+
+func (r *SQLiteUserRepository) EmailExists(email string) bool {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE email = ?)", email).Scan(&exists)
+	return err == nil && exists
+}
+
+// sqlRow is satisfied by both *sql.Row and *sql.Rows
+type sqlRow interface {
+	Scan(dest ...any) error
+}
+
+// scanSQLUser reads a single users row in sqliteUserColumns order
+func scanSQLUser(row sqlRow) (*models.User, error) {
+	var user models.User
+	if err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.Status, &user.CreatedAt, &user.UpdatedAt, &user.Version, &user.DeletedAt); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// requireAffected reports ErrUserNotFound when a statement touched no rows
+func requireAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// translateSQLiteError maps driver errors onto repository errors
+func translateSQLiteError(err error) error {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE {
+		return ErrEmailExists
+	}
+	return err
+}
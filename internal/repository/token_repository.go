@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"example.com/user/internal/models"
+)
+
+var (
+	ErrTokenNotFound = errors.New("refresh token not found")
+	ErrTokenRevoked  = errors.New("refresh token revoked")
+)
+
+// TokenRepository stores refresh tokens by hash together with their revocation state
+type TokenRepository interface {
+	CreateRefreshToken(token *models.RefreshToken) error
+	// GetRefreshToken looks a token up by hash, whether or not it is revoked
+	GetRefreshToken(hash string) (*models.RefreshToken, error)
+	// RevokeRefreshToken marks a token as used. It returns ErrTokenRevoked if it already
+	// was, so two concurrent rotations of one token can't both succeed.
+	RevokeRefreshToken(hash string) error
+	// RevokeTokenFamily revokes every token of a session
+	RevokeTokenFamily(familyID string) error
+}
+
+// tokenHashKey is the unique index of InMemoryTokenRepository
+const tokenHashKey = "hash"
+
+// InMemoryTokenRepository implements TokenRepository on top of the generic MemoryRepository
+type InMemoryTokenRepository struct {
+	store *MemoryRepository[models.RefreshToken, *models.RefreshToken, int64]
+}
+
+// NewInMemoryTokenRepository creates an empty in-memory token repository
+func NewInMemoryTokenRepository() *InMemoryTokenRepository {
+	return &InMemoryTokenRepository{
+		store: NewMemoryRepository[models.RefreshToken, *models.RefreshToken, int64](MemoryOptions[models.RefreshToken]{
+			NotFound: ErrTokenNotFound,
+			Unique: []UniqueKey[models.RefreshToken]{
+				{Name: tokenHashKey, Key: func(t *models.RefreshToken) string { return t.TokenHash }, Err: ErrInvalidInput},
+			},
+		}),
+	}
+}
+
+func (r *InMemoryTokenRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	if token.TokenHash == "" || token.FamilyID == "" || token.Subject == "" {
+		return ErrInvalidInput
+	}
+
+	token.ID = 0
+	return r.store.Create(token)
+}
+
+func (r *InMemoryTokenRepository) GetRefreshToken(hash string) (*models.RefreshToken, error) {
+	id, ok := r.store.Lookup(tokenHashKey, hash)
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+	return r.store.GetByID(id)
+}
+
+func (r *InMemoryTokenRepository) RevokeRefreshToken(hash string) error {
+	id, ok := r.store.Lookup(tokenHashKey, hash)
+	if !ok {
+		return ErrTokenNotFound
+	}
+
+	_, err := r.store.Modify(id, func(token *models.RefreshToken) error {
+		if token.IsRevoked() {
+			return ErrTokenRevoked
+		}
+		now := time.Now()
+		token.RevokedAt = &now
+		return nil
+	})
+	return err
+}
+
+func (r *InMemoryTokenRepository) RevokeTokenFamily(familyID string) error {
+	now := time.Now()
+	family := r.store.Find(func(token *models.RefreshToken) bool {
+		return token.FamilyID == familyID && !token.IsRevoked()
+	})
+
+	for _, token := range family {
+		_, err := r.store.Modify(token.ID, func(token *models.RefreshToken) error {
+			if token.RevokedAt == nil {
+				token.RevokedAt = &now
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
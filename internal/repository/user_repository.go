@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"context"
 	"errors"
-	"sync"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"example.com/user/internal/models"
@@ -13,52 +16,92 @@ var (
 	ErrUserNotFound    = errors.New("user not found")
 	ErrEmailExists     = errors.New("email already exists")
 	ErrInvalidInput    = errors.New("invalid input")
+	ErrInvalidPageToken = errors.New("invalid page token")
+	ErrInvalidOrderBy   = errors.New("invalid order_by")
+	ErrVersionConflict  = errors.New("version conflict")
+	ErrUserNotDeleted   = errors.New("user is not deleted")
 )
 
-// UserRepository defines the interface for user data operations
+// BatchError reports the items rejected by CreateMany or DeleteMany. Batches are
+// atomic, so when it is returned nothing from the batch was written.
+type BatchError struct {
+	// Errors maps the index of each rejected item to the reason it was rejected
+	Errors map[int]error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch rejected: %d item(s) failed", len(e.Errors))
+}
+
+// UserRepository defines the interface for user data operations.
+// Soft-deleted users are invisible to GetByID and Update and are only listed
+// when the filter asks for them; their email stays reserved.
 type UserRepository interface {
 	GetByID(id int32) (*models.User, error)
 	Create(user *models.User) error
+	// CreateMany creates users atomically, assigning their IDs; if any user is rejected
+	// none are created and a *BatchError lists every rejected user
+	CreateMany(users []*models.User) error
+	// Update stores user if its Version still matches the stored one, then bumps
+	// user.Version; otherwise it returns ErrVersionConflict
 	Update(user *models.User) error
+	// Delete soft-deletes a user; Undelete restores it
 	Delete(id int32) error
-	List(filter *pb.UserFilter) ([]*models.User, error)
+	Undelete(id int32) error
+	// DeleteMany soft-deletes users atomically, like CreateMany; repeated IDs are deleted once
+	DeleteMany(ids []int32) error
+	// List returns users matching filter in filter.OrderBy order, plus the token of
+	// the next page when filter.PageSize is set and more results remain
+	List(filter *pb.UserFilter) ([]*models.User, string, error)
+	// Count returns how many users match filter across all pages; paging, offset and limit
+	// are ignored
+	Count(filter *pb.UserFilter) (int, error)
 	EmailExists(email string) bool
+	// WithTx runs fn atomically: every change made through the repository passed
+	// to fn is committed if fn returns nil and discarded otherwise
+	WithTx(ctx context.Context, fn func(repo UserRepository) error) error
 }
 
-// InMemoryUserRepository implements UserRepository using in-memory storage
+// InMemoryUserRepository implements UserRepository on top of the generic MemoryRepository
 type InMemoryUserRepository struct {
-	users  map[int32]*models.User
-	nextID int32
-	mutex  sync.RWMutex
+	store *MemoryRepository[models.User, *models.User, int32]
+}
+
+// emailKey is the unique index that keeps emails reserved, deleted users included
+const emailKey = "email"
+
+// newUserStore creates the generic store backing InMemoryUserRepository
+func newUserStore() *MemoryRepository[models.User, *models.User, int32] {
+	return NewMemoryRepository[models.User, *models.User, int32](MemoryOptions[models.User]{
+		NotFound: ErrUserNotFound,
+		Unique: []UniqueKey[models.User]{
+			{Name: emailKey, Key: func(u *models.User) string { return u.Email }, Err: ErrEmailExists},
+		},
+	})
 }
 
-// NewInMemoryUserRepository creates a new in-memory user repository with sample data
-func NewInMemoryUserRepository() *InMemoryUserRepository {
-	now := time.Now()
-	users := map[int32]*models.User{
-		1: {ID: 1, Name: "John Doe", Email: "john@example.com", Role: "admin", CreatedAt: now, UpdatedAt: now},
-		2: {ID: 2, Name: "Jane Smith", Email: "jane@example.com", Role: "user", CreatedAt: now, UpdatedAt: now},
-		3: {ID: 3, Name: "Bob Johnson", Email: "bob@example.com", Role: "user", CreatedAt: now, UpdatedAt: now},
+// NewInMemoryUserRepository creates a new in-memory user repository holding seed,
+// numbered from 1 in order (see SampleUsers and LoadSeedFile)
+func NewInMemoryUserRepository(seed []*models.User) *InMemoryUserRepository {
+	r := &InMemoryUserRepository{store: newUserStore()}
+	for _, user := range seed {
+		userCopy := *user
+		r.Create(&userCopy)
 	}
 	
-	return &InMemoryUserRepository{
-		users:  users,
-		nextID: 4,
-	}
+	return r
 }
 
 func (r *InMemoryUserRepository) GetByID(id int32) (*models.User, error) {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
-	
-	user, exists := r.users[id]
-	if !exists {
+	user, err := r.store.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsDeleted() {
 		return nil, ErrUserNotFound
 	}
 	
-	// Return a copy to prevent external modifications
-	userCopy := *user
-	return &userCopy, nil
+	return user, nil
 }
 
 func (r *InMemoryUserRepository) Create(user *models.User) error {
@@ -66,115 +109,224 @@ func (r *InMemoryUserRepository) Create(user *models.User) error {
 		return ErrInvalidInput
 	}
 	
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-	
-	// Check for duplicate email
-	for _, existingUser := range r.users {
-		if existingUser.Email == user.Email {
-			return ErrEmailExists
-		}
-	}
-	
-	user.ID = r.nextID
-	r.nextID++
-	r.users[user.ID] = user
-	
-	return nil
+	// IDs are always assigned by the repository, and new users start out active
+	user.ID = 0
+	user.Version = 1
+	user.Status = models.StatusActive
+	return r.store.Create(user)
+}
+
+func (r *InMemoryUserRepository) CreateMany(users []*models.User) error {
+	return r.store.WithTx(context.Background(), func(tx *MemoryRepository[models.User, *models.User, int32]) error {
+		return createEach(&InMemoryUserRepository{store: tx}, users)
+	})
 }
 
 func (r *InMemoryUserRepository) Update(user *models.User) error {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-	
-	if _, exists := r.users[user.ID]; !exists {
-		return ErrUserNotFound
+	updated, err := r.store.Modify(user.ID, func(existing *models.User) error {
+		if existing.IsDeleted() {
+			return ErrUserNotFound
+		}
+		if existing.Version != user.Version {
+			return ErrVersionConflict
+		}
+
+		*existing = *user
+		existing.Version++
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 	
-	r.users[user.ID] = user
+	user.Version = updated.Version
 	return nil
 }
 
 func (r *InMemoryUserRepository) Delete(id int32) error {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-	
-	if _, exists := r.users[id]; !exists {
-		return ErrUserNotFound
-	}
-	
-	delete(r.users, id)
-	return nil
+	_, err := r.store.Modify(id, func(user *models.User) error {
+		if user.IsDeleted() {
+			return ErrUserNotFound
+		}
+
+		now := time.Now()
+		user.DeletedAt = &now
+		user.UpdatedAt = now
+		user.Version++
+		return nil
+	})
+	return err
 }
 
-func (r *InMemoryUserRepository) List(filter *pb.UserFilter) ([]*models.User, error) {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
+func (r *InMemoryUserRepository) Undelete(id int32) error {
+	_, err := r.store.Modify(id, func(user *models.User) error {
+		if !user.IsDeleted() {
+			return ErrUserNotDeleted
+		}
+
+		user.DeletedAt = nil
+		user.UpdatedAt = time.Now()
+		user.Version++
+		return nil
+	})
+	return err
+}
+
+func (r *InMemoryUserRepository) DeleteMany(ids []int32) error {
+	return r.store.WithTx(context.Background(), func(tx *MemoryRepository[models.User, *models.User, int32]) error {
+		return deleteEach(&InMemoryUserRepository{store: tx}, ids)
+	})
+}
+
+func (r *InMemoryUserRepository) List(filter *pb.UserFilter) ([]*models.User, string, error) {
+	params, err := parseListParams(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Walk users in the requested order so pages are stable across calls,
+	// starting after the last user of the previous page
+	ordered := r.store.Find(func(user *models.User) bool {
+		return params.after == nil || params.order.compare(user, params.after) > 0
+	})
+	sort.Slice(ordered, func(i, j int) bool { return params.order.compare(ordered[i], ordered[j]) < 0 })
+
+	limit := int(filter.Limit)
+	if params.size > 0 {
+		limit = params.size + 1
+	}
 	
 	var result []*models.User
-	count := 0
+	skipped := 0
 	
-	for _, user := range r.users {
-		// Apply keyword filter
-		if filter.Keyword != "" && !contains(user.Name, filter.Keyword) {
+	for _, user := range ordered {
+		if !matchesFilter(user, filter) {
 			continue
 		}
 		
-		// Apply role filter
-		if len(filter.Roles) > 0 {
-			roleMatch := false
-			for _, role := range filter.Roles {
-				if user.Role == role {
-					roleMatch = true
-					break
-				}
-			}
-			if !roleMatch {
-				continue
-			}
+		// Apply offset
+		if skipped < int(filter.Offset) {
+			skipped++
+			continue
 		}
 		
 		// Apply limit
-		if filter.Limit > 0 && count >= int(filter.Limit) {
+		if limit > 0 && len(result) >= limit {
 			break
 		}
 		
-		// Create a copy to prevent external modifications
-		userCopy := *user
-		result = append(result, &userCopy)
-		count++
+		// Find already returned copies, so these can be handed out as is
+		result = append(result, user)
 	}
 	
-	return result, nil
+	result, nextPageToken := params.paginate(result)
+	return result, nextPageToken, nil
 }
 
-func (r *InMemoryUserRepository) EmailExists(email string) bool {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
+func (r *InMemoryUserRepository) Count(filter *pb.UserFilter) (int, error) {
+	return len(r.store.Find(func(user *models.User) bool { return matchesFilter(user, filter) })), nil
+}
+
+// matchesFilter reports whether user passes the deleted, keyword, name, email, role and
+// creation time filters of filter
+func matchesFilter(user *models.User, filter *pb.UserFilter) bool {
+	// Skip soft-deleted users unless asked for
+	if user.IsDeleted() && !filter.GetIncludeDeleted() {
+		return false
+	}
 	
-	for _, user := range r.users {
-		if user.Email == email {
-			return true
+	// Apply keyword filter, over both name and email
+	if keyword := filter.GetKeyword(); keyword != "" && !containsFold(user.Name, keyword) && !containsFold(user.Email, keyword) {
+		return false
+	}
+
+	// Apply name filter
+	if name := filter.GetNameContains(); name != "" && !containsFold(user.Name, name) {
+		return false
+	}
+
+	// Apply email filter
+	if email := filter.GetEmailContains(); email != "" && !containsFold(user.Email, email) {
+		return false
+	}
+
+	// Apply role filter
+	if len(filter.GetRoles()) > 0 {
+		roleMatch := false
+		for _, role := range filter.GetRoles() {
+			if user.Role == role {
+				roleMatch = true
+				break
+			}
+		}
+		if !roleMatch {
+			return false
 		}
 	}
-	return false
+
+	// Apply creation time range
+	if after := filter.GetCreatedAfter(); after != nil && user.CreatedAt.Before(after.AsTime()) {
+		return false
+	}
+	if before := filter.GetCreatedBefore(); before != nil && !user.CreatedAt.Before(before.AsTime()) {
+		return false
+	}
+
+	return true
+}
+
+func (r *InMemoryUserRepository) EmailExists(email string) bool {
+	_, exists := r.store.Lookup(emailKey, email)
+	return exists
 }
 
-// contains is a simple string search helper
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && 
-		   (s == substr || 
-		    (len(s) > len(substr) && 
-		     (s[:len(substr)] == substr || 
-		      s[len(s)-len(substr):] == substr ||
-		      findSubstring(s, substr))))
+func (r *InMemoryUserRepository) WithTx(ctx context.Context, fn func(repo UserRepository) error) error {
+	return r.store.WithTx(ctx, func(tx *MemoryRepository[models.User, *models.User, int32]) error {
+		return fn(&InMemoryUserRepository{store: tx})
+	})
+}
+
+// createEach creates users one at a time through repo, which must be bound to a
+// transaction, and reports every failure as a *BatchError so the caller rolls back
+func createEach(repo UserRepository, users []*models.User) error {
+	failed := make(map[int]error)
+	for i, user := range users {
+		if err := repo.Create(user); err != nil {
+			failed[i] = err
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	// The batch is about to be rolled back, so the IDs handed out are void
+	for _, user := range users {
+		user.ID = 0
+	}
+	return &BatchError{Errors: failed}
 }
 
-func findSubstring(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
+// deleteEach is the DeleteMany counterpart of createEach
+func deleteEach(repo UserRepository, ids []int32) error {
+	failed := make(map[int]error)
+	seen := make(map[int32]bool, len(ids))
+	for i, id := range ids {
+		if seen[id] {
+			continue
 		}
+		seen[id] = true
+
+		if err := repo.Delete(id); err != nil {
+			failed[i] = err
+		}
+	}
+	if len(failed) == 0 {
+		return nil
 	}
-	return false
+	return &BatchError{Errors: failed}
+}
+
+// containsFold reports whether substr is within s, ignoring case
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
 }
\ No newline at end of file
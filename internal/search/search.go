@@ -0,0 +1,197 @@
+// Package search parses the query language of SearchUsers, such as
+// `role:admin created>2024-01-01 name~john email~example.com`, into the UserFilter it
+// stands for.
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	pb "example.com/user/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// operators are the comparisons a term may make, longest first so ">=" isn't read as ">"
+var operators = []string{">=", "<=", ":", "~", ">", "<"}
+
+// Parse translates query into a filter. Terms are separated by spaces and all must hold:
+//
+//	role:admin          the role is admin; several role terms match any of them
+//	john                the name or email contains john, ignoring case
+//	name~john           the name contains john, ignoring case
+//	email~example.com   the email contains example.com, ignoring case
+//	created>2024-01-01  created after that day; also >=, <, <= and : (on that day), with
+//	                    a date or an RFC 3339 time
+//	deleted:true        soft-deleted users are included
+//
+// Values with spaces are quoted, as in name~"john doe".
+func Parse(query string) (*pb.UserFilter, error) {
+	terms, err := split(query)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &pb.UserFilter{}
+	for _, term := range terms {
+		field, op, value := parseTerm(term)
+		if err := apply(filter, field, op, value); err != nil {
+			return nil, fmt.Errorf("%s: %w", term, err)
+		}
+	}
+	return filter, nil
+}
+
+// split cuts query into terms at unquoted spaces, leaving quotes in place
+func split(query string) ([]string, error) {
+	var terms []string
+	var term strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			term.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+		default:
+			term.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote")
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms, nil
+}
+
+// parseTerm splits a term into its field, operator and unquoted value; a bare word has
+// no field or operator
+func parseTerm(term string) (field, op, value string) {
+	end := strings.IndexFunc(term, func(r rune) bool { return !unicode.IsLetter(r) && r != '_' })
+	if end > 0 {
+		for _, candidate := range operators {
+			if strings.HasPrefix(term[end:], candidate) {
+				return term[:end], candidate, unquote(term[end+len(candidate):])
+			}
+		}
+	}
+	return "", "", unquote(term)
+}
+
+// unquote strips the quotes around a value
+func unquote(value string) string {
+	if s, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+		return s
+	}
+	return strings.ReplaceAll(value, `"`, "")
+}
+
+// apply sets the part of filter a term stands for
+func apply(filter *pb.UserFilter, field, op, value string) error {
+	if value == "" {
+		return errors.New("missing value")
+	}
+
+	switch field {
+	case "":
+		if filter.Keyword != "" {
+			return errors.New("only one word is allowed")
+		}
+		filter.Keyword = value
+	case "name":
+		if op != "~" {
+			return errors.New(`name only supports ~ (contains)`)
+		}
+		if filter.NameContains != "" {
+			return errors.New("only one name term is allowed")
+		}
+		filter.NameContains = value
+	case "email":
+		if op != "~" {
+			return errors.New(`email only supports ~ (contains)`)
+		}
+		if filter.EmailContains != "" {
+			return errors.New("only one email term is allowed")
+		}
+		filter.EmailContains = value
+	case "role":
+		if op != ":" {
+			return errors.New("role only supports :")
+		}
+		filter.Roles = append(filter.Roles, value)
+	case "deleted":
+		include, err := strconv.ParseBool(value)
+		if op != ":" || err != nil {
+			return errors.New("deleted only supports :true or :false")
+		}
+		filter.IncludeDeleted = include
+	case "created":
+		return applyCreated(filter, op, value)
+	default:
+		return fmt.Errorf("unknown field %q (fields: name, email, role, created, deleted)", field)
+	}
+	return nil
+}
+
+// applyCreated narrows the creation time range of filter. A date covers its whole day
+// in UTC, a time just that instant, so created>2024-01-01 starts the day after.
+func applyCreated(filter *pb.UserFilter, op, value string) error {
+	start, end, err := parseInstant(value)
+	if err != nil {
+		return err
+	}
+
+	switch op {
+	case ">":
+		filter.CreatedAfter = later(filter.CreatedAfter, end)
+	case ">=":
+		filter.CreatedAfter = later(filter.CreatedAfter, start)
+	case "<":
+		filter.CreatedBefore = earlier(filter.CreatedBefore, start)
+	case "<=":
+		filter.CreatedBefore = earlier(filter.CreatedBefore, end)
+	case ":":
+		filter.CreatedAfter = later(filter.CreatedAfter, start)
+		filter.CreatedBefore = earlier(filter.CreatedBefore, end)
+	default:
+		return errors.New("created only supports :, >, >=, < and <=")
+	}
+	return nil
+}
+
+// parseInstant parses a date or an RFC 3339 time into the half-open range it covers
+func parseInstant(value string) (start, end time.Time, err error) {
+	if day, err := time.Parse(time.DateOnly, value); err == nil {
+		return day, day.AddDate(0, 0, 1), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%q is neither a date such as 2024-01-01 nor an RFC 3339 time", value)
+	}
+	return t, t.Add(time.Nanosecond), nil
+}
+
+// later returns the later of a bound already set, if any, and t
+func later(bound *timestamppb.Timestamp, t time.Time) *timestamppb.Timestamp {
+	if bound != nil && bound.AsTime().After(t) {
+		return bound
+	}
+	return timestamppb.New(t)
+}
+
+// earlier returns the earlier of a bound already set, if any, and t
+func earlier(bound *timestamppb.Timestamp, t time.Time) *timestamppb.Timestamp {
+	if bound != nil && bound.AsTime().Before(t) {
+		return bound
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,97 @@
+package search
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	pb "example.com/user/proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func day(s string) *timestamppb.Timestamp {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return timestamppb.New(t)
+}
+
+func TestParse(t *testing.T) {
+	instant := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		query string
+		want  *pb.UserFilter
+	}{
+		{"", &pb.UserFilter{}},
+		{"   ", &pb.UserFilter{}},
+		{"john", &pb.UserFilter{Keyword: "john"}},
+		{"name~john", &pb.UserFilter{NameContains: "john"}},
+		{"john name~doe", &pb.UserFilter{Keyword: "john", NameContains: "doe"}},
+		{`name~"john doe"`, &pb.UserFilter{NameContains: "john doe"}},
+		{`"john doe"`, &pb.UserFilter{Keyword: "john doe"}},
+		{"email~example.com", &pb.UserFilter{EmailContains: "example.com"}},
+		{"role:admin", &pb.UserFilter{Roles: []string{"admin"}}},
+		{"role:admin role:auditor", &pb.UserFilter{Roles: []string{"admin", "auditor"}}},
+		{"deleted:true", &pb.UserFilter{IncludeDeleted: true}},
+		{"deleted:false", &pb.UserFilter{}},
+		{"created>2024-01-01", &pb.UserFilter{CreatedAfter: day("2024-01-02")}},
+		{"created>=2024-01-01", &pb.UserFilter{CreatedAfter: day("2024-01-01")}},
+		{"created<2024-01-01", &pb.UserFilter{CreatedBefore: day("2024-01-01")}},
+		{"created<=2024-01-01", &pb.UserFilter{CreatedBefore: day("2024-01-02")}},
+		{"created:2024-01-01", &pb.UserFilter{CreatedAfter: day("2024-01-01"), CreatedBefore: day("2024-01-02")}},
+		{"created>=2024-01-01T12:00:00Z", &pb.UserFilter{CreatedAfter: timestamppb.New(instant)}},
+		{"created>2024-01-01T12:00:00Z", &pb.UserFilter{CreatedAfter: timestamppb.New(instant.Add(time.Nanosecond))}},
+		// Several bounds narrow the range
+		{"created>=2024-01-01 created>=2024-03-01 created<2024-06-01 created<2024-05-01",
+			&pb.UserFilter{CreatedAfter: day("2024-03-01"), CreatedBefore: day("2024-05-01")}},
+		{"role:admin created>2024-01-01 name~john email~example.com", &pb.UserFilter{
+			Roles:         []string{"admin"},
+			CreatedAfter:  day("2024-01-02"),
+			NameContains:  "john",
+			EmailContains: "example.com",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.query, err)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`name~"john`, "unterminated quote"},
+		{"john doe", "only one word is allowed"},
+		{"name~john name~doe", "only one name term is allowed"},
+		{"name:john", "name only supports ~"},
+		{"email~a email~b", "only one email term is allowed"},
+		{"email:a@example.com", "email only supports ~"},
+		{"role~admin", "role only supports :"},
+		{"role:", "missing value"},
+		{"deleted:maybe", "deleted only supports :true or :false"},
+		{"deleted~true", "deleted only supports :true or :false"},
+		{"created>yesterday", "neither a date"},
+		{"created~2024-01-01", "created only supports"},
+		{"age>30", `unknown field "age"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse(%q) error = %v, want one containing %q", tt.query, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"example.com/user/internal/auth"
+	"example.com/user/internal/config"
+	"example.com/user/internal/models"
+	"example.com/user/internal/search"
+	pb "example.com/user/proto"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// Supported values for AUTH_MODE
+const (
+	AuthModeNone   = "none"
+	AuthModeJWT    = "jwt"
+	AuthModeOIDC   = "oidc"
+	AuthModeAPIKey = "apikey"
+	AuthModeHMAC   = "hmac"
+)
+
+// publicMethods can be called without credentials: reflection so tools like grpcurl can
+// discover the API, health checks for probes and load balancers, and RefreshToken since
+// it is used once the access token has expired
+var publicMethods = []string{
+	healthpb.Health_Check_FullMethodName,
+	healthpb.Health_List_FullMethodName,
+	healthpb.Health_Watch_FullMethodName,
+	grpc_reflection_v1.ServerReflection_ServerReflectionInfo_FullMethodName,
+	grpc_reflection_v1alpha.ServerReflection_ServerReflectionInfo_FullMethodName,
+	pb.AuthService_RefreshToken_FullMethodName,
+}
+
+// rbacPolicy restricts destructive and bulk RPCs, account suspension, the audit log and
+// AdminService to admins; every other method is open to any authenticated caller unless
+// listed in ADMIN_METHODS
+var rbacPolicy = auth.Policy{
+	pb.UserService_DeleteUser_FullMethodName:             {auth.RoleAdmin},
+	pb.UserService_UndeleteUser_FullMethodName:           {auth.RoleAdmin},
+	pb.UserService_SuspendUser_FullMethodName:            {auth.RoleAdmin},
+	pb.UserService_ActivateUser_FullMethodName:           {auth.RoleAdmin},
+	pb.UserService_CreateUsers_FullMethodName:            {auth.RoleAdmin},
+	pb.UserService_BulkUpdateUsers_FullMethodName:        {auth.RoleAdmin},
+	pb.UserService_ImportUsers_FullMethodName:            {auth.RoleAdmin},
+	pb.UserService_GetAuditLog_FullMethodName:            {auth.RoleAdmin},
+	pb.UserService_GetUserHistory_FullMethodName:         {auth.RoleAdmin},
+	"/" + pb.AdminService_ServiceDesc.ServiceName + "/*": {auth.RoleAdmin},
+}
+
+// requestPolicy keeps dumps of the whole user table to admins: other callers must
+// narrow StreamUsers, ListUsers, SearchUsers and ExportUsers down by keyword (a bare word),
+// name, email or role, and WatchUsers down to given users. It also keeps reading the chat
+// history as a given user to admins, as others read theirs without naming themselves,
+// and giving users roles, which sessions pick up when they are renewed.
+var requestPolicy = auth.RequestPolicy{
+	pb.UserService_CreateUser_FullMethodName:     {Roles: []string{auth.RoleAdmin}, Matches: assignsRole},
+	pb.UserService_UpdateUser_FullMethodName:     {Roles: []string{auth.RoleAdmin}, Matches: assignsRole},
+	pb.UserService_StreamUsers_FullMethodName:    {Roles: []string{auth.RoleAdmin}, Matches: unfilteredUserList},
+	pb.UserService_ListUsers_FullMethodName:      {Roles: []string{auth.RoleAdmin}, Matches: unfilteredUserList},
+	pb.UserService_SearchUsers_FullMethodName:    {Roles: []string{auth.RoleAdmin}, Matches: unfilteredUserList},
+	pb.UserService_ExportUsers_FullMethodName:    {Roles: []string{auth.RoleAdmin}, Matches: unfilteredUserList},
+	pb.UserService_WatchUsers_FullMethodName:     {Roles: []string{auth.RoleAdmin}, Matches: unfilteredWatch},
+	pb.UserService_GetChatHistory_FullMethodName: {Roles: []string{auth.RoleAdmin}, Matches: namesChatUser},
+}
+
+func unfilteredUserList(req any) bool {
+	switch r := req.(type) {
+	case *pb.UserFilter:
+		return r.Keyword == "" && r.NameContains == "" && r.EmailContains == "" && len(r.Roles) == 0
+	case *pb.ListUsersRequest:
+		return r.Keyword == "" && r.EmailContains == "" && len(r.Roles) == 0
+	case *pb.ExportUsersRequest:
+		return r.Keyword == "" && r.EmailContains == "" && len(r.Roles) == 0
+	case *pb.SearchUsersRequest:
+		// A query that doesn't parse fails validation instead
+		filter, err := search.Parse(r.Query)
+		return err == nil && filter.Keyword == "" && filter.NameContains == "" && filter.EmailContains == "" &&
+			len(filter.Roles) == 0
+	}
+	return false
+}
+
+func assignsRole(req any) bool {
+	switch r := req.(type) {
+	case *pb.CreateUserRequest:
+		// New users get the default role anyway
+		role := models.RoleName(r.RoleType, r.Role)
+		return role != "" && role != models.RoleUser
+	case *pb.UpdateUserRequest:
+		if len(r.GetUpdateMask().GetPaths()) > 0 {
+			return models.MaskNames(r, "role")
+		}
+		return models.RoleName(r.RoleType, r.Role) != ""
+	}
+	return false
+}
+
+func namesChatUser(req any) bool {
+	r, ok := req.(*pb.ChatHistoryRequest)
+	return ok && r.User != ""
+}
+
+func unfilteredWatch(req any) bool {
+	r, ok := req.(*pb.WatchUsersRequest)
+	return ok && len(r.UserIds) == 0
+}
+
+// authorizationPolicy extends rbacPolicy with the admin-only method patterns of cfg
+func authorizationPolicy(cfg config.AuthConfig) (auth.Policy, error) {
+	policy := make(auth.Policy, len(rbacPolicy)+len(cfg.AdminMethods))
+	for method, roles := range rbacPolicy {
+		policy[method] = roles
+	}
+	for _, pattern := range cfg.AdminMethods {
+		policy[pattern] = []string{auth.RoleAdmin}
+	}
+	return policy, policy.Validate()
+}
+
+// authenticator builds the Authenticator for the configured AUTH_MODE, or nil when
+// authentication is off
+func authenticator(cfg config.AuthConfig) (auth.Authenticator, error) {
+	switch cfg.Mode {
+	case AuthModeNone, "":
+		return nil, nil
+	case AuthModeJWT:
+		return auth.NewJWTAuthenticator(cfg.JWTSecret, cfg.JWTIssuer, cfg.JWTAudience)
+	case AuthModeOIDC:
+		return auth.NewOIDCAuthenticator(context.Background(), cfg.OIDCIssuerURL, cfg.OIDCAudience, cfg.OIDCRoleClaim)
+	case AuthModeAPIKey:
+		keys := cfg.APIKeys
+		if cfg.APIKeysFile != "" {
+			fromFile, err := auth.LoadAPIKeysFile(cfg.APIKeysFile)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, fromFile...)
+		}
+		return auth.NewAPIKeyAuthenticator(keys)
+	case AuthModeHMAC:
+		return auth.NewSignatureAuthenticator(cfg.SigningKeys, cfg.SignatureWindow)
+	}
+	return nil, fmt.Errorf("unknown AUTH_MODE %q (expected %s, %s, %s, %s or %s)",
+		cfg.Mode, AuthModeNone, AuthModeJWT, AuthModeOIDC, AuthModeAPIKey, AuthModeHMAC)
+}
@@ -0,0 +1,287 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"example.com/user/internal/auth"
+	"example.com/user/internal/config"
+	"example.com/user/internal/models"
+	"example.com/user/internal/repository"
+	"example.com/user/internal/service"
+	pb "example.com/user/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+// authTestServer serves UserService and AuthService behind the authentication and
+// authorization interceptors of the server, in jwt mode
+type authTestServer struct {
+	users  pb.UserServiceClient
+	auth   pb.AuthServiceClient
+	issuer *auth.JWTAuthenticator
+	repo   repository.UserRepository
+}
+
+func newAuthTestServer(t *testing.T) *authTestServer {
+	t.Helper()
+
+	issuer, err := auth.NewJWTAuthenticator(testJWTSecret, "test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := repository.NewInMemoryUserRepository(repository.SampleUsers())
+	userSvc := service.NewUserService(repo, repository.NewInMemoryAuditRepository(), repository.NewHooks(),
+		repository.NewInMemoryPreferencesRepository(), nil, 0, repository.NewInMemoryChatRepository())
+	authSvc := service.NewAuthService(repository.NewInMemoryTokenRepository(), userSvc.CheckAccount, userSvc.AccountRole,
+		issuer, time.Minute, time.Hour)
+
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		auth.UnaryServerInterceptor(issuer, publicMethods...),
+		auth.UnaryAccountInterceptor(userSvc.CheckAccount),
+		auth.UnaryAuthorizationInterceptor(rbacPolicy, requestPolicy),
+	))
+	pb.RegisterUserServiceServer(srv, userSvc)
+	pb.RegisterAuthServiceServer(srv, authSvc)
+
+	lis := bufconn.Listen(inprocessBufferSize)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///test",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return &authTestServer{
+		users:  pb.NewUserServiceClient(conn),
+		auth:   pb.NewAuthServiceClient(conn),
+		issuer: issuer,
+		repo:   repo,
+	}
+}
+
+// as returns a context calling with an access token of subject and role
+func (s *authTestServer) as(t *testing.T, subject, role string) context.Context {
+	t.Helper()
+
+	token, err := s.issuer.Issue(subject, role, "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+// refreshedRole renews the session of ctx once and returns the role of the new access token
+func (s *authTestServer) refreshedRole(t *testing.T, ctx context.Context) (string, error) {
+	t.Helper()
+
+	session, err := s.auth.IssueTokens(ctx, &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+	res, err := s.auth.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: session.RefreshToken})
+	if err != nil {
+		return "", err
+	}
+	claims, err := s.issuer.Verify(res.AccessToken)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	return claims.Role, nil
+}
+
+func TestSelfPromotionIsNotRenewed(t *testing.T) {
+	s := newAuthTestServer(t)
+	// Jane, user 2, is a plain user
+	ctx := s.as(t, "2", auth.RoleUser)
+
+	promotions := []*pb.UpdateUserRequest{
+		{Id: 2, RoleType: pb.Role_ROLE_ADMIN},
+		{Id: 2, Role: auth.RoleAdmin},
+		{Id: 2, Name: "Jane", Email: "jane@example.com", Role: auth.RoleAdmin, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}}},
+		{Id: 2, RoleType: pb.Role_ROLE_ADMIN, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"role"}}},
+	}
+	for _, req := range promotions {
+		if _, err := s.users.UpdateUser(ctx, req); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("UpdateUser(%v) = %v, want PermissionDenied", req, err)
+		}
+	}
+
+	role, err := s.refreshedRole(t, ctx)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if role != auth.RoleUser {
+		t.Errorf("refreshed role = %q, want %q", role, auth.RoleUser)
+	}
+}
+
+func TestRefreshPicksUpRoleChangeByAdmin(t *testing.T) {
+	s := newAuthTestServer(t)
+	ctx := s.as(t, "2", auth.RoleUser)
+	session, err := s.auth.IssueTokens(ctx, &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+
+	admin := s.as(t, "ops", auth.RoleAdmin)
+	if _, err := s.users.UpdateUser(admin, &pb.UpdateUserRequest{Id: 2, Role: "auditor"}); err != nil {
+		t.Fatalf("UpdateUser as admin: %v", err)
+	}
+
+	res, err := s.auth.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: session.RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	claims, err := s.issuer.Verify(res.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Role != "auditor" {
+		t.Errorf("refreshed role = %q, want auditor", claims.Role)
+	}
+}
+
+func TestRefreshRefusesDeletedUsers(t *testing.T) {
+	s := newAuthTestServer(t)
+	ctx := s.as(t, "1", auth.RoleAdmin)
+	session, err := s.auth.IssueTokens(ctx, &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+	if err := s.repo.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.auth.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: session.RefreshToken})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("RefreshToken of a deleted user = %v, want Unauthenticated", err)
+	}
+	if _, err := s.users.GetUser(ctx, &pb.UserRequest{Id: 2}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("GetUser as a deleted user = %v, want Unauthenticated", err)
+	}
+}
+
+func TestAssignsRole(t *testing.T) {
+	role := &fieldmaskpb.FieldMask{Paths: []string{"role"}}
+	name := &fieldmaskpb.FieldMask{Paths: []string{"name"}}
+	all := &fieldmaskpb.FieldMask{Paths: []string{"*"}}
+
+	tests := []struct {
+		name string
+		req  any
+		want bool
+	}{
+		{"create without role", &pb.CreateUserRequest{Name: "a", Email: "a@example.com"}, false},
+		{"create as user", &pb.CreateUserRequest{Role: models.RoleUser}, false},
+		{"create as user by type", &pb.CreateUserRequest{RoleType: pb.Role_ROLE_USER}, false},
+		{"create as admin", &pb.CreateUserRequest{RoleType: pb.Role_ROLE_ADMIN}, true},
+		{"create with custom role", &pb.CreateUserRequest{Role: "auditor"}, true},
+		{"update name", &pb.UpdateUserRequest{Id: 1, Name: "a"}, false},
+		{"update role by name", &pb.UpdateUserRequest{Id: 1, Role: "user"}, true},
+		{"update role by type", &pb.UpdateUserRequest{Id: 1, RoleType: pb.Role_ROLE_ADMIN}, true},
+		{"mask names role", &pb.UpdateUserRequest{Id: 1, UpdateMask: role}, true},
+		{"mask names everything", &pb.UpdateUserRequest{Id: 1, UpdateMask: all}, true},
+		{"role outside mask", &pb.UpdateUserRequest{Id: 1, Role: "admin", UpdateMask: name}, false},
+		{"other request", &pb.UserRequest{Id: 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := assignsRole(tt.req); got != tt.want {
+				t.Errorf("assignsRole() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestPolicyMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		req    any
+		want   bool
+	}{
+		{"stream everyone", pb.UserService_StreamUsers_FullMethodName, &pb.UserFilter{}, true},
+		{"stream by keyword", pb.UserService_StreamUsers_FullMethodName, &pb.UserFilter{Keyword: "jo"}, false},
+		{"stream by name", pb.UserService_StreamUsers_FullMethodName, &pb.UserFilter{NameContains: "jo"}, false},
+		{"stream by role", pb.UserService_StreamUsers_FullMethodName, &pb.UserFilter{Roles: []string{"user"}}, false},
+		// Paging and ordering don't narrow anything down
+		{"stream in order", pb.UserService_StreamUsers_FullMethodName, &pb.UserFilter{OrderBy: "name", Limit: 1}, true},
+		{"list everyone", pb.UserService_ListUsers_FullMethodName, &pb.ListUsersRequest{PageSize: 10}, true},
+		{"list by email", pb.UserService_ListUsers_FullMethodName, &pb.ListUsersRequest{EmailContains: "@example"}, false},
+		{"export everyone", pb.UserService_ExportUsers_FullMethodName, &pb.ExportUsersRequest{}, true},
+		{"export by role", pb.UserService_ExportUsers_FullMethodName, &pb.ExportUsersRequest{Roles: []string{"admin"}}, false},
+		{"search everyone", pb.UserService_SearchUsers_FullMethodName, &pb.SearchUsersRequest{}, true},
+		{"search by date only", pb.UserService_SearchUsers_FullMethodName, &pb.SearchUsersRequest{Query: "created>2024-01-01"}, true},
+		{"search by word", pb.UserService_SearchUsers_FullMethodName, &pb.SearchUsersRequest{Query: "john"}, false},
+		{"search by role", pb.UserService_SearchUsers_FullMethodName, &pb.SearchUsersRequest{Query: "role:admin"}, false},
+		{"unparsable search", pb.UserService_SearchUsers_FullMethodName, &pb.SearchUsersRequest{Query: "age>3"}, false},
+		{"watch everyone", pb.UserService_WatchUsers_FullMethodName, &pb.WatchUsersRequest{}, true},
+		{"watch some", pb.UserService_WatchUsers_FullMethodName, &pb.WatchUsersRequest{UserIds: []int32{2}}, false},
+		{"own chat history", pb.UserService_GetChatHistory_FullMethodName, &pb.ChatHistoryRequest{Peer: "bob"}, false},
+		{"chat history as another", pb.UserService_GetChatHistory_FullMethodName, &pb.ChatHistoryRequest{User: "ada"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, ok := requestPolicy[tt.method]
+			if !ok {
+				t.Fatalf("no request rule for %s", tt.method)
+			}
+			if got := rule.Matches(tt.req); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizationPolicy(t *testing.T) {
+	policy, err := authorizationPolicy(config.AuthConfig{AdminMethods: []string{
+		pb.UserService_GetUser_FullMethodName,
+		"/" + pb.UserService_ServiceDesc.ServiceName + "/Batch*",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method string
+		want   bool // whether a user may call it
+	}{
+		{pb.UserService_DeleteUser_FullMethodName, false},
+		{pb.AdminService_SetLogLevel_FullMethodName, false},
+		{pb.UserService_GetUser_FullMethodName, false},
+		{pb.UserService_BatchGetUsers_FullMethodName, false},
+		{pb.UserService_ListUsers_FullMethodName, true},
+		{pb.UserService_UpdateUser_FullMethodName, true},
+	}
+	for _, tt := range tests {
+		if got := policy.Allows(tt.method, auth.RoleUser); got != tt.want {
+			t.Errorf("Allows(%s, user) = %v, want %v", tt.method, got, tt.want)
+		}
+		if !policy.Allows(tt.method, auth.RoleAdmin) {
+			t.Errorf("Allows(%s, admin) = false, want true", tt.method)
+		}
+	}
+	if len(rbacPolicy) != len(policy)-2 {
+		t.Errorf("authorizationPolicy changed rbacPolicy, which now has %d entries", len(rbacPolicy))
+	}
+
+	if _, err := authorizationPolicy(config.AuthConfig{AdminMethods: []string{"/user.UserService/[Get"}}); err == nil {
+		t.Error("authorizationPolicy with a malformed pattern = nil error, want one")
+	}
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// certReloader serves a certificate and key pair from disk, picking up new files when
+// they are rotated (e.g. by cert-manager) without restarting the server. The files are
+// checked on handshakes, at most once per interval.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	stamp   [2]fileStamp // of certFile and keyFile when cert was loaded
+	checked time.Time
+}
+
+// fileStamp identifies a version of a file
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func newCertReloader(certFile, keyFile string, interval time.Duration) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile, interval: interval}
+
+	stamp, err := r.stat()
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	r.cert, r.stamp, r.checked = &cert, stamp, time.Now()
+
+	return r, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) >= r.interval {
+		r.checked = time.Now()
+		r.reloadIfChanged()
+	}
+	return r.cert, nil
+}
+
+// reloadIfChanged loads the files again if either changed. While a rotation is half
+// written the pair doesn't match; the current certificate is kept and the load retried
+// on a later check.
+func (r *certReloader) reloadIfChanged() {
+	stamp, err := r.stat()
+	if err != nil {
+		slog.Warn("Failed to check TLS certificate for changes", "error", err)
+		return
+	}
+	if stamp == r.stamp {
+		return
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		slog.Warn("Failed to reload TLS certificate, keeping the current one", "error", err)
+		return
+	}
+	r.cert, r.stamp = &cert, stamp
+	slog.Info("🔄 Reloaded TLS certificate", "file", r.certFile, "expires", cert.Leaf.NotAfter.Format(time.RFC3339))
+}
+
+// stat follows symlinks, so the atomic symlink swap of Kubernetes secret volumes counts
+// as a change
+func (r *certReloader) stat() ([2]fileStamp, error) {
+	var stamp [2]fileStamp
+	for i, path := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return stamp, err
+		}
+		stamp[i] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	return stamp, nil
+}
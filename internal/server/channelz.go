@@ -0,0 +1,17 @@
+package server
+
+import (
+	"google.golang.org/grpc"
+	channelzsvc "google.golang.org/grpc/channelz/service"
+	"google.golang.org/grpc/reflection"
+)
+
+// newChannelzServer creates a plaintext gRPC server exposing channelz, the live state of
+// the process's gRPC servers, channels, sockets and streams, for tools like grpcdebug.
+// It has no authentication, so its listener must stay private.
+func newChannelzServer() *grpc.Server {
+	srv := grpc.NewServer()
+	channelzsvc.RegisterChannelzServiceToServer(srv)
+	reflection.Register(srv)
+	return srv
+}
@@ -0,0 +1,338 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"example.com/user/internal/ratelimit"
+	pb "example.com/user/proto"
+	"example.com/user/proto/protoconnect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// connectFrontend serves UserService over the Connect protocol with connect-go, which
+// also speaks gRPC and gRPC-Web, so plain HTTP clients such as curl can call it with JSON.
+// Requests become calls on an in-process gRPC server, so they share the gRPC handlers
+// and interceptors.
+type connectFrontend struct {
+	http    *http.Server
+	backend *inprocess
+}
+
+// newConnectFrontend creates the Connect endpoint on addr in front of server, serving
+// HTTPS with tlsConfig unless it is nil
+func newConnectFrontend(addr string, server *grpc.Server, tlsConfig *tls.Config, maxMessageSize int) (*connectFrontend, error) {
+	backend, err := newInprocess(server, maxMessageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(protoconnect.NewUserServiceHandler(
+		&connectService{client: pb.NewUserServiceClient(backend.conn)},
+		connect.WithReadMaxBytes(maxMessageSize),
+	))
+
+	// gRPC clients need HTTP/2, which plaintext connections only get with h2c
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	return &connectFrontend{
+		http: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			TLSConfig:         tlsConfig,
+			Protocols:         protocols,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		backend: backend,
+	}, nil
+}
+
+// serve serves Connect requests on lis, and their calls in process, until shutdown
+func (c *connectFrontend) serve(lis net.Listener) {
+	c.backend.serve()
+	serveHTTP(c.http, lis, "Connect")
+}
+
+// shutdown stops taking requests and waits up to timeout for those in flight; their calls
+// are drained along with the other servers'
+func (c *connectFrontend) shutdown(timeout time.Duration) {
+	shutdownHTTP(c.http, timeout)
+	c.backend.conn.Close()
+}
+
+// connectService implements the connect-go UserService by relaying each request to the
+// gRPC UserService
+type connectService struct {
+	protoconnect.UnimplementedUserServiceHandler
+	client pb.UserServiceClient
+}
+
+func (s *connectService) GetUser(ctx context.Context, req *connect.Request[pb.UserRequest]) (*connect.Response[pb.UserResponse], error) {
+	return relayUnary(ctx, req, s.client.GetUser)
+}
+
+func (s *connectService) BatchGetUsers(ctx context.Context, req *connect.Request[pb.BatchGetUsersRequest]) (*connect.Response[pb.BatchGetUsersResponse], error) {
+	return relayUnary(ctx, req, s.client.BatchGetUsers)
+}
+
+func (s *connectService) CreateUser(ctx context.Context, req *connect.Request[pb.CreateUserRequest]) (*connect.Response[pb.UserResponse], error) {
+	return relayUnary(ctx, req, s.client.CreateUser)
+}
+
+func (s *connectService) ValidateUser(ctx context.Context, req *connect.Request[pb.CreateUserRequest]) (*connect.Response[pb.ValidateUserResponse], error) {
+	return relayUnary(ctx, req, s.client.ValidateUser)
+}
+
+func (s *connectService) UpdateUser(ctx context.Context, req *connect.Request[pb.UpdateUserRequest]) (*connect.Response[pb.UserResponse], error) {
+	return relayUnary(ctx, req, s.client.UpdateUser)
+}
+
+func (s *connectService) DeleteUser(ctx context.Context, req *connect.Request[pb.UserRequest]) (*connect.Response[emptypb.Empty], error) {
+	return relayUnary(ctx, req, s.client.DeleteUser)
+}
+
+func (s *connectService) UndeleteUser(ctx context.Context, req *connect.Request[pb.UserRequest]) (*connect.Response[pb.UserResponse], error) {
+	return relayUnary(ctx, req, s.client.UndeleteUser)
+}
+
+func (s *connectService) SuspendUser(ctx context.Context, req *connect.Request[pb.UserRequest]) (*connect.Response[pb.UserResponse], error) {
+	return relayUnary(ctx, req, s.client.SuspendUser)
+}
+
+func (s *connectService) ActivateUser(ctx context.Context, req *connect.Request[pb.UserRequest]) (*connect.Response[pb.UserResponse], error) {
+	return relayUnary(ctx, req, s.client.ActivateUser)
+}
+
+func (s *connectService) GetPreferences(ctx context.Context, req *connect.Request[pb.UserRequest]) (*connect.Response[pb.UserPreferences], error) {
+	return relayUnary(ctx, req, s.client.GetPreferences)
+}
+
+func (s *connectService) UpdatePreferences(ctx context.Context, req *connect.Request[pb.UpdatePreferencesRequest]) (*connect.Response[pb.UserPreferences], error) {
+	return relayUnary(ctx, req, s.client.UpdatePreferences)
+}
+
+func (s *connectService) ListUsers(ctx context.Context, req *connect.Request[pb.ListUsersRequest]) (*connect.Response[pb.ListUsersResponse], error) {
+	return relayUnary(ctx, req, s.client.ListUsers)
+}
+
+func (s *connectService) SearchUsers(ctx context.Context, req *connect.Request[pb.SearchUsersRequest]) (*connect.Response[pb.ListUsersResponse], error) {
+	return relayUnary(ctx, req, s.client.SearchUsers)
+}
+
+func (s *connectService) GetChatHistory(ctx context.Context, req *connect.Request[pb.ChatHistoryRequest]) (*connect.Response[pb.ChatHistoryResponse], error) {
+	return relayUnary(ctx, req, s.client.GetChatHistory)
+}
+
+func (s *connectService) GetAuditLog(ctx context.Context, req *connect.Request[pb.AuditLogRequest]) (*connect.Response[pb.AuditLogResponse], error) {
+	return relayUnary(ctx, req, s.client.GetAuditLog)
+}
+
+func (s *connectService) GetUserHistory(ctx context.Context, req *connect.Request[pb.UserHistoryRequest]) (*connect.Response[pb.UserHistoryResponse], error) {
+	return relayUnary(ctx, req, s.client.GetUserHistory)
+}
+
+func (s *connectService) StreamUsers(ctx context.Context, req *connect.Request[pb.UserFilter], stream *connect.ServerStream[pb.UserResponse]) error {
+	call, err := s.client.StreamUsers(outgoingContext(ctx, req.Header(), req.Peer()), req.Msg)
+	if err != nil {
+		return connectError(err, nil)
+	}
+	return relayResponses(call, stream.Send, stream.ResponseHeader(), stream.ResponseTrailer())
+}
+
+func (s *connectService) ExportUsers(ctx context.Context, req *connect.Request[pb.ExportUsersRequest], stream *connect.ServerStream[pb.FileChunk]) error {
+	call, err := s.client.ExportUsers(outgoingContext(ctx, req.Header(), req.Peer()), req.Msg)
+	if err != nil {
+		return connectError(err, nil)
+	}
+	return relayResponses(call, stream.Send, stream.ResponseHeader(), stream.ResponseTrailer())
+}
+
+func (s *connectService) GetAvatar(ctx context.Context, req *connect.Request[pb.UserRequest], stream *connect.ServerStream[pb.AvatarChunk]) error {
+	call, err := s.client.GetAvatar(outgoingContext(ctx, req.Header(), req.Peer()), req.Msg)
+	if err != nil {
+		return connectError(err, nil)
+	}
+	return relayResponses(call, stream.Send, stream.ResponseHeader(), stream.ResponseTrailer())
+}
+
+func (s *connectService) WatchUsers(ctx context.Context, req *connect.Request[pb.WatchUsersRequest], stream *connect.ServerStream[pb.UserEvent]) error {
+	call, err := s.client.WatchUsers(outgoingContext(ctx, req.Header(), req.Peer()), req.Msg)
+	if err != nil {
+		return connectError(err, nil)
+	}
+	return relayResponses(call, stream.Send, stream.ResponseHeader(), stream.ResponseTrailer())
+}
+
+func (s *connectService) CreateUsers(ctx context.Context, stream *connect.ClientStream[pb.CreateUserRequest]) (*connect.Response[pb.BulkCreateResponse], error) {
+	return relayRequests(ctx, stream, s.client.CreateUsers)
+}
+
+func (s *connectService) BulkUpdateUsers(ctx context.Context, stream *connect.ClientStream[pb.UpdateUserRequest]) (*connect.Response[pb.BulkUpdateResponse], error) {
+	return relayRequests(ctx, stream, s.client.BulkUpdateUsers)
+}
+
+func (s *connectService) ImportUsers(ctx context.Context, stream *connect.ClientStream[pb.ImportUsersRequest]) (*connect.Response[pb.ImportUsersResponse], error) {
+	return relayRequests(ctx, stream, s.client.ImportUsers)
+}
+
+func (s *connectService) UploadAvatar(ctx context.Context, stream *connect.ClientStream[pb.UploadAvatarRequest]) (*connect.Response[pb.Avatar], error) {
+	return relayRequests(ctx, stream, s.client.UploadAvatar)
+}
+
+func (s *connectService) Chat(ctx context.Context, stream *connect.BidiStream[pb.ChatMessage, pb.ChatMessage]) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	call, err := s.client.Chat(outgoingContext(ctx, stream.RequestHeader(), stream.Peer()))
+	if err != nil {
+		return connectError(err, nil)
+	}
+
+	go func() {
+		for {
+			msg, err := stream.Receive()
+			if errors.Is(err, io.EOF) {
+				call.CloseSend()
+				return
+			}
+			if err != nil {
+				cancel()
+				return
+			}
+			if err := call.Send(msg); err != nil {
+				return
+			}
+		}
+	}()
+	return relayResponses(call, stream.Send, stream.ResponseHeader(), stream.ResponseTrailer())
+}
+
+// relayUnary makes the unary call with the message and headers of req
+func relayUnary[Req, Res any](ctx context.Context, req *connect.Request[Req], call func(context.Context, *Req, ...grpc.CallOption) (*Res, error)) (*connect.Response[Res], error) {
+	var header, trailer metadata.MD
+	res, err := call(outgoingContext(ctx, req.Header(), req.Peer()), req.Msg, grpc.Header(&header), grpc.Trailer(&trailer))
+	if err != nil {
+		return nil, connectError(err, trailer)
+	}
+	resp := connect.NewResponse(res)
+	copyMetadata(resp.Header(), header)
+	copyMetadata(resp.Trailer(), trailer)
+	return resp, nil
+}
+
+// responseStream is the receiving half of a gRPC call
+type responseStream[Res any] interface {
+	Recv() (*Res, error)
+	Header() (metadata.MD, error)
+	Trailer() metadata.MD
+}
+
+// relayResponses passes the responses of call to send until it ends, along with its
+// header and trailer
+func relayResponses[Res any](call responseStream[Res], send func(*Res) error, header, trailer http.Header) error {
+	// Headers go out with the first response, so they must be in place before it
+	if md, err := call.Header(); err == nil {
+		copyMetadata(header, md)
+	}
+	for {
+		res, err := call.Recv()
+		if errors.Is(err, io.EOF) {
+			copyMetadata(trailer, call.Trailer())
+			return nil
+		}
+		if err != nil {
+			return connectError(err, call.Trailer())
+		}
+		if err := send(res); err != nil {
+			return err
+		}
+	}
+}
+
+// relayRequests passes the requests of a Connect client stream on to the call open starts,
+// then returns its result
+func relayRequests[Req, Res any](ctx context.Context, stream *connect.ClientStream[Req], open func(context.Context, ...grpc.CallOption) (grpc.ClientStreamingClient[Req, Res], error)) (*connect.Response[Res], error) {
+	call, err := open(outgoingContext(ctx, stream.RequestHeader(), stream.Peer()))
+	if err != nil {
+		return nil, connectError(err, nil)
+	}
+	for stream.Receive() {
+		// Send fails with io.EOF once the call has ended; CloseAndRecv reports why
+		if err := call.Send(stream.Msg()); err != nil {
+			break
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+
+	res, err := call.CloseAndRecv()
+	if err != nil {
+		return nil, connectError(err, call.Trailer())
+	}
+	return clientStreamResponse(res, call)
+}
+
+// clientStreamResponse wraps the result of a client stream with its header and trailer
+func clientStreamResponse[Res any](res *Res, call grpc.ClientStream) (*connect.Response[Res], error) {
+	resp := connect.NewResponse(res)
+	if md, err := call.Header(); err == nil {
+		copyMetadata(resp.Header(), md)
+	}
+	copyMetadata(resp.Trailer(), call.Trailer())
+	return resp, nil
+}
+
+// outgoingContext passes the forwarded headers of a Connect request, and the address of
+// the client that sent it, on to its call
+func outgoingContext(ctx context.Context, header http.Header, peer connect.Peer) context.Context {
+	md := metadata.Pairs(ratelimit.ClientAddressKey, peer.Addr)
+	for _, key := range append([]string{"authorization"}, forwardedHeaders...) {
+		if values := header.Values(key); len(values) > 0 {
+			md.Append(key, values...)
+		}
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// copyMetadata adds the gRPC metadata md to the HTTP header h, encoding binary values
+// the way Connect expects
+func copyMetadata(h http.Header, md metadata.MD) {
+	for key, values := range md {
+		for _, value := range values {
+			if strings.HasSuffix(key, "-bin") {
+				value = connect.EncodeBinaryHeader([]byte(value))
+			}
+			h.Add(key, value)
+		}
+	}
+}
+
+// connectError converts the gRPC status error of a call to a Connect error with the same
+// code, message and details, carrying trailer as its metadata
+func connectError(err error, trailer metadata.MD) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	cerr := connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+	for _, detail := range st.Proto().GetDetails() {
+		if d, err := connect.NewErrorDetail(detail); err == nil {
+			cerr.AddDetail(d)
+		}
+	}
+	copyMetadata(cerr.Meta(), trailer)
+	return cerr
+}
@@ -0,0 +1,18 @@
+package server
+
+import (
+	pb "example.com/user/proto"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// controlServices are the services maintenance mode and load shedding never turn away:
+// AdminService so operators can act on a busy or draining server, and health checks and
+// reflection like publicMethods
+var controlServices = []string{
+	pb.AdminService_ServiceDesc.ServiceName,
+	healthpb.Health_ServiceDesc.ServiceName,
+	grpc_reflection_v1.ServerReflection_ServiceDesc.ServiceName,
+	grpc_reflection_v1alpha.ServerReflection_ServiceDesc.ServiceName,
+}
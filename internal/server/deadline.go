@@ -0,0 +1,20 @@
+package server
+
+import (
+	"example.com/user/internal/config"
+	"example.com/user/internal/deadline"
+)
+
+// deadlinePolicy builds the deadline policy of cfg
+func deadlinePolicy(cfg config.ServerConfig) (deadline.Policy, error) {
+	methods, err := deadline.ParseMethodTimeouts(cfg.MethodTimeouts)
+	if err != nil {
+		return deadline.Policy{}, err
+	}
+	return deadline.Policy{
+		Unary:   cfg.DefaultTimeout,
+		Stream:  cfg.DefaultStreamTimeout,
+		Min:     cfg.MinDeadline,
+		Methods: methods,
+	}, nil
+}
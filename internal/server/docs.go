@@ -0,0 +1,54 @@
+package server
+
+import (
+	"net/http"
+
+	pb "example.com/user/proto"
+)
+
+// Paths of the API documentation served by the gateway
+const (
+	openAPIPath = "/openapi.yaml"
+	docsPath    = "/docs/"
+)
+
+// swaggerUIPage renders the OpenAPI spec with Swagger UI, loaded from a CDN so the
+// server doesn't have to ship its assets
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>User Service API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "` + openAPIPath + `", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
+
+// docsHandler serves the OpenAPI spec of the gateway and Swagger UI, passing every other
+// request to api
+func docsHandler(api http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", api)
+	mux.HandleFunc(openAPIPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(pb.OpenAPI)
+	})
+	mux.HandleFunc(docsPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != docsPath {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(swaggerUIPage))
+	})
+	return mux
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"example.com/user/internal/ratelimit"
+	pb "example.com/user/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// gateway serves UserService as REST/JSON over HTTP, following the google.api.http rules
+// of the proto, along with its OpenAPI documentation. Requests become calls on an
+// in-process gRPC server with the interceptors of the network listeners, so they are
+// authenticated, limited, validated and logged the same way.
+type gateway struct {
+	http    *http.Server
+	backend *inprocess
+}
+
+// newGateway creates the gateway on addr in front of server, serving HTTPS with
+// tlsConfig unless it is nil
+func newGateway(addr string, server *grpc.Server, tlsConfig *tls.Config, maxMessageSize int) (*gateway, error) {
+	backend, err := newInprocess(server, maxMessageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(gatewayHeaderMatcher),
+		runtime.WithMetadata(clientAddress),
+		runtime.WithForwardResponseOption(setETag),
+	)
+	if err := pb.RegisterUserServiceHandler(context.Background(), mux, backend.conn); err != nil {
+		backend.conn.Close()
+		return nil, fmt.Errorf("register gateway handlers: %w", err)
+	}
+
+	return &gateway{
+		http: &http.Server{
+			Addr:              addr,
+			Handler:           docsHandler(mux),
+			TLSConfig:         tlsConfig,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		backend: backend,
+	}, nil
+}
+
+// gatewayHeaderMatcher forwards forwardedHeaders under their own name and other headers
+// the way the gateway does by default, but never the client address, which only the
+// gateway sets
+func gatewayHeaderMatcher(key string) (string, bool) {
+	if key = strings.ToLower(key); slices.Contains(forwardedHeaders, key) {
+		return key, true
+	}
+	name, ok := runtime.DefaultHeaderMatcher(key)
+	if strings.EqualFold(name, ratelimit.ClientAddressKey) {
+		return "", false
+	}
+	return name, ok
+}
+
+// clientAddress passes the address of the HTTP client on to its calls, for them to be
+// rate limited per client rather than all as the gateway
+func clientAddress(_ context.Context, r *http.Request) metadata.MD {
+	return metadata.Pairs(ratelimit.ClientAddressKey, r.RemoteAddr)
+}
+
+// setETag sets the ETag header of responses holding a single user, for clients to send
+// back in If-Match
+func setETag(ctx context.Context, w http.ResponseWriter, m proto.Message) error {
+	if user, ok := m.(*pb.UserResponse); ok && user.Etag != "" {
+		w.Header().Set("ETag", `"`+user.Etag+`"`)
+	}
+	return nil
+}
+
+// serve serves REST requests on lis, and their calls in process, until shutdown
+func (g *gateway) serve(lis net.Listener) {
+	g.backend.serve()
+	serveHTTP(g.http, lis, "Gateway")
+}
+
+// shutdown stops taking requests and waits up to timeout for those in flight; their calls
+// are drained along with the other servers'
+func (g *gateway) shutdown(timeout time.Duration) {
+	shutdownHTTP(g.http, timeout)
+	g.backend.conn.Close()
+}
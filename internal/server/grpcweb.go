@@ -0,0 +1,64 @@
+package server
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"google.golang.org/grpc"
+)
+
+// allOrigins in GRPC_WEB_ALLOWED_ORIGINS lets pages from any origin call the endpoint
+const allOrigins = "*"
+
+// grpcWeb serves gRPC-Web, the variant of gRPC browsers can speak, so browser clients
+// call the services directly instead of through a proxy such as Envoy. Calls run on a
+// server of their own: gRPC can't drain calls it serves over net/http, so that server is
+// stopped only once the HTTP server has finished them.
+type grpcWeb struct {
+	http   *http.Server
+	server *grpc.Server
+}
+
+// newGRPCWeb creates the gRPC-Web endpoint on addr in front of server, serving HTTPS with
+// tlsConfig unless it is nil. Pages may call it from their own origin or one of origins.
+func newGRPCWeb(addr string, server *grpc.Server, origins []string, tlsConfig *tls.Config) *grpcWeb {
+	allowed := func(origin string) bool {
+		return slices.Contains(origins, allOrigins) || slices.Contains(origins, origin)
+	}
+	wrapped := grpcweb.WrapServer(server,
+		grpcweb.WithOriginFunc(allowed),
+		// Browsers can't stream requests over HTTP/1.1, so client and bidirectional
+		// streams such as Chat go over WebSockets
+		grpcweb.WithWebsockets(true),
+		grpcweb.WithWebsocketOriginFunc(func(r *http.Request) bool {
+			host, err := grpcweb.WebsocketRequestOrigin(r)
+			return (err == nil && host == r.Host) || allowed(r.Header.Get("Origin"))
+		}),
+	)
+
+	return &grpcWeb{
+		http: &http.Server{
+			Addr:              addr,
+			Handler:           wrapped,
+			TLSConfig:         tlsConfig,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		server: server,
+	}
+}
+
+// serve serves gRPC-Web requests on lis until shutdown
+func (w *grpcWeb) serve(lis net.Listener) {
+	serveHTTP(w.http, lis, "gRPC-Web")
+}
+
+// shutdown stops taking requests, waits up to timeout for those in flight, then cancels
+// the rest. WebSocket streams aren't waited for.
+func (w *grpcWeb) shutdown(timeout time.Duration) {
+	shutdownHTTP(w.http, timeout)
+	w.server.Stop()
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Service names of the probe-oriented health statuses, for Kubernetes gRPC probes
+const (
+	// livenessService is SERVING while the process runs, from startup until shutdown
+	livenessService = "liveness"
+	// readinessService is SERVING while storage is reachable and fully migrated
+	readinessService = "readiness"
+)
+
+// Paths of the HTTP health endpoints served next to metrics, for probes and load
+// balancers that can't speak gRPC
+const (
+	livenessPath  = "/healthz"
+	readinessPath = "/readyz"
+)
+
+// readinessTimeout bounds a single readiness check
+const readinessTimeout = 2 * time.Second
+
+// healthChecker reports the serving status of the server through grpc.health.v1.Health.
+// The server as a whole (the empty service name) and each service it registered follow
+// readiness, since they should only be routed calls storage can answer.
+type healthChecker struct {
+	*health.Server
+	services []string
+	ready    func(ctx context.Context) error
+	interval time.Duration
+	recheck  chan struct{}
+	stop     chan struct{}
+}
+
+// newHealthChecker creates a checker for services that reports NOT_SERVING until serving
+// starts, then polls ready every interval and whenever refresh is called
+func newHealthChecker(ready func(ctx context.Context) error, interval time.Duration, services ...string) *healthChecker {
+	h := &healthChecker{
+		Server:   health.NewServer(),
+		services: append([]string{"", readinessService}, services...),
+		ready:    ready,
+		interval: interval,
+		recheck:  make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+	h.SetServingStatus(livenessService, healthpb.HealthCheckResponse_NOT_SERVING)
+	h.setReady(false)
+	return h
+}
+
+// start marks the process live, checks readiness and keeps checking it in the background
+func (h *healthChecker) start() {
+	h.SetServingStatus(livenessService, healthpb.HealthCheckResponse_SERVING)
+
+	ready := h.check(true)
+	go func() {
+		// Without an interval readiness is only checked again on refresh
+		var tick <-chan time.Time
+		if h.interval > 0 {
+			ticker := time.NewTicker(h.interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for {
+			select {
+			case <-tick:
+				ready = h.check(ready)
+			case <-h.recheck:
+				ready = h.check(ready)
+			case <-h.stop:
+				return
+			}
+		}
+	}()
+}
+
+// refresh checks readiness again without waiting for the next interval, e.g. once
+// maintenance mode changes
+func (h *healthChecker) refresh() {
+	select {
+	case h.recheck <- struct{}{}:
+	default:
+		// A check is already pending
+	}
+}
+
+// handleHTTP serves the liveness and readiness statuses on mux, answering 200 while
+// SERVING and 503 otherwise
+func (h *healthChecker) handleHTTP(mux *http.ServeMux) {
+	for path, service := range map[string]string{livenessPath: livenessService, readinessPath: readinessService} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			res, err := h.Check(r.Context(), &healthpb.HealthCheckRequest{Service: service})
+			if err != nil || res.Status != healthpb.HealthCheckResponse_SERVING {
+				http.Error(w, healthpb.HealthCheckResponse_NOT_SERVING.String(), http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprintln(w, res.Status)
+		})
+	}
+}
+
+// Shutdown stops readiness checks and reports every status NOT_SERVING for good
+func (h *healthChecker) Shutdown() {
+	close(h.stop)
+	h.Server.Shutdown()
+}
+
+// check runs the readiness check and reports its result, logging changes from wasReady
+func (h *healthChecker) check(wasReady bool) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), readinessTimeout)
+	defer cancel()
+
+	err := h.ready(ctx)
+	switch {
+	case err != nil && wasReady:
+		slog.Warn("Server not ready", "error", err)
+	case err == nil && !wasReady:
+		slog.Info("Server ready")
+	}
+	h.setReady(err == nil)
+	return err == nil
+}
+
+// setReady reports the readiness status for the server and every service
+func (h *healthChecker) setReady(ready bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if ready {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	for _, service := range h.services {
+		h.SetServingStatus(service, status)
+	}
+}
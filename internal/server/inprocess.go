@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"example.com/user/internal/auth"
+	"example.com/user/internal/logging"
+	"example.com/user/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// inprocessBufferSize is the buffer of an in-process connection
+const inprocessBufferSize = 1 << 20
+
+// forwardedHeaders are the HTTP headers frontends pass on to their calls as metadata,
+// besides Authorization which they always pass on
+var forwardedHeaders = []string{auth.APIKeyHeader, logging.RequestIDHeader, service.IfMatchHeader}
+
+// inprocess is a gRPC server reached over an in-memory connection. Frontends translating
+// other protocols, such as the REST gateway, call the services through it, so their
+// requests pass through the same interceptors as gRPC calls.
+type inprocess struct {
+	server *grpc.Server
+	lis    *bufconn.Listener
+	conn   *grpc.ClientConn
+}
+
+// newInprocess connects to server in process, allowing messages up to maxMessageSize bytes
+func newInprocess(server *grpc.Server, maxMessageSize int) (*inprocess, error) {
+	lis := bufconn.Listen(inprocessBufferSize)
+	conn, err := grpc.NewClient("passthrough:///user-service",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMessageSize), grpc.MaxCallSendMsgSize(maxMessageSize)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("connect in process: %w", err)
+	}
+	return &inprocess{server: server, lis: lis, conn: conn}, nil
+}
+
+// serve serves calls over the in-memory connection until the server stops
+func (p *inprocess) serve() {
+	go func() {
+		if err := p.server.Serve(p.lis); err != nil {
+			slog.Error("In-process gRPC server failed", "error", err)
+		}
+	}()
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+// Interceptor names accepted by INTERCEPTORS
+const (
+	interceptorLogging     = "logging"
+	interceptorMetrics     = "metrics"
+	interceptorErrorInfo   = "errorinfo"
+	interceptorRecovery    = "recovery"
+	interceptorDeadline    = "deadline"
+	interceptorMaintenance = "maintenance"
+	interceptorInFlight    = "inflight"
+	interceptorMTLS        = "mtls"
+	interceptorAuth        = "auth"
+	interceptorRateLimit   = "ratelimit"
+	interceptorValidation  = "validation"
+)
+
+// defaultInterceptors is the order used when INTERCEPTORS is empty. Logging comes first
+// so whatever later interceptors log can be correlated, metrics before authentication
+// and rate limiting so rejected calls are counted too, error details around everything
+// that may fail a call so each error carries them, maintenance and load shedding
+// before authentication so calls turned away cost nothing more, and rate limiting after
+// authentication so clients are told apart by principal. Validation comes last, so only
+// calls that were let in learn how their requests are checked.
+var defaultInterceptors = []string{
+	interceptorLogging,
+	interceptorMetrics,
+	interceptorErrorInfo,
+	interceptorRecovery,
+	interceptorDeadline,
+	interceptorMaintenance,
+	interceptorInFlight,
+	interceptorMTLS,
+	interceptorAuth,
+	interceptorRateLimit,
+	interceptorValidation,
+}
+
+// requiredInterceptors may not be left out while configured, since doing so would
+// silently drop a security check
+var requiredInterceptors = map[string]string{
+	interceptorMTLS: "TLS_CLIENT_CA_FILE",
+	interceptorAuth: "AUTH_MODE",
+}
+
+// interceptor is the unary and stream halves of a middleware
+type interceptor struct {
+	unary  []grpc.UnaryServerInterceptor
+	stream []grpc.StreamServerInterceptor
+}
+
+// interceptorChain returns the server options chaining the interceptors named in order,
+// outermost first, or in defaultInterceptors order when names is empty. available holds
+// the interceptors whose feature is configured; naming one that isn't is allowed and
+// skips it, so the same list can serve every deployment.
+func interceptorChain(names []string, available map[string]interceptor) ([]grpc.ServerOption, error) {
+	if len(names) == 0 {
+		names = defaultInterceptors
+	}
+
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if !slices.Contains(defaultInterceptors, name) {
+			return nil, fmt.Errorf("unknown interceptor %q in INTERCEPTORS (expected %s)", name, strings.Join(defaultInterceptors, ", "))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("interceptor %q is listed twice in INTERCEPTORS", name)
+		}
+		seen[name] = true
+
+		unary = append(unary, available[name].unary...)
+		stream = append(stream, available[name].stream...)
+	}
+
+	for name := range available {
+		if seen[name] {
+			continue
+		}
+		if setting, ok := requiredInterceptors[name]; ok {
+			return nil, fmt.Errorf("INTERCEPTORS must include %q while %s is set", name, setting)
+		}
+		slog.Warn("Interceptor disabled by INTERCEPTORS", "interceptor", name)
+	}
+
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}, nil
+}
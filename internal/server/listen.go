@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// unixScheme prefixes listen addresses naming a Unix domain socket, as in gRPC targets
+const unixScheme = "unix:"
+
+// listen listens on addr: a TCP address such as ":50051", or a Unix domain socket given as
+// "unix:///abs/path" or "unix:rel/path". A socket left behind by a process that didn't
+// shut down cleanly is replaced; the socket file is removed when the listener closes.
+func listen(addr string) (net.Listener, error) {
+	path, ok := socketPath(addr)
+	if !ok {
+		return net.Listen("tcp", addr)
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Mode().Type() == fs.ModeSocket {
+		// A live server still accepting connections must not lose its socket
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("listen on %s: another server is already listening", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale socket: %w", err)
+		}
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return net.Listen("unix", path)
+}
+
+// socketPath returns the socket path of a unix: address
+func socketPath(addr string) (string, bool) {
+	rest, ok := strings.CutPrefix(addr, unixScheme)
+	if !ok {
+		return "", false
+	}
+	// unix:///abs/path has an empty authority before the absolute path
+	if path, ok := strings.CutPrefix(rest, "//"); ok {
+		return path, true
+	}
+	return rest, true
+}
+
+// serveHTTP serves srv on lis in the background, over TLS when srv has a TLS config; name
+// identifies the server in logs
+func serveHTTP(srv *http.Server, lis net.Listener, name string) {
+	go func() {
+		var err error
+		if srv.TLSConfig != nil {
+			err = srv.ServeTLS(lis, "", "")
+		} else {
+			err = srv.Serve(lis)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error(name+" server failed", "error", err)
+		}
+	}()
+}
+
+// shutdownHTTP stops srv taking requests and waits up to timeout for those in flight,
+// then closes their connections
+func shutdownHTTP(srv *http.Server, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		srv.Close()
+	}
+}
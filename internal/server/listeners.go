@@ -0,0 +1,74 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+// Service names accepted by GRPC_LISTENERS
+const (
+	serviceUser  = "user"
+	serviceAdmin = "admin"
+	serviceAuth  = "auth"
+)
+
+// allServices is every service name, in registration order
+var allServices = []string{serviceUser, serviceAdmin, serviceAuth}
+
+// grpcListener is a GRPC_LISTENERS entry: an address and the server of the services
+// offered there. Health, and reflection when enabled, are served on every listener.
+type grpcListener struct {
+	addr     string
+	services []string
+	server   *grpc.Server
+}
+
+// parseListener parses a GRPC_LISTENERS entry of the form "address[=service+service]";
+// without services the listener serves all of them
+func parseListener(spec string) (grpcListener, error) {
+	addr, list, found := strings.Cut(spec, "=")
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return grpcListener{}, fmt.Errorf("listener %q has no address", spec)
+	}
+	if !found {
+		return grpcListener{addr: addr}, nil
+	}
+
+	var services []string
+	for _, name := range strings.Split(list, "+") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(allServices, name) {
+			return grpcListener{}, fmt.Errorf("unknown service %q in listener %q (expected %s)", name, spec, strings.Join(allServices, ", "))
+		}
+		if !slices.Contains(services, name) {
+			services = append(services, name)
+		}
+	}
+	return grpcListener{addr: addr, services: services}, nil
+}
+
+// listenAll listens on the address of every listener, closing those already open when one fails
+func listenAll(listeners []grpcListener) ([]net.Listener, error) {
+	opened := make([]net.Listener, 0, len(listeners))
+	for _, l := range listeners {
+		lis, err := listen(l.addr)
+		if err != nil {
+			closeListeners(opened)
+			return nil, fmt.Errorf("listen on %s: %w", l.addr, err)
+		}
+		opened = append(opened, lis)
+	}
+	return opened, nil
+}
+
+// closeListeners closes listeners that were never served
+func closeListeners(listeners []net.Listener) {
+	for _, lis := range listeners {
+		lis.Close()
+	}
+}
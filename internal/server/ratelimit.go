@@ -0,0 +1,40 @@
+package server
+
+import (
+	"math"
+
+	"example.com/user/internal/config"
+	"example.com/user/internal/ratelimit"
+)
+
+// rateLimiter builds the Limiter for the configured limits, or nil when rate limiting is off
+func rateLimiter(cfg config.RateLimitConfig) (*ratelimit.Limiter, error) {
+	if !rateLimited(cfg) {
+		return nil, nil
+	}
+
+	fallback, methods, err := rateLimits(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return ratelimit.New(fallback, methods), nil
+}
+
+// rateLimited reports whether cfg sets any limit
+func rateLimited(cfg config.RateLimitConfig) bool {
+	return cfg.Rate > 0 || len(cfg.Methods) > 0
+}
+
+// rateLimits returns the default and per-method limits of cfg
+func rateLimits(cfg config.RateLimitConfig) (ratelimit.Limit, map[string]ratelimit.Limit, error) {
+	methods, err := ratelimit.ParseMethodLimits(cfg.Methods)
+	if err != nil {
+		return ratelimit.Limit{}, nil, err
+	}
+
+	fallback := ratelimit.Limit{Rate: max(cfg.Rate, 0), Burst: cfg.Burst}
+	if fallback.Burst <= 0 {
+		fallback.Burst = int(math.Ceil(fallback.Rate))
+	}
+	return fallback, methods, nil
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"log/slog"
+
+	"example.com/user/internal/config"
+	"example.com/user/internal/logging"
+)
+
+// reload applies CONFIG_FILE again and puts the settings that can change at runtime into
+// effect: LOG_LEVEL, the RATE_LIMIT_* limits and the call deadlines. Other settings take
+// effect at the next restart. When any reloaded setting is invalid, none is applied.
+func (s *Server) reload() {
+	if err := config.ApplyFile(); err != nil {
+		slog.Error("Failed to reload configuration", "error", err)
+		return
+	}
+	cfg := config.Load()
+
+	deadlines, err := deadlinePolicy(cfg.Server)
+	if err != nil {
+		slog.Error("Failed to reload configuration", "error", err)
+		return
+	}
+	fallback, methods, err := rateLimits(cfg.RateLimit)
+	if err != nil {
+		slog.Error("Failed to reload configuration", "error", err)
+		return
+	}
+	// Only a changed level is applied, so reloading keeps a level set through AdminService
+	if cfg.Log.Level != s.config.Log.Level {
+		if err := logging.SetLevel(cfg.Log.Level); err != nil {
+			slog.Error("Failed to reload configuration", "error", err)
+			return
+		}
+		s.config.Log.Level = cfg.Log.Level
+	}
+
+	s.deadlines.Set(deadlines)
+	s.config.Server.DefaultTimeout = cfg.Server.DefaultTimeout
+	s.config.Server.DefaultStreamTimeout = cfg.Server.DefaultStreamTimeout
+	s.config.Server.MinDeadline = cfg.Server.MinDeadline
+	s.config.Server.MethodTimeouts = cfg.Server.MethodTimeouts
+
+	// The rate limit interceptor is only chained when rate limiting is on at startup
+	switch {
+	case s.limiter != nil && rateLimited(cfg.RateLimit):
+		s.limiter.SetLimits(fallback, methods)
+		s.config.RateLimit = cfg.RateLimit
+	case s.limiter != nil || rateLimited(cfg.RateLimit):
+		slog.Warn("Turning rate limiting on or off takes a restart")
+	}
+
+	slog.Info("🔄 Configuration reloaded", "log_level", logging.Level(),
+		"rps", s.config.RateLimit.Rate, "method_rate_limits", len(s.config.RateLimit.Methods),
+		"call_timeout", s.config.Server.DefaultTimeout, "stream_timeout", s.config.Server.DefaultStreamTimeout,
+		"method_timeouts", len(s.config.Server.MethodTimeouts))
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package server
+
+import "os"
+
+// notifyReloadSignal is a no-op where SIGHUP does not exist; configuration changes
+// take effect at the next restart
+func notifyReloadSignal(c chan<- os.Signal) {}
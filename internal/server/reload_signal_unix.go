@@ -0,0 +1,14 @@
+//go:build unix
+
+package server
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyReloadSignal relays SIGHUP, which asks the server to reload its configuration
+func notifyReloadSignal(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGHUP)
+}
@@ -1,68 +1,738 @@
 package server
 
 import (
-	"log"
-	"net"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
+	"example.com/user/internal/auth"
+	"example.com/user/internal/blob"
 	"example.com/user/internal/config"
+	"example.com/user/internal/deadline"
+	"example.com/user/internal/errorinfo"
+	"example.com/user/internal/logging"
+	"example.com/user/internal/maintenance"
+	"example.com/user/internal/metrics"
+	"example.com/user/internal/ratelimit"
+	"example.com/user/internal/reporting"
 	"example.com/user/internal/repository"
 	"example.com/user/internal/service"
+	"example.com/user/internal/tracing"
+	"example.com/user/internal/validation"
 	pb "example.com/user/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 )
 
 // Server wraps the gRPC server with configuration
 type Server struct {
 	grpcServer *grpc.Server
-	userSvc    *service.UserService
-	config     *config.Config
+	// shared serves HTTP endpoints on the gRPC port; nil unless METRICS_ADDR or
+	// GATEWAY_ADDR is GRPC_PORT
+	shared *sharedPort
+	// listeners are the servers of GRPC_LISTENERS, started alongside grpcServer
+	listeners []grpcListener
+	health    *healthChecker
+	userSvc   *service.UserService
+	store     *repository.Store
+	config    *config.Config
+	// metricsServer serves Prometheus metrics; nil when METRICS_ADDR=off
+	metricsServer *http.Server
+	// debugServer serves pprof profiles and expvar stats; nil when DEBUG_ADDR=off
+	debugServer *http.Server
+	// channelzServer serves channelz on its own listener; nil when CHANNELZ_ADDR=off
+	channelzServer *grpc.Server
+	// gateway serves UserService as REST/JSON; nil when GATEWAY_ADDR=off
+	gateway *gateway
+	// grpcWeb serves gRPC-Web to browsers; nil when GRPC_WEB_ADDR=off
+	grpcWeb *grpcWeb
+	// connect serves UserService over the Connect protocol; nil when CONNECT_ADDR=off
+	connect *connectFrontend
+	// accessLog receives a JSON line per call; nil when ACCESS_LOG=off
+	accessLog *logging.AccessLog
+	// reporter receives Internal errors and recovered panics; nil when ERROR_REPORTER=none
+	reporter reporting.Reporter
+	// shutdownTracing flushes server spans; nil when TRACING_EXPORTER=none
+	shutdownTracing func(context.Context) error
+	// snapshotSignals receives SIGUSR1 while the memory backend has SNAPSHOT_FILE set
+	snapshotSignals chan os.Signal
+	// deadlines and limiter apply settings that reload replaces; limiter is nil while
+	// rate limiting is off
+	deadlines *deadline.Enforcer
+	limiter   *ratelimit.Limiter
+	// reloadSignals receives SIGHUP while CONFIG_FILE is set
+	reloadSignals chan os.Signal
 }
 
+// addrDisabled is the METRICS_ADDR, DEBUG_ADDR, CHANNELZ_ADDR, GATEWAY_ADDR, GRPC_WEB_ADDR
+// and CONNECT_ADDR value that turns the endpoint off
+const addrDisabled = "off"
+
 // New creates a new gRPC server instance
-func New() *Server {
+func New() (*Server, error) {
+	if err := config.ApplyFile(); err != nil {
+		return nil, err
+	}
 	cfg := config.Load()
+	if err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		return nil, err
+	}
+	if err := cfg.ResolveSecrets(context.Background()); err != nil {
+		return nil, err
+	}
+
+	redactor, err := logging.NewRedactor(cfg.Log.RedactFields, cfg.Log.RedactMode, cfg.Log.RedactKey)
+	if err != nil {
+		return nil, err
+	}
+	logging.SetRedactor(redactor)
+
+	accessLog, err := logging.OpenAccessLog(cfg.Log.AccessLog)
+	if err != nil {
+		return nil, err
+	}
 	
-	// Initialize repository
-	userRepo := repository.NewInMemoryUserRepository()
+	tlsConfig, err := serverTLSConfig(cfg.Server)
+	if err != nil {
+		return nil, err
+	}
+	// REST and Connect requests carry no client certificate for the mTLS interceptor to check
+	if cfg.Server.GatewayAddr != addrDisabled && cfg.Server.TLSClientCAFile != "" {
+		return nil, errors.New("GATEWAY_ADDR can't be combined with TLS_CLIENT_CA_FILE")
+	}
+	if cfg.Server.ConnectAddr != addrDisabled && cfg.Server.TLSClientCAFile != "" {
+		return nil, errors.New("CONNECT_ADDR can't be combined with TLS_CLIENT_CA_FILE")
+	}
+	sharePort := cfg.Server.MetricsAddr == cfg.Server.Port || cfg.Server.GatewayAddr == cfg.Server.Port
+	if sharePort && cfg.Server.TLSClientCAFile != "" {
+		return nil, errors.New("serving HTTP on GRPC_PORT can't be combined with TLS_CLIENT_CA_FILE")
+	}
+	for _, endpoint := range []struct{ name, addr string }{
+		{"DEBUG_ADDR", cfg.Server.DebugAddr},
+		{"CHANNELZ_ADDR", cfg.Server.ChannelzAddr},
+		{"GRPC_WEB_ADDR", cfg.Server.GRPCWebAddr},
+		{"CONNECT_ADDR", cfg.Server.ConnectAddr},
+	} {
+		if endpoint.addr == cfg.Server.Port {
+			return nil, fmt.Errorf("%s can't share GRPC_PORT; only METRICS_ADDR and GATEWAY_ADDR can", endpoint.name)
+		}
+	}
+
+	authn, err := authenticator(cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := authorizationPolicy(cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	limiter, err := rateLimiter(cfg.RateLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, "user-service")
+	if err != nil {
+		return nil, err
+	}
+
+	reporter, err := reporting.New(cfg.Errors, "user-service")
+	if err != nil {
+		return nil, err
+	}
+
+	var metricsHandler http.Handler
+	var repoMetrics *metrics.Repository
+	var grpcMetrics *metrics.GRPC
+	if cfg.Server.MetricsAddr != addrDisabled {
+		latencyBuckets, err := metrics.ParseBuckets(cfg.Server.LatencyBuckets)
+		if err != nil {
+			return nil, fmt.Errorf("METRICS_LATENCY_BUCKETS: %w", err)
+		}
+		reg := metrics.NewRegistry()
+		repoMetrics = metrics.NewRepository(reg)
+		grpcMetrics = metrics.NewGRPC(reg, latencyBuckets)
+		metricsHandler = metrics.Handler(reg)
+	}
+
+	var debugServer *http.Server
+	if cfg.Server.DebugAddr != addrDisabled {
+		debugServer = newDebugServer(cfg.Server.DebugAddr)
+	}
+	var channelzServer *grpc.Server
+	if cfg.Server.ChannelzAddr != addrDisabled {
+		channelzServer = newChannelzServer()
+	}
+
+	// Initialize the blob store keeping avatars
+	avatars, err := blob.Open(cfg.Blob)
+	if err != nil {
+		return nil, fmt.Errorf("open %s blob storage: %w", cfg.Blob.Backend, err)
+	}
+
+	// Initialize repository for the configured storage backend
+	store, err := repository.Open(cfg.Storage, repoMetrics)
+	if err != nil {
+		return nil, fmt.Errorf("open %s storage: %w", cfg.Storage.Backend, err)
+	}
+	slog.Info("💾 Storage ready", "backend", cfg.Storage.Backend)
 	
 	// Initialize service
-	userSvc := service.NewUserService(userRepo)
+	userSvc := service.NewUserService(store.Users, store.Audit, store.Hooks, store.Preferences, avatars, cfg.Blob.MaxAvatarSize, store.Chats)
 	
 	// Create gRPC server with options
-	grpcServer := grpc.NewServer(
+	opts := []grpc.ServerOption{
 		grpc.MaxConcurrentStreams(cfg.Server.MaxConcurrentStreams),
 		grpc.MaxRecvMsgSize(cfg.Server.MaxMessageSize),
 		grpc.MaxSendMsgSize(cfg.Server.MaxMessageSize),
-	)
+		// Pinging idle connections keeps load balancers and NATs from dropping long-lived
+		// streams such as Chat between messages
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:                  cfg.Server.Keepalive.Time,
+			Timeout:               cfg.Server.Keepalive.Timeout,
+			MaxConnectionIdle:     cfg.Server.Keepalive.MaxConnectionIdle,
+			MaxConnectionAge:      cfg.Server.Keepalive.MaxConnectionAge,
+			MaxConnectionAgeGrace: cfg.Server.Keepalive.MaxConnectionAgeGrace,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.Server.Keepalive.MinTime,
+			PermitWithoutStream: cfg.Server.Keepalive.PermitWithoutStream,
+		}),
+	}
+	// Transport credentials are left out of opts, since the gateway's in-process server has none
+	var transport []grpc.ServerOption
+	if tlsConfig != nil {
+		transport = append(transport, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		slog.Info("🔒 TLS enabled", "certificate", cfg.Server.TLSCertFile)
+	} else {
+		slog.Warn("TLS disabled (GRPC_INSECURE=true): serving plaintext")
+	}
+	// The stats handler runs before every interceptor, so their work is part of the call's span
+	if shutdownTracing != nil {
+		opts = append(opts, grpc.StatsHandler(tracing.ServerHandler()))
+		slog.Info("🔭 Tracing enabled", "endpoint", cfg.Tracing.Endpoint, "sample_ratio", cfg.Tracing.SampleRatio)
+	}
+	deadlines, err := deadlinePolicy(cfg.Server)
+	if err != nil {
+		return nil, err
+	}
+	deadlineEnforcer := deadline.NewEnforcer(deadlines)
+	// Operators switch maintenance mode through AdminService, so it must stay reachable
+	maintenanceMode := maintenance.New(controlServices...)
+	// Collect the interceptors of every configured feature, chained in INTERCEPTORS order
+	interceptors := map[string]interceptor{
+		interceptorLogging: {
+			unary:  []grpc.UnaryServerInterceptor{logging.UnaryServerInterceptor(accessLog)},
+			stream: []grpc.StreamServerInterceptor{logging.StreamServerInterceptor(accessLog)},
+		},
+		interceptorErrorInfo: {
+			unary:  []grpc.UnaryServerInterceptor{errorinfo.UnaryServerInterceptor()},
+			stream: []grpc.StreamServerInterceptor{errorinfo.StreamServerInterceptor()},
+		},
+		interceptorRecovery: {
+			unary:  []grpc.UnaryServerInterceptor{reporting.UnaryServerInterceptor(reporter)},
+			stream: []grpc.StreamServerInterceptor{reporting.StreamServerInterceptor(reporter)},
+		},
+		interceptorDeadline: {
+			unary:  []grpc.UnaryServerInterceptor{deadlineEnforcer.UnaryServerInterceptor()},
+			stream: []grpc.StreamServerInterceptor{deadlineEnforcer.StreamServerInterceptor()},
+		},
+		interceptorMaintenance: {
+			unary:  []grpc.UnaryServerInterceptor{maintenanceMode.UnaryServerInterceptor()},
+			stream: []grpc.StreamServerInterceptor{maintenanceMode.StreamServerInterceptor()},
+		},
+		interceptorValidation: {
+			unary:  []grpc.UnaryServerInterceptor{validation.UnaryServerInterceptor()},
+			stream: []grpc.StreamServerInterceptor{validation.StreamServerInterceptor()},
+		},
+	}
+	if accessLog != nil {
+		slog.Info("📜 Access log enabled", "destination", cfg.Log.AccessLog)
+	}
+	if reporter != nil {
+		slog.Info("🚨 Error reporting enabled", "reporter", cfg.Errors.Reporter, "environment", cfg.Errors.Environment)
+	}
+	if grpcMetrics != nil {
+		interceptors[interceptorMetrics] = interceptor{
+			unary:  []grpc.UnaryServerInterceptor{grpcMetrics.UnaryServerInterceptor()},
+			stream: []grpc.StreamServerInterceptor{grpcMetrics.StreamServerInterceptor()},
+		}
+	}
+	if cfg.Server.TLSClientCAFile != "" {
+		interceptors[interceptorMTLS] = interceptor{
+			unary:  []grpc.UnaryServerInterceptor{auth.MTLSUnaryInterceptor()},
+			stream: []grpc.StreamServerInterceptor{auth.MTLSStreamInterceptor()},
+		}
+		slog.Info("🔐 Client certificates required", "ca", cfg.Server.TLSClientCAFile)
+	}
+	if authn != nil {
+		interceptors[interceptorAuth] = interceptor{
+			unary: []grpc.UnaryServerInterceptor{
+				auth.UnaryServerInterceptor(authn, publicMethods...),
+				auth.UnaryAccountInterceptor(userSvc.CheckAccount),
+				auth.UnaryAuthorizationInterceptor(policy, requestPolicy),
+			},
+			stream: []grpc.StreamServerInterceptor{
+				auth.StreamServerInterceptor(authn, publicMethods...),
+				auth.StreamAccountInterceptor(userSvc.CheckAccount),
+				auth.StreamAuthorizationInterceptor(policy, requestPolicy),
+			},
+		}
+		slog.Info("🔑 Authentication enabled", "mode", cfg.Auth.Mode)
+	}
+	if cfg.Server.MaxInFlight > 0 {
+		inFlight := ratelimit.NewInFlight(cfg.Server.MaxInFlight, controlServices...)
+		interceptors[interceptorInFlight] = interceptor{
+			unary:  []grpc.UnaryServerInterceptor{inFlight.UnaryServerInterceptor()},
+			stream: []grpc.StreamServerInterceptor{inFlight.StreamServerInterceptor()},
+		}
+		slog.Info("🧯 Load shedding enabled", "max_in_flight_calls", cfg.Server.MaxInFlight)
+	}
+	if limiter != nil {
+		interceptors[interceptorRateLimit] = interceptor{
+			unary:  []grpc.UnaryServerInterceptor{limiter.UnaryServerInterceptor()},
+			stream: []grpc.StreamServerInterceptor{limiter.StreamServerInterceptor()},
+		}
+		slog.Info("🚦 Rate limiting enabled", "rps", cfg.RateLimit.Rate, "method_overrides", len(cfg.RateLimit.Methods))
+	}
+	chain, err := interceptorChain(cfg.Server.Interceptors, interceptors)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, chain...)
+
+	// Services by name, so each listener registers those it serves
+	adminSvc := service.NewAdminService(maintenanceMode)
+	registrations := map[string]func(grpc.ServiceRegistrar){
+		serviceUser:  func(r grpc.ServiceRegistrar) { pb.RegisterUserServiceServer(r, userSvc) },
+		serviceAdmin: func(r grpc.ServiceRegistrar) { pb.RegisterAdminServiceServer(r, adminSvc) },
+	}
+	services := []string{pb.UserService_ServiceDesc.ServiceName, pb.AdminService_ServiceDesc.ServiceName}
+	// Session tokens are JWTs, so they are only issued when the server verifies JWTs
+	if issuer, ok := authn.(*auth.JWTAuthenticator); ok {
+		authSvc := service.NewAuthService(store.Tokens, userSvc.CheckAccount, userSvc.AccountRole, issuer, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL)
+		registrations[serviceAuth] = func(r grpc.ServiceRegistrar) { pb.RegisterAuthServiceServer(r, authSvc) }
+		services = append(services, pb.AuthService_ServiceDesc.ServiceName)
+	}
+	// Replicas in maintenance report NOT_SERVING so load balancers route calls elsewhere
+	ready := func(ctx context.Context) error {
+		if err := maintenanceMode.Err(); err != nil {
+			return err
+		}
+		return store.Ready(ctx)
+	}
+	healthChecker := newHealthChecker(ready, cfg.Server.ReadinessInterval, services...)
+	maintenanceMode.OnChange(healthChecker.refresh)
+
+	// HTTP endpoints at GRPC_PORT are served on the gRPC port, the others on their own
+	var sharedMux *http.ServeMux
+	if sharePort {
+		sharedMux = http.NewServeMux()
+	}
+	var metricsServer *http.Server
+	if metricsHandler != nil {
+		mux := sharedMux
+		if cfg.Server.MetricsAddr != cfg.Server.Port {
+			mux = http.NewServeMux()
+			metricsServer = &http.Server{Addr: cfg.Server.MetricsAddr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		}
+		mux.Handle(metrics.Path, metricsHandler)
+		healthChecker.handleHTTP(mux)
+	}
+
+	// newGRPCServer creates a server of the named services, or of all of them when names is
+	// empty; every server shares the options, health status and service implementations
+	newGRPCServer := func(names []string, extra ...grpc.ServerOption) *grpc.Server {
+		srv := grpc.NewServer(append(slices.Clip(opts), extra...)...)
+		for _, name := range allServices {
+			if register, ok := registrations[name]; ok && (len(names) == 0 || slices.Contains(names, name)) {
+				register(srv)
+			}
+		}
+		healthpb.RegisterHealthServer(srv, healthChecker)
+		if cfg.Server.Reflection {
+			reflection.Register(srv)
+		}
+		if grpcMetrics != nil {
+			grpcMetrics.Initialize(srv)
+		}
+		return srv
+	}
+
+	listeners := make([]grpcListener, 0, len(cfg.Server.Listeners))
+	for _, spec := range cfg.Server.Listeners {
+		l, err := parseListener(spec)
+		if err != nil {
+			return nil, fmt.Errorf("GRPC_LISTENERS: %w", err)
+		}
+		for _, name := range l.services {
+			if _, ok := registrations[name]; !ok {
+				return nil, fmt.Errorf("GRPC_LISTENERS: listener %s serves %s, which requires AUTH_MODE=jwt", l.addr, name)
+			}
+		}
+		l.server = newGRPCServer(l.services, transport...)
+		listeners = append(listeners, l)
+	}
+
+	var gw *gateway
+	if cfg.Server.GatewayAddr != addrDisabled {
+		gw, err = newGateway(cfg.Server.GatewayAddr, newGRPCServer([]string{serviceUser}), tlsConfig, cfg.Server.MaxMessageSize)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Server.GatewayAddr == cfg.Server.Port {
+			sharedMux.Handle("/", gw.http.Handler)
+		}
+	}
 	
-	// Register services
-	pb.RegisterUserServiceServer(grpcServer, userSvc)
-	reflection.Register(grpcServer)
+	var web *grpcWeb
+	if cfg.Server.GRPCWebAddr != addrDisabled {
+		web = newGRPCWeb(cfg.Server.GRPCWebAddr, newGRPCServer(nil), cfg.Server.GRPCWebOrigins, tlsConfig)
+	}
+
+	var connect *connectFrontend
+	if cfg.Server.ConnectAddr != addrDisabled {
+		connect, err = newConnectFrontend(cfg.Server.ConnectAddr, newGRPCServer([]string{serviceUser}), tlsConfig, cfg.Server.MaxMessageSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The shared port terminates TLS itself, so its gRPC server gets plaintext
+	var shared *sharedPort
+	mainTransport := transport
+	if sharedMux != nil {
+		shared = newSharedPort(sharedMux, tlsConfig)
+		mainTransport = nil
+	}
 	
 	return &Server{
-		grpcServer: grpcServer,
-		userSvc:    userSvc,
-		config:     cfg,
-	}
+		grpcServer:      newGRPCServer(nil, mainTransport...),
+		shared:          shared,
+		listeners:       listeners,
+		health:          healthChecker,
+		userSvc:         userSvc,
+		store:           store,
+		config:          cfg,
+		metricsServer:   metricsServer,
+		debugServer:     debugServer,
+		channelzServer:  channelzServer,
+		gateway:         gw,
+		grpcWeb:         web,
+		connect:         connect,
+		accessLog:       accessLog,
+		reporter:        reporter,
+		shutdownTracing: shutdownTracing,
+		deadlines:       deadlineEnforcer,
+		limiter:         limiter,
+	}, nil
 }
 
 // Start starts the gRPC server on the configured port
 func (s *Server) Start() error {
-	lis, err := net.Listen("tcp", s.config.Server.Port)
+	lis, err := listen(s.config.Server.Port)
+	if err != nil {
+		return err
+	}
+	extraLis, err := listenAll(s.listeners)
 	if err != nil {
+		lis.Close()
 		return err
 	}
 	
-	log.Printf("🚀 gRPC Server started on %s", s.config.Server.Port)
-	log.Printf("📍 Health Check: grpc_health_probe -addr=%s", s.config.Server.Port)
-	log.Printf("📍 API Discovery: grpcurl -plaintext %s list", s.config.Server.Port)
+	if s.metricsServer != nil {
+		go func() {
+			if err := s.metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("Metrics server failed", "error", err)
+			}
+		}()
+		slog.Info("📈 Metrics enabled", "url", "http://"+s.metricsServer.Addr+metrics.Path)
+	}
+
+	if s.debugServer != nil {
+		go func() {
+			if err := s.debugServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("Debug server failed", "error", err)
+			}
+		}()
+		slog.Info("🐛 Debug endpoints enabled", "pprof", "http://"+s.debugServer.Addr+"/debug/pprof/", "vars", "http://"+s.debugServer.Addr+"/debug/vars")
+	}
+
+	if s.channelzServer != nil {
+		channelzLis, err := listen(s.config.Server.ChannelzAddr)
+		if err != nil {
+			lis.Close()
+			closeListeners(extraLis)
+			return fmt.Errorf("listen for channelz: %w", err)
+		}
+		go func() {
+			if err := s.channelzServer.Serve(channelzLis); err != nil {
+				slog.Error("Channelz server failed", "error", err)
+			}
+		}()
+		slog.Info("🔬 Channelz enabled", "command", "grpcdebug "+channelzLis.Addr().String()+" channelz servers")
+	}
+
+	if s.gateway != nil && s.sharesPort(s.config.Server.GatewayAddr) {
+		// Requests arrive through the shared port
+		s.gateway.backend.serve()
+	} else if s.gateway != nil {
+		gatewayLis, err := listen(s.config.Server.GatewayAddr)
+		if err != nil {
+			lis.Close()
+			closeListeners(extraLis)
+			return fmt.Errorf("listen for gateway: %w", err)
+		}
+		s.gateway.serve(gatewayLis)
+	}
+	if s.gateway != nil {
+		scheme := "http"
+		if s.gateway.http.TLSConfig != nil {
+			scheme = "https"
+		}
+		slog.Info("🌐 REST gateway enabled", "url", scheme+"://"+s.config.Server.GatewayAddr+"/v1/users", "docs", scheme+"://"+s.config.Server.GatewayAddr+docsPath)
+	}
+
+	if s.grpcWeb != nil {
+		webLis, err := listen(s.config.Server.GRPCWebAddr)
+		if err != nil {
+			lis.Close()
+			closeListeners(extraLis)
+			return fmt.Errorf("listen for gRPC-Web: %w", err)
+		}
+		s.grpcWeb.serve(webLis)
+		slog.Info("🌐 gRPC-Web enabled", "addr", s.config.Server.GRPCWebAddr, "allowed_origins", s.config.Server.GRPCWebOrigins)
+	}
+
+	if s.connect != nil {
+		connectLis, err := listen(s.config.Server.ConnectAddr)
+		if err != nil {
+			lis.Close()
+			closeListeners(extraLis)
+			return fmt.Errorf("listen for Connect: %w", err)
+		}
+		s.connect.serve(connectLis)
+		slog.Info("🔌 Connect enabled", "addr", s.config.Server.ConnectAddr)
+	}
+
+	if s.store.SnapshotEnabled() {
+		s.snapshotSignals = make(chan os.Signal, 1)
+		notifySnapshotSignal(s.snapshotSignals)
+		go func() {
+			for range s.snapshotSignals {
+				s.saveSnapshot()
+			}
+		}()
+		slog.Info("📸 Snapshots enabled", "file", s.config.Storage.SnapshotFile, "save", fmt.Sprintf("kill -USR1 %d", os.Getpid()))
+	}
+
+	if s.config.Server.ConfigFile != "" {
+		s.reloadSignals = make(chan os.Signal, 1)
+		notifyReloadSignal(s.reloadSignals)
+		go func() {
+			for range s.reloadSignals {
+				s.reload()
+			}
+		}()
+		slog.Info("🔄 Config reload enabled", "file", s.config.Server.ConfigFile, "reload", fmt.Sprintf("kill -HUP %d", os.Getpid()))
+	}
+
+	if s.shared != nil {
+		lis = s.shared.split(lis)
+		slog.Info("🔀 HTTP endpoints share the gRPC port", "addr", s.config.Server.Port,
+			"metrics", s.sharesPort(s.config.Server.MetricsAddr), "gateway", s.sharesPort(s.config.Server.GatewayAddr))
+	}
+
+	s.health.start()
+	for i, l := range s.listeners {
+		go func() {
+			if err := l.server.Serve(extraLis[i]); err != nil {
+				slog.Error("gRPC listener failed", "addr", l.addr, "error", err)
+			}
+		}()
+		services := "all"
+		if len(l.services) > 0 {
+			services = strings.Join(l.services, ",")
+		}
+		slog.Info("🚀 gRPC listener started", "addr", l.addr, "services", services)
+	}
+	slog.Info("🚀 gRPC server started", "addr", s.config.Server.Port)
+	slog.Info("📍 Health check", "command", "grpc_health_probe -addr="+s.config.Server.Port)
+	grpcurlTarget := s.config.Server.Port
+	if path, ok := socketPath(grpcurlTarget); ok {
+		grpcurlTarget = "-unix " + path
+	}
+	switch {
+	case !s.config.Server.Reflection:
+		slog.Info("Reflection disabled; set ENABLE_REFLECTION=true to allow API discovery")
+	case s.config.Server.Insecure:
+		slog.Info("📍 API discovery", "command", "grpcurl -plaintext "+grpcurlTarget+" list")
+	default:
+		slog.Info("📍 API discovery", "command", "grpcurl -cacert <ca.pem> "+grpcurlTarget+" list")
+	}
 	
 	return s.grpcServer.Serve(lis)
 }
 
-// Stop gracefully stops the gRPC server
+// Stop gracefully stops the gRPC server: it stops accepting calls, waits up to
+// SHUTDOWN_TIMEOUT for those in flight, then cancels the rest and releases storage
 func (s *Server) Stop() {
-	log.Println("🛑 Shutting down gRPC server...")
-	s.grpcServer.GracefulStop()
-}
\ No newline at end of file
+	slog.Info("🛑 Shutting down gRPC server", "timeout", s.config.Server.ShutdownTimeout)
+	// Report NOT_SERVING first so load balancers stop routing here while calls drain
+	s.health.Shutdown()
+	// Stop taking REST, gRPC-Web, Connect and shared port HTTP requests while their calls
+	// drain with the others
+	var frontends sync.WaitGroup
+	if s.gateway != nil {
+		frontends.Add(1)
+		go func() {
+			defer frontends.Done()
+			s.gateway.shutdown(s.config.Server.ShutdownTimeout)
+		}()
+	}
+	if s.grpcWeb != nil {
+		frontends.Add(1)
+		go func() {
+			defer frontends.Done()
+			s.grpcWeb.shutdown(s.config.Server.ShutdownTimeout)
+		}()
+	}
+	if s.connect != nil {
+		frontends.Add(1)
+		go func() {
+			defer frontends.Done()
+			s.connect.shutdown(s.config.Server.ShutdownTimeout)
+		}()
+	}
+	if s.shared != nil {
+		frontends.Add(1)
+		go func() {
+			defer frontends.Done()
+			s.shared.shutdown(s.config.Server.ShutdownTimeout)
+		}()
+	}
+	s.drain(s.config.Server.ShutdownTimeout)
+	frontends.Wait()
+
+	if s.metricsServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.metricsServer.Shutdown(ctx); err != nil {
+			slog.Error("Failed to stop metrics server", "error", err)
+		}
+	}
+
+	if s.channelzServer != nil {
+		s.channelzServer.Stop()
+	}
+
+	// Profiles can take as long as their seconds parameter, so don't wait for them
+	if s.debugServer != nil {
+		if err := s.debugServer.Close(); err != nil {
+			slog.Error("Failed to stop debug server", "error", err)
+		}
+	}
+
+	if s.reloadSignals != nil {
+		signal.Stop(s.reloadSignals)
+		close(s.reloadSignals)
+	}
+
+	if s.snapshotSignals != nil {
+		signal.Stop(s.snapshotSignals)
+		close(s.snapshotSignals)
+		s.saveSnapshot()
+	}
+
+	if err := s.store.Close(); err != nil {
+		slog.Error("Failed to close storage", "error", err)
+	}
+
+	if err := s.accessLog.Close(); err != nil {
+		slog.Error("Failed to close access log", "error", err)
+	}
+
+	if s.reporter != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.reporter.Flush(ctx); err != nil {
+			slog.Error("Failed to flush error reports", "error", err)
+		}
+	}
+
+	if s.shutdownTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.shutdownTracing(ctx); err != nil {
+			slog.Error("Failed to flush traces", "error", err)
+		}
+	}
+}
+
+// sharesPort reports whether the HTTP endpoint at addr is served on the gRPC port
+func (s *Server) sharesPort(addr string) bool {
+	return s.shared != nil && addr == s.config.Server.Port
+}
+
+// drain waits up to timeout for in-flight calls to finish on every listener, then cancels
+// those left, such as long-lived streams that would otherwise hold up shutdown
+func (s *Server) drain(timeout time.Duration) {
+	servers := []*grpc.Server{s.grpcServer}
+	for _, l := range s.listeners {
+		servers = append(servers, l.server)
+	}
+	if s.gateway != nil {
+		servers = append(servers, s.gateway.backend.server)
+	}
+	if s.connect != nil {
+		servers = append(servers, s.connect.backend.server)
+	}
+
+	var wg sync.WaitGroup
+	for _, srv := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			srv.GracefulStop()
+		}()
+	}
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-drained:
+	case <-timer.C:
+		slog.Warn("Calls still running after the shutdown timeout, cancelling them", "timeout", timeout)
+		for _, srv := range servers {
+			srv.Stop()
+		}
+		<-drained
+	}
+}
+
+// saveSnapshot writes the in-memory users to SNAPSHOT_FILE
+func (s *Server) saveSnapshot() {
+	n, err := s.store.Snapshot()
+	if err != nil {
+		slog.Error("Failed to save snapshot", "error", err)
+		return
+	}
+	slog.Info("📸 Saved snapshot", "users", n, "file", s.config.Storage.SnapshotFile)
+}
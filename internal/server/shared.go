@@ -0,0 +1,120 @@
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/soheilhy/cmux"
+)
+
+// grpcContentType starts the content type of every gRPC request
+const grpcContentType = "application/grpc"
+
+// sharedPort serves HTTP endpoints such as the REST gateway and metrics on the gRPC port.
+// cmux reads the start of each connection and hands HTTP/1 connections to an HTTP server
+// and HTTP/2 connections carrying gRPC to the gRPC server. gRPC clients wait for the
+// server's SETTINGS before sending a request, which the HTTP/2 server of net/http can't
+// take over, so the HTTP endpoints are served over HTTP/1.1 only. TLS is terminated
+// before that, so both servers see plaintext. The port closes when the gRPC server stops.
+type sharedPort struct {
+	http      *http.Server
+	tlsConfig *tls.Config
+}
+
+// newSharedPort creates a shared port serving handler, over TLS with tlsConfig unless
+// it is nil
+func newSharedPort(handler http.Handler, tlsConfig *tls.Config) *sharedPort {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	return &sharedPort{
+		http: &http.Server{
+			Handler:           handler,
+			Protocols:         protocols,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		tlsConfig: tlsConfig,
+	}
+}
+
+// split starts serving HTTP requests arriving on lis and returns the listener of its gRPC
+// connections
+func (p *sharedPort) split(lis net.Listener) net.Listener {
+	if p.tlsConfig != nil {
+		lis = tls.NewListener(lis, sharedTLSConfig(p.tlsConfig))
+	}
+	mux := cmux.New(lis)
+	mux.SetReadTimeout(5 * time.Second)
+	// HTTP/1 goes first: waiting for the HTTP/2 preface would stall on short requests
+	httpLis := &httpListener{Listener: mux.Match(cmux.HTTP1Fast()), closed: make(chan struct{})}
+	grpcLis := mux.MatchWithWriters(cmux.HTTP2MatchHeaderFieldPrefixSendSettings("content-type", grpcContentType))
+
+	serveHTTP(p.http, httpLis, "Shared port HTTP")
+	go func() {
+		if err := mux.Serve(); err != nil && !errors.Is(err, net.ErrClosed) {
+			slog.Error("Shared port failed", "error", err)
+		}
+	}()
+	return grpcLis
+}
+
+// shutdown stops taking HTTP requests and waits up to timeout for those in flight
+func (p *sharedPort) shutdown(timeout time.Duration) {
+	shutdownHTTP(p.http, timeout)
+}
+
+// sharedTLSConfig negotiates HTTP/1.1 with clients offering it, such as browsers and
+// curl, and HTTP/2 with the others, such as gRPC clients
+func sharedTLSConfig(base *tls.Config) *tls.Config {
+	http1 := base.Clone()
+	http1.NextProtos = []string{"http/1.1"}
+	h2 := base.Clone()
+	h2.NextProtos = []string{"h2"}
+
+	tlsConfig := base.Clone()
+	tlsConfig.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		if slices.Contains(hello.SupportedProtos, "http/1.1") {
+			return http1, nil
+		}
+		return h2, nil
+	}
+	return tlsConfig
+}
+
+// httpListener hands the HTTP connections of the shared port to the HTTP server. Closing
+// a cmux listener closes the port, so closing this one only stops handing connections
+// over, and it reports itself closed once the HTTP server closes it, whichever of the
+// two servers stops first.
+type httpListener struct {
+	net.Listener
+	closed chan struct{}
+	once   sync.Once
+}
+
+func (l *httpListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	select {
+	case <-l.closed:
+		if conn != nil {
+			conn.Close()
+		}
+		return nil, net.ErrClosed
+	default:
+	}
+	if err != nil {
+		// The gRPC server closed the port; wait for the HTTP server to stop as well
+		<-l.closed
+		return nil, net.ErrClosed
+	}
+	return conn, nil
+}
+
+func (l *httpListener) Close() error {
+	l.once.Do(func() { close(l.closed) })
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package server
+
+import "os"
+
+// notifySnapshotSignal is a no-op where SIGUSR1 does not exist; snapshots are
+// still saved on shutdown
+func notifySnapshotSignal(c chan<- os.Signal) {}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package server
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifySnapshotSignal relays SIGUSR1, which asks the server to save a snapshot
+func notifySnapshotSignal(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGUSR1)
+}
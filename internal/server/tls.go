@@ -0,0 +1,93 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"example.com/user/internal/config"
+)
+
+// serverTLSConfig builds the server's TLS configuration from cfg, shared by the gRPC
+// listeners and the REST gateway. It returns nil only when plaintext was explicitly
+// requested with GRPC_INSECURE.
+func serverTLSConfig(cfg config.ServerConfig) (*tls.Config, error) {
+	tlsConfigured := cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" || cfg.TLSKey != ""
+
+	switch {
+	case cfg.TLSClientCAFile != "" && cfg.Insecure:
+		return nil, errors.New("TLS_CLIENT_CA_FILE requires TLS; unset GRPC_INSECURE")
+	case cfg.Insecure && tlsConfigured:
+		return nil, errors.New("GRPC_INSECURE=true conflicts with TLS_CERT_FILE/TLS_KEY_FILE/TLS_KEY; set only one")
+	case cfg.Insecure:
+		return nil, nil
+	case !tlsConfigured:
+		return nil, errors.New("TLS is not configured: set TLS_CERT_FILE and TLS_KEY_FILE, or GRPC_INSECURE=true to serve plaintext")
+	case cfg.TLSKeyFile != "" && cfg.TLSKey != "":
+		return nil, errors.New("TLS_KEY_FILE and TLS_KEY are mutually exclusive")
+	case cfg.TLSCertFile == "" || (cfg.TLSKeyFile == "" && cfg.TLSKey == ""):
+		return nil, errors.New("TLS_CERT_FILE and TLS_KEY_FILE (or TLS_KEY) must be set together")
+	}
+
+	cert, err := loadKeyPair(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("load TLS key pair: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	// A key held in memory can't follow a rotated certificate, so only files are reloaded
+	if cfg.TLSReloadInterval > 0 && cfg.TLSKey == "" {
+		reloader, err := newCertReloader(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSReloadInterval)
+		if err != nil {
+			return nil, fmt.Errorf("load TLS key pair: %w", err)
+		}
+		tlsConfig.Certificates = nil
+		tlsConfig.GetCertificate = reloader.GetCertificate
+	}
+
+	// Mutual TLS: only clients with a certificate signed by the bundle may connect
+	if cfg.TLSClientCAFile != "" {
+		pool, err := loadCertPool(cfg.TLSClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// loadKeyPair loads the server certificate with its key from TLS_KEY_FILE, or from
+// TLS_KEY when the key was handed over in memory (e.g. resolved from Vault)
+func loadKeyPair(cfg config.ServerConfig) (tls.Certificate, error) {
+	if cfg.TLSKey == "" {
+		return tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+	}
+
+	certPEM, err := os.ReadFile(cfg.TLSCertFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(certPEM, []byte(cfg.TLSKey))
+}
+
+// loadCertPool reads a PEM bundle of CA certificates
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read CA bundle: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", path)
+	}
+	return pool, nil
+}
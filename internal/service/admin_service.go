@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"example.com/user/internal/logging"
+	"example.com/user/internal/maintenance"
+	pb "example.com/user/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AdminService implements the gRPC AdminService: runtime operations for operators
+type AdminService struct {
+	pb.UnimplementedAdminServiceServer
+	maintenance *maintenance.Mode
+
+	mu sync.Mutex
+	// revert restores baseLevel at revertsAt while a temporary level is set
+	revert    *time.Timer
+	revertsAt time.Time
+	baseLevel string
+}
+
+// NewAdminService creates an AdminService switching the server's maintenance mode
+func NewAdminService(mode *maintenance.Mode) *AdminService {
+	return &AdminService{maintenance: mode}
+}
+
+// GetLogLevel implements unary RPC reporting the minimum log level
+func (s *AdminService) GetLogLevel(ctx context.Context, _ *emptypb.Empty) (*pb.LogLevelResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.levelResponse(), nil
+}
+
+// SetLogLevel implements unary RPC changing the minimum log level, for a while when a
+// duration is given. Setting a level cancels any pending revert; a temporary level set
+// over another one still reverts to the last permanent level.
+func (s *AdminService) SetLogLevel(ctx context.Context, req *pb.SetLogLevelRequest) (*pb.LogLevelResponse, error) {
+	if req.Duration != nil {
+		if err := req.Duration.CheckValid(); err != nil || req.Duration.AsDuration() <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "Duration must be positive")
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := logging.Level()
+	if err := logging.SetLevel(req.Level); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	base := previous
+	if s.revert != nil {
+		s.revert.Stop()
+		s.revert = nil
+		base = s.baseLevel
+	}
+
+	if req.Duration != nil {
+		d := req.Duration.AsDuration()
+		s.baseLevel = base
+		s.revertsAt = time.Now().Add(d)
+		var timer *time.Timer
+		timer = time.AfterFunc(d, func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			// A later change may have replaced this revert after the timer fired
+			if s.revert != timer {
+				return
+			}
+			s.revert = nil
+			logging.SetLevel(base)
+			slog.Warn("Temporary log level expired", "level", base)
+		})
+		s.revert = timer
+	}
+
+	// Logged at warn so the change shows up unless errors only are being logged
+	slog.WarnContext(ctx, "Log level changed", "from", previous, "to", logging.Level(),
+		"actor", logging.Redact("actor", actor(ctx)), "duration", req.Duration.AsDuration())
+	return s.levelResponse(), nil
+}
+
+// levelResponse describes the current level; s.mu must be held
+func (s *AdminService) levelResponse() *pb.LogLevelResponse {
+	res := &pb.LogLevelResponse{Level: logging.Level()}
+	if s.revert != nil {
+		res.RevertsAt = timestamppb.New(s.revertsAt)
+	}
+	return res
+}
+
+// GetMaintenance implements unary RPC reporting whether maintenance mode is on
+func (s *AdminService) GetMaintenance(ctx context.Context, _ *emptypb.Empty) (*pb.MaintenanceResponse, error) {
+	return maintenanceResponse(s.maintenance.State()), nil
+}
+
+// SetMaintenance implements unary RPC turning maintenance mode on or off. Calls in flight
+// are left to finish; only new ones are turned away.
+func (s *AdminService) SetMaintenance(ctx context.Context, req *pb.SetMaintenanceRequest) (*pb.MaintenanceResponse, error) {
+	if !req.Enabled {
+		state := s.maintenance.Disable()
+		slog.WarnContext(ctx, "Maintenance mode off", "actor", logging.Redact("actor", actor(ctx)))
+		return maintenanceResponse(state), nil
+	}
+
+	if req.RetryAfter != nil {
+		if err := req.RetryAfter.CheckValid(); err != nil || req.RetryAfter.AsDuration() <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "Retry after must be positive")
+		}
+	}
+	state := s.maintenance.Enable(req.Reason, req.RetryAfter.AsDuration())
+	slog.WarnContext(ctx, "Maintenance mode on", "reason", state.Reason, "retry_after", state.RetryAfter,
+		"actor", logging.Redact("actor", actor(ctx)))
+	return maintenanceResponse(state), nil
+}
+
+// maintenanceResponse describes state
+func maintenanceResponse(state maintenance.State) *pb.MaintenanceResponse {
+	res := &pb.MaintenanceResponse{Enabled: state.Enabled, Reason: state.Reason}
+	if state.Enabled {
+		res.RetryAfter = durationpb.New(state.RetryAfter)
+		res.Since = timestamppb.New(state.Since)
+	}
+	return res
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"example.com/user/internal/auth"
+	"example.com/user/internal/errorinfo"
+	"example.com/user/internal/logging"
+	"example.com/user/internal/models"
+	"example.com/user/internal/repository"
+	"example.com/user/pkg/usererrors"
+	pb "example.com/user/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// anonymousActor is recorded for changes made without authentication
+const anonymousActor = "anonymous"
+
+// GetAuditLog implements unary RPC returning recorded changes, newest first
+func (s *UserService) GetAuditLog(ctx context.Context, req *pb.AuditLogRequest) (*pb.AuditLogResponse, error) {
+	if err := s.checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	entries, next, err := s.listAudit(repository.AuditFilter{
+		UserID:    req.UserId,
+		Actor:     req.Actor,
+		PageSize:  int(req.PageSize),
+		PageToken: req.PageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AuditLogResponse{Entries: entries, NextPageToken: next}, nil
+}
+
+// GetUserHistory implements unary RPC returning the changes made to one user, newest
+// first. Deleted users keep their history; users that never existed have none.
+func (s *UserService) GetUserHistory(ctx context.Context, req *pb.UserHistoryRequest) (*pb.UserHistoryResponse, error) {
+	if err := s.checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	changes, next, err := s.listAudit(repository.AuditFilter{
+		UserID:    req.UserId,
+		PageSize:  int(req.PageSize),
+		PageToken: req.PageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Every user has at least its creation on record, unless created before the audit log
+	if len(changes) == 0 && req.PageToken == "" {
+		if _, err := s.repo.GetByID(req.UserId); err == repository.ErrUserNotFound {
+			return nil, errorinfo.Errorf(codes.NotFound, usererrors.ReasonUserNotFound, "User ID=%d not found", req.UserId)
+		}
+	}
+	return &pb.UserHistoryResponse{Changes: changes, NextPageToken: next}, nil
+}
+
+// listAudit reads a page of audit entries matching filter in their protobuf form
+func (s *UserService) listAudit(filter repository.AuditFilter) ([]*pb.AuditEntry, string, error) {
+	entries, next, err := s.audit.List(filter)
+	if err != nil {
+		if err == repository.ErrInvalidPageToken {
+			return nil, "", errInvalidPageToken
+		}
+		return nil, "", status.Errorf(codes.Internal, "Failed to read audit log: %v", err)
+	}
+
+	res := make([]*pb.AuditEntry, len(entries))
+	for i, entry := range entries {
+		res[i] = entry.ToProto()
+	}
+	return res, next, nil
+}
+
+// recordChange appends a change made by method to the audit log. The change is already
+// committed when this runs, so a failure to record it is logged rather than returned.
+func (s *UserService) recordChange(ctx context.Context, method string, userID int32, oldValue, newValue *models.User) {
+	entry := &models.AuditEntry{
+		Actor:     actor(ctx),
+		Method:    method,
+		UserID:    userID,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		CreatedAt: time.Now(),
+	}
+	if err := s.audit.Append(entry); err != nil {
+		slog.ErrorContext(ctx, "Failed to record change in audit log",
+			"change", method, "user_id", userID, "actor", logging.Redact("actor", entry.Actor), "error", err)
+	}
+}
+
+// actor identifies the caller as recorded in the audit log, anonymousActor when caller
+// can't
+func actor(ctx context.Context) string {
+	if name, ok := caller(ctx); ok {
+		return name
+	}
+	return anonymousActor
+}
+
+// caller identifies the caller: the authenticated principal, else the client
+// certificate's subject under mutual TLS; false when neither is known
+func caller(ctx context.Context) (string, bool) {
+	if p, ok := auth.PrincipalFromContext(ctx); ok && p.Subject != "" {
+		return p.Subject, true
+	}
+	if id, ok := auth.ClientIdentityFromContext(ctx); ok && id.CommonName != "" {
+		return id.CommonName, true
+	}
+	return "", false
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"example.com/user/internal/auth"
+	"example.com/user/internal/logging"
+	"example.com/user/internal/models"
+	"example.com/user/internal/repository"
+	pb "example.com/user/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AuthService implements the gRPC AuthService: short-lived access tokens renewed with
+// rotating, single-use refresh tokens
+type AuthService struct {
+	pb.UnimplementedAuthServiceServer
+	tokens     repository.TokenRepository
+	accounts   auth.AccountChecker
+	roles      auth.RoleResolver
+	issuer     *auth.JWTAuthenticator
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewAuthService creates an AuthService signing access tokens with issuer, refusing to
+// renew sessions of the accounts that accounts rejects and renewing the others with the
+// role roles resolves
+func NewAuthService(tokens repository.TokenRepository, accounts auth.AccountChecker, roles auth.RoleResolver, issuer *auth.JWTAuthenticator, accessTTL, refreshTTL time.Duration) *AuthService {
+	return &AuthService{
+		tokens:     tokens,
+		accounts:   accounts,
+		roles:      roles,
+		issuer:     issuer,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// IssueTokens starts a new session for the authenticated caller
+func (s *AuthService) IssueTokens(ctx context.Context, _ *emptypb.Empty) (*pb.TokenResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Authentication required")
+	}
+
+	family, err := randomToken(16)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to start session: %v", err)
+	}
+
+	return s.issue(principal.Subject, principal.Role, family)
+}
+
+// RefreshToken rotates a refresh token. Presenting a token that was already used means it
+// leaked, so the whole session is revoked.
+func (s *AuthService) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.TokenResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "Refresh token is required")
+	}
+
+	hash := hashToken(req.RefreshToken)
+	stored, err := s.tokens.GetRefreshToken(hash)
+	if err == repository.ErrTokenNotFound {
+		return nil, status.Error(codes.Unauthenticated, "Invalid refresh token")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to load refresh token: %v", err)
+	}
+	slog.DebugContext(ctx, "Refreshing session", "subject", logging.Redact("subject", stored.Subject))
+
+	if stored.IsRevoked() {
+		return nil, s.revokeSession(ctx, stored)
+	}
+	if stored.IsExpired(time.Now()) {
+		return nil, status.Error(codes.Unauthenticated, "Refresh token expired")
+	}
+	// RefreshToken is public, so the account is checked here; the session resumes if the
+	// account is reactivated before the token expires
+	principal := &auth.Principal{Subject: stored.Subject, Role: stored.Role}
+	if err := s.accounts(ctx, principal); err != nil {
+		return nil, err
+	}
+	// The role may have changed since the session started
+	role, err := s.roles(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	// Losing this race to a concurrent refresh is also reuse
+	switch err := s.tokens.RevokeRefreshToken(hash); err {
+	case nil:
+	case repository.ErrTokenRevoked:
+		return nil, s.revokeSession(ctx, stored)
+	default:
+		return nil, status.Errorf(codes.Internal, "Failed to rotate refresh token: %v", err)
+	}
+
+	return s.issue(stored.Subject, role, stored.FamilyID)
+}
+
+// revokeSession revokes every token of a session after a refresh token was reused
+func (s *AuthService) revokeSession(ctx context.Context, reused *models.RefreshToken) error {
+	slog.WarnContext(ctx, "Refresh token reused, revoking session", "subject", logging.Redact("subject", reused.Subject))
+	if err := s.tokens.RevokeTokenFamily(reused.FamilyID); err != nil {
+		return status.Errorf(codes.Internal, "Failed to revoke session: %v", err)
+	}
+	return status.Error(codes.Unauthenticated, "Refresh token already used; session revoked")
+}
+
+// issue signs an access token and stores the next refresh token of family
+func (s *AuthService) issue(subject, role, family string) (*pb.TokenResponse, error) {
+	now := time.Now()
+
+	accessToken, err := s.issuer.Issue(subject, role, "", s.accessTTL)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to sign access token: %v", err)
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to generate refresh token: %v", err)
+	}
+	stored := &models.RefreshToken{
+		TokenHash: hashToken(refreshToken),
+		FamilyID:  family,
+		Subject:   subject,
+		Role:      role,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.refreshTTL),
+	}
+	if err := s.tokens.CreateRefreshToken(stored); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to store refresh token: %v", err)
+	}
+
+	return &pb.TokenResponse{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  timestamppb.New(now.Add(s.accessTTL)),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(stored.ExpiresAt),
+	}, nil
+}
+
+// randomToken returns n random bytes, base64url-encoded
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken is how refresh tokens are stored, so a leaked table can't be replayed
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
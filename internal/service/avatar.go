@@ -0,0 +1,160 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"example.com/user/internal/blob"
+	"example.com/user/internal/errorinfo"
+	"example.com/user/internal/repository"
+	"example.com/user/internal/validation"
+	"example.com/user/pkg/usererrors"
+	pb "example.com/user/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// avatarTypes are the content types of the images accepted as avatars
+var avatarTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp"}
+
+// sniffLen is how much of an upload is read to detect its content type
+const sniffLen = 512
+
+// avatarKey is the blob key of the avatar of user id
+func avatarKey(id int32) string {
+	return fmt.Sprintf("avatars/%d", id)
+}
+
+// UploadAvatar implements client streaming RPC storing an image as the avatar of a user.
+// The image is written to the blob store as it arrives and replaces the previous avatar
+// only once complete, so a failed upload leaves the avatar as it was.
+func (s *UserService) UploadAvatar(stream pb.UserService_UploadAvatarServer) error {
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "Invalid request: user_id is required")
+	}
+	if err != nil {
+		return err
+	}
+	if err := validation.Validate(first); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetByID(first.UserId); err != nil {
+		if err == repository.ErrUserNotFound {
+			return errorinfo.Errorf(codes.NotFound, usererrors.ReasonUserNotFound, "User ID=%d not found", first.UserId)
+		}
+		return status.Errorf(codes.Internal, "Failed to get user: %v", err)
+	}
+
+	data := &uploadReader{stream: stream, buf: first.Data, size: len(first.Data), limit: s.maxAvatarSize}
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(data, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	if n == 0 {
+		return status.Error(codes.InvalidArgument, "Avatar is empty")
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	if !slices.Contains(avatarTypes, contentType) {
+		return status.Error(codes.InvalidArgument, "Avatar must be a PNG, JPEG, GIF or WebP image")
+	}
+	if first.ContentType != "" && first.ContentType != contentType {
+		return status.Errorf(codes.InvalidArgument, "content_type %q doesn't match the image, which is %s", first.ContentType, contentType)
+	}
+
+	info, err := s.avatars.Put(ctx, avatarKey(first.UserId), contentType, io.MultiReader(bytes.NewReader(head), data))
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		if ctxErr := s.checkContext(ctx); ctxErr != nil {
+			return ctxErr
+		}
+		return status.Errorf(codes.Internal, "Failed to store avatar: %v", err)
+	}
+	return stream.SendAndClose(avatarProto(first.UserId, info))
+}
+
+// uploadReader reads the data of the messages of an upload stream, failing with
+// ResourceExhausted once more than limit bytes have arrived
+type uploadReader struct {
+	stream pb.UserService_UploadAvatarServer
+	buf    []byte
+	size   int
+	limit  int
+}
+
+func (r *uploadReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			// io.EOF ends the upload
+			return 0, err
+		}
+		r.buf = req.Data
+		r.size += len(req.Data)
+	}
+	if r.size > r.limit {
+		return 0, status.Errorf(codes.ResourceExhausted, "Avatar is larger than %d bytes", r.limit)
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// GetAvatar implements server streaming RPC sending the avatar of a user: first its
+// description, then its data in chunks
+func (s *UserService) GetAvatar(req *pb.UserRequest, stream pb.UserService_GetAvatarServer) error {
+	ctx := stream.Context()
+	if err := s.checkContext(ctx); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetByID(req.Id); err != nil {
+		if err == repository.ErrUserNotFound {
+			return errorinfo.Errorf(codes.NotFound, usererrors.ReasonUserNotFound, "User ID=%d not found", req.Id)
+		}
+		return status.Errorf(codes.Internal, "Failed to get user: %v", err)
+	}
+
+	data, info, err := s.avatars.Get(ctx, avatarKey(req.Id))
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			return status.Errorf(codes.NotFound, "User ID=%d has no avatar", req.Id)
+		}
+		return status.Errorf(codes.Internal, "Failed to read avatar: %v", err)
+	}
+	defer data.Close()
+
+	if err := stream.Send(&pb.AvatarChunk{Avatar: avatarProto(req.Id, info)}); err != nil {
+		return err
+	}
+	chunks := &chunkWriter{send: func(data []byte) error {
+		return stream.Send(&pb.AvatarChunk{Data: data})
+	}}
+	if _, err := io.Copy(chunks, data); err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Errorf(codes.Internal, "Failed to read avatar: %v", err)
+	}
+	return chunks.flush()
+}
+
+// avatarProto converts the stored avatar of user id into its protobuf form
+func avatarProto(id int32, info blob.Info) *pb.Avatar {
+	return &pb.Avatar{
+		UserId:      id,
+		ContentType: info.ContentType,
+		Size:        info.Size,
+		UpdatedAt:   timestamppb.New(info.ModTime),
+	}
+}
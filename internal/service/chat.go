@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"example.com/user/internal/auth"
+	"example.com/user/internal/chat"
+	"example.com/user/internal/logging"
+	"example.com/user/internal/models"
+	"example.com/user/internal/repository"
+	"example.com/user/internal/validation"
+	pb "example.com/user/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// chatHeartbeat is how often Chat streams are sent a heartbeat
+	chatHeartbeat = 30 * time.Second
+	// chatServerName is who heartbeats and echoes are from; messages to it are echoed
+	chatServerName = "Server"
+)
+
+// Chat implements bidirectional streaming RPC. Messages reach the user named by to, the
+// other members of their room, or else every other stream, and are kept in the chat
+// history; JOIN and LEAVE messages enter and leave rooms, telling their members. Rooms
+// are open to every caller, so joining one takes no permission.
+func (s *UserService) Chat(stream pb.UserService_ChatServer) error {
+	ctx := stream.Context()
+	member := chat.NewMember()
+	defer func() {
+		name, rooms := s.chat.Close(member)
+		for _, room := range rooms {
+			s.chat.Broadcast(room, chatNotice(pb.MessageType_MESSAGE_TYPE_LEAVE, room, name), nil)
+		}
+	}()
+
+	// Only this goroutine sends, as gRPC streams don't allow concurrent sends; the
+	// receiving one delivers to the member's outbox instead
+	received := make(chan error, 1)
+	go func() {
+		received <- s.receiveChat(stream, member)
+	}()
+
+	ticker := time.NewTicker(chatHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case msg := <-member.Outbox():
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			heartbeat := &pb.ChatMessage{
+				From:      chatServerName,
+				Message:   "Heartbeat",
+				Timestamp: timestamppb.Now(),
+				Type:      pb.MessageType_MESSAGE_TYPE_TEXT,
+			}
+			if err := stream.Send(heartbeat); err != nil {
+				return err
+			}
+		case err := <-received:
+			// The client closed its side: send what was queued for it, then end the stream
+			if err != nil {
+				return err
+			}
+			for {
+				select {
+				case msg := <-member.Outbox():
+					if err := stream.Send(msg); err != nil {
+						return err
+					}
+				default:
+					return nil
+				}
+			}
+		case <-member.Overflowed():
+			return status.Errorf(codes.Aborted,
+				"Chat fell more than %d messages behind; some were dropped", chat.OutboxSize)
+		case <-ctx.Done():
+			// Recv doesn't watch the context; returning ends the stream, which unblocks it
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// receiveChat handles the messages of stream until the client closes its side, which
+// it reports as nil. The stream is registered under the identity of the caller, so no
+// one can chat as, or be sent the messages of, someone else; only when the server
+// doesn't authenticate callers is it registered under the from name of its first
+// message, which the others must keep.
+func (s *UserService) receiveChat(stream pb.UserService_ChatServer, member *chat.Member) error {
+	name, bound := caller(stream.Context())
+	if bound {
+		if name == chatServerName {
+			return status.Errorf(codes.PermissionDenied, "%s is reserved for the server", chatServerName)
+		}
+		// Known callers can be sent to before they send anything
+		s.chat.Register(name, member)
+	}
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		slog.DebugContext(stream.Context(), "Message received",
+			"from", logging.Redact("from", msg.From), "to", logging.Redact("to", msg.To),
+			"room", msg.Room, "type", msg.Type, "message", logging.Redact("message", msg.Message))
+
+		// Stream messages skip the validation interceptor
+		if err := validation.Validate(msg); err != nil {
+			return err
+		}
+		switch {
+		case bound && msg.From != "" && msg.From != name:
+			return validation.FieldError("from", fmt.Sprintf("must be empty or %s, the authenticated caller", name))
+		case bound:
+		case msg.From == "":
+			return validation.FieldError("from", "is required when callers aren't authenticated")
+		case msg.From == chatServerName:
+			return validation.FieldError("from", "is reserved for the server")
+		case name == "":
+			name = msg.From
+			s.chat.Register(name, member)
+		case msg.From != name:
+			return validation.FieldError("from", fmt.Sprintf("must stay %s, the name the stream started with", name))
+		}
+		msg.From = name
+
+		switch {
+		case msg.Type == pb.MessageType_MESSAGE_TYPE_JOIN:
+			// Without a room, joining only registers the stream, so it can be sent to
+			// before it sends anything
+			if msg.Room != "" && s.chat.Join(msg.Room, member) {
+				s.chat.Broadcast(msg.Room, chatNotice(msg.Type, msg.Room, name), nil)
+			}
+		case msg.Type == pb.MessageType_MESSAGE_TYPE_LEAVE:
+			if s.chat.Leave(msg.Room, member) {
+				notice := chatNotice(msg.Type, msg.Room, name)
+				member.Deliver(notice)
+				s.chat.Broadcast(msg.Room, notice, nil)
+			}
+		case msg.To == chatServerName:
+			member.Deliver(&pb.ChatMessage{
+				From:      chatServerName,
+				To:        name,
+				Message:   fmt.Sprintf("Echo: %s", msg.Message),
+				Timestamp: timestamppb.Now(),
+				Type:      pb.MessageType_MESSAGE_TYPE_TEXT,
+			})
+		case msg.To != "":
+			// Users not connected read it from the history once back
+			s.chat.Send(msg.To, s.relay(stream.Context(), msg))
+		case msg.Room != "":
+			if !s.chat.InRoom(msg.Room, member) {
+				return status.Errorf(codes.FailedPrecondition, "Join room %s before sending to it", msg.Room)
+			}
+			s.chat.Broadcast(msg.Room, s.relay(stream.Context(), msg), member)
+		default:
+			s.chat.BroadcastAll(s.relay(stream.Context(), msg), member)
+		}
+	}
+}
+
+// relay keeps msg in the chat history and returns the copy its recipients are sent,
+// stamped with the time it was sent and its ID in the history. A message that can't be
+// kept is still delivered, without an ID.
+func (s *UserService) relay(ctx context.Context, msg *pb.ChatMessage) *pb.ChatMessage {
+	kept := models.ChatMessageFromProto(msg, time.Now())
+	if err := s.chats.Append(kept); err != nil {
+		slog.ErrorContext(ctx, "Failed to keep chat message in history",
+			"from", logging.Redact("from", msg.From), "room", msg.Room, "error", err)
+		kept.ID = 0
+	}
+	return kept.ToProto()
+}
+
+// GetChatHistory implements unary RPC returning the latest messages of a room, of two
+// users or sent to everyone, oldest first. Known callers read as themselves, and only
+// admins may name another user; a room's history is for the users in it.
+func (s *UserService) GetChatHistory(ctx context.Context, req *pb.ChatHistoryRequest) (*pb.ChatHistoryResponse, error) {
+	if err := s.checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	user, known := caller(ctx)
+	p, _ := auth.PrincipalFromContext(ctx)
+	admin := p != nil && p.Role == auth.RoleAdmin
+	switch {
+	case !known:
+		user = req.User
+	case req.User == "" || req.User == user:
+	case !admin:
+		return nil, status.Errorf(codes.PermissionDenied, "Only admins may read the chat history of another user than %s", user)
+	default:
+		user = req.User
+	}
+	if (req.Room != "" || req.Peer != "") && user == "" {
+		return nil, validation.FieldError("user", "is required with room or peer when callers aren't authenticated")
+	}
+	if req.Room != "" && !admin && !s.chat.Present(req.Room, user) {
+		return nil, status.Errorf(codes.PermissionDenied, "Join room %s to read its history", req.Room)
+	}
+
+	messages, more, err := s.chats.History(repository.ChatFilter{
+		Room:   req.Room,
+		User:   user,
+		Peer:   req.Peer,
+		Before: req.Before,
+		Limit:  int(req.Limit),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to read chat history: %v", err)
+	}
+
+	// History returns the newest first
+	res := &pb.ChatHistoryResponse{Messages: make([]*pb.ChatMessage, len(messages)), HasMore: more}
+	for i, msg := range messages {
+		res.Messages[len(messages)-1-i] = msg.ToProto()
+	}
+	return res, nil
+}
+
+// chatNotice tells the members of room that name joined or left it
+func chatNotice(t pb.MessageType, room, name string) *pb.ChatMessage {
+	verb := "joined"
+	if t == pb.MessageType_MESSAGE_TYPE_LEAVE {
+		verb = "left"
+	}
+	return &pb.ChatMessage{
+		From:      name,
+		Message:   fmt.Sprintf("%s %s %s", name, verb, room),
+		Timestamp: timestamppb.Now(),
+		Type:      t,
+		Room:      room,
+	}
+}
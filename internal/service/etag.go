@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"example.com/user/internal/errorinfo"
+	"example.com/user/internal/models"
+	"example.com/user/pkg/usererrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// IfMatchHeader is the metadata key of the ETags UpdateUser and DeleteUser require the
+// user to still have, as HTTP's If-Match header does
+const IfMatchHeader = "if-match"
+
+// ifMatch returns the ETags of the if-match metadata of ctx, nil when there are none.
+// Values hold one or more comma-separated ETags, quoted or not, or "*" for any.
+func ifMatch(ctx context.Context) []string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var etags []string
+	for _, value := range md.Get(IfMatchHeader) {
+		for _, etag := range strings.Split(value, ",") {
+			if etag = strings.TrimSpace(etag); etag != "" {
+				etags = append(etags, etag)
+			}
+		}
+	}
+	return etags
+}
+
+// checkETag fails with FAILED_PRECONDITION unless user has one of etags, or etags is
+// empty. Weak ETags never match, as If-Match compares strongly.
+func checkETag(user *models.User, etags []string) error {
+	if len(etags) == 0 {
+		return nil
+	}
+	current := user.ETag()
+	for _, etag := range etags {
+		if etag == "*" || strings.Trim(etag, `"`) == current {
+			return nil
+		}
+	}
+	return errorinfo.Errorf(codes.FailedPrecondition, usererrors.ReasonVersionConflict,
+		"User ID=%d has changed: its ETag is %s, not %s", user.ID, current, strings.Join(etags, ", "))
+}
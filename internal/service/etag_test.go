@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"example.com/user/internal/repository"
+	pb "example.com/user/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   []string
+	}{
+		{"none", nil, nil},
+		{"one", []string{`"abc"`}, []string{`"abc"`}},
+		{"list", []string{`"abc", def ,`}, []string{`"abc"`, "def"}},
+		{"repeated", []string{"abc", "*"}, []string{"abc", "*"}},
+		{"blank", []string{" "}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.MD{}
+			for _, value := range tt.values {
+				md.Append(IfMatchHeader, value)
+			}
+			got := ifMatch(metadata.NewIncomingContext(context.Background(), md))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ifMatch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestETagPreconditions(t *testing.T) {
+	// current returns the ETag user 2 has in s
+	current := func(t *testing.T, s *UserService) string {
+		t.Helper()
+		user, err := s.repo.GetByID(2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return user.ETag()
+	}
+	update := func(ctx context.Context, s *UserService) error {
+		_, err := s.UpdateUser(ctx, &pb.UpdateUserRequest{Id: 2, Name: "Jane Doe"})
+		return err
+	}
+	deleteUser := func(ctx context.Context, s *UserService) error {
+		_, err := s.DeleteUser(ctx, &pb.UserRequest{Id: 2})
+		return err
+	}
+
+	tests := []struct {
+		name    string
+		call    func(ctx context.Context, s *UserService) error
+		ifMatch func(etag string) string // nil sends no if-match
+		want    codes.Code
+	}{
+		{"update unconditionally", update, nil, codes.OK},
+		{"update current", update, func(etag string) string { return etag }, codes.OK},
+		{"update current quoted", update, func(etag string) string { return `"` + etag + `"` }, codes.OK},
+		{"update any", update, func(string) string { return "*" }, codes.OK},
+		{"update one of several", update, func(etag string) string { return `"stale", "` + etag + `"` }, codes.OK},
+		{"update stale", update, func(string) string { return `"stale"` }, codes.FailedPrecondition},
+		{"update weak", update, func(etag string) string { return `W/"` + etag + `"` }, codes.FailedPrecondition},
+		{"delete unconditionally", deleteUser, nil, codes.OK},
+		{"delete current", deleteUser, func(etag string) string { return `"` + etag + `"` }, codes.OK},
+		{"delete stale", deleteUser, func(string) string { return `"stale"` }, codes.FailedPrecondition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService()
+			ctx := context.Background()
+			if tt.ifMatch != nil {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(IfMatchHeader, tt.ifMatch(current(t, s))))
+			}
+
+			err := tt.call(ctx, s)
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("code = %v (%v), want %v", got, err, tt.want)
+			}
+
+			// A failed precondition leaves the user as it was
+			user, err := s.repo.GetByID(2)
+			if tt.want != codes.OK && (err != nil || user.Version != 1) {
+				t.Errorf("user 2 after a failed precondition = %+v, %v; want it unchanged", user, err)
+			}
+		})
+	}
+}
+
+func TestETagChangesWithUser(t *testing.T) {
+	s := newTestService()
+	before, err := s.GetUser(context.Background(), &pb.UserRequest{Id: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := s.UpdateUser(context.Background(), &pb.UpdateUserRequest{Id: 2, Name: "Jane Doe"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.Etag == "" || before.Etag == after.Etag {
+		t.Fatalf("ETag %q before the update and %q after, want two different ones", before.Etag, after.Etag)
+	}
+
+	// The ETag read before the update no longer allows deleting
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(IfMatchHeader, before.Etag))
+	if _, err := s.DeleteUser(ctx, &pb.UserRequest{Id: 2}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("DeleteUser with the old ETag = %v, want FailedPrecondition", err)
+	}
+	if _, err := s.repo.GetByID(2); err == repository.ErrUserNotFound {
+		t.Error("user 2 was deleted despite the failed precondition")
+	}
+}
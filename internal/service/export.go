@@ -0,0 +1,90 @@
+package service
+
+import (
+	"bytes"
+
+	"example.com/user/internal/repository"
+	"example.com/user/internal/userfile"
+	pb "example.com/user/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// chunkSize is how many bytes of a file or avatar each chunk sent carries, but the last
+	chunkSize = 64 << 10
+	// exportPageSize is how many users an export reads from the repository at a time
+	exportPageSize = 500
+)
+
+// ExportUsers implements server streaming RPC sending the users matching req as a file,
+// read from the repository a page at a time
+func (s *UserService) ExportUsers(req *pb.ExportUsersRequest, stream pb.UserService_ExportUsersServer) error {
+	chunks := &chunkWriter{send: func(data []byte) error {
+		return stream.Send(&pb.FileChunk{Data: data})
+	}}
+	file, err := userfile.NewWriter(chunks, req.Format)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid format: %v", err)
+	}
+
+	filter := &pb.UserFilter{
+		Keyword:        req.Keyword,
+		EmailContains:  req.EmailContains,
+		Roles:          req.Roles,
+		OrderBy:        req.OrderBy,
+		IncludeDeleted: req.IncludeDeleted,
+		PageSize:       exportPageSize,
+	}
+	for {
+		if err := s.checkContext(stream.Context()); err != nil {
+			return err
+		}
+		users, next, err := s.repo.List(filter)
+		if err != nil {
+			if err == repository.ErrInvalidOrderBy {
+				return errInvalidOrderBy
+			}
+			return status.Errorf(codes.Internal, "Failed to list users: %v", err)
+		}
+		for _, user := range users {
+			if err := file.Write(user.ToProto()); err != nil {
+				return err
+			}
+		}
+		if next == "" {
+			break
+		}
+		filter.PageToken = next
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return chunks.flush()
+}
+
+// chunkWriter buffers what is written to it and hands it to send in chunks of
+// chunkSize bytes
+type chunkWriter struct {
+	buf  bytes.Buffer
+	send func(data []byte) error
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for w.buf.Len() >= chunkSize {
+		if err := w.send(w.buf.Next(chunkSize)); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// flush sends whatever is left, if anything
+func (w *chunkWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	return w.send(w.buf.Next(w.buf.Len()))
+}
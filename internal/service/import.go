@@ -0,0 +1,120 @@
+package service
+
+import (
+	"fmt"
+	"io"
+
+	"example.com/user/internal/models"
+	"example.com/user/internal/repository"
+	"example.com/user/internal/userfile"
+	"example.com/user/internal/validation"
+	pb "example.com/user/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// importMaxBytes is the largest file ImportUsers accepts, as the whole file is read
+// before any of it is imported
+const importMaxBytes = 32 << 20
+
+// ImportUsers implements client streaming RPC creating the users of a file as ExportUsers
+// writes it. Like CreateUsers, the import is atomic: every user is checked first and, if
+// any is rejected, the errors are reported by line and nothing is imported. Names,
+// emails, roles and statuses are imported; users get new IDs, times and versions, and
+// soft-deleted users are skipped.
+func (s *UserService) ImportUsers(stream pb.UserService_ImportUsersServer) error {
+	var format pb.FileFormat
+	var data []byte
+	for first := true; ; first = false {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if first {
+			format = req.Format
+		}
+		if len(data)+len(req.Data) > importMaxBytes {
+			return status.Errorf(codes.ResourceExhausted, "Import is larger than %d MiB; split the file", importMaxBytes>>20)
+		}
+		data = append(data, req.Data...)
+	}
+
+	records, err := userfile.Read(data, format)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid file: %v", err)
+	}
+
+	var errors []*pb.ImportError
+	var imported []userfile.Record
+	var users []*models.User
+	skipped := 0
+	for _, record := range records {
+		if record.Err != nil {
+			errors = append(errors, &pb.ImportError{Line: int32(record.Line), Message: record.Err.Error()})
+			continue
+		}
+		if record.User.DeletedAt != nil {
+			skipped++
+			continue
+		}
+		req := &pb.CreateUserRequest{
+			Name:     record.User.Name,
+			Email:    record.User.Email,
+			Role:     record.User.Role,
+			RoleType: record.User.RoleType,
+		}
+		if err := validation.Validate(req); err != nil {
+			errors = append(errors, &pb.ImportError{Line: int32(record.Line), Message: status.Convert(err).Message()})
+			continue
+		}
+		imported = append(imported, record)
+		users = append(users, models.FromCreateRequest(req, 0))
+	}
+	if len(errors) > 0 {
+		return stream.SendAndClose(&pb.ImportUsersResponse{SkippedCount: int32(skipped), Errors: errors})
+	}
+
+	// New users start out active, so suspended ones are suspended in the same transaction
+	err = s.repo.WithTx(stream.Context(), func(tx repository.UserRepository) error {
+		if err := tx.CreateMany(users); err != nil {
+			return err
+		}
+		for i, user := range users {
+			if imported[i].User.Status == pb.UserStatus_USER_STATUS_SUSPENDED {
+				user.Status = models.StatusSuspended
+				if err := tx.Update(user); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if batchErr, rejected := err.(*repository.BatchError); rejected {
+		for i, record := range imported {
+			if userErr, failed := batchErr.Errors[i]; failed {
+				errors = append(errors, &pb.ImportError{Line: int32(record.Line), Message: fmt.Sprintf("Email %s: %v", record.User.Email, userErr)})
+			}
+		}
+		return stream.SendAndClose(&pb.ImportUsersResponse{SkippedCount: int32(skipped), Errors: errors})
+	}
+	if err != nil {
+		if ctxErr := s.checkContext(stream.Context()); ctxErr != nil {
+			return ctxErr
+		}
+		return status.Errorf(codes.Internal, "Failed to import users: %v", err)
+	}
+
+	userIDs := make([]int32, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+		s.recordChange(stream.Context(), "ImportUsers", user.ID, nil, user)
+	}
+	return stream.SendAndClose(&pb.ImportUsersResponse{
+		ImportedCount: int32(len(users)),
+		UserIds:       userIDs,
+		SkippedCount:  int32(skipped),
+	})
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+
+	"example.com/user/internal/errorinfo"
+	"example.com/user/internal/models"
+	"example.com/user/internal/repository"
+	"example.com/user/pkg/usererrors"
+	pb "example.com/user/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetPreferences implements unary RPC returning the preferences of a user, the defaults
+// until it saves some
+func (s *UserService) GetPreferences(ctx context.Context, req *pb.UserRequest) (*pb.UserPreferences, error) {
+	prefs, err := s.preferences(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return prefs.ToProto(), nil
+}
+
+// UpdatePreferences implements unary RPC changing the preferences of a user
+func (s *UserService) UpdatePreferences(ctx context.Context, req *pb.UpdatePreferencesRequest) (*pb.UserPreferences, error) {
+	prefs, err := s.preferences(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs.Update(req)
+	if err := s.prefs.SavePreferences(prefs); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to save preferences: %v", err)
+	}
+	return prefs.ToProto(), nil
+}
+
+// preferences returns the stored preferences of user id, or its defaults
+func (s *UserService) preferences(ctx context.Context, id int32) (*models.Preferences, error) {
+	if err := s.checkContext(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetByID(id); err != nil {
+		if err == repository.ErrUserNotFound {
+			return nil, errorinfo.Errorf(codes.NotFound, usererrors.ReasonUserNotFound, "User ID=%d not found", id)
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to get user: %v", err)
+	}
+
+	prefs, err := s.prefs.GetPreferences(id)
+	if err == repository.ErrPreferencesNotFound {
+		return models.DefaultPreferences(id), nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get preferences: %v", err)
+	}
+	return prefs, nil
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"example.com/user/internal/auth"
+	"example.com/user/internal/errorinfo"
+	"example.com/user/internal/models"
+	"example.com/user/internal/repository"
+	"example.com/user/pkg/usererrors"
+	pb "example.com/user/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SuspendUser implements unary RPC suspending a user, whose credentials are rejected
+// from then on
+func (s *UserService) SuspendUser(ctx context.Context, req *pb.UserRequest) (*pb.UserResponse, error) {
+	return s.setStatus(ctx, "SuspendUser", req.Id, models.StatusSuspended)
+}
+
+// ActivateUser implements unary RPC reactivating a suspended user
+func (s *UserService) ActivateUser(ctx context.Context, req *pb.UserRequest) (*pb.UserResponse, error) {
+	return s.setStatus(ctx, "ActivateUser", req.Id, models.StatusActive)
+}
+
+// setStatus moves the user with id to target, failing when it is already there
+func (s *UserService) setStatus(ctx context.Context, method string, id int32, target string) (*pb.UserResponse, error) {
+	if err := s.checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetByID(id)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return nil, errorinfo.Errorf(codes.NotFound, usererrors.ReasonUserNotFound, "User ID=%d not found", id)
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to get user: %v", err)
+	}
+	if user.IsSuspended() == (target == models.StatusSuspended) {
+		return nil, status.Errorf(codes.FailedPrecondition, "User ID=%d is already %s", id, target)
+	}
+
+	previous := *user
+	user.Status = target
+	user.UpdatedAt = time.Now()
+	if err := s.repo.Update(user); err != nil {
+		switch err {
+		case repository.ErrVersionConflict:
+			return nil, errorinfo.Errorf(codes.FailedPrecondition, usererrors.ReasonVersionConflict, "User ID=%d was modified concurrently", id)
+		case repository.ErrUserNotFound:
+			return nil, errorinfo.Errorf(codes.NotFound, usererrors.ReasonUserNotFound, "User ID=%d not found", id)
+		default:
+			return nil, status.Errorf(codes.Internal, "Failed to update user: %v", err)
+		}
+	}
+	s.recordChange(ctx, method, user.ID, &previous, user)
+
+	return user.ToProto(), nil
+}
+
+// CheckAccount is the auth.AccountChecker rejecting suspended and deleted users. Only
+// subjects that are user IDs are checked; others, such as API key names, are not users.
+func (s *UserService) CheckAccount(ctx context.Context, p *auth.Principal) error {
+	user, err := s.account(p)
+	switch {
+	case err != nil:
+		return err
+	case user != nil && user.IsSuspended():
+		return errorinfo.Errorf(codes.PermissionDenied, usererrors.ReasonUserSuspended, "User ID=%d is suspended", user.ID)
+	}
+	return nil
+}
+
+// AccountRole is the auth.RoleResolver returning the stored role of the user p is, so
+// renewed sessions pick up role changes. Other subjects keep the role of p.
+func (s *UserService) AccountRole(ctx context.Context, p *auth.Principal) (string, error) {
+	user, err := s.account(p)
+	switch {
+	case err != nil:
+		return "", err
+	case user == nil:
+		return p.Role, nil
+	}
+	return user.Role, nil
+}
+
+// account returns the stored user whose ID is the subject of p, or nil when the subject
+// isn't a user ID. A user ID without a user, as once the user is deleted, is
+// UNAUTHENTICATED, so its credentials can't be used or renewed.
+func (s *UserService) account(p *auth.Principal) (*models.User, error) {
+	id, err := strconv.ParseInt(p.Subject, 10, 32)
+	if err != nil {
+		return nil, nil
+	}
+
+	user, err := s.repo.GetByID(int32(id))
+	switch {
+	case err == repository.ErrUserNotFound:
+		return nil, status.Errorf(codes.Unauthenticated, "User ID=%d no longer exists", id)
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "Failed to load account: %v", err)
+	}
+	return user, nil
+}
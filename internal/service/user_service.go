@@ -4,36 +4,68 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
-	"sync"
 	"time"
 
+	"example.com/user/internal/blob"
+	"example.com/user/internal/chat"
+	"example.com/user/internal/errorinfo"
 	"example.com/user/internal/models"
 	"example.com/user/internal/repository"
+	"example.com/user/internal/search"
+	"example.com/user/internal/validation"
+	"example.com/user/pkg/usererrors"
 	pb "example.com/user/proto"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
-	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NextPageTokenKey is the trailer carrying StreamUsers' next page token
+const NextPageTokenKey = "next-page-token"
+
+// defaultListPageSize is the page size of ListUsers when the request sets none
+const defaultListPageSize = 50
+
+// Errors for list parameters the repository turns down
+var (
+	errInvalidOrderBy = validation.FieldError("order_by",
+		`must be "id", "name", "email" or "created_at", optionally followed by "asc" or "desc"`)
+	errInvalidPageToken = validation.FieldError("page_token", "must be a next page token of the same listing")
 )
 
 // UserService implements the gRPC UserService interface
 type UserService struct {
 	pb.UnimplementedUserServiceServer
-	repo repository.UserRepository
+	repo          repository.UserRepository
+	audit         repository.AuditRepository
+	hooks         *repository.Hooks
+	prefs         repository.PreferencesRepository
+	avatars       blob.Store
+	maxAvatarSize int
+	chat          *chat.Hub
+	chats         repository.ChatRepository
 }
 
-// NewUserService creates a new UserService instance
-func NewUserService(repo repository.UserRepository) *UserService {
+// NewUserService creates a new UserService instance recording every change in audit,
+// telling watchers of the changes hooks publishes, keeping preferences in prefs, avatars
+// of up to maxAvatarSize bytes in avatars and the chat history in chats
+func NewUserService(repo repository.UserRepository, audit repository.AuditRepository, hooks *repository.Hooks, prefs repository.PreferencesRepository, avatars blob.Store, maxAvatarSize int, chats repository.ChatRepository) *UserService {
 	return &UserService{
-		repo: repo,
+		repo:          repo,
+		audit:         audit,
+		hooks:         hooks,
+		prefs:         prefs,
+		avatars:       avatars,
+		maxAvatarSize: maxAvatarSize,
+		chat:          chat.NewHub(),
+		chats:         chats,
 	}
 }
 
 // GetUser implements unary RPC for user retrieval
 func (s *UserService) GetUser(ctx context.Context, req *pb.UserRequest) (*pb.UserResponse, error) {
-	log.Printf("GetUser called: ID=%d", req.Id)
-	
 	// Check context for timeout/cancellation
 	if err := s.checkContext(ctx); err != nil {
 		return nil, err
@@ -42,7 +74,7 @@ func (s *UserService) GetUser(ctx context.Context, req *pb.UserRequest) (*pb.Use
 	user, err := s.repo.GetByID(req.Id)
 	if err != nil {
 		if err == repository.ErrUserNotFound {
-			return nil, status.Errorf(codes.NotFound, "User ID=%d not found", req.Id)
+			return nil, errorinfo.Errorf(codes.NotFound, usererrors.ReasonUserNotFound, "User ID=%d not found", req.Id)
 		}
 		return nil, status.Errorf(codes.Internal, "Failed to get user: %v", err)
 	}
@@ -50,82 +82,211 @@ func (s *UserService) GetUser(ctx context.Context, req *pb.UserRequest) (*pb.Use
 	return user.ToProto(), nil
 }
 
+// BatchGetUsers implements unary RPC returning several users at once
+func (s *UserService) BatchGetUsers(ctx context.Context, req *pb.BatchGetUsersRequest) (*pb.BatchGetUsersResponse, error) {
+	if err := s.checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	res := &pb.BatchGetUsersResponse{}
+	seen := make(map[int32]bool, len(req.Ids))
+	for _, id := range req.Ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		user, err := s.repo.GetByID(id)
+		switch err {
+		case nil:
+			res.Users = append(res.Users, user.ToProto())
+		case repository.ErrUserNotFound:
+			res.MissingIds = append(res.MissingIds, id)
+		default:
+			return nil, status.Errorf(codes.Internal, "Failed to get user ID=%d: %v", id, err)
+		}
+	}
+
+	return res, nil
+}
+
 // CreateUser implements unary RPC for user creation
 func (s *UserService) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.UserResponse, error) {
-	log.Printf("CreateUser called: email=%s", req.Email)
-	
 	if err := s.checkContext(ctx); err != nil {
 		return nil, err
 	}
 	
+	// Checked here too, as the validation interceptor may be left out of INTERCEPTORS
+	if err := validation.Validate(req); err != nil {
+		return nil, err
+	}
+
 	user := models.FromCreateRequest(req, 0) // ID will be set by repository
 	
+	// Validate already turned down what the repository takes as invalid input
 	if err := s.repo.Create(user); err != nil {
 		switch err {
-		case repository.ErrInvalidInput:
-			return nil, status.Error(codes.InvalidArgument, "Name and email are required")
 		case repository.ErrEmailExists:
-			return nil, status.Errorf(codes.AlreadyExists, "Email %s already in use", req.Email)
+			return nil, emailInUse(req.Email)
 		default:
 			return nil, status.Errorf(codes.Internal, "Failed to create user: %v", err)
 		}
 	}
+	s.recordChange(ctx, "CreateUser", user.ID, nil, user)
 	
 	return user.ToProto(), nil
 }
 
 // UpdateUser implements unary RPC for user updates
 func (s *UserService) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UserResponse, error) {
-	log.Printf("UpdateUser called: ID=%d", req.Id)
-	
 	if err := s.checkContext(ctx); err != nil {
 		return nil, err
 	}
 	
+	// Checked here too, as for CreateUser
+	if err := validation.Validate(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.update(ctx, "UpdateUser", req, ifMatch(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return user.ToProto(), nil
+}
+
+// update applies req and records the change under method, failing with a status error.
+// Unless etags is empty, the user must have one of them.
+func (s *UserService) update(ctx context.Context, method string, req *pb.UpdateUserRequest, etags []string) (*models.User, error) {
 	user, err := s.repo.GetByID(req.Id)
 	if err != nil {
 		if err == repository.ErrUserNotFound {
-			return nil, status.Errorf(codes.NotFound, "User ID=%d not found", req.Id)
+			return nil, errorinfo.Errorf(codes.NotFound, usererrors.ReasonUserNotFound, "User ID=%d not found", req.Id)
 		}
 		return nil, status.Errorf(codes.Internal, "Failed to get user: %v", err)
 	}
 	
+	// Reject callers working from a stale copy of the user
+	if req.Version != 0 && req.Version != user.Version {
+		return nil, errorinfo.Errorf(codes.FailedPrecondition, usererrors.ReasonVersionConflict,
+			"User ID=%d is at version %d, not %d", req.Id, user.Version, req.Version)
+	}
+	if err := checkETag(user, etags); err != nil {
+		return nil, err
+	}
+
+	previous := *user
 	user.Update(req)
 	
 	if err := s.repo.Update(user); err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to update user: %v", err)
+		switch err {
+		case repository.ErrVersionConflict:
+			return nil, errorinfo.Errorf(codes.FailedPrecondition, usererrors.ReasonVersionConflict, "User ID=%d was modified concurrently", req.Id)
+		case repository.ErrUserNotFound:
+			return nil, errorinfo.Errorf(codes.NotFound, usererrors.ReasonUserNotFound, "User ID=%d not found", req.Id)
+		case repository.ErrEmailExists:
+			return nil, emailInUse(req.Email)
+		default:
+			return nil, status.Errorf(codes.Internal, "Failed to update user: %v", err)
+		}
 	}
+	s.recordChange(ctx, method, user.ID, &previous, user)
 	
-	return user.ToProto(), nil
+	return user, nil
 }
 
 // DeleteUser implements unary RPC for user deletion
 func (s *UserService) DeleteUser(ctx context.Context, req *pb.UserRequest) (*emptypb.Empty, error) {
-	log.Printf("DeleteUser called: ID=%d", req.Id)
-	
 	if err := s.checkContext(ctx); err != nil {
 		return nil, err
 	}
 	
-	if err := s.repo.Delete(req.Id); err != nil {
+	// Read the user first so the audit log has what was deleted, in the same transaction
+	// so the If-Match precondition still holds when deleting
+	etags := ifMatch(ctx)
+	var user *models.User
+	err := s.repo.WithTx(ctx, func(repo repository.UserRepository) error {
+		var err error
+		if user, err = repo.GetByID(req.Id); err != nil {
+			return err
+		}
+		if err := checkETag(user, etags); err != nil {
+			return err
+		}
+		return repo.Delete(req.Id)
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
 		if err == repository.ErrUserNotFound {
-			return nil, status.Errorf(codes.NotFound, "User ID=%d not found", req.Id)
+			return nil, errorinfo.Errorf(codes.NotFound, usererrors.ReasonUserNotFound, "User ID=%d not found", req.Id)
 		}
 		return nil, status.Errorf(codes.Internal, "Failed to delete user: %v", err)
 	}
+	s.recordChange(ctx, "DeleteUser", req.Id, user, nil)
 	
 	return &emptypb.Empty{}, nil
 }
 
+// UndeleteUser implements unary RPC restoring a soft-deleted user
+func (s *UserService) UndeleteUser(ctx context.Context, req *pb.UserRequest) (*pb.UserResponse, error) {
+	if err := s.checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Undelete(req.Id); err != nil {
+		switch err {
+		case repository.ErrUserNotFound:
+			return nil, errorinfo.Errorf(codes.NotFound, usererrors.ReasonUserNotFound, "User ID=%d not found", req.Id)
+		case repository.ErrUserNotDeleted:
+			return nil, status.Errorf(codes.FailedPrecondition, "User ID=%d is not deleted", req.Id)
+		default:
+			return nil, status.Errorf(codes.Internal, "Failed to undelete user: %v", err)
+		}
+	}
+
+	user, err := s.repo.GetByID(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get user: %v", err)
+	}
+	s.recordChange(ctx, "UndeleteUser", user.ID, nil, user)
+
+	return user.ToProto(), nil
+}
+
 // StreamUsers implements server streaming RPC
 func (s *UserService) StreamUsers(filter *pb.UserFilter, stream pb.UserService_StreamUsersServer) error {
-	log.Printf("StreamUsers called: filter=%v", filter)
+	// A resumed stream continues after the last user the client received
+	if filter.ResumeAfterId > 0 {
+		token, err := repository.ResumePageToken(filter.OrderBy, filter.ResumeAfterId, s.repo.GetByID)
+		switch err {
+		case nil:
+		case repository.ErrInvalidOrderBy:
+			return errInvalidOrderBy
+		case repository.ErrUserNotFound:
+			return status.Errorf(codes.FailedPrecondition, "Cannot resume after user ID=%d: not found", filter.ResumeAfterId)
+		default:
+			return status.Errorf(codes.Internal, "Failed to resume listing: %v", err)
+		}
+		filter = proto.CloneOf(filter)
+		filter.PageToken, filter.ResumeAfterId = token, 0
+	}
 	
-	users, err := s.repo.List(filter)
+	users, nextPageToken, err := s.repo.List(filter)
 	if err != nil {
+		switch err {
+		case repository.ErrInvalidPageToken:
+			return errInvalidPageToken
+		case repository.ErrInvalidOrderBy:
+			return errInvalidOrderBy
+		}
 		return status.Errorf(codes.Internal, "Failed to list users: %v", err)
 	}
 	
+	// The next page token is only known up front, so hand it back as a trailer
+	stream.SetTrailer(metadata.Pairs(NextPageTokenKey, nextPageToken))
+
 	for _, user := range users {
 		// Check if context is cancelled
 		if stream.Context().Err() != nil {
@@ -143,14 +304,73 @@ func (s *UserService) StreamUsers(filter *pb.UserFilter, stream pb.UserService_S
 	return nil
 }
 
-// CreateUsers implements client streaming RPC for bulk user creation
-func (s *UserService) CreateUsers(stream pb.UserService_CreateUsersServer) error {
-	log.Println("CreateUsers called - client streaming")
+// ListUsers implements unary RPC returning one page of users
+func (s *UserService) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	if err := s.checkContext(ctx); err != nil {
+		return nil, err
+	}
 	
-	var createdCount int32
-	var userIDs []int32
-	var errors []string
+	filter := &pb.UserFilter{
+		Keyword:        req.Keyword,
+		EmailContains:  req.EmailContains,
+		Roles:          req.Roles,
+		PageSize:       req.PageSize,
+		PageToken:      req.PageToken,
+		OrderBy:        req.OrderBy,
+		IncludeDeleted: req.IncludeDeleted,
+	}
+	return s.listPage(filter)
+}
+
+// SearchUsers implements unary RPC returning one page of the users matching a query
+func (s *UserService) SearchUsers(ctx context.Context, req *pb.SearchUsersRequest) (*pb.ListUsersResponse, error) {
+	if err := s.checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	filter, err := search.Parse(req.Query)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid query: %v", err)
+	}
+	filter.PageSize = req.PageSize
+	filter.PageToken = req.PageToken
+	filter.OrderBy = req.OrderBy
+	return s.listPage(filter)
+}
+
+// listPage returns a page of the users matching filter, of the default size unless the
+// filter sets one, with the number matching over all pages
+func (s *UserService) listPage(filter *pb.UserFilter) (*pb.ListUsersResponse, error) {
+	if filter.PageSize == 0 {
+		filter.PageSize = defaultListPageSize
+	}
+
+	users, nextPageToken, err := s.repo.List(filter)
+	if err != nil {
+		switch err {
+		case repository.ErrInvalidPageToken:
+			return nil, errInvalidPageToken
+		case repository.ErrInvalidOrderBy:
+			return nil, errInvalidOrderBy
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to list users: %v", err)
+	}
+	total, err := s.repo.Count(filter)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to count users: %v", err)
+	}
 	
+	res := &pb.ListUsersResponse{NextPageToken: nextPageToken, TotalSize: int32(total)}
+	for _, user := range users {
+		res.Users = append(res.Users, user.ToProto())
+	}
+	return res, nil
+}
+
+// CreateUsers implements client streaming RPC for bulk user creation.
+// The batch is applied atomically: if any user fails, none are created.
+func (s *UserService) CreateUsers(stream pb.UserService_CreateUsersServer) error {
+	var requests []*pb.CreateUserRequest
 	for {
 		req, err := stream.Recv()
 		if err == io.EOF {
@@ -159,83 +379,94 @@ func (s *UserService) CreateUsers(stream pb.UserService_CreateUsersServer) error
 		if err != nil {
 			return err
 		}
-		
-		user := models.FromCreateRequest(req, 0)
-		if err := s.repo.Create(user); err != nil {
-			errors = append(errors, fmt.Sprintf("Email %s: %v", req.Email, err))
-			continue
+		requests = append(requests, req)
+	}
+
+	var userIDs []int32
+	var errors []string
+
+	// Report every invalid record up front; like any other failure, they reject the batch
+	for _, req := range requests {
+		if err := validation.Validate(req); err != nil {
+			errors = append(errors, fmt.Sprintf("Email %s: %s", req.Email, status.Convert(err).Message()))
 		}
-		
-		createdCount++
-		userIDs = append(userIDs, user.ID)
+	}
+	if len(errors) > 0 {
+		return stream.SendAndClose(&pb.BulkCreateResponse{Errors: errors})
+	}
+
+	users := make([]*models.User, len(requests))
+	for i, req := range requests {
+		users[i] = models.FromCreateRequest(req, 0)
+	}
+
+	err := s.repo.CreateMany(users)
+	batchErr, rejected := err.(*repository.BatchError)
+	switch {
+	case err == nil:
+		for _, user := range users {
+			userIDs = append(userIDs, user.ID)
+			s.recordChange(stream.Context(), "CreateUsers", user.ID, nil, user)
+		}
+	case rejected:
+		// Report every failing record; the repository rolled back the whole batch
+		for i, req := range requests {
+			if reqErr, failed := batchErr.Errors[i]; failed {
+				errors = append(errors, fmt.Sprintf("Email %s: %v", req.Email, reqErr))
+			}
+		}
+	default:
+		return status.Errorf(codes.Internal, "Failed to create users: %v", err)
 	}
 	
 	return stream.SendAndClose(&pb.BulkCreateResponse{
-		CreatedCount: createdCount,
+		CreatedCount: int32(len(userIDs)),
 		UserIds:      userIDs,
 		Errors:       errors,
 	})
 }
 
-// Chat implements bidirectional streaming RPC
-func (s *UserService) Chat(stream pb.UserService_ChatServer) error {
-	log.Println("Chat called - bidirectional streaming")
-	
-	var wg sync.WaitGroup
-	wg.Add(2)
-	
-	// Message receiving goroutine
-	go func() {
-		defer wg.Done()
-		for {
-			msg, err := stream.Recv()
-			if err != nil {
-				return
-			}
-			
-			log.Printf("Message received: %s -> %s: %s", msg.From, msg.To, msg.Message)
-			
-			// Send echo response
-			response := &pb.ChatMessage{
-				From:      "Server",
-				To:        msg.From,
-				Message:   fmt.Sprintf("Echo: %s", msg.Message),
-				Timestamp: timestamppb.New(time.Now()),
-				Type:      pb.MessageType_MESSAGE_TYPE_TEXT,
-			}
-			
-			if err := stream.Send(response); err != nil {
-				return
-			}
+// BulkUpdateUsers implements client streaming RPC for bulk partial updates. Unlike
+// CreateUsers, each update is applied on its own, so one stale or invalid record doesn't
+// hold back the others; nothing is applied until the client has sent every update.
+func (s *UserService) BulkUpdateUsers(stream pb.UserService_BulkUpdateUsersServer) error {
+	var requests []*pb.UpdateUserRequest
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
 		}
-	}()
+		if err != nil {
+			return err
+		}
+		requests = append(requests, req)
+	}
 	
-	// Heartbeat sending goroutine
-	go func() {
-		defer wg.Done()
-		ticker := time.NewTicker(30 * time.Second)
-		defer ticker.Stop()
-		
-		for {
-			select {
-			case <-ticker.C:
-				heartbeat := &pb.ChatMessage{
-					From:      "Server",
-					Message:   "Heartbeat",
-					Timestamp: timestamppb.New(time.Now()),
-					Type:      pb.MessageType_MESSAGE_TYPE_TEXT,
-				}
-				if err := stream.Send(heartbeat); err != nil {
-					return
-				}
-			case <-stream.Context().Done():
-				return
-			}
+	ctx := stream.Context()
+	res := &pb.BulkUpdateResponse{}
+	for i, req := range requests {
+		if err := s.checkContext(ctx); err != nil {
+			return err
+		}
+		err := validation.Validate(req)
+		if err == nil {
+			_, err = s.update(ctx, "BulkUpdateUsers", req, nil)
+		}
+		if err != nil {
+			st := status.Convert(err)
+			res.Errors = append(res.Errors, &pb.BulkUpdateError{
+				Index:   int32(i),
+				Id:      req.Id,
+				Code:    int32(st.Code()),
+				Message: st.Message(),
+			})
+			continue
 		}
-	}()
+		res.UserIds = append(res.UserIds, req.Id)
+	}
+	res.UpdatedCount = int32(len(res.UserIds))
 	
-	wg.Wait()
-	return nil
+	return stream.SendAndClose(res)
 }
 
 // checkContext validates the request context for timeout/cancellation
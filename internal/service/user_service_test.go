@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"example.com/user/internal/repository"
+	pb "example.com/user/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// newTestService returns a UserService over in-memory repositories holding the sample users
+func newTestService() *UserService {
+	return NewUserService(repository.NewInMemoryUserRepository(repository.SampleUsers()),
+		repository.NewInMemoryAuditRepository(), repository.NewHooks(), repository.NewInMemoryPreferencesRepository(),
+		nil, 0, repository.NewInMemoryChatRepository())
+}
+
+// violatedFields returns the fields of the BadRequest detail of an InvalidArgument err
+func violatedFields(t *testing.T, err error) []string {
+	t.Helper()
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("error = %v, want InvalidArgument", err)
+	}
+	var fields []string
+	for _, detail := range st.Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range br.FieldViolations {
+				fields = append(fields, v.Field)
+			}
+		}
+	}
+	return fields
+}
+
+func TestWritesReportFieldViolations(t *testing.T) {
+	everything := &fieldmaskpb.FieldMask{Paths: []string{"*"}}
+
+	tests := []struct {
+		name string
+		call func(s *UserService) error
+		want []string
+	}{
+		{"create without name and email", func(s *UserService) error {
+			_, err := s.CreateUser(context.Background(), &pb.CreateUserRequest{})
+			return err
+		}, []string{"name", "email"}},
+		{"create with bad email and role", func(s *UserService) error {
+			_, err := s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Ada", Email: "ada", Role: "Not A Role"})
+			return err
+		}, []string{"email", "role"}},
+		{"update with bad email", func(s *UserService) error {
+			_, err := s.UpdateUser(context.Background(), &pb.UpdateUserRequest{Id: 2, Email: "jane at example.com"})
+			return err
+		}, []string{"email"}},
+		{"update clearing masked fields", func(s *UserService) error {
+			_, err := s.UpdateUser(context.Background(), &pb.UpdateUserRequest{Id: 2, UpdateMask: everything})
+			return err
+		}, []string{"name", "email"}},
+		{"update with bad role and mask", func(s *UserService) error {
+			_, err := s.UpdateUser(context.Background(), &pb.UpdateUserRequest{
+				Id: 2, Role: "Root!", UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"role", "password"}},
+			})
+			return err
+		}, []string{"role", "update_mask.paths[1]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService()
+			got := violatedFields(t, tt.call(s))
+			if len(got) != len(tt.want) {
+				t.Fatalf("violated fields = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("violated fields = %v, want %v", got, tt.want)
+				}
+			}
+
+			// Nothing was written
+			user, err := s.repo.GetByID(2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if user.Version != 1 {
+				t.Errorf("user 2 is at version %d after a rejected write", user.Version)
+			}
+		})
+	}
+}
+
+func TestListUsers(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.ListUsersRequest
+		want []int32
+	}{
+		{"all", &pb.ListUsersRequest{}, []int32{1, 2, 3}},
+		{"by role", &pb.ListUsersRequest{Roles: []string{"user"}}, []int32{2, 3}},
+		{"by keyword", &pb.ListUsersRequest{Keyword: "SMITH"}, []int32{2}},
+		{"by email", &pb.ListUsersRequest{EmailContains: "bob@"}, []int32{3}},
+		{"ordered", &pb.ListUsersRequest{OrderBy: "name desc"}, []int32{1, 2, 3}},
+		{"ordered by email", &pb.ListUsersRequest{OrderBy: "email"}, []int32{3, 2, 1}},
+		{"in pages", &pb.ListUsersRequest{PageSize: 1, OrderBy: "id desc"}, []int32{3, 2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService()
+			var got []int32
+			for req := tt.req; ; {
+				resp, err := s.ListUsers(context.Background(), req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if int(resp.TotalSize) != len(tt.want) {
+					t.Errorf("total_size = %d, want %d", resp.TotalSize, len(tt.want))
+				}
+				for _, user := range resp.Users {
+					got = append(got, user.Id)
+				}
+				if resp.NextPageToken == "" || len(got) > len(tt.want) {
+					break
+				}
+				req.PageToken = resp.NextPageToken
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("listed users = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListUsersRejectsBadParameters(t *testing.T) {
+	s := newTestService()
+	first, err := s.ListUsers(context.Background(), &pb.ListUsersRequest{PageSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		req  *pb.ListUsersRequest
+		want string
+	}{
+		{"unknown order", &pb.ListUsersRequest{OrderBy: "role"}, "order_by"},
+		{"garbled token", &pb.ListUsersRequest{PageToken: "garbled"}, "page_token"},
+		{"token of another order", &pb.ListUsersRequest{OrderBy: "name", PageToken: first.NextPageToken}, "page_token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.ListUsers(context.Background(), tt.req)
+			if got := violatedFields(t, err); !slices.Equal(got, []string{tt.want}) {
+				t.Errorf("violated fields = %v, want [%s]", got, tt.want)
+			}
+		})
+	}
+}
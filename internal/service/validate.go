@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"example.com/user/internal/errorinfo"
+	"example.com/user/internal/validation"
+	"example.com/user/pkg/usererrors"
+	pb "example.com/user/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// emailTaken describes the email of a user being another user's
+const emailTaken = "is already in use"
+
+// emailInUse is the ALREADY_EXISTS error of a user given the email of another, with the
+// email field violation ValidateUser reports for it
+func emailInUse(email string) error {
+	st := status.Newf(codes.AlreadyExists, "Email %s already in use", email)
+	if detailed, err := st.WithDetails(
+		errorinfo.New(usererrors.ReasonEmailTaken),
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "email", Description: emailTaken}}},
+	); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// ValidateUser implements unary RPC checking a new user as CreateUser would, without
+// creating it, so forms can report every problem before submitting
+func (s *UserService) ValidateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.ValidateUserResponse, error) {
+	if err := s.checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	res := &pb.ValidateUserResponse{}
+	for _, v := range validation.Violations(req) {
+		res.Violations = append(res.Violations, &pb.FieldViolation{Field: v.Field, Description: v.Description})
+	}
+	// Only a well-formed email is worth looking up
+	if !slices.ContainsFunc(res.Violations, func(v *pb.FieldViolation) bool { return v.Field == "email" }) &&
+		s.repo.EmailExists(req.Email) {
+		res.Violations = append(res.Violations, &pb.FieldViolation{Field: "email", Description: emailTaken})
+	}
+	res.Valid = len(res.Violations) == 0
+	return res, nil
+}
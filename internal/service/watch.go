@@ -0,0 +1,77 @@
+package service
+
+import (
+	"slices"
+	"sync"
+
+	"example.com/user/internal/repository"
+	pb "example.com/user/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// watchBuffer is how many events a watcher may fall behind by before its stream is ended
+const watchBuffer = 256
+
+// eventTypes maps repository events onto their protobuf form
+var eventTypes = map[repository.EventType]pb.UserEventType{
+	repository.EventCreated:   pb.UserEventType_USER_EVENT_TYPE_CREATED,
+	repository.EventUpdated:   pb.UserEventType_USER_EVENT_TYPE_UPDATED,
+	repository.EventDeleted:   pb.UserEventType_USER_EVENT_TYPE_DELETED,
+	repository.EventUndeleted: pb.UserEventType_USER_EVENT_TYPE_UNDELETED,
+}
+
+// WatchUsers implements server streaming RPC pushing changes to users as they are
+// committed, until the caller goes away
+func (s *UserService) WatchUsers(req *pb.WatchUsersRequest, stream pb.UserService_WatchUsersServer) error {
+	// Hooks run inside the writer, so they only queue events; a watcher too slow to keep
+	// up is cut off rather than allowed to hold writes back or miss changes silently
+	events := make(chan repository.Event, watchBuffer)
+	overflowed := make(chan struct{})
+	var overflow sync.Once
+	unsubscribe := s.hooks.Subscribe(func(e repository.Event) {
+		if !watches(req, e) {
+			return
+		}
+		select {
+		case events <- e:
+		default:
+			overflow.Do(func() { close(overflowed) })
+		}
+	})
+	defer unsubscribe()
+
+	// Send the headers now, so the caller knows changes from here on will reach it
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return s.checkContext(ctx)
+		case <-overflowed:
+			return status.Errorf(codes.Aborted,
+				"Watch fell more than %d changes behind; reread the users and watch again", watchBuffer)
+		case e := <-events:
+			if err := stream.Send(&pb.UserEvent{
+				Type:      eventTypes[e.Type],
+				User:      e.User.ToProto(),
+				Timestamp: timestamppb.New(e.At),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// watches reports whether e is among the changes req asks for
+func watches(req *pb.WatchUsersRequest, e repository.Event) bool {
+	if len(req.UserIds) > 0 && !slices.Contains(req.UserIds, e.User.ID) {
+		return false
+	}
+	return len(req.Types) == 0 || slices.Contains(req.Types, eventTypes[e.Type])
+}
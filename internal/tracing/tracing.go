@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"example.com/user/internal/config"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"google.golang.org/grpc/stats"
+)
+
+// Exporters accepted by TRACING_EXPORTER
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+)
+
+// Setup installs the global tracer provider and W3C trace context propagator described by
+// cfg, naming spans after service unless TRACING_SERVICE_NAME overrides it. The returned
+// function flushes pending spans and must be called on exit; it is nil when tracing is off.
+func Setup(ctx context.Context, cfg config.TracingConfig, service string) (func(context.Context) error, error) {
+	switch cfg.Exporter {
+	case ExporterNone, "":
+		return nil, nil
+	case ExporterOTLP:
+	default:
+		return nil, fmt.Errorf("unknown TRACING_EXPORTER %q (expected %s or %s)", cfg.Exporter, ExporterNone, ExporterOTLP)
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1, got %g", cfg.SampleRatio)
+	}
+	if cfg.ServiceName != "" {
+		service = cfg.ServiceName
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(service),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("build tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// Follow the caller's sampling decision so a trace is never recorded in part
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+// ServerHandler starts a span for every RPC the server handles, continuing the trace
+// context the caller sent in its metadata
+func ServerHandler() stats.Handler {
+	return otelgrpc.NewServerHandler()
+}
+
+// ClientHandler starts a span for every RPC the client makes and sends its trace
+// context in the call's metadata
+func ClientHandler() stats.Handler {
+	return otelgrpc.NewClientHandler()
+}
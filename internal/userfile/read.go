@@ -0,0 +1,163 @@
+package userfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"example.com/user/internal/models"
+	pb "example.com/user/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// requiredColumns must be in the header row of a CSV file; the others are optional
+var requiredColumns = []string{"name", "email"}
+
+// Record is a user read from a file, or the reason the user on Line couldn't be read
+type Record struct {
+	Line int
+	User *pb.UserResponse
+	Err  error
+}
+
+// Read parses a whole file of format, FILE_FORMAT_UNKNOWN meaning CSV. Users that can't
+// be read are returned with Err set, the others still read; an error is returned only
+// when the file as a whole can't be, e.g. for a CSV file without the columns needed.
+func Read(data []byte, format pb.FileFormat) ([]Record, error) {
+	switch format {
+	case pb.FileFormat_FILE_FORMAT_UNKNOWN, pb.FileFormat_FILE_FORMAT_CSV:
+		return readCSV(data)
+	case pb.FileFormat_FILE_FORMAT_NDJSON:
+		return readNDJSON(data), nil
+	case pb.FileFormat_FILE_FORMAT_JSON:
+		return readJSON(data)
+	}
+	return nil, fmt.Errorf("unknown file format %v", format)
+}
+
+func readCSV(data []byte) ([]Record, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, errors.New("missing header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("header row has no %s column", name)
+		}
+	}
+
+	var records []Record
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		line, _ := r.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				line, err = parseErr.StartLine, parseErr.Err
+			}
+			records = append(records, Record{Line: line, Err: err})
+			continue
+		}
+		user, err := csvUser(row, columns)
+		records = append(records, Record{Line: line, User: user, Err: err})
+	}
+}
+
+// csvUser reads the user of a CSV row; columns holds the index of each named column
+func csvUser(row []string, columns map[string]int) (*pb.UserResponse, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok {
+			return row[i]
+		}
+		return ""
+	}
+
+	user := &pb.UserResponse{Name: field("name"), Email: field("email"), Role: field("role")}
+	switch status := field("status"); status {
+	case "", models.StatusActive:
+		user.Status = pb.UserStatus_USER_STATUS_ACTIVE
+	case models.StatusSuspended:
+		user.Status = pb.UserStatus_USER_STATUS_SUSPENDED
+	default:
+		return nil, fmt.Errorf("status must be %q or %q, not %q", models.StatusActive, models.StatusSuspended, status)
+	}
+	if deletedAt := field("deleted_at"); deletedAt != "" {
+		t, err := time.Parse(time.RFC3339Nano, deletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("deleted_at must be an RFC 3339 time, not %q", deletedAt)
+		}
+		user.DeletedAt = timestamppb.New(t)
+	}
+	return user, nil
+}
+
+func readNDJSON(data []byte) []Record {
+	var records []Record
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		user, err := jsonUser(text)
+		records = append(records, Record{Line: line, User: user, Err: err})
+	}
+	return records
+}
+
+func readJSON(data []byte) ([]Record, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, errors.New("expected a JSON array of users")
+	}
+
+	var records []Record
+	for dec.More() {
+		// Skip the whitespace before the element, so it is counted on the line it starts on
+		start := dec.InputOffset()
+		for start < int64(len(data)) && slices.Contains([]byte(" \t\r\n,"), data[start]) {
+			start++
+		}
+		line := bytes.Count(data[:start], []byte("\n")) + 1
+
+		var element json.RawMessage
+		if err := dec.Decode(&element); err != nil {
+			// The rest of the array can't be found again once it is malformed
+			return append(records, Record{Line: line, Err: err}), nil
+		}
+		user, err := jsonUser(element)
+		records = append(records, Record{Line: line, User: user, Err: err})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, errors.New("unterminated JSON array")
+	}
+	return records, nil
+}
+
+// jsonUser reads a user in its JSON form; fields it doesn't know are ignored
+func jsonUser(data []byte) (*pb.UserResponse, error) {
+	user := &pb.UserResponse{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
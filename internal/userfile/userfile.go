@@ -0,0 +1,129 @@
+// Package userfile writes users to the files ExportUsers produces: CSV, NDJSON or a JSON
+// array, as described by pb.FileFormat.
+package userfile
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"example.com/user/internal/models"
+	pb "example.com/user/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Columns are the header row of a CSV file
+var Columns = []string{"id", "name", "email", "role", "status", "created_at", "updated_at", "version", "deleted_at"}
+
+// Writer writes users to a file one at a time
+type Writer interface {
+	Write(user *pb.UserResponse) error
+	// Close ends the file, writing what is still buffered; it doesn't close the underlying writer
+	Close() error
+}
+
+// NewWriter starts a file of format on w; FILE_FORMAT_UNKNOWN means CSV
+func NewWriter(w io.Writer, format pb.FileFormat) (Writer, error) {
+	switch format {
+	case pb.FileFormat_FILE_FORMAT_UNKNOWN, pb.FileFormat_FILE_FORMAT_CSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(Columns); err != nil {
+			return nil, err
+		}
+		return &csvWriter{w: cw}, nil
+	case pb.FileFormat_FILE_FORMAT_NDJSON:
+		return &ndjsonWriter{w: w}, nil
+	case pb.FileFormat_FILE_FORMAT_JSON:
+		return &jsonWriter{w: w}, nil
+	}
+	return nil, fmt.Errorf("unknown file format %v", format)
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Write(user *pb.UserResponse) error {
+	return c.w.Write([]string{
+		strconv.Itoa(int(user.Id)),
+		user.Name,
+		user.Email,
+		user.Role,
+		statusName(user.Status),
+		formatTime(user.CreatedAt),
+		formatTime(user.UpdatedAt),
+		strconv.FormatInt(user.Version, 10),
+		formatTime(user.DeletedAt),
+	})
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// ndjsonWriter writes users in their JSON form, one per line
+type ndjsonWriter struct {
+	w io.Writer
+}
+
+func (n *ndjsonWriter) Write(user *pb.UserResponse) error {
+	line, err := protojson.Marshal(user)
+	if err != nil {
+		return err
+	}
+	_, err = n.w.Write(append(line, '\n'))
+	return err
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
+
+// jsonWriter writes users in their JSON form as the elements of an array
+type jsonWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonWriter) Write(user *pb.UserResponse) error {
+	element, err := protojson.Marshal(user)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if j.count == 0 {
+		sep = "[\n"
+	}
+	j.count++
+	_, err = io.WriteString(j.w, sep+string(element))
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	end := "\n]\n"
+	if j.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
+
+// statusName is the CSV form of a status, as models names it
+func statusName(status pb.UserStatus) string {
+	if status == pb.UserStatus_USER_STATUS_SUSPENDED {
+		return models.StatusSuspended
+	}
+	return models.StatusActive
+}
+
+// formatTime is the CSV form of a timestamp, empty when unset
+func formatTime(t *timestamppb.Timestamp) string {
+	if t == nil {
+		return ""
+	}
+	return t.AsTime().Format(time.RFC3339Nano)
+}
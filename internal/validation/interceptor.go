@@ -0,0 +1,46 @@
+package validation
+
+import (
+	"context"
+
+	pb "example.com/user/proto"
+	"google.golang.org/grpc"
+)
+
+// UnaryServerInterceptor rejects requests that fail Validate before they reach the
+// handler, except those of ValidateUser, which reports violations in its response
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if info.FullMethod == pb.UserService_ValidateUser_FullMethodName {
+			return handler(ctx, req)
+		}
+		if err := Validate(req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects the request of a server-streaming call that fails
+// Validate. Messages of client streams are left to their handlers, which can report
+// invalid ones individually.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if info.IsClientStream {
+			return handler(srv, ss)
+		}
+		return handler(srv, &validatingStream{ss})
+	}
+}
+
+// validatingStream validates the messages received on a server stream
+type validatingStream struct {
+	grpc.ServerStream
+}
+
+func (s *validatingStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return Validate(m)
+}
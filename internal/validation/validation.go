@@ -0,0 +1,340 @@
+// Package validation checks requests against the field constraints documented in
+// proto/user.proto before they reach a handler.
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	// Time zones are checked against the embedded database, as servers may have none
+	_ "time/tzdata"
+	"unicode/utf8"
+
+	"example.com/user/internal/models"
+	"example.com/user/internal/search"
+	pb "example.com/user/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// maxNameLength bounds user names, in characters
+	maxNameLength = 100
+	// maxKeywordLength bounds search keywords, in characters
+	maxKeywordLength = 100
+	// maxEmailLength is the longest address SMTP allows
+	maxEmailLength = 254
+	// maxBatchGetIDs bounds the users read by one BatchGetUsers call
+	maxBatchGetIDs = 100
+	// maxQueryLength bounds SearchUsers queries, in characters
+	maxQueryLength = 500
+	// maxLocaleLength bounds locales, which are ASCII
+	maxLocaleLength = 35
+	// maxChatMessageLength bounds chat messages, in characters
+	maxChatMessageLength = 4096
+)
+
+// localePattern matches BCP 47 language tags: a language and optional subtags such as a
+// script, region or variant
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{1,8})*$`)
+
+// roomPattern matches chat room names
+var roomPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+// roleRule describes the role names accepted
+const roleRule = `"user", "admin" or a custom role of up to 32 lowercase letters, digits, "-" and "_", starting with a letter`
+
+// Validate checks m against the constraints of its message type. It returns nil when m
+// is valid or its type has no constraints, and otherwise an InvalidArgument error with a
+// google.rpc.BadRequest detail listing every offending field.
+func Validate(m any) error {
+	return violations(Violations(m)).err()
+}
+
+// Violations lists the constraints of its message type that m breaks, in the order
+// Validate reports them; nil when there are none
+func Violations(m any) []*errdetails.BadRequest_FieldViolation {
+	var v violations
+	switch r := m.(type) {
+	case *pb.UserRequest:
+		v.positive("id", int64(r.Id))
+	case *pb.UploadAvatarRequest:
+		v.positive("user_id", int64(r.UserId))
+	case *pb.BatchGetUsersRequest:
+		if len(r.Ids) == 0 || len(r.Ids) > maxBatchGetIDs {
+			v.add("ids", fmt.Sprintf("must hold 1 to %d IDs", maxBatchGetIDs))
+		}
+		for i, id := range r.Ids {
+			v.positive(fmt.Sprintf("ids[%d]", i), int64(id))
+		}
+	case *pb.CreateUserRequest:
+		v.name("name", r.Name, true)
+		v.email("email", r.Email, true)
+		v.role(r.RoleType, r.Role)
+	case *pb.UpdateUserRequest:
+		v.positive("id", int64(r.Id))
+		// Fields named in update_mask are set even when empty, so name and email are then required
+		v.name("name", r.Name, models.MaskNames(r, "name"))
+		v.email("email", r.Email, models.MaskNames(r, "email"))
+		v.role(r.RoleType, r.Role)
+		v.notNegative("version", r.Version)
+		for i, path := range r.GetUpdateMask().GetPaths() {
+			if path != "*" && !slices.Contains(models.UpdatableFields, path) {
+				v.add(fmt.Sprintf("update_mask.paths[%d]", i),
+					fmt.Sprintf(`must be one of %s or "*"`, strings.Join(models.UpdatableFields, ", ")))
+			}
+		}
+	case *pb.UpdatePreferencesRequest:
+		v.positive("user_id", int64(r.UserId))
+		v.locale("locale", r.Locale)
+		v.timezone("timezone", r.Timezone)
+		for i, path := range r.GetUpdateMask().GetPaths() {
+			if path != "*" && !slices.Contains(models.PreferenceFields, path) {
+				v.add(fmt.Sprintf("update_mask.paths[%d]", i),
+					fmt.Sprintf(`must be one of %s or "*"`, strings.Join(models.PreferenceFields, ", ")))
+			}
+		}
+	case *pb.UserFilter:
+		v.keyword("keyword", r.Keyword)
+		v.keyword("name_contains", r.NameContains)
+		v.keyword("email_contains", r.EmailContains)
+		v.notNegative("limit", int64(r.Limit))
+		v.notNegative("offset", int64(r.Offset))
+		v.roles("roles", r.Roles)
+		v.notNegative("page_size", int64(r.PageSize))
+		v.notNegative("resume_after_id", int64(r.ResumeAfterId))
+		if r.ResumeAfterId > 0 && r.Offset > 0 {
+			v.add("offset", "must be 0 when resume_after_id is set")
+		}
+		v.timeRange("created_after", r.CreatedAfter, "created_before", r.CreatedBefore)
+	case *pb.ListUsersRequest:
+		v.notNegative("page_size", int64(r.PageSize))
+		v.keyword("keyword", r.Keyword)
+		v.keyword("email_contains", r.EmailContains)
+		v.roles("roles", r.Roles)
+	case *pb.ExportUsersRequest:
+		if _, known := pb.FileFormat_name[int32(r.Format)]; !known {
+			v.add("format", "must be a known file format")
+		}
+		v.keyword("keyword", r.Keyword)
+		v.keyword("email_contains", r.EmailContains)
+		v.roles("roles", r.Roles)
+	case *pb.SearchUsersRequest:
+		v.query("query", r.Query)
+		v.notNegative("page_size", int64(r.PageSize))
+	case *pb.WatchUsersRequest:
+		for i, id := range r.UserIds {
+			v.positive(fmt.Sprintf("user_ids[%d]", i), int64(id))
+		}
+		for i, t := range r.Types {
+			if _, known := pb.UserEventType_name[int32(t)]; !known || t == pb.UserEventType_USER_EVENT_TYPE_UNKNOWN {
+				v.add(fmt.Sprintf("types[%d]", i), "must be a known event type other than UNKNOWN")
+			}
+		}
+	case *pb.AuditLogRequest:
+		v.notNegative("user_id", int64(r.UserId))
+		v.notNegative("page_size", int64(r.PageSize))
+	case *pb.UserHistoryRequest:
+		v.positive("user_id", int64(r.UserId))
+		v.notNegative("page_size", int64(r.PageSize))
+	case *pb.ChatMessage:
+		if _, known := pb.MessageType_name[int32(r.Type)]; !known {
+			v.add("type", "must be a known message type")
+		}
+		v.room("room", r.Room)
+		if r.Room == "" && r.Type == pb.MessageType_MESSAGE_TYPE_LEAVE {
+			v.add("room", "is required to leave")
+		}
+		// Chat requires from unless it takes the name of the authenticated caller
+		v.name("from", r.From, false)
+		v.name("to", r.To, false)
+		if r.To != "" && r.Room != "" {
+			v.add("to", "must be empty when room is set")
+		}
+		if utf8.RuneCountInString(r.Message) > maxChatMessageLength {
+			v.add("message", fmt.Sprintf("must be at most %d characters", maxChatMessageLength))
+		}
+	case *pb.ChatHistoryRequest:
+		v.room("room", r.Room)
+		v.name("peer", r.Peer, false)
+		v.name("user", r.User, false)
+		switch {
+		case r.Room != "" && r.Peer != "":
+			v.add("peer", "must be empty when room is set")
+		case r.Room == "" && r.Peer == "" && r.User != "":
+			v.add("user", "must be empty unless room or peer is set")
+		}
+		v.notNegative("before", r.Before)
+		v.notNegative("limit", int64(r.Limit))
+	}
+	return v
+}
+
+// FieldError returns the error Validate would for field breaking a constraint only a
+// handler can check, such as a page token being one it issued
+func FieldError(field, description string) error {
+	return violations{{Field: field, Description: description}}.err()
+}
+
+// violations collects the constraints a message breaks
+type violations []*errdetails.BadRequest_FieldViolation
+
+func (v *violations) add(field, description string) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{Field: field, Description: description})
+}
+
+func (v *violations) positive(field string, n int64) {
+	if n <= 0 {
+		v.add(field, "must be positive")
+	}
+}
+
+func (v *violations) notNegative(field string, n int64) {
+	if n < 0 {
+		v.add(field, "must not be negative")
+	}
+}
+
+func (v *violations) name(field, name string, required bool) {
+	switch {
+	case name == "":
+		if required {
+			v.add(field, "is required")
+		}
+	case strings.TrimSpace(name) == "":
+		v.add(field, "must not be blank")
+	case utf8.RuneCountInString(name) > maxNameLength:
+		v.add(field, fmt.Sprintf("must be at most %d characters", maxNameLength))
+	}
+}
+
+func (v *violations) email(field, email string, required bool) {
+	if email == "" {
+		if required {
+			v.add(field, "is required")
+		}
+		return
+	}
+	// ParseAddress also accepts forms such as "Ada <ada@example.com>", which aren't wanted
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || addr.Name != "" || len(email) > maxEmailLength {
+		v.add(field, "must be an email address such as ada@example.com")
+	}
+}
+
+// locale checks an optional BCP 47 language tag
+func (v *violations) locale(field, locale string) {
+	if locale != "" && (len(locale) > maxLocaleLength || !localePattern.MatchString(locale)) {
+		v.add(field, "must be a BCP 47 language tag such as en-US")
+	}
+}
+
+// timezone checks an optional IANA time zone name
+func (v *violations) timezone(field, name string) {
+	if name == "" {
+		return
+	}
+	// LoadLocation also takes "Local", the zone of the server, which means nothing to users
+	if _, err := time.LoadLocation(name); err != nil || name == "Local" {
+		v.add(field, "must be an IANA time zone such as Europe/Paris")
+	}
+}
+
+// role checks the role of a create or update request, given by name, as a Role or both
+func (v *violations) role(t pb.Role, name string) {
+	if _, known := pb.Role_name[int32(t)]; !known {
+		v.add("role_type", "must be a known role")
+		return
+	}
+	switch {
+	case t == pb.Role_ROLE_CUSTOM && (name == "" || models.RoleType(name) != pb.Role_ROLE_CUSTOM):
+		v.add("role", "must name the custom role when role_type is ROLE_CUSTOM")
+	case name == "":
+	case !models.ValidRoleName(name):
+		v.add("role", "must be "+roleRule)
+	case t != pb.Role_ROLE_UNKNOWN && models.RoleType(name) != t:
+		v.add("role", fmt.Sprintf("must be empty or %q to match role_type", models.RoleName(t, "")))
+	}
+}
+
+func (v *violations) roles(field string, values []string) {
+	for i, role := range values {
+		if !models.ValidRoleName(role) {
+			v.add(fmt.Sprintf("%s[%d]", field, i), "must be "+roleRule)
+		}
+	}
+}
+
+func (v *violations) room(field, room string) {
+	if room != "" && !roomPattern.MatchString(room) {
+		v.add(field, `must be up to 64 letters, digits, "-", "_" and ".", starting with a letter or digit`)
+	}
+}
+
+func (v *violations) keyword(field, keyword string) {
+	if utf8.RuneCountInString(keyword) > maxKeywordLength {
+		v.add(field, fmt.Sprintf("must be at most %d characters", maxKeywordLength))
+	}
+}
+
+// query checks that a SearchUsers query parses into a valid filter
+func (v *violations) query(field, query string) {
+	if utf8.RuneCountInString(query) > maxQueryLength {
+		v.add(field, fmt.Sprintf("must be at most %d characters", maxQueryLength))
+		return
+	}
+	filter, err := search.Parse(query)
+	if err != nil {
+		v.add(field, err.Error())
+		return
+	}
+	if utf8.RuneCountInString(filter.Keyword) > maxKeywordLength {
+		v.add(field, fmt.Sprintf("word must be at most %d characters", maxKeywordLength))
+	}
+	if utf8.RuneCountInString(filter.NameContains) > maxKeywordLength {
+		v.add(field, fmt.Sprintf("name term must be at most %d characters", maxKeywordLength))
+	}
+	if utf8.RuneCountInString(filter.EmailContains) > maxKeywordLength {
+		v.add(field, fmt.Sprintf("email term must be at most %d characters", maxKeywordLength))
+	}
+	for _, role := range filter.Roles {
+		if !models.ValidRoleName(role) {
+			v.add(field, fmt.Sprintf("role:%s: role must be %s", role, roleRule))
+		}
+	}
+}
+
+// timeRange checks two optional timestamps bounding a range
+func (v *violations) timeRange(startField string, start *timestamppb.Timestamp, endField string, end *timestamppb.Timestamp) {
+	if start != nil && start.CheckValid() != nil {
+		v.add(startField, "must be a valid timestamp")
+	}
+	if end != nil && end.CheckValid() != nil {
+		v.add(endField, "must be a valid timestamp")
+	}
+	if start != nil && end != nil && !end.AsTime().After(start.AsTime()) {
+		v.add(endField, "must be after "+startField)
+	}
+}
+
+// err returns the InvalidArgument error reporting v, or nil when v is empty
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	problems := make([]string, len(v))
+	for i, fv := range v {
+		problems[i] = fv.Field + " " + fv.Description
+	}
+	st := status.New(codes.InvalidArgument, "Invalid request: "+strings.Join(problems, "; "))
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: v}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
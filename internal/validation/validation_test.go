@@ -0,0 +1,135 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	pb "example.com/user/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestViolations(t *testing.T) {
+	long := strings.Repeat("x", maxNameLength+1)
+	mask := func(paths ...string) *fieldmaskpb.FieldMask { return &fieldmaskpb.FieldMask{Paths: paths} }
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		msg  any
+		want []string
+	}{
+		{"valid user request", &pb.UserRequest{Id: 1}, nil},
+		{"zero id", &pb.UserRequest{}, []string{"id"}},
+		{"no batch ids", &pb.BatchGetUsersRequest{}, []string{"ids"}},
+		{"bad batch id", &pb.BatchGetUsersRequest{Ids: []int32{1, -2}}, []string{"ids[1]"}},
+
+		{"valid create", &pb.CreateUserRequest{Name: "Ada", Email: "ada@example.com"}, nil},
+		{"create with custom role", &pb.CreateUserRequest{Name: "Ada", Email: "ada@example.com", Role: "auditor"}, nil},
+		{"create with matching role and type", &pb.CreateUserRequest{Name: "Ada", Email: "ada@example.com",
+			Role: "admin", RoleType: pb.Role_ROLE_ADMIN}, nil},
+		{"create without name or email", &pb.CreateUserRequest{}, []string{"name", "email"}},
+		{"blank name", &pb.CreateUserRequest{Name: "  ", Email: "ada@example.com"}, []string{"name"}},
+		{"long name", &pb.CreateUserRequest{Name: long, Email: "ada@example.com"}, []string{"name"}},
+		{"display name email", &pb.CreateUserRequest{Name: "Ada", Email: "Ada <ada@example.com>"}, []string{"email"}},
+		{"bad role", &pb.CreateUserRequest{Name: "Ada", Email: "ada@example.com", Role: "Admin!"}, []string{"role"}},
+		{"unknown role type", &pb.CreateUserRequest{Name: "Ada", Email: "ada@example.com", RoleType: 42}, []string{"role_type"}},
+		{"custom type without role", &pb.CreateUserRequest{Name: "Ada", Email: "ada@example.com",
+			RoleType: pb.Role_ROLE_CUSTOM}, []string{"role"}},
+		{"role contradicting type", &pb.CreateUserRequest{Name: "Ada", Email: "ada@example.com",
+			Role: "user", RoleType: pb.Role_ROLE_ADMIN}, []string{"role"}},
+
+		{"valid update", &pb.UpdateUserRequest{Id: 1, Name: "Ada"}, nil},
+		{"update leaving fields unset", &pb.UpdateUserRequest{Id: 1}, nil},
+		{"masked fields are required", &pb.UpdateUserRequest{Id: 1, UpdateMask: mask("name", "email")}, []string{"name", "email"}},
+		{"star mask requires both", &pb.UpdateUserRequest{Id: 1, UpdateMask: mask("*")}, []string{"name", "email"}},
+		{"unknown mask path", &pb.UpdateUserRequest{Id: 1, Name: "Ada", UpdateMask: mask("name", "id")},
+			[]string{"update_mask.paths[1]"}},
+		{"negative version", &pb.UpdateUserRequest{Id: 1, Version: -1}, []string{"version"}},
+
+		{"bad locale and zone", &pb.UpdatePreferencesRequest{UserId: 1, Locale: "english!", Timezone: "Mars/Base"},
+			[]string{"locale", "timezone"}},
+		{"local zone", &pb.UpdatePreferencesRequest{UserId: 1, Timezone: "Local"}, []string{"timezone"}},
+		{"valid preferences", &pb.UpdatePreferencesRequest{UserId: 1, Locale: "en-US", Timezone: "Europe/Paris"}, nil},
+
+		{"valid filter", &pb.UserFilter{Keyword: "ada", Roles: []string{"admin"}}, nil},
+		{"long filter terms", &pb.UserFilter{Keyword: long, NameContains: long, EmailContains: long},
+			[]string{"keyword", "name_contains", "email_contains"}},
+		{"bad filter role", &pb.UserFilter{Roles: []string{"user", "No"}}, []string{"roles[1]"}},
+		{"resume with offset", &pb.UserFilter{ResumeAfterId: 3, Offset: 1}, []string{"offset"}},
+		{"inverted range", &pb.UserFilter{CreatedAfter: timestamppb.New(now), CreatedBefore: timestamppb.New(now.Add(-time.Hour))},
+			[]string{"created_before"}},
+
+		{"valid search", &pb.SearchUsersRequest{Query: "role:admin name~ada"}, nil},
+		{"unparsable search", &pb.SearchUsersRequest{Query: "age>3"}, []string{"query"}},
+		{"bad search role", &pb.SearchUsersRequest{Query: "role:Nope"}, []string{"query"}},
+		{"long search word", &pb.SearchUsersRequest{Query: long}, []string{"query"}},
+		{"negative page size", &pb.ListUsersRequest{PageSize: -1}, []string{"page_size"}},
+
+		{"valid chat message", &pb.ChatMessage{Message: "hi", Room: "general"}, nil},
+		{"bad room", &pb.ChatMessage{Room: "-general"}, []string{"room"}},
+		{"leave without room", &pb.ChatMessage{Type: pb.MessageType_MESSAGE_TYPE_LEAVE}, []string{"room"}},
+		{"to and room", &pb.ChatMessage{To: "ada", Room: "general"}, []string{"to"}},
+		{"long message", &pb.ChatMessage{Message: strings.Repeat("x", maxChatMessageLength+1)}, []string{"message"}},
+		{"history of room and peer", &pb.ChatHistoryRequest{Room: "general", Peer: "ada"}, []string{"peer"}},
+		{"history user alone", &pb.ChatHistoryRequest{User: "ada"}, []string{"user"}},
+		{"history of peer", &pb.ChatHistoryRequest{User: "ada", Peer: "bob"}, nil},
+
+		{"message without constraints", &pb.AvatarChunk{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, v := range Violations(tt.msg) {
+				got = append(got, v.Field)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("violated fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(&pb.UserRequest{Id: 1}); err != nil {
+		t.Errorf("Validate(valid) = %v, want nil", err)
+	}
+
+	err := Validate(&pb.CreateUserRequest{Email: "nope"})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("Validate(invalid) code = %v, want InvalidArgument", st.Code())
+	}
+	var got []string
+	for _, detail := range st.Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range br.FieldViolations {
+				got = append(got, fmt.Sprintf("%s: %s", v.Field, v.Description))
+			}
+		}
+	}
+	want := []string{"name: is required", "email: must be an email address such as ada@example.com"}
+	if !slices.Equal(got, want) {
+		t.Errorf("BadRequest violations = %v, want %v", got, want)
+	}
+}
+
+func TestFieldError(t *testing.T) {
+	st := status.Convert(FieldError("page_token", "must be a next page token"))
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("code = %v, want InvalidArgument", st.Code())
+	}
+	if len(st.Details()) != 1 {
+		t.Fatalf("details = %v, want one BadRequest", st.Details())
+	}
+	br, ok := st.Details()[0].(*errdetails.BadRequest)
+	if !ok || len(br.FieldViolations) != 1 || br.FieldViolations[0].Field != "page_token" {
+		t.Errorf("detail = %v, want a page_token violation", st.Details()[0])
+	}
+}
@@ -0,0 +1,74 @@
+package userclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"example.com/user/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// credentials are the authentication headers attached to every call
+type credentials struct {
+	token  string
+	apiKey string
+	// signingKeyID and signingSecret sign every call when set
+	signingKeyID  string
+	signingSecret []byte
+}
+
+// attach adds "authorization: Bearer <token>" and "x-api-key" headers when configured,
+// and signs the call to method with request req (nil for streams)
+func (c credentials) attach(ctx context.Context, method string, req any) (context.Context, error) {
+	if c.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.token)
+	}
+	if c.apiKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, auth.APIKeyHeader, c.apiKey)
+	}
+	if c.signingKeyID != "" {
+		random := make([]byte, 16)
+		if _, err := rand.Read(random); err != nil {
+			return nil, err
+		}
+		nonce := hex.EncodeToString(random)
+		timestamp := time.Now().Unix()
+		signature, err := auth.Sign(c.signingSecret, method, timestamp, nonce, req)
+		if err != nil {
+			return nil, err
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx,
+			auth.SignatureKeyHeader, c.signingKeyID,
+			auth.SignatureTimestampHeader, strconv.FormatInt(timestamp, 10),
+			auth.SignatureNonceHeader, nonce,
+			auth.SignatureHeader, signature,
+		)
+	}
+	return ctx, nil
+}
+
+// unaryInterceptor attaches the credentials to every unary call
+func (c credentials) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, err := c.attach(ctx, method, req)
+		if err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// streamInterceptor attaches the credentials to every streaming call
+func (c credentials) streamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, err := c.attach(ctx, method, nil)
+		if err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
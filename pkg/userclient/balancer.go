@@ -0,0 +1,52 @@
+package userclient
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+// listScheme is the resolver scheme of a comma-separated list of server addresses
+const listScheme = "user-servers"
+
+// lbPolicies are the load balancing policies the client can spread calls with
+var lbPolicies = []string{"round_robin", "pick_first"}
+
+// dialTarget returns the target to connect to for address and the options it needs. A
+// single address, which may be a DNS name resolving to every replica, is used as is; a
+// comma-separated list of host:port addresses is served by a resolver of its own.
+func dialTarget(address string) (string, []grpc.DialOption, error) {
+	if !strings.Contains(address, ",") {
+		return address, nil, nil
+	}
+
+	var addrs []resolver.Address
+	for _, entry := range strings.Split(address, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		host, _, err := net.SplitHostPort(entry)
+		if err != nil {
+			return "", nil, fmt.Errorf("server address %q: %w", entry, err)
+		}
+		// Each server's certificate is checked against its own host
+		addrs = append(addrs, resolver.Address{Addr: entry, ServerName: host})
+	}
+	r := manual.NewBuilderWithScheme(listScheme)
+	r.InitialState(resolver.State{Addresses: addrs})
+	return listScheme + ":///servers", []grpc.DialOption{grpc.WithResolvers(r)}, nil
+}
+
+// loadBalancing returns the service config option spreading calls with policy
+func loadBalancing(policy string) (grpc.DialOption, error) {
+	if !slices.Contains(lbPolicies, policy) {
+		return nil, fmt.Errorf("load balancing policy must be one of %s, got %q", strings.Join(lbPolicies, ", "), policy)
+	}
+	return grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"loadBalancingConfig": [{%q: {}}]}`, policy)), nil
+}
@@ -0,0 +1,160 @@
+package userclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// breakerFailureCodes are the codes saying the server is unwell rather than the call wrong;
+// other errors, such as NOT_FOUND, show the server is answering
+var breakerFailureCodes = []codes.Code{
+	codes.Unavailable,
+	codes.DeadlineExceeded,
+	codes.ResourceExhausted,
+	codes.Internal,
+	codes.Unknown,
+}
+
+// breakerState is where the circuit breaker stands
+type breakerState int
+
+const (
+	// breakerClosed lets calls through
+	breakerClosed breakerState = iota
+	// breakerOpen fails calls at once until the cooldown ends
+	breakerOpen
+	// breakerHalfOpen lets one probe call through, whose outcome closes or opens the breaker
+	breakerHalfOpen
+)
+
+// circuitBreaker fails calls fast after a run of consecutive failures, instead of waiting
+// on a server that is down. Once the cooldown ends, a single probe call decides whether
+// calls flow again.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	// openedAt is when the breaker opened, probedAt when the last probe started
+	openedAt time.Time
+	probedAt time.Time
+}
+
+// newCircuitBreaker creates a breaker opening after threshold consecutive failures and
+// probing after cooldown; nil when threshold is 0, which turns it off
+func newCircuitBreaker(threshold int, cooldown time.Duration) (*circuitBreaker, error) {
+	if threshold < 0 || cooldown <= 0 {
+		return nil, errors.New("failures must not be negative and cooldown must be positive")
+	}
+	if threshold == 0 {
+		return nil, nil
+	}
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}, nil
+}
+
+// allow reports whether a call may go ahead, or the UNAVAILABLE error failing it
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	switch b.state {
+	case breakerOpen:
+		if wait := b.openedAt.Add(b.cooldown).Sub(now); wait > 0 {
+			return status.Errorf(codes.Unavailable, "Circuit breaker open after %d consecutive failures, retry in %s",
+				b.failures, wait.Round(time.Millisecond))
+		}
+		b.state = breakerHalfOpen
+		b.probedAt = now
+		slog.Info("Circuit breaker half-open, probing the server")
+	case breakerHalfOpen:
+		// A probe whose outcome never came, such as an abandoned stream, is given up on
+		if now.Sub(b.probedAt) < b.cooldown {
+			return status.Error(codes.Unavailable, "Circuit breaker half-open, waiting for the probe call")
+		}
+		b.probedAt = now
+	}
+	return nil
+}
+
+// record counts the outcome of a call let through by allow
+func (b *circuitBreaker) record(err error) {
+	failed := err != nil && slices.Contains(breakerFailureCodes, status.Code(err))
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case !failed:
+		if b.state != breakerClosed {
+			slog.Info("Circuit breaker closed")
+		}
+		b.state, b.failures = breakerClosed, 0
+	case b.state == breakerHalfOpen:
+		b.state, b.openedAt = breakerOpen, time.Now()
+		slog.Warn("Circuit breaker reopened, probe failed", "cooldown", b.cooldown, "error", err)
+	case b.state == breakerClosed:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.state, b.openedAt = breakerOpen, time.Now()
+			slog.Warn("Circuit breaker opened", "failures", b.failures, "cooldown", b.cooldown, "error", err)
+		}
+	}
+}
+
+// unaryInterceptor fails unary calls fast while the breaker is open
+func (b *circuitBreaker) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := b.allow(); err != nil {
+			return err
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		b.record(err)
+		return err
+	}
+}
+
+// streamInterceptor fails new streams fast while the breaker is open. A stream counts as a
+// success once a response arrives or it ends cleanly.
+func (b *circuitBreaker) streamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if err := b.allow(); err != nil {
+			return nil, err
+		}
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			b.record(err)
+			return nil, err
+		}
+		return &breakerStream{ClientStream: stream, breaker: b}, nil
+	}
+}
+
+// breakerStream reports the outcome of a stream to the breaker once it is known
+type breakerStream struct {
+	grpc.ClientStream
+	breaker *circuitBreaker
+	once    sync.Once
+}
+
+func (s *breakerStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	s.once.Do(func() {
+		outcome := err
+		if errors.Is(outcome, io.EOF) {
+			outcome = nil
+		}
+		s.breaker.record(outcome)
+	})
+	return err
+}
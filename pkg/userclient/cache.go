@@ -0,0 +1,47 @@
+package userclient
+
+import (
+	"sync/atomic"
+	"time"
+
+	"example.com/user/internal/cache"
+	pb "example.com/user/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// userCache keeps users returned by GetUser and BatchGetUsers. Writes made through the same client
+// invalidate the user they touch; changes made elsewhere are only seen once entries
+// expire.
+type userCache struct {
+	users *cache.LRU[int32, *pb.UserResponse]
+	// writes counts invalidations, so a read that started before one doesn't cache
+	// what it read
+	writes atomic.Uint64
+}
+
+func newUserCache(size int, ttl time.Duration) *userCache {
+	return &userCache{users: cache.NewLRU[int32, *pb.UserResponse](size, ttl)}
+}
+
+// get returns a copy of the cached user with id, which the caller may change
+func (c *userCache) get(id int32) (*pb.UserResponse, bool) {
+	user, ok := c.users.Get(id)
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(user).(*pb.UserResponse), true
+}
+
+// set caches a copy of user, read after generation writes had been counted
+func (c *userCache) set(user *pb.UserResponse, generation uint64) {
+	if c.writes.Load() != generation {
+		return
+	}
+	c.users.Set(user.Id, proto.Clone(user).(*pb.UserResponse))
+}
+
+// invalidate drops the user with id
+func (c *userCache) invalidate(id int32) {
+	c.writes.Add(1)
+	c.users.Delete(id)
+}
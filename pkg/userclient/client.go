@@ -0,0 +1,609 @@
+// Package userclient is a Go client of the user service. Calls are retried, rate limited,
+// signed and guarded by a circuit breaker as set up by the options given to New, and fail
+// with errors matching ErrNotFound and its kin.
+package userclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"log/slog"
+	"slices"
+
+	pb "example.com/user/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Client wraps the gRPC client connection and operations
+type Client struct {
+	conn   *grpc.ClientConn
+	client pb.UserServiceClient
+	// cache holds users read by GetUser; nil unless WithCache is given
+	cache *userCache
+	// resumeCodes are the codes a dropped StreamUsers is resumed after
+	resumeCodes []codes.Code
+}
+
+// New creates a new gRPC client instance, connected to the server at address within the
+// connection timeout. address is a host:port, a DNS name resolving to every replica, or a
+// comma-separated list of host:port addresses; connections use TLS unless WithInsecure
+// is given.
+func New(address string, options ...Option) (*Client, error) {
+	set := defaultSettings()
+	for _, option := range options {
+		option(&set)
+	}
+
+	if err := set.retry.validate(); err != nil {
+		return nil, fmt.Errorf("invalid retry policy: %w", err)
+	}
+	transport, err := transportCredentials(set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client TLS configuration: %w", err)
+	}
+	target, targetOpts, err := dialTarget(address)
+	if err != nil {
+		return nil, err
+	}
+	balancing, err := loadBalancing(set.lbPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if set.hedging.delay < 0 {
+		return nil, errors.New("invalid hedging delay: must not be negative")
+	}
+	if set.metrics != nil {
+		set.retry.onRetry = set.metrics.Retried
+		set.hedging.onHedge = set.metrics.Hedged
+	}
+	breaker, err := newCircuitBreaker(set.breaker.failures, set.breaker.cooldown)
+	if err != nil {
+		return nil, fmt.Errorf("invalid circuit breaker: %w", err)
+	}
+	limiter, err := newRateLimiter(set.rateLimit.rps, set.rateLimit.burst)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate limit: %w", err)
+	}
+
+	// Metrics see each call as the caller does, the breaker once its hedged attempts and
+	// their retries are spent; every attempt then waits for the rate limiter, and is
+	// signed afresh once let through
+	var unary []grpc.UnaryClientInterceptor
+	var stream []grpc.StreamClientInterceptor
+	if set.metrics != nil {
+		unary = append(unary, set.metrics.UnaryClientInterceptor())
+		stream = append(stream, set.metrics.StreamClientInterceptor())
+	}
+	if breaker != nil {
+		unary = append(unary, breaker.unaryInterceptor())
+		stream = append(stream, breaker.streamInterceptor())
+	}
+	if set.hedging.delay > 0 {
+		unary = append(unary, set.hedging.unaryInterceptor())
+	}
+	unary = append(unary, set.retry.unaryInterceptor())
+	stream = append(stream, set.retry.streamInterceptor())
+	if limiter != nil {
+		unary = append(unary, limiter.unaryInterceptor())
+		stream = append(stream, limiter.streamInterceptor())
+	}
+	unary = append(unary, set.creds.unaryInterceptor())
+	stream = append(stream, set.creds.streamInterceptor())
+
+	opts := append([]grpc.DialOption{
+		transport,
+		balancing,
+		grpc.WithChainUnaryInterceptor(unary...),
+		grpc.WithChainStreamInterceptor(stream...),
+	}, targetOpts...)
+	// Pings keep long-lived streams such as Chat open through NATs and load balancers that
+	// drop idle connections; the server must allow pings this frequent
+	if set.keepalive != nil {
+		opts = append(opts, grpc.WithKeepaliveParams(*set.keepalive))
+	}
+	opts = append(opts, set.dialOpts...)
+
+	conn, err := grpc.NewClient(target, opts...)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), set.connectTimeout)
+		err = waitForReady(ctx, conn)
+		cancel()
+		if err != nil {
+			conn.Close()
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", address, err)
+	}
+
+	return &Client{
+		conn:        conn,
+		client:      pb.NewUserServiceClient(conn),
+		cache:       set.cache,
+		resumeCodes: set.retry.codes,
+	}, nil
+}
+
+// waitForReady starts connecting conn and waits until it is ready or ctx ends, retrying
+// failed connection attempts meanwhile
+func waitForReady(ctx context.Context, conn *grpc.ClientConn) error {
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Shutdown:
+			return errors.New("connection closed")
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			if state == connectivity.TransientFailure {
+				return fmt.Errorf("%w: server unreachable", ctx.Err())
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+// Users returns the UserService stub calling through the connection, for the calls
+// without a method of their own; its errors are plain gRPC status errors
+func (c *Client) Users() pb.UserServiceClient {
+	return c.client
+}
+
+// Close closes the client connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// GetUser returns the user with id, from the cache when WithCache is given
+func (c *Client) GetUser(ctx context.Context, id int32) (*pb.UserResponse, error) {
+	if c.cache == nil {
+		user, err := c.client.GetUser(ctx, &pb.UserRequest{Id: id})
+		return user, wrapError(err)
+	}
+
+	if user, ok := c.cache.get(id); ok {
+		return user, nil
+	}
+	generation := c.cache.writes.Load()
+	user, err := c.client.GetUser(ctx, &pb.UserRequest{Id: id})
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	c.cache.set(user, generation)
+	return user, nil
+}
+
+// BatchGetUsers returns the users with ids in one call, in the order of ids, and the IDs
+// without a user. With WithCache, cached users are taken from the cache and only the
+// others are asked for.
+func (c *Client) BatchGetUsers(ctx context.Context, ids []int32) (*pb.BatchGetUsersResponse, error) {
+	if c.cache == nil {
+		res, err := c.client.BatchGetUsers(ctx, &pb.BatchGetUsersRequest{Ids: ids})
+		return res, wrapError(err)
+	}
+
+	found := make(map[int32]*pb.UserResponse, len(ids))
+	var uncached []int32
+	for _, id := range ids {
+		if user, ok := c.cache.get(id); ok {
+			found[id] = user
+		} else if !slices.Contains(uncached, id) {
+			uncached = append(uncached, id)
+		}
+	}
+	res := &pb.BatchGetUsersResponse{}
+	// With nothing cached the server is asked anyway, so invalid requests fail alike
+	if len(uncached) > 0 || len(found) == 0 {
+		generation := c.cache.writes.Load()
+		fetched, err := c.client.BatchGetUsers(ctx, &pb.BatchGetUsersRequest{Ids: uncached})
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		for _, user := range fetched.Users {
+			c.cache.set(user, generation)
+			found[user.Id] = user
+		}
+		res.MissingIds = fetched.MissingIds
+	}
+
+	// Answer in the order asked for, as the server does
+	for _, id := range ids {
+		if user, ok := found[id]; ok {
+			res.Users = append(res.Users, user)
+			delete(found, id)
+		}
+	}
+	return res, nil
+}
+
+// CreateUser creates a user and returns it
+func (c *Client) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.UserResponse, error) {
+	user, err := c.client.CreateUser(ctx, req)
+	return user, wrapError(err)
+}
+
+// ValidateUser checks req as CreateUser would, email uniqueness included, without
+// creating the user; a request that breaks constraints is reported in the response
+// rather than as an error
+func (c *Client) ValidateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.ValidateUserResponse, error) {
+	res, err := c.client.ValidateUser(ctx, req)
+	return res, wrapError(err)
+}
+
+// UpdateUser changes the fields named by the update_mask of req, or without one those it
+// sets, and returns the user
+func (c *Client) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UserResponse, error) {
+	// Invalidate even on failure: a version conflict means the cached copy is stale
+	c.forget(req.GetId())
+	user, err := c.client.UpdateUser(ctx, req)
+	return user, wrapError(err)
+}
+
+// DeleteUser soft-deletes the user with id
+func (c *Client) DeleteUser(ctx context.Context, id int32) error {
+	c.forget(id)
+	_, err := c.client.DeleteUser(ctx, &pb.UserRequest{Id: id})
+	return wrapError(err)
+}
+
+// UndeleteUser restores the soft-deleted user with id and returns it
+func (c *Client) UndeleteUser(ctx context.Context, id int32) (*pb.UserResponse, error) {
+	c.forget(id)
+	user, err := c.client.UndeleteUser(ctx, &pb.UserRequest{Id: id})
+	return user, wrapError(err)
+}
+
+// SuspendUser suspends the user with id, whose credentials are rejected until
+// ActivateUser, and returns it
+func (c *Client) SuspendUser(ctx context.Context, id int32) (*pb.UserResponse, error) {
+	c.forget(id)
+	user, err := c.client.SuspendUser(ctx, &pb.UserRequest{Id: id})
+	return user, wrapError(err)
+}
+
+// ActivateUser reactivates the suspended user with id and returns it
+func (c *Client) ActivateUser(ctx context.Context, id int32) (*pb.UserResponse, error) {
+	c.forget(id)
+	user, err := c.client.ActivateUser(ctx, &pb.UserRequest{Id: id})
+	return user, wrapError(err)
+}
+
+// GetPreferences returns the preferences of the user with id, the defaults until it
+// saves some
+func (c *Client) GetPreferences(ctx context.Context, id int32) (*pb.UserPreferences, error) {
+	prefs, err := c.client.GetPreferences(ctx, &pb.UserRequest{Id: id})
+	return prefs, wrapError(err)
+}
+
+// UpdatePreferences changes the preferences of a user and returns them
+func (c *Client) UpdatePreferences(ctx context.Context, req *pb.UpdatePreferencesRequest) (*pb.UserPreferences, error) {
+	prefs, err := c.client.UpdatePreferences(ctx, req)
+	return prefs, wrapError(err)
+}
+
+// forget drops the user with id from the cache, if any
+func (c *Client) forget(id int32) {
+	if c.cache != nil {
+		c.cache.invalidate(id)
+	}
+}
+
+// StreamUsers iterates over the users matching filter, following the server's pages
+// until the last one. A stream dropped with a retryable code after delivering users is
+// reopened after the last of them, so none is seen twice. Iteration ends after the first
+// error; breaking out of the loop ends the stream.
+func (c *Client) StreamUsers(ctx context.Context, filter *pb.UserFilter) iter.Seq2[*pb.UserResponse, error] {
+	return func(yield func(*pb.UserResponse, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// Page tokens go into a copy, leaving the caller's filter as it was
+		next := &pb.UserFilter{}
+		if filter != nil {
+			next = proto.Clone(filter).(*pb.UserFilter)
+		}
+	pages:
+		for {
+			var trailer metadata.MD
+			stream, err := c.client.StreamUsers(ctx, next, grpc.Trailer(&trailer))
+			if err != nil {
+				yield(nil, wrapError(err))
+				return
+			}
+			var last *pb.UserResponse
+			received := 0
+			for {
+				user, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					// Without a user since the stream opened, the retry policy has had its say
+					if last == nil || ctx.Err() != nil || !slices.Contains(c.resumeCodes, status.Code(err)) {
+						yield(nil, wrapError(err))
+						return
+					}
+					slog.Warn("Resuming dropped stream", "after_id", last.Id, "error", err)
+					if !resume(next, last.Id, received) {
+						return
+					}
+					continue pages
+				}
+				last = user
+				received++
+				if !yield(user, nil) {
+					return
+				}
+			}
+
+			tokens := trailer.Get("next-page-token")
+			if len(tokens) == 0 || tokens[0] == "" {
+				return
+			}
+			next.PageToken, next.ResumeAfterId = tokens[0], 0
+		}
+	}
+}
+
+// resume sets filter up to continue a stream that dropped after delivering received users,
+// the last with lastID; false when the stream had already delivered all it was to
+func resume(filter *pb.UserFilter, lastID int32, received int) bool {
+	if filter.PageSize == 0 && filter.Limit > 0 {
+		filter.Limit -= int32(received)
+		if filter.Limit <= 0 {
+			return false
+		}
+	}
+	filter.ResumeAfterId = lastID
+	filter.PageToken = ""
+	filter.Offset = 0
+	return true
+}
+
+// ListUsers returns a page of the users matching req, with the token of the next page and
+// how many match over all pages
+func (c *Client) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	res, err := c.client.ListUsers(ctx, req)
+	return res, wrapError(err)
+}
+
+// SearchUsers returns a page of the users matching the query of req, such as
+// `role:admin created>2024-01-01 name~john`, like ListUsers
+func (c *Client) SearchUsers(ctx context.Context, req *pb.SearchUsersRequest) (*pb.ListUsersResponse, error) {
+	res, err := c.client.SearchUsers(ctx, req)
+	return res, wrapError(err)
+}
+
+// ExportUsers writes the users matching req to w as a file of req.Format. When it fails
+// part way, w holds the start of the file only.
+func (c *Client) ExportUsers(ctx context.Context, req *pb.ExportUsersRequest, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.ExportUsers(ctx, req)
+	if err != nil {
+		return wrapError(err)
+	}
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return wrapError(err)
+		}
+		if _, err := w.Write(chunk.Data); err != nil {
+			return err
+		}
+	}
+}
+
+// GetAvatar writes the avatar of user id to w and returns its description. When it fails
+// part way, w holds the start of the image only.
+func (c *Client) GetAvatar(ctx context.Context, id int32, w io.Writer) (*pb.Avatar, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.GetAvatar(ctx, &pb.UserRequest{Id: id})
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	var avatar *pb.Avatar
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return avatar, nil
+		}
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		if chunk.Avatar != nil {
+			avatar = chunk.Avatar
+		}
+		if _, err := w.Write(chunk.Data); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// WatchUsers iterates over changes to users as the server commits them, until ctx ends or
+// the stream fails. Changes made while no watch is open are not replayed, so after an
+// error reread the users that matter before watching again.
+func (c *Client) WatchUsers(ctx context.Context, req *pb.WatchUsersRequest) iter.Seq2[*pb.UserEvent, error] {
+	return func(yield func(*pb.UserEvent, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		stream, err := c.client.WatchUsers(ctx, req)
+		if err != nil {
+			yield(nil, wrapError(err))
+			return
+		}
+		for {
+			event, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(nil, wrapError(err))
+				return
+			}
+			if !yield(event, nil) {
+				return
+			}
+		}
+	}
+}
+
+// CreateUsers creates users in one stream and returns how many were created, with the
+// reasons the others were rejected
+func (c *Client) CreateUsers(ctx context.Context, users iter.Seq[*pb.CreateUserRequest]) (*pb.BulkCreateResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.CreateUsers(ctx)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	for user := range users {
+		if err := stream.Send(user); err != nil {
+			if errors.Is(err, io.EOF) {
+				// The server ended the call; its status comes from CloseAndRecv
+				break
+			}
+			// Cancelling the call keeps the server from creating the users sent so far
+			return nil, wrapError(err)
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	return res, wrapError(err)
+}
+
+// BulkUpdateUsers sends updates in one stream and returns the users updated, with the
+// reasons the others were rejected. Each update is applied on its own, once every update
+// has been sent.
+func (c *Client) BulkUpdateUsers(ctx context.Context, updates iter.Seq[*pb.UpdateUserRequest]) (*pb.BulkUpdateResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.BulkUpdateUsers(ctx)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	for update := range updates {
+		c.forget(update.Id)
+		if err := stream.Send(update); err != nil {
+			if errors.Is(err, io.EOF) {
+				// The server ended the call; its status comes from CloseAndRecv
+				break
+			}
+			// Cancelling the call keeps the server from applying the updates sent so far
+			return nil, wrapError(err)
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	return res, wrapError(err)
+}
+
+// uploadChunkSize is how many bytes of a file each message of ImportUsers or
+// UploadAvatar carries
+const uploadChunkSize = 64 << 10
+
+// ImportUsers sends the file of format read from r in one stream and returns the users
+// created. The import is atomic, so when the response lists errors nothing was created.
+func (c *Client) ImportUsers(ctx context.Context, format pb.FileFormat, r io.Reader) (*pb.ImportUsersResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.ImportUsers(ctx)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	err = sendChunks(r, func(data []byte) error {
+		return stream.Send(&pb.ImportUsersRequest{Format: format, Data: data})
+	})
+	if err != nil && !errors.Is(err, io.EOF) {
+		// Cancelling the call keeps the server from importing part of the file
+		return nil, wrapError(err)
+	}
+	res, err := stream.CloseAndRecv()
+	return res, wrapError(err)
+}
+
+// UploadAvatar sends the image read from r as the avatar of user id; contentType may be
+// left empty for the server to detect it. The previous avatar is only replaced once the
+// whole image has arrived.
+func (c *Client) UploadAvatar(ctx context.Context, id int32, contentType string, r io.Reader) (*pb.Avatar, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.UploadAvatar(ctx)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	err = sendChunks(r, func(data []byte) error {
+		return stream.Send(&pb.UploadAvatarRequest{UserId: id, ContentType: contentType, Data: data})
+	})
+	if err != nil && !errors.Is(err, io.EOF) {
+		// Cancelling the call keeps the server from storing part of the image
+		return nil, wrapError(err)
+	}
+	res, err := stream.CloseAndRecv()
+	return res, wrapError(err)
+}
+
+// sendChunks sends what is read from r in chunks of up to uploadChunkSize bytes, at least
+// one even when r is empty, so the fields of the call always arrive. io.EOF from send
+// means the server ended the call, its status then coming from CloseAndRecv.
+func sendChunks(r io.Reader, send func(data []byte) error) error {
+	buf := make([]byte, uploadChunkSize)
+	for first := true; ; first = false {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 || first {
+			if err := send(buf[:n]); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Chat opens the bidirectional chat stream; errors of the stream itself are plain gRPC
+// status errors
+func (c *Client) Chat(ctx context.Context) (pb.UserService_ChatClient, error) {
+	stream, err := c.client.Chat(ctx)
+	return stream, wrapError(err)
+}
+
+// GetChatHistory returns the latest chat messages of a room, of two users or sent to
+// everyone, oldest first
+func (c *Client) GetChatHistory(ctx context.Context, req *pb.ChatHistoryRequest) (*pb.ChatHistoryResponse, error) {
+	res, err := c.client.GetChatHistory(ctx, req)
+	return res, wrapError(err)
+}
+
+// GetAuditLog returns a page of the audit log, newest first; admins only
+func (c *Client) GetAuditLog(ctx context.Context, req *pb.AuditLogRequest) (*pb.AuditLogResponse, error) {
+	res, err := c.client.GetAuditLog(ctx, req)
+	return res, wrapError(err)
+}
+
+// GetUserHistory returns a page of the changes made to one user, newest first; admins only
+func (c *Client) GetUserHistory(ctx context.Context, req *pb.UserHistoryRequest) (*pb.UserHistoryResponse, error) {
+	res, err := c.client.GetUserHistory(ctx, req)
+	return res, wrapError(err)
+}
@@ -0,0 +1,38 @@
+package userclient
+
+import "example.com/user/pkg/usererrors"
+
+// Errors returned by the client's methods match these with errors.Is, by status code;
+// they are those of package usererrors
+var (
+	ErrNotFound           = usererrors.ErrNotFound
+	ErrAlreadyExists      = usererrors.ErrAlreadyExists
+	ErrInvalidArgument    = usererrors.ErrInvalidArgument
+	ErrFailedPrecondition = usererrors.ErrFailedPrecondition
+	ErrUnauthenticated    = usererrors.ErrUnauthenticated
+	ErrPermissionDenied   = usererrors.ErrPermissionDenied
+	ErrResourceExhausted  = usererrors.ErrResourceExhausted
+	ErrUnavailable        = usererrors.ErrUnavailable
+	ErrDeadlineExceeded   = usererrors.ErrDeadlineExceeded
+)
+
+// Reasons of the errors of the client's methods, in Error.Reason; they are those of
+// package usererrors
+const (
+	ReasonUserNotFound    = usererrors.ReasonUserNotFound
+	ReasonEmailTaken      = usererrors.ReasonEmailTaken
+	ReasonVersionConflict = usererrors.ReasonVersionConflict
+	ReasonUserSuspended   = usererrors.ReasonUserSuspended
+	ReasonRateLimited     = usererrors.ReasonRateLimited
+	ReasonServerBusy      = usererrors.ReasonServerBusy
+	ReasonMaintenance     = usererrors.ReasonMaintenance
+)
+
+// Error is a call that failed with a gRPC status, with its code, message and details
+type Error = usererrors.Error
+
+// wrapError decodes an error with a gRPC status into an *Error, leaving other errors, such
+// as invalid options, as they are
+func wrapError(err error) error {
+	return usererrors.Decode(err)
+}
@@ -0,0 +1,18 @@
+package userclient
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// ifMatchHeader is the metadata key of the ETags a change requires the user to have
+const ifMatchHeader = "if-match"
+
+// IfMatch returns ctx making UpdateUser and DeleteUser fail with ErrFailedPrecondition,
+// and reason ReasonVersionConflict, unless the user still has one of etags, the Etag of
+// a UserResponse read before; "*" matches any user
+func IfMatch(ctx context.Context, etags ...string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, ifMatchHeader, strings.Join(etags, ", "))
+}
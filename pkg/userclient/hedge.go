@@ -0,0 +1,86 @@
+package userclient
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	pb "example.com/user/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// hedgedMethods are the reads worth sending twice when the first answer is slow
+var hedgedMethods = []string{
+	pb.UserService_GetUser_FullMethodName,
+}
+
+// hedgingPolicy sends a second attempt of a slow read after delay and takes the first
+// answer to succeed. With round_robin the second attempt goes to another server, so a
+// single slow or stalled replica doesn't hold the call up.
+type hedgingPolicy struct {
+	// delay is how long the first attempt may take before the second is sent; 0 turns
+	// hedging off
+	delay   time.Duration
+	methods []string
+	// onHedge, when set, is told of every second attempt as it is sent
+	onHedge func(method string)
+}
+
+// attempt is the outcome of one attempt of a hedged call
+type attempt struct {
+	reply proto.Message
+	err   error
+}
+
+// unaryInterceptor hedges the unary calls among methods
+func (p hedgingPolicy) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		out, ok := reply.(proto.Message)
+		if !ok || !slices.Contains(p.methods, method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		// The attempt that loses is cancelled when the call returns
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		// Each attempt decodes into a reply of its own, so the loser can't write over the
+		// winner's
+		results := make(chan attempt, 2)
+		send := func() {
+			r := out.ProtoReflect().New().Interface()
+			err := invoker(ctx, method, req, r, cc, opts...)
+			results <- attempt{reply: r, err: err}
+		}
+		go send()
+
+		timer := time.NewTimer(p.delay)
+		defer timer.Stop()
+		pending, hedged := 1, false
+		for {
+			select {
+			case <-timer.C:
+				hedged = true
+				pending++
+				slog.Debug("Hedging call", "method", method, "delay", p.delay)
+				if p.onHedge != nil {
+					p.onHedge(method)
+				}
+				go send()
+			case res := <-results:
+				pending--
+				if res.err == nil {
+					proto.Reset(out)
+					proto.Merge(out, res.reply)
+					return nil
+				}
+				// A failure before the hedge is sent is returned as is, leaving it to
+				// the retry policy; after, the other attempt may still succeed
+				if !hedged || pending == 0 {
+					return res.err
+				}
+			}
+		}
+	}
+}
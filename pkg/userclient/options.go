@@ -0,0 +1,186 @@
+package userclient
+
+import (
+	"crypto/tls"
+	"time"
+
+	"example.com/user/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+)
+
+// Option customizes a client created by New
+type Option func(*settings)
+
+// settings holds what the options of New set
+type settings struct {
+	connectTimeout time.Duration
+	lbPolicy       string
+	creds          credentials
+
+	insecure   bool
+	tlsConfig  *tls.Config
+	caFile     string
+	certFile   string
+	keyFile    string
+	serverName string
+
+	retry     retryPolicy
+	hedging   hedgingPolicy
+	breaker   breakerSettings
+	rateLimit rateLimitSettings
+	keepalive *keepalive.ClientParameters
+	metrics   *metrics.GRPCClient
+	cache     *userCache
+	dialOpts  []grpc.DialOption
+}
+
+// breakerSettings configure the circuit breaker; failures of 0 turn it off
+type breakerSettings struct {
+	failures int
+	cooldown time.Duration
+}
+
+// rateLimitSettings configure the client rate limiter; rps of 0 turns it off
+type rateLimitSettings struct {
+	rps   float64
+	burst int
+}
+
+// defaultSettings are used for whatever the options leave out
+func defaultSettings() settings {
+	return settings{
+		connectTimeout: 5 * time.Second,
+		lbPolicy:       "round_robin",
+		retry: retryPolicy{
+			maxAttempts:    3,
+			codes:          defaultRetryCodes,
+			initialBackoff: 100 * time.Millisecond,
+			maxBackoff:     2 * time.Second,
+			methods:        idempotentMethods,
+		},
+		hedging: hedgingPolicy{methods: hedgedMethods},
+		breaker: breakerSettings{failures: 5, cooldown: 10 * time.Second},
+	}
+}
+
+// WithConnectTimeout bounds how long New waits for the connection to be ready (default 5s)
+func WithConnectTimeout(d time.Duration) Option {
+	return func(s *settings) { s.connectTimeout = d }
+}
+
+// WithLoadBalancing spreads calls over the servers of the address with policy,
+// "round_robin" (the default) or "pick_first"
+func WithLoadBalancing(policy string) Option {
+	return func(s *settings) { s.lbPolicy = policy }
+}
+
+// WithAuthToken sends token as a bearer token with every call
+func WithAuthToken(token string) Option {
+	return func(s *settings) { s.creds.token = token }
+}
+
+// WithAPIKey sends key in the x-api-key header of every call
+func WithAPIKey(key string) Option {
+	return func(s *settings) { s.creds.apiKey = key }
+}
+
+// WithSigningKey signs every call with secret, for servers in hmac auth mode knowing it
+// as keyID
+func WithSigningKey(keyID string, secret []byte) Option {
+	return func(s *settings) { s.creds.signingKeyID, s.creds.signingSecret = keyID, secret }
+}
+
+// WithInsecure connects in plaintext instead of TLS
+func WithInsecure() Option {
+	return func(s *settings) { s.insecure = true }
+}
+
+// WithTLSConfig connects with TLS configured by cfg instead of the system defaults; the
+// other TLS options are applied over a clone of it
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(s *settings) { s.tlsConfig = cfg }
+}
+
+// WithCAFile verifies the server against the PEM CA bundle at path instead of the
+// system roots
+func WithCAFile(path string) Option {
+	return func(s *settings) { s.caFile = path }
+}
+
+// WithClientCertificate presents the PEM certificate and key at certFile and keyFile, for
+// servers requiring mutual TLS
+func WithClientCertificate(certFile, keyFile string) Option {
+	return func(s *settings) { s.certFile, s.keyFile = certFile, keyFile }
+}
+
+// WithServerName verifies name in the server certificate instead of the host dialed
+func WithServerName(name string) Option {
+	return func(s *settings) { s.serverName = name }
+}
+
+// WithRetry retries idempotent calls failing with a retryable code, making up to
+// maxAttempts attempts in all (1 turns retries off) with exponential backoff between
+// initialBackoff and maxBackoff (defaults 3, 100ms and 2s)
+func WithRetry(maxAttempts int, initialBackoff, maxBackoff time.Duration) Option {
+	return func(s *settings) {
+		s.retry.maxAttempts = maxAttempts
+		s.retry.initialBackoff = initialBackoff
+		s.retry.maxBackoff = maxBackoff
+	}
+}
+
+// WithRetryCodes sets the status codes retried (default UNAVAILABLE and
+// RESOURCE_EXHAUSTED)
+func WithRetryCodes(retryable ...codes.Code) Option {
+	return func(s *settings) { s.retry.codes = retryable }
+}
+
+// WithHedging sends a second GetUser when the first has had no answer after delay, taking
+// whichever succeeds first and cancelling the other; 0 (the default) turns hedging off.
+// With round_robin the second attempt goes to another server.
+func WithHedging(delay time.Duration) Option {
+	return func(s *settings) { s.hedging.delay = delay }
+}
+
+// WithCircuitBreaker fails calls fast for cooldown after failures consecutive calls fail
+// (defaults 5 and 10s); failures of 0 turns the breaker off
+func WithCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return func(s *settings) { s.breaker = breakerSettings{failures: failures, cooldown: cooldown} }
+}
+
+// WithRateLimit sends at most rps calls a second, with bursts of up to burst (0 for rps
+// rounded up); retries and hedged attempts count as calls, and every message sent by
+// CreateUsers or on a Chat stream takes its turn too. A call waits for its turn unless
+// that would take it past its deadline, when it fails with DEADLINE_EXCEEDED. An rps of 0
+// (the default) sends without limit.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(s *settings) { s.rateLimit = rateLimitSettings{rps: rps, burst: burst} }
+}
+
+// WithKeepalive pings the server as params say, keeping idle connections open through
+// NATs and load balancers; the server must allow pings this frequent
+func WithKeepalive(params keepalive.ClientParameters) Option {
+	return func(s *settings) { s.keepalive = &params }
+}
+
+// WithMetrics records every call's latency and status code, and every retry, in metrics
+// registered with reg, for applications exporting their own Prometheus metrics
+func WithMetrics(reg prometheus.Registerer) Option {
+	return func(s *settings) { s.metrics = metrics.NewGRPCClient(reg) }
+}
+
+// WithCache keeps up to size users returned by GetUser and BatchGetUsers for ttl (zero
+// for no expiry), answering repeated reads without a call. Updates and deletes made
+// through the client drop the user they touch; changes made by others show once the
+// entry expires.
+func WithCache(size int, ttl time.Duration) Option {
+	return func(s *settings) { s.cache = newUserCache(size, ttl) }
+}
+
+// WithDialOptions passes opts on to grpc.NewClient, such as a stats handler for tracing
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(s *settings) { s.dialOpts = append(s.dialOpts, opts...) }
+}
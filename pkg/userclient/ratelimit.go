@@ -0,0 +1,83 @@
+package userclient
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rateLimiter holds outgoing attempts, and the messages sent on client streams such as
+// CreateUsers, to a token bucket, so bulk work stays under the server's rate limits
+type rateLimiter struct {
+	limiter *rate.Limiter
+}
+
+// newRateLimiter creates a limiter of rps tokens per second holding up to burst, rps
+// rounded up when burst is 0; nil when rps is 0, which turns it off
+func newRateLimiter(rps float64, burst int) (*rateLimiter, error) {
+	if rps < 0 || burst < 0 {
+		return nil, errors.New("rate and burst must not be negative")
+	}
+	if rps == 0 {
+		return nil, nil
+	}
+	if burst == 0 {
+		burst = int(math.Ceil(rps))
+	}
+	return &rateLimiter{limiter: rate.NewLimiter(rate.Limit(rps), burst)}, nil
+}
+
+// wait blocks until a token is free, failing at once when none will be before ctx's
+// deadline
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if err := l.limiter.Wait(ctx); err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return status.Error(codes.DeadlineExceeded, "Client rate limit would delay the call past its deadline")
+	}
+	return nil
+}
+
+// unaryInterceptor takes a token for every attempt of a unary call
+func (l *rateLimiter) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := l.wait(ctx); err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// streamInterceptor takes a token for every stream opened and, on client streams, for
+// every message sent
+func (l *rateLimiter) streamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if err := l.wait(ctx); err != nil {
+			return nil, err
+		}
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil || !desc.ClientStreams {
+			return stream, err
+		}
+		return &limitedStream{ClientStream: stream, limiter: l}, nil
+	}
+}
+
+// limitedStream waits for a token before each message it sends
+type limitedStream struct {
+	grpc.ClientStream
+	limiter *rateLimiter
+}
+
+func (s *limitedStream) SendMsg(m any) error {
+	if err := s.limiter.wait(s.Context()); err != nil {
+		return err
+	}
+	return s.ClientStream.SendMsg(m)
+}
@@ -0,0 +1,200 @@
+package userclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	pb "example.com/user/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// idempotentMethods are the calls safe to repeat, as they change nothing on the server
+var idempotentMethods = []string{
+	pb.UserService_GetUser_FullMethodName,
+	pb.UserService_BatchGetUsers_FullMethodName,
+	pb.UserService_ValidateUser_FullMethodName,
+	pb.UserService_GetPreferences_FullMethodName,
+	pb.UserService_StreamUsers_FullMethodName,
+	pb.UserService_ExportUsers_FullMethodName,
+	pb.UserService_GetAvatar_FullMethodName,
+	pb.UserService_ListUsers_FullMethodName,
+	pb.UserService_SearchUsers_FullMethodName,
+	pb.UserService_GetAuditLog_FullMethodName,
+	pb.UserService_GetUserHistory_FullMethodName,
+	pb.UserService_GetChatHistory_FullMethodName,
+}
+
+// defaultRetryCodes are the codes retried unless WithRetryCodes says otherwise: the call
+// didn't reach the server, or the server turned it away before running it
+var defaultRetryCodes = []codes.Code{codes.Unavailable, codes.ResourceExhausted}
+
+// retryPolicy repeats failed idempotent calls with exponential backoff and full jitter
+type retryPolicy struct {
+	// maxAttempts counts the first attempt; 1 turns retries off
+	maxAttempts    int
+	codes          []codes.Code
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	methods        []string
+	// onRetry, when set, is told of every retry as it starts
+	onRetry func(method string)
+}
+
+// validate checks the policy set by WithRetry
+func (p retryPolicy) validate() error {
+	if p.maxAttempts < 1 {
+		return errors.New("max attempts must be at least 1")
+	}
+	if p.initialBackoff <= 0 || p.maxBackoff < p.initialBackoff {
+		return errors.New("initial backoff must be positive and at most the max backoff")
+	}
+	return nil
+}
+
+// backoff returns how long to wait before the given retry, counting from 1. A server
+// asking for a longer delay with a RetryInfo detail in err gets it.
+func (p retryPolicy) backoff(retry int, err error) time.Duration {
+	ceiling := p.maxBackoff
+	if shift := retry - 1; shift < 30 && p.initialBackoff<<shift < p.maxBackoff {
+		ceiling = p.initialBackoff << shift
+	}
+	delay := rand.N(ceiling) + 1
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay().AsDuration() > delay {
+			delay = info.GetRetryDelay().AsDuration()
+		}
+	}
+	return delay
+}
+
+// wait sleeps before retrying method after attempt failed with err, reporting false when
+// the call shouldn't be retried or ctx ends first
+func (p retryPolicy) wait(ctx context.Context, method string, attempt int, err error) bool {
+	if attempt >= p.maxAttempts || !slices.Contains(p.codes, status.Code(err)) {
+		return false
+	}
+	delay := p.backoff(attempt, err)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return false
+	}
+	slog.Warn("Retrying call", "method", method, "attempt", attempt+1, "delay", delay, "error", err)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		if p.onRetry != nil {
+			p.onRetry(method)
+		}
+		return true
+	}
+}
+
+// unaryInterceptor retries idempotent unary calls
+func (p retryPolicy) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !slices.Contains(p.methods, method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || !p.wait(ctx, method, attempt, err) {
+				return err
+			}
+		}
+	}
+}
+
+// streamInterceptor retries idempotent server streams that fail before the first response
+// arrives; once one has, the caller may have acted on it, so later failures are returned
+func (p retryPolicy) streamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if desc.ClientStreams || !slices.Contains(p.methods, method) {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		open := func() (grpc.ClientStream, error) {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		attempt := 1
+		stream, err := open()
+		for ; err != nil; attempt++ {
+			if !p.wait(ctx, method, attempt, err) {
+				return nil, err
+			}
+			stream, err = open()
+		}
+		return &retryingStream{ClientStream: stream, ctx: ctx, method: method, policy: p, open: open, attempt: attempt}, nil
+	}
+}
+
+// retryingStream reopens a server stream and sends its request again when it fails before
+// its first response
+type retryingStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	method string
+	policy retryPolicy
+	open   func() (grpc.ClientStream, error)
+
+	req      any
+	closed   bool
+	received bool
+	// attempt counts the streams opened so far
+	attempt int
+}
+
+func (s *retryingStream) SendMsg(m any) error {
+	s.req = m
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *retryingStream) CloseSend() error {
+	s.closed = true
+	return s.ClientStream.CloseSend()
+}
+
+func (s *retryingStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	for err != nil && !s.received && !errors.Is(err, io.EOF) {
+		if !s.policy.wait(s.ctx, s.method, s.attempt, err) {
+			return err
+		}
+		s.attempt++
+		if err = s.reopen(); err == nil {
+			err = s.ClientStream.RecvMsg(m)
+		}
+	}
+	if err == nil {
+		s.received = true
+	}
+	return err
+}
+
+// reopen replaces the failed stream with a new one carrying the same request
+func (s *retryingStream) reopen() error {
+	stream, err := s.open()
+	if err != nil {
+		return err
+	}
+	s.ClientStream = stream
+	// A failed send reports io.EOF, leaving the status to RecvMsg
+	if s.req != nil {
+		if err := stream.SendMsg(s.req); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	}
+	if s.closed {
+		return stream.CloseSend()
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package userclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	grpccredentials "google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// transportCredentials returns the dial option securing the connection: TLS verifying the
+// server against the CA file or the system roots, presenting a client certificate when
+// one is set, or plaintext with WithInsecure
+func transportCredentials(s settings) (grpc.DialOption, error) {
+	tlsConfigured := s.tlsConfig != nil || s.caFile != "" || s.certFile != "" || s.keyFile != "" || s.serverName != ""
+
+	switch {
+	case s.insecure && tlsConfigured:
+		return nil, errors.New("insecure connections conflict with TLS settings; set only one")
+	case s.insecure:
+		return grpc.WithTransportCredentials(insecure.NewCredentials()), nil
+	case (s.certFile == "") != (s.keyFile == ""):
+		return nil, errors.New("client certificate and key files must be set together")
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s.tlsConfig != nil {
+		tlsConfig = s.tlsConfig.Clone()
+	}
+	if s.serverName != "" {
+		tlsConfig.ServerName = s.serverName
+	}
+	if s.caFile != "" {
+		pem, err := os.ReadFile(s.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA bundle: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", s.caFile)
+		}
+	}
+	// Mutual TLS: present a certificate for servers that require one
+	if s.certFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client key pair: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return grpc.WithTransportCredentials(grpccredentials.NewTLS(tlsConfig)), nil
+}
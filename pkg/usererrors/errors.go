@@ -0,0 +1,167 @@
+// Package usererrors decodes the errors of user service calls into typed errors, so
+// callers check errors.Is(err, usererrors.ErrNotFound), the reason of a failure or the
+// field violations of a rejected request instead of matching messages. It works on
+// errors of any gRPC client of the service, the generated stub included.
+package usererrors
+
+import (
+	"errors"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Decoded errors match these with errors.Is, by status code
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrAlreadyExists      = errors.New("already exists")
+	ErrInvalidArgument    = errors.New("invalid argument")
+	ErrFailedPrecondition = errors.New("failed precondition")
+	ErrUnauthenticated    = errors.New("unauthenticated")
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrResourceExhausted  = errors.New("resource exhausted")
+	ErrUnavailable        = errors.New("unavailable")
+	ErrDeadlineExceeded   = errors.New("deadline exceeded")
+)
+
+// Domain is the domain of the google.rpc.ErrorInfo the service attaches to its errors
+const Domain = "user.example.com"
+
+// Reasons of errors with a more specific cause than their status code. Other errors
+// have their code as reason, in upper snake case such as "NOT_FOUND".
+const (
+	ReasonUserNotFound    = "USER_NOT_FOUND"       // NOT_FOUND: no user has the ID
+	ReasonEmailTaken      = "EMAIL_ALREADY_EXISTS" // ALREADY_EXISTS: another user has the email
+	ReasonVersionConflict = "VERSION_CONFLICT"     // FAILED_PRECONDITION: the user changed since read
+	ReasonUserSuspended   = "USER_SUSPENDED"       // PERMISSION_DENIED: the caller's account is suspended
+	ReasonRateLimited     = "RATE_LIMITED"         // RESOURCE_EXHAUSTED: the caller is over its rate limit
+	ReasonServerBusy      = "SERVER_BUSY"          // RESOURCE_EXHAUSTED: the server sheds load
+	ReasonMaintenance     = "MAINTENANCE"          // UNAVAILABLE: the server is in maintenance
+)
+
+// MetadataRetryable is the ErrorInfo metadata key telling whether the same call may
+// succeed if tried again, "true" or "false"
+const MetadataRetryable = "retryable"
+
+// sentinels maps status codes to the errors matching them
+var sentinels = map[codes.Code]error{
+	codes.NotFound:           ErrNotFound,
+	codes.AlreadyExists:      ErrAlreadyExists,
+	codes.InvalidArgument:    ErrInvalidArgument,
+	codes.FailedPrecondition: ErrFailedPrecondition,
+	codes.Unauthenticated:    ErrUnauthenticated,
+	codes.PermissionDenied:   ErrPermissionDenied,
+	codes.ResourceExhausted:  ErrResourceExhausted,
+	codes.Unavailable:        ErrUnavailable,
+	codes.DeadlineExceeded:   ErrDeadlineExceeded,
+}
+
+// FieldViolation is a field of a request that broke a constraint, such as an email
+// that isn't one
+type FieldViolation struct {
+	Field       string // proto field name, such as "email" or "roles[1]"
+	Description string // what is wrong, such as "is required"
+}
+
+// Error is a call that failed with a gRPC status. It matches the sentinel error of its
+// code with errors.Is, and status.FromError still finds the status.
+type Error struct {
+	Code    codes.Code
+	Message string
+	// Reason is the cause of the error, one of the Reason constants or the code in upper
+	// snake case; empty when the server didn't say
+	Reason string
+	// Metadata holds further facts about the error, such as MetadataRetryable
+	Metadata map[string]string
+	// Retryable reports whether the server said the call may succeed if tried again
+	Retryable bool
+	// FieldViolations lists what was wrong with an INVALID_ARGUMENT request
+	FieldViolations []FieldViolation
+	// RetryDelay is how long the server asked to wait before trying again, such as
+	// during maintenance; 0 when it didn't say
+	RetryDelay time.Duration
+
+	status *status.Status
+}
+
+func (e *Error) Error() string {
+	return e.Code.String() + ": " + e.Message
+}
+
+// Is reports whether target is the sentinel error of e's code
+func (e *Error) Is(target error) bool {
+	sentinel, ok := sentinels[e.Code]
+	return ok && target == sentinel
+}
+
+// GRPCStatus returns the status the call failed with
+func (e *Error) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// Violation returns the description of the violation of field, if any
+func (e *Error) Violation(field string) (string, bool) {
+	for _, v := range e.FieldViolations {
+		if v.Field == field {
+			return v.Description, true
+		}
+	}
+	return "", false
+}
+
+// Decode turns an error carrying a gRPC status into an *Error with its details decoded.
+// nil, errors already decoded and errors without a status are returned as they are.
+func Decode(err error) error {
+	if err == nil {
+		return nil
+	}
+	var decoded *Error
+	if errors.As(err, &decoded) {
+		return err
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	e := &Error{Code: st.Code(), Message: st.Message(), status: st}
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetDomain() == Domain {
+				e.Reason = d.GetReason()
+				e.Metadata = d.GetMetadata()
+				e.Retryable = e.Retryable || d.GetMetadata()[MetadataRetryable] == "true"
+			}
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				e.FieldViolations = append(e.FieldViolations, FieldViolation{Field: v.GetField(), Description: v.GetDescription()})
+			}
+		case *errdetails.RetryInfo:
+			e.RetryDelay = d.GetRetryDelay().AsDuration()
+			e.Retryable = true
+		}
+	}
+	return e
+}
+
+// FieldViolations returns the field violations of err, decoding it when needed; nil when
+// it has none
+func FieldViolations(err error) []FieldViolation {
+	var e *Error
+	if errors.As(Decode(err), &e) {
+		return e.FieldViolations
+	}
+	return nil
+}
+
+// Reason returns the reason of err, decoding it when needed; empty when it has none
+func Reason(err error) string {
+	var e *Error
+	if errors.As(Decode(err), &e) {
+		return e.Reason
+	}
+	return ""
+}
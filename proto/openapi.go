@@ -0,0 +1,8 @@
+package proto
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI v3 description of the REST/JSON gateway, generated from user.proto
+//
+//go:embed user.openapi.yaml
+var OpenAPI []byte
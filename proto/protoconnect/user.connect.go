@@ -0,0 +1,1136 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: proto/user.proto
+
+package protoconnect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	proto "example.com/user/proto"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// UserServiceName is the fully-qualified name of the UserService service.
+	UserServiceName = "user.UserService"
+	// AuthServiceName is the fully-qualified name of the AuthService service.
+	AuthServiceName = "user.AuthService"
+	// AdminServiceName is the fully-qualified name of the AdminService service.
+	AdminServiceName = "user.AdminService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// UserServiceGetUserProcedure is the fully-qualified name of the UserService's GetUser RPC.
+	UserServiceGetUserProcedure = "/user.UserService/GetUser"
+	// UserServiceBatchGetUsersProcedure is the fully-qualified name of the UserService's BatchGetUsers
+	// RPC.
+	UserServiceBatchGetUsersProcedure = "/user.UserService/BatchGetUsers"
+	// UserServiceCreateUserProcedure is the fully-qualified name of the UserService's CreateUser RPC.
+	UserServiceCreateUserProcedure = "/user.UserService/CreateUser"
+	// UserServiceValidateUserProcedure is the fully-qualified name of the UserService's ValidateUser
+	// RPC.
+	UserServiceValidateUserProcedure = "/user.UserService/ValidateUser"
+	// UserServiceUpdateUserProcedure is the fully-qualified name of the UserService's UpdateUser RPC.
+	UserServiceUpdateUserProcedure = "/user.UserService/UpdateUser"
+	// UserServiceDeleteUserProcedure is the fully-qualified name of the UserService's DeleteUser RPC.
+	UserServiceDeleteUserProcedure = "/user.UserService/DeleteUser"
+	// UserServiceUndeleteUserProcedure is the fully-qualified name of the UserService's UndeleteUser
+	// RPC.
+	UserServiceUndeleteUserProcedure = "/user.UserService/UndeleteUser"
+	// UserServiceSuspendUserProcedure is the fully-qualified name of the UserService's SuspendUser RPC.
+	UserServiceSuspendUserProcedure = "/user.UserService/SuspendUser"
+	// UserServiceActivateUserProcedure is the fully-qualified name of the UserService's ActivateUser
+	// RPC.
+	UserServiceActivateUserProcedure = "/user.UserService/ActivateUser"
+	// UserServiceGetPreferencesProcedure is the fully-qualified name of the UserService's
+	// GetPreferences RPC.
+	UserServiceGetPreferencesProcedure = "/user.UserService/GetPreferences"
+	// UserServiceUpdatePreferencesProcedure is the fully-qualified name of the UserService's
+	// UpdatePreferences RPC.
+	UserServiceUpdatePreferencesProcedure = "/user.UserService/UpdatePreferences"
+	// UserServiceStreamUsersProcedure is the fully-qualified name of the UserService's StreamUsers RPC.
+	UserServiceStreamUsersProcedure = "/user.UserService/StreamUsers"
+	// UserServiceExportUsersProcedure is the fully-qualified name of the UserService's ExportUsers RPC.
+	UserServiceExportUsersProcedure = "/user.UserService/ExportUsers"
+	// UserServiceGetAvatarProcedure is the fully-qualified name of the UserService's GetAvatar RPC.
+	UserServiceGetAvatarProcedure = "/user.UserService/GetAvatar"
+	// UserServiceListUsersProcedure is the fully-qualified name of the UserService's ListUsers RPC.
+	UserServiceListUsersProcedure = "/user.UserService/ListUsers"
+	// UserServiceSearchUsersProcedure is the fully-qualified name of the UserService's SearchUsers RPC.
+	UserServiceSearchUsersProcedure = "/user.UserService/SearchUsers"
+	// UserServiceWatchUsersProcedure is the fully-qualified name of the UserService's WatchUsers RPC.
+	UserServiceWatchUsersProcedure = "/user.UserService/WatchUsers"
+	// UserServiceCreateUsersProcedure is the fully-qualified name of the UserService's CreateUsers RPC.
+	UserServiceCreateUsersProcedure = "/user.UserService/CreateUsers"
+	// UserServiceBulkUpdateUsersProcedure is the fully-qualified name of the UserService's
+	// BulkUpdateUsers RPC.
+	UserServiceBulkUpdateUsersProcedure = "/user.UserService/BulkUpdateUsers"
+	// UserServiceImportUsersProcedure is the fully-qualified name of the UserService's ImportUsers RPC.
+	UserServiceImportUsersProcedure = "/user.UserService/ImportUsers"
+	// UserServiceUploadAvatarProcedure is the fully-qualified name of the UserService's UploadAvatar
+	// RPC.
+	UserServiceUploadAvatarProcedure = "/user.UserService/UploadAvatar"
+	// UserServiceChatProcedure is the fully-qualified name of the UserService's Chat RPC.
+	UserServiceChatProcedure = "/user.UserService/Chat"
+	// UserServiceGetChatHistoryProcedure is the fully-qualified name of the UserService's
+	// GetChatHistory RPC.
+	UserServiceGetChatHistoryProcedure = "/user.UserService/GetChatHistory"
+	// UserServiceGetAuditLogProcedure is the fully-qualified name of the UserService's GetAuditLog RPC.
+	UserServiceGetAuditLogProcedure = "/user.UserService/GetAuditLog"
+	// UserServiceGetUserHistoryProcedure is the fully-qualified name of the UserService's
+	// GetUserHistory RPC.
+	UserServiceGetUserHistoryProcedure = "/user.UserService/GetUserHistory"
+	// AuthServiceIssueTokensProcedure is the fully-qualified name of the AuthService's IssueTokens RPC.
+	AuthServiceIssueTokensProcedure = "/user.AuthService/IssueTokens"
+	// AuthServiceRefreshTokenProcedure is the fully-qualified name of the AuthService's RefreshToken
+	// RPC.
+	AuthServiceRefreshTokenProcedure = "/user.AuthService/RefreshToken"
+	// AdminServiceGetLogLevelProcedure is the fully-qualified name of the AdminService's GetLogLevel
+	// RPC.
+	AdminServiceGetLogLevelProcedure = "/user.AdminService/GetLogLevel"
+	// AdminServiceSetLogLevelProcedure is the fully-qualified name of the AdminService's SetLogLevel
+	// RPC.
+	AdminServiceSetLogLevelProcedure = "/user.AdminService/SetLogLevel"
+	// AdminServiceGetMaintenanceProcedure is the fully-qualified name of the AdminService's
+	// GetMaintenance RPC.
+	AdminServiceGetMaintenanceProcedure = "/user.AdminService/GetMaintenance"
+	// AdminServiceSetMaintenanceProcedure is the fully-qualified name of the AdminService's
+	// SetMaintenance RPC.
+	AdminServiceSetMaintenanceProcedure = "/user.AdminService/SetMaintenance"
+)
+
+// UserServiceClient is a client for the user.UserService service.
+type UserServiceClient interface {
+	// Simple request-response
+	GetUser(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error)
+	// Several users by ID in one call; IDs without a user are listed rather than failing it
+	BatchGetUsers(context.Context, *connect.Request[proto.BatchGetUsersRequest]) (*connect.Response[proto.BatchGetUsersResponse], error)
+	// Create user
+	CreateUser(context.Context, *connect.Request[proto.CreateUserRequest]) (*connect.Response[proto.UserResponse], error)
+	// Checks a new user as CreateUser would, email uniqueness included, without creating
+	// it; what is wrong is listed in the response rather than failing the call
+	ValidateUser(context.Context, *connect.Request[proto.CreateUserRequest]) (*connect.Response[proto.ValidateUserResponse], error)
+	// Update user
+	UpdateUser(context.Context, *connect.Request[proto.UpdateUserRequest]) (*connect.Response[proto.UserResponse], error)
+	// Delete user (soft delete - the user can be restored with UndeleteUser)
+	DeleteUser(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[emptypb.Empty], error)
+	// Restore a soft-deleted user
+	UndeleteUser(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error)
+	// Suspend a user: the account is kept but can no longer authenticate
+	SuspendUser(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error)
+	// Reactivate a suspended user
+	ActivateUser(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error)
+	// Settings a user keeps for themselves; defaults until first updated
+	GetPreferences(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserPreferences], error)
+	UpdatePreferences(context.Context, *connect.Request[proto.UpdatePreferencesRequest]) (*connect.Response[proto.UserPreferences], error)
+	// Server-side streaming - user list
+	StreamUsers(context.Context, *connect.Request[proto.UserFilter]) (*connect.ServerStreamForClient[proto.UserResponse], error)
+	// The users matching a filter as a file, streamed in chunks
+	ExportUsers(context.Context, *connect.Request[proto.ExportUsersRequest]) (*connect.ServerStreamForClient[proto.FileChunk], error)
+	// Server-side streaming - the avatar of a user, in chunks
+	GetAvatar(context.Context, *connect.Request[proto.UserRequest]) (*connect.ServerStreamForClient[proto.AvatarChunk], error)
+	// One page of the user list, for callers that want a page rather than a stream
+	ListUsers(context.Context, *connect.Request[proto.ListUsersRequest]) (*connect.Response[proto.ListUsersResponse], error)
+	// One page of the users matching a query such as `role:admin created>2024-01-01 name~john`
+	SearchUsers(context.Context, *connect.Request[proto.SearchUsersRequest]) (*connect.Response[proto.ListUsersResponse], error)
+	// Server-side streaming - changes to users as they are committed, for keeping local
+	// copies in sync. Only changes made through this server are seen.
+	WatchUsers(context.Context, *connect.Request[proto.WatchUsersRequest]) (*connect.ServerStreamForClient[proto.UserEvent], error)
+	// Client-side streaming - bulk user creation
+	CreateUsers(context.Context) *connect.ClientStreamForClient[proto.CreateUserRequest, proto.BulkCreateResponse]
+	// Client-side streaming - bulk partial updates, each applied on its own
+	BulkUpdateUsers(context.Context) *connect.ClientStreamForClient[proto.UpdateUserRequest, proto.BulkUpdateResponse]
+	// Client-side streaming - a file of users, as ExportUsers writes, created atomically
+	ImportUsers(context.Context) *connect.ClientStreamForClient[proto.ImportUsersRequest, proto.ImportUsersResponse]
+	// Client-side streaming - an image replacing the avatar of a user
+	UploadAvatar(context.Context) *connect.ClientStreamForClient[proto.UploadAvatarRequest, proto.Avatar]
+	// Bidirectional streaming - real-time messaging: messages reach the user they are to,
+	// the other members of their room or every other stream, and JOIN and LEAVE messages
+	// enter and leave rooms. Rooms scope who is sent a message but aren't private: any
+	// caller may join any room, and its members are told when one does.
+	Chat(context.Context) *connect.BidiStreamForClient[proto.ChatMessage, proto.ChatMessage]
+	// Chat messages sent to a room, between two users or to everyone, newest last, so
+	// clients can backfill what they missed
+	GetChatHistory(context.Context, *connect.Request[proto.ChatHistoryRequest]) (*connect.Response[proto.ChatHistoryResponse], error)
+	// Audit trail of user changes, newest first (admin only)
+	GetAuditLog(context.Context, *connect.Request[proto.AuditLogRequest]) (*connect.Response[proto.AuditLogResponse], error)
+	// Changes made to one user, newest first, from the audit log (admin only)
+	GetUserHistory(context.Context, *connect.Request[proto.UserHistoryRequest]) (*connect.Response[proto.UserHistoryResponse], error)
+}
+
+// NewUserServiceClient constructs a client for the user.UserService service. By default, it uses
+// the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewUserServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) UserServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	userServiceMethods := proto.File_proto_user_proto.Services().ByName("UserService").Methods()
+	return &userServiceClient{
+		getUser: connect.NewClient[proto.UserRequest, proto.UserResponse](
+			httpClient,
+			baseURL+UserServiceGetUserProcedure,
+			connect.WithSchema(userServiceMethods.ByName("GetUser")),
+			connect.WithClientOptions(opts...),
+		),
+		batchGetUsers: connect.NewClient[proto.BatchGetUsersRequest, proto.BatchGetUsersResponse](
+			httpClient,
+			baseURL+UserServiceBatchGetUsersProcedure,
+			connect.WithSchema(userServiceMethods.ByName("BatchGetUsers")),
+			connect.WithClientOptions(opts...),
+		),
+		createUser: connect.NewClient[proto.CreateUserRequest, proto.UserResponse](
+			httpClient,
+			baseURL+UserServiceCreateUserProcedure,
+			connect.WithSchema(userServiceMethods.ByName("CreateUser")),
+			connect.WithClientOptions(opts...),
+		),
+		validateUser: connect.NewClient[proto.CreateUserRequest, proto.ValidateUserResponse](
+			httpClient,
+			baseURL+UserServiceValidateUserProcedure,
+			connect.WithSchema(userServiceMethods.ByName("ValidateUser")),
+			connect.WithClientOptions(opts...),
+		),
+		updateUser: connect.NewClient[proto.UpdateUserRequest, proto.UserResponse](
+			httpClient,
+			baseURL+UserServiceUpdateUserProcedure,
+			connect.WithSchema(userServiceMethods.ByName("UpdateUser")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteUser: connect.NewClient[proto.UserRequest, emptypb.Empty](
+			httpClient,
+			baseURL+UserServiceDeleteUserProcedure,
+			connect.WithSchema(userServiceMethods.ByName("DeleteUser")),
+			connect.WithClientOptions(opts...),
+		),
+		undeleteUser: connect.NewClient[proto.UserRequest, proto.UserResponse](
+			httpClient,
+			baseURL+UserServiceUndeleteUserProcedure,
+			connect.WithSchema(userServiceMethods.ByName("UndeleteUser")),
+			connect.WithClientOptions(opts...),
+		),
+		suspendUser: connect.NewClient[proto.UserRequest, proto.UserResponse](
+			httpClient,
+			baseURL+UserServiceSuspendUserProcedure,
+			connect.WithSchema(userServiceMethods.ByName("SuspendUser")),
+			connect.WithClientOptions(opts...),
+		),
+		activateUser: connect.NewClient[proto.UserRequest, proto.UserResponse](
+			httpClient,
+			baseURL+UserServiceActivateUserProcedure,
+			connect.WithSchema(userServiceMethods.ByName("ActivateUser")),
+			connect.WithClientOptions(opts...),
+		),
+		getPreferences: connect.NewClient[proto.UserRequest, proto.UserPreferences](
+			httpClient,
+			baseURL+UserServiceGetPreferencesProcedure,
+			connect.WithSchema(userServiceMethods.ByName("GetPreferences")),
+			connect.WithClientOptions(opts...),
+		),
+		updatePreferences: connect.NewClient[proto.UpdatePreferencesRequest, proto.UserPreferences](
+			httpClient,
+			baseURL+UserServiceUpdatePreferencesProcedure,
+			connect.WithSchema(userServiceMethods.ByName("UpdatePreferences")),
+			connect.WithClientOptions(opts...),
+		),
+		streamUsers: connect.NewClient[proto.UserFilter, proto.UserResponse](
+			httpClient,
+			baseURL+UserServiceStreamUsersProcedure,
+			connect.WithSchema(userServiceMethods.ByName("StreamUsers")),
+			connect.WithClientOptions(opts...),
+		),
+		exportUsers: connect.NewClient[proto.ExportUsersRequest, proto.FileChunk](
+			httpClient,
+			baseURL+UserServiceExportUsersProcedure,
+			connect.WithSchema(userServiceMethods.ByName("ExportUsers")),
+			connect.WithClientOptions(opts...),
+		),
+		getAvatar: connect.NewClient[proto.UserRequest, proto.AvatarChunk](
+			httpClient,
+			baseURL+UserServiceGetAvatarProcedure,
+			connect.WithSchema(userServiceMethods.ByName("GetAvatar")),
+			connect.WithClientOptions(opts...),
+		),
+		listUsers: connect.NewClient[proto.ListUsersRequest, proto.ListUsersResponse](
+			httpClient,
+			baseURL+UserServiceListUsersProcedure,
+			connect.WithSchema(userServiceMethods.ByName("ListUsers")),
+			connect.WithClientOptions(opts...),
+		),
+		searchUsers: connect.NewClient[proto.SearchUsersRequest, proto.ListUsersResponse](
+			httpClient,
+			baseURL+UserServiceSearchUsersProcedure,
+			connect.WithSchema(userServiceMethods.ByName("SearchUsers")),
+			connect.WithClientOptions(opts...),
+		),
+		watchUsers: connect.NewClient[proto.WatchUsersRequest, proto.UserEvent](
+			httpClient,
+			baseURL+UserServiceWatchUsersProcedure,
+			connect.WithSchema(userServiceMethods.ByName("WatchUsers")),
+			connect.WithClientOptions(opts...),
+		),
+		createUsers: connect.NewClient[proto.CreateUserRequest, proto.BulkCreateResponse](
+			httpClient,
+			baseURL+UserServiceCreateUsersProcedure,
+			connect.WithSchema(userServiceMethods.ByName("CreateUsers")),
+			connect.WithClientOptions(opts...),
+		),
+		bulkUpdateUsers: connect.NewClient[proto.UpdateUserRequest, proto.BulkUpdateResponse](
+			httpClient,
+			baseURL+UserServiceBulkUpdateUsersProcedure,
+			connect.WithSchema(userServiceMethods.ByName("BulkUpdateUsers")),
+			connect.WithClientOptions(opts...),
+		),
+		importUsers: connect.NewClient[proto.ImportUsersRequest, proto.ImportUsersResponse](
+			httpClient,
+			baseURL+UserServiceImportUsersProcedure,
+			connect.WithSchema(userServiceMethods.ByName("ImportUsers")),
+			connect.WithClientOptions(opts...),
+		),
+		uploadAvatar: connect.NewClient[proto.UploadAvatarRequest, proto.Avatar](
+			httpClient,
+			baseURL+UserServiceUploadAvatarProcedure,
+			connect.WithSchema(userServiceMethods.ByName("UploadAvatar")),
+			connect.WithClientOptions(opts...),
+		),
+		chat: connect.NewClient[proto.ChatMessage, proto.ChatMessage](
+			httpClient,
+			baseURL+UserServiceChatProcedure,
+			connect.WithSchema(userServiceMethods.ByName("Chat")),
+			connect.WithClientOptions(opts...),
+		),
+		getChatHistory: connect.NewClient[proto.ChatHistoryRequest, proto.ChatHistoryResponse](
+			httpClient,
+			baseURL+UserServiceGetChatHistoryProcedure,
+			connect.WithSchema(userServiceMethods.ByName("GetChatHistory")),
+			connect.WithClientOptions(opts...),
+		),
+		getAuditLog: connect.NewClient[proto.AuditLogRequest, proto.AuditLogResponse](
+			httpClient,
+			baseURL+UserServiceGetAuditLogProcedure,
+			connect.WithSchema(userServiceMethods.ByName("GetAuditLog")),
+			connect.WithClientOptions(opts...),
+		),
+		getUserHistory: connect.NewClient[proto.UserHistoryRequest, proto.UserHistoryResponse](
+			httpClient,
+			baseURL+UserServiceGetUserHistoryProcedure,
+			connect.WithSchema(userServiceMethods.ByName("GetUserHistory")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// userServiceClient implements UserServiceClient.
+type userServiceClient struct {
+	getUser           *connect.Client[proto.UserRequest, proto.UserResponse]
+	batchGetUsers     *connect.Client[proto.BatchGetUsersRequest, proto.BatchGetUsersResponse]
+	createUser        *connect.Client[proto.CreateUserRequest, proto.UserResponse]
+	validateUser      *connect.Client[proto.CreateUserRequest, proto.ValidateUserResponse]
+	updateUser        *connect.Client[proto.UpdateUserRequest, proto.UserResponse]
+	deleteUser        *connect.Client[proto.UserRequest, emptypb.Empty]
+	undeleteUser      *connect.Client[proto.UserRequest, proto.UserResponse]
+	suspendUser       *connect.Client[proto.UserRequest, proto.UserResponse]
+	activateUser      *connect.Client[proto.UserRequest, proto.UserResponse]
+	getPreferences    *connect.Client[proto.UserRequest, proto.UserPreferences]
+	updatePreferences *connect.Client[proto.UpdatePreferencesRequest, proto.UserPreferences]
+	streamUsers       *connect.Client[proto.UserFilter, proto.UserResponse]
+	exportUsers       *connect.Client[proto.ExportUsersRequest, proto.FileChunk]
+	getAvatar         *connect.Client[proto.UserRequest, proto.AvatarChunk]
+	listUsers         *connect.Client[proto.ListUsersRequest, proto.ListUsersResponse]
+	searchUsers       *connect.Client[proto.SearchUsersRequest, proto.ListUsersResponse]
+	watchUsers        *connect.Client[proto.WatchUsersRequest, proto.UserEvent]
+	createUsers       *connect.Client[proto.CreateUserRequest, proto.BulkCreateResponse]
+	bulkUpdateUsers   *connect.Client[proto.UpdateUserRequest, proto.BulkUpdateResponse]
+	importUsers       *connect.Client[proto.ImportUsersRequest, proto.ImportUsersResponse]
+	uploadAvatar      *connect.Client[proto.UploadAvatarRequest, proto.Avatar]
+	chat              *connect.Client[proto.ChatMessage, proto.ChatMessage]
+	getChatHistory    *connect.Client[proto.ChatHistoryRequest, proto.ChatHistoryResponse]
+	getAuditLog       *connect.Client[proto.AuditLogRequest, proto.AuditLogResponse]
+	getUserHistory    *connect.Client[proto.UserHistoryRequest, proto.UserHistoryResponse]
+}
+
+// GetUser calls user.UserService.GetUser.
+func (c *userServiceClient) GetUser(ctx context.Context, req *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error) {
+	return c.getUser.CallUnary(ctx, req)
+}
+
+// BatchGetUsers calls user.UserService.BatchGetUsers.
+func (c *userServiceClient) BatchGetUsers(ctx context.Context, req *connect.Request[proto.BatchGetUsersRequest]) (*connect.Response[proto.BatchGetUsersResponse], error) {
+	return c.batchGetUsers.CallUnary(ctx, req)
+}
+
+// CreateUser calls user.UserService.CreateUser.
+func (c *userServiceClient) CreateUser(ctx context.Context, req *connect.Request[proto.CreateUserRequest]) (*connect.Response[proto.UserResponse], error) {
+	return c.createUser.CallUnary(ctx, req)
+}
+
+// ValidateUser calls user.UserService.ValidateUser.
+func (c *userServiceClient) ValidateUser(ctx context.Context, req *connect.Request[proto.CreateUserRequest]) (*connect.Response[proto.ValidateUserResponse], error) {
+	return c.validateUser.CallUnary(ctx, req)
+}
+
+// UpdateUser calls user.UserService.UpdateUser.
+func (c *userServiceClient) UpdateUser(ctx context.Context, req *connect.Request[proto.UpdateUserRequest]) (*connect.Response[proto.UserResponse], error) {
+	return c.updateUser.CallUnary(ctx, req)
+}
+
+// DeleteUser calls user.UserService.DeleteUser.
+func (c *userServiceClient) DeleteUser(ctx context.Context, req *connect.Request[proto.UserRequest]) (*connect.Response[emptypb.Empty], error) {
+	return c.deleteUser.CallUnary(ctx, req)
+}
+
+// UndeleteUser calls user.UserService.UndeleteUser.
+func (c *userServiceClient) UndeleteUser(ctx context.Context, req *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error) {
+	return c.undeleteUser.CallUnary(ctx, req)
+}
+
+// SuspendUser calls user.UserService.SuspendUser.
+func (c *userServiceClient) SuspendUser(ctx context.Context, req *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error) {
+	return c.suspendUser.CallUnary(ctx, req)
+}
+
+// ActivateUser calls user.UserService.ActivateUser.
+func (c *userServiceClient) ActivateUser(ctx context.Context, req *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error) {
+	return c.activateUser.CallUnary(ctx, req)
+}
+
+// GetPreferences calls user.UserService.GetPreferences.
+func (c *userServiceClient) GetPreferences(ctx context.Context, req *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserPreferences], error) {
+	return c.getPreferences.CallUnary(ctx, req)
+}
+
+// UpdatePreferences calls user.UserService.UpdatePreferences.
+func (c *userServiceClient) UpdatePreferences(ctx context.Context, req *connect.Request[proto.UpdatePreferencesRequest]) (*connect.Response[proto.UserPreferences], error) {
+	return c.updatePreferences.CallUnary(ctx, req)
+}
+
+// StreamUsers calls user.UserService.StreamUsers.
+func (c *userServiceClient) StreamUsers(ctx context.Context, req *connect.Request[proto.UserFilter]) (*connect.ServerStreamForClient[proto.UserResponse], error) {
+	return c.streamUsers.CallServerStream(ctx, req)
+}
+
+// ExportUsers calls user.UserService.ExportUsers.
+func (c *userServiceClient) ExportUsers(ctx context.Context, req *connect.Request[proto.ExportUsersRequest]) (*connect.ServerStreamForClient[proto.FileChunk], error) {
+	return c.exportUsers.CallServerStream(ctx, req)
+}
+
+// GetAvatar calls user.UserService.GetAvatar.
+func (c *userServiceClient) GetAvatar(ctx context.Context, req *connect.Request[proto.UserRequest]) (*connect.ServerStreamForClient[proto.AvatarChunk], error) {
+	return c.getAvatar.CallServerStream(ctx, req)
+}
+
+// ListUsers calls user.UserService.ListUsers.
+func (c *userServiceClient) ListUsers(ctx context.Context, req *connect.Request[proto.ListUsersRequest]) (*connect.Response[proto.ListUsersResponse], error) {
+	return c.listUsers.CallUnary(ctx, req)
+}
+
+// SearchUsers calls user.UserService.SearchUsers.
+func (c *userServiceClient) SearchUsers(ctx context.Context, req *connect.Request[proto.SearchUsersRequest]) (*connect.Response[proto.ListUsersResponse], error) {
+	return c.searchUsers.CallUnary(ctx, req)
+}
+
+// WatchUsers calls user.UserService.WatchUsers.
+func (c *userServiceClient) WatchUsers(ctx context.Context, req *connect.Request[proto.WatchUsersRequest]) (*connect.ServerStreamForClient[proto.UserEvent], error) {
+	return c.watchUsers.CallServerStream(ctx, req)
+}
+
+// CreateUsers calls user.UserService.CreateUsers.
+func (c *userServiceClient) CreateUsers(ctx context.Context) *connect.ClientStreamForClient[proto.CreateUserRequest, proto.BulkCreateResponse] {
+	return c.createUsers.CallClientStream(ctx)
+}
+
+// BulkUpdateUsers calls user.UserService.BulkUpdateUsers.
+func (c *userServiceClient) BulkUpdateUsers(ctx context.Context) *connect.ClientStreamForClient[proto.UpdateUserRequest, proto.BulkUpdateResponse] {
+	return c.bulkUpdateUsers.CallClientStream(ctx)
+}
+
+// ImportUsers calls user.UserService.ImportUsers.
+func (c *userServiceClient) ImportUsers(ctx context.Context) *connect.ClientStreamForClient[proto.ImportUsersRequest, proto.ImportUsersResponse] {
+	return c.importUsers.CallClientStream(ctx)
+}
+
+// UploadAvatar calls user.UserService.UploadAvatar.
+func (c *userServiceClient) UploadAvatar(ctx context.Context) *connect.ClientStreamForClient[proto.UploadAvatarRequest, proto.Avatar] {
+	return c.uploadAvatar.CallClientStream(ctx)
+}
+
+// Chat calls user.UserService.Chat.
+func (c *userServiceClient) Chat(ctx context.Context) *connect.BidiStreamForClient[proto.ChatMessage, proto.ChatMessage] {
+	return c.chat.CallBidiStream(ctx)
+}
+
+// GetChatHistory calls user.UserService.GetChatHistory.
+func (c *userServiceClient) GetChatHistory(ctx context.Context, req *connect.Request[proto.ChatHistoryRequest]) (*connect.Response[proto.ChatHistoryResponse], error) {
+	return c.getChatHistory.CallUnary(ctx, req)
+}
+
+// GetAuditLog calls user.UserService.GetAuditLog.
+func (c *userServiceClient) GetAuditLog(ctx context.Context, req *connect.Request[proto.AuditLogRequest]) (*connect.Response[proto.AuditLogResponse], error) {
+	return c.getAuditLog.CallUnary(ctx, req)
+}
+
+// GetUserHistory calls user.UserService.GetUserHistory.
+func (c *userServiceClient) GetUserHistory(ctx context.Context, req *connect.Request[proto.UserHistoryRequest]) (*connect.Response[proto.UserHistoryResponse], error) {
+	return c.getUserHistory.CallUnary(ctx, req)
+}
+
+// UserServiceHandler is an implementation of the user.UserService service.
+type UserServiceHandler interface {
+	// Simple request-response
+	GetUser(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error)
+	// Several users by ID in one call; IDs without a user are listed rather than failing it
+	BatchGetUsers(context.Context, *connect.Request[proto.BatchGetUsersRequest]) (*connect.Response[proto.BatchGetUsersResponse], error)
+	// Create user
+	CreateUser(context.Context, *connect.Request[proto.CreateUserRequest]) (*connect.Response[proto.UserResponse], error)
+	// Checks a new user as CreateUser would, email uniqueness included, without creating
+	// it; what is wrong is listed in the response rather than failing the call
+	ValidateUser(context.Context, *connect.Request[proto.CreateUserRequest]) (*connect.Response[proto.ValidateUserResponse], error)
+	// Update user
+	UpdateUser(context.Context, *connect.Request[proto.UpdateUserRequest]) (*connect.Response[proto.UserResponse], error)
+	// Delete user (soft delete - the user can be restored with UndeleteUser)
+	DeleteUser(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[emptypb.Empty], error)
+	// Restore a soft-deleted user
+	UndeleteUser(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error)
+	// Suspend a user: the account is kept but can no longer authenticate
+	SuspendUser(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error)
+	// Reactivate a suspended user
+	ActivateUser(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error)
+	// Settings a user keeps for themselves; defaults until first updated
+	GetPreferences(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserPreferences], error)
+	UpdatePreferences(context.Context, *connect.Request[proto.UpdatePreferencesRequest]) (*connect.Response[proto.UserPreferences], error)
+	// Server-side streaming - user list
+	StreamUsers(context.Context, *connect.Request[proto.UserFilter], *connect.ServerStream[proto.UserResponse]) error
+	// The users matching a filter as a file, streamed in chunks
+	ExportUsers(context.Context, *connect.Request[proto.ExportUsersRequest], *connect.ServerStream[proto.FileChunk]) error
+	// Server-side streaming - the avatar of a user, in chunks
+	GetAvatar(context.Context, *connect.Request[proto.UserRequest], *connect.ServerStream[proto.AvatarChunk]) error
+	// One page of the user list, for callers that want a page rather than a stream
+	ListUsers(context.Context, *connect.Request[proto.ListUsersRequest]) (*connect.Response[proto.ListUsersResponse], error)
+	// One page of the users matching a query such as `role:admin created>2024-01-01 name~john`
+	SearchUsers(context.Context, *connect.Request[proto.SearchUsersRequest]) (*connect.Response[proto.ListUsersResponse], error)
+	// Server-side streaming - changes to users as they are committed, for keeping local
+	// copies in sync. Only changes made through this server are seen.
+	WatchUsers(context.Context, *connect.Request[proto.WatchUsersRequest], *connect.ServerStream[proto.UserEvent]) error
+	// Client-side streaming - bulk user creation
+	CreateUsers(context.Context, *connect.ClientStream[proto.CreateUserRequest]) (*connect.Response[proto.BulkCreateResponse], error)
+	// Client-side streaming - bulk partial updates, each applied on its own
+	BulkUpdateUsers(context.Context, *connect.ClientStream[proto.UpdateUserRequest]) (*connect.Response[proto.BulkUpdateResponse], error)
+	// Client-side streaming - a file of users, as ExportUsers writes, created atomically
+	ImportUsers(context.Context, *connect.ClientStream[proto.ImportUsersRequest]) (*connect.Response[proto.ImportUsersResponse], error)
+	// Client-side streaming - an image replacing the avatar of a user
+	UploadAvatar(context.Context, *connect.ClientStream[proto.UploadAvatarRequest]) (*connect.Response[proto.Avatar], error)
+	// Bidirectional streaming - real-time messaging: messages reach the user they are to,
+	// the other members of their room or every other stream, and JOIN and LEAVE messages
+	// enter and leave rooms. Rooms scope who is sent a message but aren't private: any
+	// caller may join any room, and its members are told when one does.
+	Chat(context.Context, *connect.BidiStream[proto.ChatMessage, proto.ChatMessage]) error
+	// Chat messages sent to a room, between two users or to everyone, newest last, so
+	// clients can backfill what they missed
+	GetChatHistory(context.Context, *connect.Request[proto.ChatHistoryRequest]) (*connect.Response[proto.ChatHistoryResponse], error)
+	// Audit trail of user changes, newest first (admin only)
+	GetAuditLog(context.Context, *connect.Request[proto.AuditLogRequest]) (*connect.Response[proto.AuditLogResponse], error)
+	// Changes made to one user, newest first, from the audit log (admin only)
+	GetUserHistory(context.Context, *connect.Request[proto.UserHistoryRequest]) (*connect.Response[proto.UserHistoryResponse], error)
+}
+
+// NewUserServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewUserServiceHandler(svc UserServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	userServiceMethods := proto.File_proto_user_proto.Services().ByName("UserService").Methods()
+	userServiceGetUserHandler := connect.NewUnaryHandler(
+		UserServiceGetUserProcedure,
+		svc.GetUser,
+		connect.WithSchema(userServiceMethods.ByName("GetUser")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceBatchGetUsersHandler := connect.NewUnaryHandler(
+		UserServiceBatchGetUsersProcedure,
+		svc.BatchGetUsers,
+		connect.WithSchema(userServiceMethods.ByName("BatchGetUsers")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceCreateUserHandler := connect.NewUnaryHandler(
+		UserServiceCreateUserProcedure,
+		svc.CreateUser,
+		connect.WithSchema(userServiceMethods.ByName("CreateUser")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceValidateUserHandler := connect.NewUnaryHandler(
+		UserServiceValidateUserProcedure,
+		svc.ValidateUser,
+		connect.WithSchema(userServiceMethods.ByName("ValidateUser")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceUpdateUserHandler := connect.NewUnaryHandler(
+		UserServiceUpdateUserProcedure,
+		svc.UpdateUser,
+		connect.WithSchema(userServiceMethods.ByName("UpdateUser")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceDeleteUserHandler := connect.NewUnaryHandler(
+		UserServiceDeleteUserProcedure,
+		svc.DeleteUser,
+		connect.WithSchema(userServiceMethods.ByName("DeleteUser")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceUndeleteUserHandler := connect.NewUnaryHandler(
+		UserServiceUndeleteUserProcedure,
+		svc.UndeleteUser,
+		connect.WithSchema(userServiceMethods.ByName("UndeleteUser")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceSuspendUserHandler := connect.NewUnaryHandler(
+		UserServiceSuspendUserProcedure,
+		svc.SuspendUser,
+		connect.WithSchema(userServiceMethods.ByName("SuspendUser")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceActivateUserHandler := connect.NewUnaryHandler(
+		UserServiceActivateUserProcedure,
+		svc.ActivateUser,
+		connect.WithSchema(userServiceMethods.ByName("ActivateUser")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceGetPreferencesHandler := connect.NewUnaryHandler(
+		UserServiceGetPreferencesProcedure,
+		svc.GetPreferences,
+		connect.WithSchema(userServiceMethods.ByName("GetPreferences")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceUpdatePreferencesHandler := connect.NewUnaryHandler(
+		UserServiceUpdatePreferencesProcedure,
+		svc.UpdatePreferences,
+		connect.WithSchema(userServiceMethods.ByName("UpdatePreferences")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceStreamUsersHandler := connect.NewServerStreamHandler(
+		UserServiceStreamUsersProcedure,
+		svc.StreamUsers,
+		connect.WithSchema(userServiceMethods.ByName("StreamUsers")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceExportUsersHandler := connect.NewServerStreamHandler(
+		UserServiceExportUsersProcedure,
+		svc.ExportUsers,
+		connect.WithSchema(userServiceMethods.ByName("ExportUsers")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceGetAvatarHandler := connect.NewServerStreamHandler(
+		UserServiceGetAvatarProcedure,
+		svc.GetAvatar,
+		connect.WithSchema(userServiceMethods.ByName("GetAvatar")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceListUsersHandler := connect.NewUnaryHandler(
+		UserServiceListUsersProcedure,
+		svc.ListUsers,
+		connect.WithSchema(userServiceMethods.ByName("ListUsers")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceSearchUsersHandler := connect.NewUnaryHandler(
+		UserServiceSearchUsersProcedure,
+		svc.SearchUsers,
+		connect.WithSchema(userServiceMethods.ByName("SearchUsers")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceWatchUsersHandler := connect.NewServerStreamHandler(
+		UserServiceWatchUsersProcedure,
+		svc.WatchUsers,
+		connect.WithSchema(userServiceMethods.ByName("WatchUsers")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceCreateUsersHandler := connect.NewClientStreamHandler(
+		UserServiceCreateUsersProcedure,
+		svc.CreateUsers,
+		connect.WithSchema(userServiceMethods.ByName("CreateUsers")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceBulkUpdateUsersHandler := connect.NewClientStreamHandler(
+		UserServiceBulkUpdateUsersProcedure,
+		svc.BulkUpdateUsers,
+		connect.WithSchema(userServiceMethods.ByName("BulkUpdateUsers")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceImportUsersHandler := connect.NewClientStreamHandler(
+		UserServiceImportUsersProcedure,
+		svc.ImportUsers,
+		connect.WithSchema(userServiceMethods.ByName("ImportUsers")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceUploadAvatarHandler := connect.NewClientStreamHandler(
+		UserServiceUploadAvatarProcedure,
+		svc.UploadAvatar,
+		connect.WithSchema(userServiceMethods.ByName("UploadAvatar")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceChatHandler := connect.NewBidiStreamHandler(
+		UserServiceChatProcedure,
+		svc.Chat,
+		connect.WithSchema(userServiceMethods.ByName("Chat")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceGetChatHistoryHandler := connect.NewUnaryHandler(
+		UserServiceGetChatHistoryProcedure,
+		svc.GetChatHistory,
+		connect.WithSchema(userServiceMethods.ByName("GetChatHistory")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceGetAuditLogHandler := connect.NewUnaryHandler(
+		UserServiceGetAuditLogProcedure,
+		svc.GetAuditLog,
+		connect.WithSchema(userServiceMethods.ByName("GetAuditLog")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceGetUserHistoryHandler := connect.NewUnaryHandler(
+		UserServiceGetUserHistoryProcedure,
+		svc.GetUserHistory,
+		connect.WithSchema(userServiceMethods.ByName("GetUserHistory")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/user.UserService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case UserServiceGetUserProcedure:
+			userServiceGetUserHandler.ServeHTTP(w, r)
+		case UserServiceBatchGetUsersProcedure:
+			userServiceBatchGetUsersHandler.ServeHTTP(w, r)
+		case UserServiceCreateUserProcedure:
+			userServiceCreateUserHandler.ServeHTTP(w, r)
+		case UserServiceValidateUserProcedure:
+			userServiceValidateUserHandler.ServeHTTP(w, r)
+		case UserServiceUpdateUserProcedure:
+			userServiceUpdateUserHandler.ServeHTTP(w, r)
+		case UserServiceDeleteUserProcedure:
+			userServiceDeleteUserHandler.ServeHTTP(w, r)
+		case UserServiceUndeleteUserProcedure:
+			userServiceUndeleteUserHandler.ServeHTTP(w, r)
+		case UserServiceSuspendUserProcedure:
+			userServiceSuspendUserHandler.ServeHTTP(w, r)
+		case UserServiceActivateUserProcedure:
+			userServiceActivateUserHandler.ServeHTTP(w, r)
+		case UserServiceGetPreferencesProcedure:
+			userServiceGetPreferencesHandler.ServeHTTP(w, r)
+		case UserServiceUpdatePreferencesProcedure:
+			userServiceUpdatePreferencesHandler.ServeHTTP(w, r)
+		case UserServiceStreamUsersProcedure:
+			userServiceStreamUsersHandler.ServeHTTP(w, r)
+		case UserServiceExportUsersProcedure:
+			userServiceExportUsersHandler.ServeHTTP(w, r)
+		case UserServiceGetAvatarProcedure:
+			userServiceGetAvatarHandler.ServeHTTP(w, r)
+		case UserServiceListUsersProcedure:
+			userServiceListUsersHandler.ServeHTTP(w, r)
+		case UserServiceSearchUsersProcedure:
+			userServiceSearchUsersHandler.ServeHTTP(w, r)
+		case UserServiceWatchUsersProcedure:
+			userServiceWatchUsersHandler.ServeHTTP(w, r)
+		case UserServiceCreateUsersProcedure:
+			userServiceCreateUsersHandler.ServeHTTP(w, r)
+		case UserServiceBulkUpdateUsersProcedure:
+			userServiceBulkUpdateUsersHandler.ServeHTTP(w, r)
+		case UserServiceImportUsersProcedure:
+			userServiceImportUsersHandler.ServeHTTP(w, r)
+		case UserServiceUploadAvatarProcedure:
+			userServiceUploadAvatarHandler.ServeHTTP(w, r)
+		case UserServiceChatProcedure:
+			userServiceChatHandler.ServeHTTP(w, r)
+		case UserServiceGetChatHistoryProcedure:
+			userServiceGetChatHistoryHandler.ServeHTTP(w, r)
+		case UserServiceGetAuditLogProcedure:
+			userServiceGetAuditLogHandler.ServeHTTP(w, r)
+		case UserServiceGetUserHistoryProcedure:
+			userServiceGetUserHistoryHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedUserServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedUserServiceHandler struct{}
+
+func (UnimplementedUserServiceHandler) GetUser(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.GetUser is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) BatchGetUsers(context.Context, *connect.Request[proto.BatchGetUsersRequest]) (*connect.Response[proto.BatchGetUsersResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.BatchGetUsers is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) CreateUser(context.Context, *connect.Request[proto.CreateUserRequest]) (*connect.Response[proto.UserResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.CreateUser is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) ValidateUser(context.Context, *connect.Request[proto.CreateUserRequest]) (*connect.Response[proto.ValidateUserResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.ValidateUser is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) UpdateUser(context.Context, *connect.Request[proto.UpdateUserRequest]) (*connect.Response[proto.UserResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.UpdateUser is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) DeleteUser(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[emptypb.Empty], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.DeleteUser is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) UndeleteUser(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.UndeleteUser is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) SuspendUser(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.SuspendUser is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) ActivateUser(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.ActivateUser is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) GetPreferences(context.Context, *connect.Request[proto.UserRequest]) (*connect.Response[proto.UserPreferences], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.GetPreferences is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) UpdatePreferences(context.Context, *connect.Request[proto.UpdatePreferencesRequest]) (*connect.Response[proto.UserPreferences], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.UpdatePreferences is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) StreamUsers(context.Context, *connect.Request[proto.UserFilter], *connect.ServerStream[proto.UserResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.StreamUsers is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) ExportUsers(context.Context, *connect.Request[proto.ExportUsersRequest], *connect.ServerStream[proto.FileChunk]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.ExportUsers is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) GetAvatar(context.Context, *connect.Request[proto.UserRequest], *connect.ServerStream[proto.AvatarChunk]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.GetAvatar is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) ListUsers(context.Context, *connect.Request[proto.ListUsersRequest]) (*connect.Response[proto.ListUsersResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.ListUsers is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) SearchUsers(context.Context, *connect.Request[proto.SearchUsersRequest]) (*connect.Response[proto.ListUsersResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.SearchUsers is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) WatchUsers(context.Context, *connect.Request[proto.WatchUsersRequest], *connect.ServerStream[proto.UserEvent]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.WatchUsers is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) CreateUsers(context.Context, *connect.ClientStream[proto.CreateUserRequest]) (*connect.Response[proto.BulkCreateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.CreateUsers is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) BulkUpdateUsers(context.Context, *connect.ClientStream[proto.UpdateUserRequest]) (*connect.Response[proto.BulkUpdateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.BulkUpdateUsers is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) ImportUsers(context.Context, *connect.ClientStream[proto.ImportUsersRequest]) (*connect.Response[proto.ImportUsersResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.ImportUsers is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) UploadAvatar(context.Context, *connect.ClientStream[proto.UploadAvatarRequest]) (*connect.Response[proto.Avatar], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.UploadAvatar is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) Chat(context.Context, *connect.BidiStream[proto.ChatMessage, proto.ChatMessage]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.Chat is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) GetChatHistory(context.Context, *connect.Request[proto.ChatHistoryRequest]) (*connect.Response[proto.ChatHistoryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.GetChatHistory is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) GetAuditLog(context.Context, *connect.Request[proto.AuditLogRequest]) (*connect.Response[proto.AuditLogResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.GetAuditLog is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) GetUserHistory(context.Context, *connect.Request[proto.UserHistoryRequest]) (*connect.Response[proto.UserHistoryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserService.GetUserHistory is not implemented"))
+}
+
+// AuthServiceClient is a client for the user.AuthService service.
+type AuthServiceClient interface {
+	// Start a session for the authenticated caller (e.g. an API key or long-lived token)
+	IssueTokens(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[proto.TokenResponse], error)
+	// Exchange a refresh token for a new access token and refresh token. Each refresh
+	// token can be used once; reusing one revokes the whole session.
+	RefreshToken(context.Context, *connect.Request[proto.RefreshTokenRequest]) (*connect.Response[proto.TokenResponse], error)
+}
+
+// NewAuthServiceClient constructs a client for the user.AuthService service. By default, it uses
+// the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewAuthServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) AuthServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	authServiceMethods := proto.File_proto_user_proto.Services().ByName("AuthService").Methods()
+	return &authServiceClient{
+		issueTokens: connect.NewClient[emptypb.Empty, proto.TokenResponse](
+			httpClient,
+			baseURL+AuthServiceIssueTokensProcedure,
+			connect.WithSchema(authServiceMethods.ByName("IssueTokens")),
+			connect.WithClientOptions(opts...),
+		),
+		refreshToken: connect.NewClient[proto.RefreshTokenRequest, proto.TokenResponse](
+			httpClient,
+			baseURL+AuthServiceRefreshTokenProcedure,
+			connect.WithSchema(authServiceMethods.ByName("RefreshToken")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// authServiceClient implements AuthServiceClient.
+type authServiceClient struct {
+	issueTokens  *connect.Client[emptypb.Empty, proto.TokenResponse]
+	refreshToken *connect.Client[proto.RefreshTokenRequest, proto.TokenResponse]
+}
+
+// IssueTokens calls user.AuthService.IssueTokens.
+func (c *authServiceClient) IssueTokens(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[proto.TokenResponse], error) {
+	return c.issueTokens.CallUnary(ctx, req)
+}
+
+// RefreshToken calls user.AuthService.RefreshToken.
+func (c *authServiceClient) RefreshToken(ctx context.Context, req *connect.Request[proto.RefreshTokenRequest]) (*connect.Response[proto.TokenResponse], error) {
+	return c.refreshToken.CallUnary(ctx, req)
+}
+
+// AuthServiceHandler is an implementation of the user.AuthService service.
+type AuthServiceHandler interface {
+	// Start a session for the authenticated caller (e.g. an API key or long-lived token)
+	IssueTokens(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[proto.TokenResponse], error)
+	// Exchange a refresh token for a new access token and refresh token. Each refresh
+	// token can be used once; reusing one revokes the whole session.
+	RefreshToken(context.Context, *connect.Request[proto.RefreshTokenRequest]) (*connect.Response[proto.TokenResponse], error)
+}
+
+// NewAuthServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewAuthServiceHandler(svc AuthServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	authServiceMethods := proto.File_proto_user_proto.Services().ByName("AuthService").Methods()
+	authServiceIssueTokensHandler := connect.NewUnaryHandler(
+		AuthServiceIssueTokensProcedure,
+		svc.IssueTokens,
+		connect.WithSchema(authServiceMethods.ByName("IssueTokens")),
+		connect.WithHandlerOptions(opts...),
+	)
+	authServiceRefreshTokenHandler := connect.NewUnaryHandler(
+		AuthServiceRefreshTokenProcedure,
+		svc.RefreshToken,
+		connect.WithSchema(authServiceMethods.ByName("RefreshToken")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/user.AuthService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AuthServiceIssueTokensProcedure:
+			authServiceIssueTokensHandler.ServeHTTP(w, r)
+		case AuthServiceRefreshTokenProcedure:
+			authServiceRefreshTokenHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedAuthServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedAuthServiceHandler struct{}
+
+func (UnimplementedAuthServiceHandler) IssueTokens(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[proto.TokenResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.AuthService.IssueTokens is not implemented"))
+}
+
+func (UnimplementedAuthServiceHandler) RefreshToken(context.Context, *connect.Request[proto.RefreshTokenRequest]) (*connect.Response[proto.TokenResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.AuthService.RefreshToken is not implemented"))
+}
+
+// AdminServiceClient is a client for the user.AdminService service.
+type AdminServiceClient interface {
+	// Report the server's minimum log level
+	GetLogLevel(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[proto.LogLevelResponse], error)
+	// Change the minimum log level without restarting, e.g. to debug during an incident.
+	// With a duration the previous level comes back once it has elapsed.
+	SetLogLevel(context.Context, *connect.Request[proto.SetLogLevelRequest]) (*connect.Response[proto.LogLevelResponse], error)
+	// Report whether the server is in maintenance mode
+	GetMaintenance(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[proto.MaintenanceResponse], error)
+	// Turn maintenance mode on or off, e.g. to drain a replica before a rollout. While it is
+	// on, new calls fail with UNAVAILABLE and a google.rpc.RetryInfo detail, and readiness
+	// reports NOT_SERVING; calls in flight, AdminService and health checks are unaffected.
+	SetMaintenance(context.Context, *connect.Request[proto.SetMaintenanceRequest]) (*connect.Response[proto.MaintenanceResponse], error)
+}
+
+// NewAdminServiceClient constructs a client for the user.AdminService service. By default, it uses
+// the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewAdminServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) AdminServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	adminServiceMethods := proto.File_proto_user_proto.Services().ByName("AdminService").Methods()
+	return &adminServiceClient{
+		getLogLevel: connect.NewClient[emptypb.Empty, proto.LogLevelResponse](
+			httpClient,
+			baseURL+AdminServiceGetLogLevelProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("GetLogLevel")),
+			connect.WithClientOptions(opts...),
+		),
+		setLogLevel: connect.NewClient[proto.SetLogLevelRequest, proto.LogLevelResponse](
+			httpClient,
+			baseURL+AdminServiceSetLogLevelProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("SetLogLevel")),
+			connect.WithClientOptions(opts...),
+		),
+		getMaintenance: connect.NewClient[emptypb.Empty, proto.MaintenanceResponse](
+			httpClient,
+			baseURL+AdminServiceGetMaintenanceProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("GetMaintenance")),
+			connect.WithClientOptions(opts...),
+		),
+		setMaintenance: connect.NewClient[proto.SetMaintenanceRequest, proto.MaintenanceResponse](
+			httpClient,
+			baseURL+AdminServiceSetMaintenanceProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("SetMaintenance")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// adminServiceClient implements AdminServiceClient.
+type adminServiceClient struct {
+	getLogLevel    *connect.Client[emptypb.Empty, proto.LogLevelResponse]
+	setLogLevel    *connect.Client[proto.SetLogLevelRequest, proto.LogLevelResponse]
+	getMaintenance *connect.Client[emptypb.Empty, proto.MaintenanceResponse]
+	setMaintenance *connect.Client[proto.SetMaintenanceRequest, proto.MaintenanceResponse]
+}
+
+// GetLogLevel calls user.AdminService.GetLogLevel.
+func (c *adminServiceClient) GetLogLevel(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[proto.LogLevelResponse], error) {
+	return c.getLogLevel.CallUnary(ctx, req)
+}
+
+// SetLogLevel calls user.AdminService.SetLogLevel.
+func (c *adminServiceClient) SetLogLevel(ctx context.Context, req *connect.Request[proto.SetLogLevelRequest]) (*connect.Response[proto.LogLevelResponse], error) {
+	return c.setLogLevel.CallUnary(ctx, req)
+}
+
+// GetMaintenance calls user.AdminService.GetMaintenance.
+func (c *adminServiceClient) GetMaintenance(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[proto.MaintenanceResponse], error) {
+	return c.getMaintenance.CallUnary(ctx, req)
+}
+
+// SetMaintenance calls user.AdminService.SetMaintenance.
+func (c *adminServiceClient) SetMaintenance(ctx context.Context, req *connect.Request[proto.SetMaintenanceRequest]) (*connect.Response[proto.MaintenanceResponse], error) {
+	return c.setMaintenance.CallUnary(ctx, req)
+}
+
+// AdminServiceHandler is an implementation of the user.AdminService service.
+type AdminServiceHandler interface {
+	// Report the server's minimum log level
+	GetLogLevel(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[proto.LogLevelResponse], error)
+	// Change the minimum log level without restarting, e.g. to debug during an incident.
+	// With a duration the previous level comes back once it has elapsed.
+	SetLogLevel(context.Context, *connect.Request[proto.SetLogLevelRequest]) (*connect.Response[proto.LogLevelResponse], error)
+	// Report whether the server is in maintenance mode
+	GetMaintenance(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[proto.MaintenanceResponse], error)
+	// Turn maintenance mode on or off, e.g. to drain a replica before a rollout. While it is
+	// on, new calls fail with UNAVAILABLE and a google.rpc.RetryInfo detail, and readiness
+	// reports NOT_SERVING; calls in flight, AdminService and health checks are unaffected.
+	SetMaintenance(context.Context, *connect.Request[proto.SetMaintenanceRequest]) (*connect.Response[proto.MaintenanceResponse], error)
+}
+
+// NewAdminServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewAdminServiceHandler(svc AdminServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	adminServiceMethods := proto.File_proto_user_proto.Services().ByName("AdminService").Methods()
+	adminServiceGetLogLevelHandler := connect.NewUnaryHandler(
+		AdminServiceGetLogLevelProcedure,
+		svc.GetLogLevel,
+		connect.WithSchema(adminServiceMethods.ByName("GetLogLevel")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceSetLogLevelHandler := connect.NewUnaryHandler(
+		AdminServiceSetLogLevelProcedure,
+		svc.SetLogLevel,
+		connect.WithSchema(adminServiceMethods.ByName("SetLogLevel")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceGetMaintenanceHandler := connect.NewUnaryHandler(
+		AdminServiceGetMaintenanceProcedure,
+		svc.GetMaintenance,
+		connect.WithSchema(adminServiceMethods.ByName("GetMaintenance")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceSetMaintenanceHandler := connect.NewUnaryHandler(
+		AdminServiceSetMaintenanceProcedure,
+		svc.SetMaintenance,
+		connect.WithSchema(adminServiceMethods.ByName("SetMaintenance")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/user.AdminService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AdminServiceGetLogLevelProcedure:
+			adminServiceGetLogLevelHandler.ServeHTTP(w, r)
+		case AdminServiceSetLogLevelProcedure:
+			adminServiceSetLogLevelHandler.ServeHTTP(w, r)
+		case AdminServiceGetMaintenanceProcedure:
+			adminServiceGetMaintenanceHandler.ServeHTTP(w, r)
+		case AdminServiceSetMaintenanceProcedure:
+			adminServiceSetMaintenanceHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedAdminServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedAdminServiceHandler struct{}
+
+func (UnimplementedAdminServiceHandler) GetLogLevel(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[proto.LogLevelResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.AdminService.GetLogLevel is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) SetLogLevel(context.Context, *connect.Request[proto.SetLogLevelRequest]) (*connect.Response[proto.LogLevelResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.AdminService.SetLogLevel is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) GetMaintenance(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[proto.MaintenanceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.AdminService.GetMaintenance is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) SetMaintenance(context.Context, *connect.Request[proto.SetMaintenanceRequest]) (*connect.Response[proto.MaintenanceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.AdminService.SetMaintenance is not implemented"))
+}
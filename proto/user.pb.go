@@ -7,9 +7,12 @@
 package proto
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -23,6 +26,222 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Role of a user. Requests may still give the role by name in the string role field
+// alone, as older clients do: "user" and "admin" map onto their values, anything else
+// onto ROLE_CUSTOM.
+type Role int32
+
+const (
+	Role_ROLE_UNKNOWN Role = 0 // Taken from the role name, "user" when that is empty
+	Role_ROLE_USER    Role = 1
+	Role_ROLE_ADMIN   Role = 2
+	// A role of the deployment's own, named in the role field with up to 32 lowercase letters,
+	// digits, "-" and "_", starting with a letter; custom roles have no admin rights
+	Role_ROLE_CUSTOM Role = 3
+)
+
+// Enum value maps for Role.
+var (
+	Role_name = map[int32]string{
+		0: "ROLE_UNKNOWN",
+		1: "ROLE_USER",
+		2: "ROLE_ADMIN",
+		3: "ROLE_CUSTOM",
+	}
+	Role_value = map[string]int32{
+		"ROLE_UNKNOWN": 0,
+		"ROLE_USER":    1,
+		"ROLE_ADMIN":   2,
+		"ROLE_CUSTOM":  3,
+	}
+)
+
+func (x Role) Enum() *Role {
+	p := new(Role)
+	*p = x
+	return p
+}
+
+func (x Role) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Role) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_user_proto_enumTypes[0].Descriptor()
+}
+
+func (Role) Type() protoreflect.EnumType {
+	return &file_proto_user_proto_enumTypes[0]
+}
+
+func (x Role) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Role.Descriptor instead.
+func (Role) EnumDescriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{0}
+}
+
+type UserStatus int32
+
+const (
+	UserStatus_USER_STATUS_UNKNOWN   UserStatus = 0
+	UserStatus_USER_STATUS_ACTIVE    UserStatus = 1
+	UserStatus_USER_STATUS_SUSPENDED UserStatus = 2 // Kept, but rejected when authenticating
+)
+
+// Enum value maps for UserStatus.
+var (
+	UserStatus_name = map[int32]string{
+		0: "USER_STATUS_UNKNOWN",
+		1: "USER_STATUS_ACTIVE",
+		2: "USER_STATUS_SUSPENDED",
+	}
+	UserStatus_value = map[string]int32{
+		"USER_STATUS_UNKNOWN":   0,
+		"USER_STATUS_ACTIVE":    1,
+		"USER_STATUS_SUSPENDED": 2,
+	}
+)
+
+func (x UserStatus) Enum() *UserStatus {
+	p := new(UserStatus)
+	*p = x
+	return p
+}
+
+func (x UserStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UserStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_user_proto_enumTypes[1].Descriptor()
+}
+
+func (UserStatus) Type() protoreflect.EnumType {
+	return &file_proto_user_proto_enumTypes[1]
+}
+
+func (x UserStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UserStatus.Descriptor instead.
+func (UserStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{1}
+}
+
+// Formats of user files. CSV has a header row naming the columns id, name, email, role,
+// status, created_at, updated_at, version and deleted_at, with RFC 3339 times; NDJSON holds
+// one UserResponse per line and JSON an array of them, in their JSON form.
+type FileFormat int32
+
+const (
+	FileFormat_FILE_FORMAT_UNKNOWN FileFormat = 0
+	FileFormat_FILE_FORMAT_CSV     FileFormat = 1
+	FileFormat_FILE_FORMAT_NDJSON  FileFormat = 2
+	FileFormat_FILE_FORMAT_JSON    FileFormat = 3
+)
+
+// Enum value maps for FileFormat.
+var (
+	FileFormat_name = map[int32]string{
+		0: "FILE_FORMAT_UNKNOWN",
+		1: "FILE_FORMAT_CSV",
+		2: "FILE_FORMAT_NDJSON",
+		3: "FILE_FORMAT_JSON",
+	}
+	FileFormat_value = map[string]int32{
+		"FILE_FORMAT_UNKNOWN": 0,
+		"FILE_FORMAT_CSV":     1,
+		"FILE_FORMAT_NDJSON":  2,
+		"FILE_FORMAT_JSON":    3,
+	}
+)
+
+func (x FileFormat) Enum() *FileFormat {
+	p := new(FileFormat)
+	*p = x
+	return p
+}
+
+func (x FileFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FileFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_user_proto_enumTypes[2].Descriptor()
+}
+
+func (FileFormat) Type() protoreflect.EnumType {
+	return &file_proto_user_proto_enumTypes[2]
+}
+
+func (x FileFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FileFormat.Descriptor instead.
+func (FileFormat) EnumDescriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{2}
+}
+
+type UserEventType int32
+
+const (
+	UserEventType_USER_EVENT_TYPE_UNKNOWN   UserEventType = 0
+	UserEventType_USER_EVENT_TYPE_CREATED   UserEventType = 1
+	UserEventType_USER_EVENT_TYPE_UPDATED   UserEventType = 2
+	UserEventType_USER_EVENT_TYPE_DELETED   UserEventType = 3
+	UserEventType_USER_EVENT_TYPE_UNDELETED UserEventType = 4
+)
+
+// Enum value maps for UserEventType.
+var (
+	UserEventType_name = map[int32]string{
+		0: "USER_EVENT_TYPE_UNKNOWN",
+		1: "USER_EVENT_TYPE_CREATED",
+		2: "USER_EVENT_TYPE_UPDATED",
+		3: "USER_EVENT_TYPE_DELETED",
+		4: "USER_EVENT_TYPE_UNDELETED",
+	}
+	UserEventType_value = map[string]int32{
+		"USER_EVENT_TYPE_UNKNOWN":   0,
+		"USER_EVENT_TYPE_CREATED":   1,
+		"USER_EVENT_TYPE_UPDATED":   2,
+		"USER_EVENT_TYPE_DELETED":   3,
+		"USER_EVENT_TYPE_UNDELETED": 4,
+	}
+)
+
+func (x UserEventType) Enum() *UserEventType {
+	p := new(UserEventType)
+	*p = x
+	return p
+}
+
+func (x UserEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UserEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_user_proto_enumTypes[3].Descriptor()
+}
+
+func (UserEventType) Type() protoreflect.EnumType {
+	return &file_proto_user_proto_enumTypes[3]
+}
+
+func (x UserEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UserEventType.Descriptor instead.
+func (UserEventType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{3}
+}
+
 type MessageType int32
 
 const (
@@ -30,6 +249,8 @@ const (
 	MessageType_MESSAGE_TYPE_TEXT    MessageType = 1
 	MessageType_MESSAGE_TYPE_FILE    MessageType = 2
 	MessageType_MESSAGE_TYPE_IMAGE   MessageType = 3
+	MessageType_MESSAGE_TYPE_JOIN    MessageType = 4 // Sent to enter room, or without one to be reachable by from; members are told who joined
+	MessageType_MESSAGE_TYPE_LEAVE   MessageType = 5 // Sent to leave room; members are told who left
 )
 
 // Enum value maps for MessageType.
@@ -39,12 +260,16 @@ var (
 		1: "MESSAGE_TYPE_TEXT",
 		2: "MESSAGE_TYPE_FILE",
 		3: "MESSAGE_TYPE_IMAGE",
+		4: "MESSAGE_TYPE_JOIN",
+		5: "MESSAGE_TYPE_LEAVE",
 	}
 	MessageType_value = map[string]int32{
 		"MESSAGE_TYPE_UNKNOWN": 0,
 		"MESSAGE_TYPE_TEXT":    1,
 		"MESSAGE_TYPE_FILE":    2,
 		"MESSAGE_TYPE_IMAGE":   3,
+		"MESSAGE_TYPE_JOIN":    4,
+		"MESSAGE_TYPE_LEAVE":   5,
 	}
 )
 
@@ -59,11 +284,11 @@ func (x MessageType) String() string {
 }
 
 func (MessageType) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_user_proto_enumTypes[0].Descriptor()
+	return file_proto_user_proto_enumTypes[4].Descriptor()
 }
 
 func (MessageType) Type() protoreflect.EnumType {
-	return &file_proto_user_proto_enumTypes[0]
+	return &file_proto_user_proto_enumTypes[4]
 }
 
 func (x MessageType) Number() protoreflect.EnumNumber {
@@ -72,13 +297,17 @@ func (x MessageType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use MessageType.Descriptor instead.
 func (MessageType) EnumDescriptor() ([]byte, []int) {
-	return file_proto_user_proto_rawDescGZIP(), []int{0}
+	return file_proto_user_proto_rawDescGZIP(), []int{4}
 }
 
 // Message structures
+//
+// Field constraints noted on request fields are checked before a call reaches its
+// handler; violations fail with INVALID_ARGUMENT and a google.rpc.BadRequest detail
+// naming each offending field.
 type UserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"` // Field numbers - NEVER change them!
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"` // Positive. Field numbers - NEVER change them!
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -121,13 +350,20 @@ func (x *UserRequest) GetId() int32 {
 }
 
 type UserResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
-	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email     string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Role      string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"` // Name of the role: "user", "admin" or a custom role
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Version   int64                  `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`                     // Incremented on every update
+	DeletedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"` // Set while the user is soft-deleted
+	Status    UserStatus             `protobuf:"varint,9,opt,name=status,proto3,enum=user.UserStatus" json:"status,omitempty"`
+	RoleType  Role                   `protobuf:"varint,10,opt,name=role_type,json=roleType,proto3,enum=user.Role" json:"role_type,omitempty"`
+	// Hash of the user's state, changing whenever the user does. Pass it in the if-match
+	// metadata of UpdateUser or DeleteUser to only change the user if it is still the same.
+	Etag          string `protobuf:"bytes,11,opt,name=etag,proto3" json:"etag,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -204,30 +440,66 @@ func (x *UserResponse) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-type CreateUserRequest struct {
+func (x *UserResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UserResponse) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *UserResponse) GetStatus() UserStatus {
+	if x != nil {
+		return x.Status
+	}
+	return UserStatus_USER_STATUS_UNKNOWN
+}
+
+func (x *UserResponse) GetRoleType() Role {
+	if x != nil {
+		return x.RoleType
+	}
+	return Role_ROLE_UNKNOWN
+}
+
+func (x *UserResponse) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+type UserPreferences struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
-	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Locale        string                 `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`     // BCP 47 language tag such as en-US, the default
+	Timezone      string                 `protobuf:"bytes,3,opt,name=timezone,proto3" json:"timezone,omitempty"` // IANA time zone such as Europe/Paris; defaults to UTC
+	Notifications *NotificationSettings  `protobuf:"bytes,4,opt,name=notifications,proto3" json:"notifications,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // Unset until first updated
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateUserRequest) Reset() {
-	*x = CreateUserRequest{}
+func (x *UserPreferences) Reset() {
+	*x = UserPreferences{}
 	mi := &file_proto_user_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateUserRequest) String() string {
+func (x *UserPreferences) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateUserRequest) ProtoMessage() {}
+func (*UserPreferences) ProtoMessage() {}
 
-func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+func (x *UserPreferences) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -239,63 +511,70 @@ func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
-func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use UserPreferences.ProtoReflect.Descriptor instead.
+func (*UserPreferences) Descriptor() ([]byte, []int) {
 	return file_proto_user_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *CreateUserRequest) GetName() string {
+func (x *UserPreferences) GetUserId() int32 {
 	if x != nil {
-		return x.Name
+		return x.UserId
 	}
-	return ""
+	return 0
 }
 
-func (x *CreateUserRequest) GetEmail() string {
+func (x *UserPreferences) GetLocale() string {
 	if x != nil {
-		return x.Email
+		return x.Locale
 	}
 	return ""
 }
 
-func (x *CreateUserRequest) GetPassword() string {
+func (x *UserPreferences) GetTimezone() string {
 	if x != nil {
-		return x.Password
+		return x.Timezone
 	}
 	return ""
 }
 
-func (x *CreateUserRequest) GetRole() string {
+func (x *UserPreferences) GetNotifications() *NotificationSettings {
 	if x != nil {
-		return x.Role
+		return x.Notifications
 	}
-	return ""
+	return nil
 }
 
-type UpdateUserRequest struct {
+func (x *UserPreferences) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// Which notifications a user receives; by default email only
+type NotificationSettings struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
-	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	Email         bool                   `protobuf:"varint,1,opt,name=email,proto3" json:"email,omitempty"`
+	Push          bool                   `protobuf:"varint,2,opt,name=push,proto3" json:"push,omitempty"`
+	WeeklyDigest  bool                   `protobuf:"varint,3,opt,name=weekly_digest,json=weeklyDigest,proto3" json:"weekly_digest,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateUserRequest) Reset() {
-	*x = UpdateUserRequest{}
+func (x *NotificationSettings) Reset() {
+	*x = NotificationSettings{}
 	mi := &file_proto_user_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateUserRequest) String() string {
+func (x *NotificationSettings) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateUserRequest) ProtoMessage() {}
+func (*NotificationSettings) ProtoMessage() {}
 
-func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+func (x *NotificationSettings) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -307,63 +586,61 @@ func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
-func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use NotificationSettings.ProtoReflect.Descriptor instead.
+func (*NotificationSettings) Descriptor() ([]byte, []int) {
 	return file_proto_user_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *UpdateUserRequest) GetId() int32 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-func (x *UpdateUserRequest) GetName() string {
+func (x *NotificationSettings) GetEmail() bool {
 	if x != nil {
-		return x.Name
+		return x.Email
 	}
-	return ""
+	return false
 }
 
-func (x *UpdateUserRequest) GetEmail() string {
+func (x *NotificationSettings) GetPush() bool {
 	if x != nil {
-		return x.Email
+		return x.Push
 	}
-	return ""
+	return false
 }
 
-func (x *UpdateUserRequest) GetRole() string {
+func (x *NotificationSettings) GetWeeklyDigest() bool {
 	if x != nil {
-		return x.Role
+		return x.WeeklyDigest
 	}
-	return ""
+	return false
 }
 
-type UserFilter struct {
+type UpdatePreferencesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Keyword       string                 `protobuf:"bytes,1,opt,name=keyword,proto3" json:"keyword,omitempty"`
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
-	Roles         []string               `protobuf:"bytes,4,rep,name=roles,proto3" json:"roles,omitempty"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Positive
+	Locale        string                 `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`                // As in UserPreferences
+	Timezone      string                 `protobuf:"bytes,3,opt,name=timezone,proto3" json:"timezone,omitempty"`            // As in UserPreferences
+	Notifications *NotificationSettings  `protobuf:"bytes,4,opt,name=notifications,proto3" json:"notifications,omitempty"`
+	// Fields to change: "locale", "timezone", "notifications" or one of its fields such as
+	// "notifications.push", or "*" for all. Fields listed are set even when empty, which
+	// resets locale and timezone to their defaults and turns notifications off. Without a
+	// mask, locale and timezone are changed when not empty, and notifications when set.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,5,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserFilter) Reset() {
-	*x = UserFilter{}
+func (x *UpdatePreferencesRequest) Reset() {
+	*x = UpdatePreferencesRequest{}
 	mi := &file_proto_user_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserFilter) String() string {
+func (x *UpdatePreferencesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserFilter) ProtoMessage() {}
+func (*UpdatePreferencesRequest) ProtoMessage() {}
 
-func (x *UserFilter) ProtoReflect() protoreflect.Message {
+func (x *UpdatePreferencesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -375,62 +652,67 @@ func (x *UserFilter) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserFilter.ProtoReflect.Descriptor instead.
-func (*UserFilter) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdatePreferencesRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePreferencesRequest) Descriptor() ([]byte, []int) {
 	return file_proto_user_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *UserFilter) GetKeyword() string {
+func (x *UpdatePreferencesRequest) GetUserId() int32 {
 	if x != nil {
-		return x.Keyword
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UpdatePreferencesRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
 	}
 	return ""
 }
 
-func (x *UserFilter) GetLimit() int32 {
+func (x *UpdatePreferencesRequest) GetTimezone() string {
 	if x != nil {
-		return x.Limit
+		return x.Timezone
 	}
-	return 0
+	return ""
 }
 
-func (x *UserFilter) GetOffset() int32 {
+func (x *UpdatePreferencesRequest) GetNotifications() *NotificationSettings {
 	if x != nil {
-		return x.Offset
+		return x.Notifications
 	}
-	return 0
+	return nil
 }
 
-func (x *UserFilter) GetRoles() []string {
+func (x *UpdatePreferencesRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
 	if x != nil {
-		return x.Roles
+		return x.UpdateMask
 	}
 	return nil
 }
 
-type BulkCreateResponse struct {
+type BatchGetUsersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	CreatedCount  int32                  `protobuf:"varint,1,opt,name=created_count,json=createdCount,proto3" json:"created_count,omitempty"`
-	UserIds       []int32                `protobuf:"varint,2,rep,packed,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
-	Errors        []string               `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	Ids           []int32                `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"` // 1 to 100 IDs, each positive
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BulkCreateResponse) Reset() {
-	*x = BulkCreateResponse{}
+func (x *BatchGetUsersRequest) Reset() {
+	*x = BatchGetUsersRequest{}
 	mi := &file_proto_user_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BulkCreateResponse) String() string {
+func (x *BatchGetUsersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BulkCreateResponse) ProtoMessage() {}
+func (*BatchGetUsersRequest) ProtoMessage() {}
 
-func (x *BulkCreateResponse) ProtoReflect() protoreflect.Message {
+func (x *BatchGetUsersRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -442,57 +724,40 @@ func (x *BulkCreateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BulkCreateResponse.ProtoReflect.Descriptor instead.
-func (*BulkCreateResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchGetUsersRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetUsersRequest) Descriptor() ([]byte, []int) {
 	return file_proto_user_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *BulkCreateResponse) GetCreatedCount() int32 {
-	if x != nil {
-		return x.CreatedCount
-	}
-	return 0
-}
-
-func (x *BulkCreateResponse) GetUserIds() []int32 {
-	if x != nil {
-		return x.UserIds
-	}
-	return nil
-}
-
-func (x *BulkCreateResponse) GetErrors() []string {
+func (x *BatchGetUsersRequest) GetIds() []int32 {
 	if x != nil {
-		return x.Errors
+		return x.Ids
 	}
 	return nil
 }
 
-type ChatMessage struct {
+type BatchGetUsersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
-	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Type          MessageType            `protobuf:"varint,5,opt,name=type,proto3,enum=user.MessageType" json:"type,omitempty"`
+	Users         []*UserResponse        `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`                                     // In the order of ids, each once
+	MissingIds    []int32                `protobuf:"varint,2,rep,packed,name=missing_ids,json=missingIds,proto3" json:"missing_ids,omitempty"` // IDs with no user, or a soft-deleted one
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatMessage) Reset() {
-	*x = ChatMessage{}
+func (x *BatchGetUsersResponse) Reset() {
+	*x = BatchGetUsersResponse{}
 	mi := &file_proto_user_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatMessage) String() string {
+func (x *BatchGetUsersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatMessage) ProtoMessage() {}
+func (*BatchGetUsersResponse) ProtoMessage() {}
 
-func (x *ChatMessage) ProtoReflect() protoreflect.Message {
+func (x *BatchGetUsersResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -504,53 +769,2386 @@ func (x *ChatMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
-func (*ChatMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchGetUsersResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetUsersResponse) Descriptor() ([]byte, []int) {
 	return file_proto_user_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *ChatMessage) GetFrom() string {
+func (x *BatchGetUsersResponse) GetUsers() []*UserResponse {
 	if x != nil {
-		return x.From
+		return x.Users
 	}
-	return ""
+	return nil
 }
 
-func (x *ChatMessage) GetTo() string {
+func (x *BatchGetUsersResponse) GetMissingIds() []int32 {
 	if x != nil {
-		return x.To
+		return x.MissingIds
 	}
-	return ""
+	return nil
 }
 
-func (x *ChatMessage) GetMessage() string {
-	if x != nil {
-		return x.Message
+type CreateUserRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Name     string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`   // Required, at most 100 characters
+	Email    string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"` // Required, a plain address such as ada@example.com
+	Password string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Role     string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"` // "user" (the default), "admin" or a custom role; see Role
+	// Overrides the role name when set: role must then be empty or match it, and name the
+	// role for ROLE_CUSTOM
+	RoleType      Role `protobuf:"varint,5,opt,name=role_type,json=roleType,proto3,enum=user.Role" json:"role_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_proto_user_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateUserRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetRoleType() Role {
+	if x != nil {
+		return x.RoleType
+	}
+	return Role_ROLE_UNKNOWN
+}
+
+type ValidateUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`          // Whether CreateUser would accept the request
+	Violations    []*FieldViolation      `protobuf:"bytes,2,rep,name=violations,proto3" json:"violations,omitempty"` // Empty when valid
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateUserResponse) Reset() {
+	*x = ValidateUserResponse{}
+	mi := &file_proto_user_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateUserResponse) ProtoMessage() {}
+
+func (x *ValidateUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateUserResponse.ProtoReflect.Descriptor instead.
+func (*ValidateUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ValidateUserResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateUserResponse) GetViolations() []*FieldViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+// A field of a request that breaks a constraint
+type FieldViolation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`             // Proto field name, such as "email"
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"` // What is wrong, such as "is required"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldViolation) Reset() {
+	*x = FieldViolation{}
+	mi := &file_proto_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldViolation) ProtoMessage() {}
+
+func (x *FieldViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldViolation.ProtoReflect.Descriptor instead.
+func (*FieldViolation) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *FieldViolation) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type UpdateUserRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`      // Positive
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`   // At most 100 characters
+	Email string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"` // A plain address such as ada@example.com
+	Role  string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`   // "user", "admin" or a custom role; see Role
+	// Expected current version; when set, the update fails with FAILED_PRECONDITION
+	// if the user has been modified since that version was read
+	Version int64 `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	// Fields to change: "name", "email" and "role", or "*" for all three. Fields listed are
+	// set even when empty, which is an error for name and email and resets role to "user";
+	// the others stay unchanged. Without a mask, the fields left empty stay unchanged.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,6,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// As in CreateUserRequest; "role" in update_mask covers both fields
+	RoleType      Role `protobuf:"varint,7,opt,name=role_type,json=roleType,proto3,enum=user.Role" json:"role_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
+	mi := &file_proto_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserRequest) ProtoMessage() {}
+
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateUserRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateUserRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UpdateUserRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateUserRequest) GetRoleType() Role {
+	if x != nil {
+		return x.RoleType
+	}
+	return Role_ROLE_UNKNOWN
+}
+
+type UserFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only users whose name or email contains this, ignoring case; at most 100 characters
+	Keyword string   `protobuf:"bytes,1,opt,name=keyword,proto3" json:"keyword,omitempty"`
+	Limit   int32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`   // Not negative
+	Offset  int32    `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"` // Not negative
+	Roles   []string `protobuf:"bytes,4,rep,name=roles,proto3" json:"roles,omitempty"`    // Role names, each "user", "admin" or a custom role
+	// Cursor-based paging: the next page token is returned in the
+	// "next-page-token" trailer (empty on the last page)
+	PageSize  int32  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"` // Not negative
+	PageToken string `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Sort order: "id", "name", "email" or "created_at", optionally followed by
+	// "asc" or "desc" (e.g. "created_at desc"). Defaults to "id asc".
+	OrderBy        string `protobuf:"bytes,7,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	IncludeDeleted bool   `protobuf:"varint,8,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"` // Also return soft-deleted users
+	// Resumes a dropped stream after this user, the last one received, in place of
+	// page_token; offset must then be 0. Not negative
+	ResumeAfterId int32 `protobuf:"varint,9,opt,name=resume_after_id,json=resumeAfterId,proto3" json:"resume_after_id,omitempty"`
+	// Only users created at or after created_after and before created_before, when set
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	// Only users whose email contains this, ignoring case; at most 100 characters
+	EmailContains string `protobuf:"bytes,12,opt,name=email_contains,json=emailContains,proto3" json:"email_contains,omitempty"`
+	// Only users whose name contains this, ignoring case; at most 100 characters
+	NameContains  string `protobuf:"bytes,13,opt,name=name_contains,json=nameContains,proto3" json:"name_contains,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserFilter) Reset() {
+	*x = UserFilter{}
+	mi := &file_proto_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserFilter) ProtoMessage() {}
+
+func (x *UserFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserFilter.ProtoReflect.Descriptor instead.
+func (*UserFilter) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UserFilter) GetKeyword() string {
+	if x != nil {
+		return x.Keyword
+	}
+	return ""
+}
+
+func (x *UserFilter) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *UserFilter) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *UserFilter) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *UserFilter) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *UserFilter) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *UserFilter) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *UserFilter) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+func (x *UserFilter) GetResumeAfterId() int32 {
+	if x != nil {
+		return x.ResumeAfterId
+	}
+	return 0
+}
+
+func (x *UserFilter) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *UserFilter) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *UserFilter) GetEmailContains() string {
+	if x != nil {
+		return x.EmailContains
+	}
+	return ""
+}
+
+func (x *UserFilter) GetNameContains() string {
+	if x != nil {
+		return x.NameContains
+	}
+	return ""
+}
+
+type ListUsersRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PageSize       int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`                   // Defaults to 50, at most 1000; not negative
+	PageToken      string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`                 // next_page_token of the previous page
+	Keyword        string                 `protobuf:"bytes,3,opt,name=keyword,proto3" json:"keyword,omitempty"`                                      // As in UserFilter
+	Roles          []string               `protobuf:"bytes,4,rep,name=roles,proto3" json:"roles,omitempty"`                                          // Role names, as in UserFilter
+	OrderBy        string                 `protobuf:"bytes,5,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`                       // As in UserFilter
+	IncludeDeleted bool                   `protobuf:"varint,6,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"` // Also return soft-deleted users
+	EmailContains  string                 `protobuf:"bytes,7,opt,name=email_contains,json=emailContains,proto3" json:"email_contains,omitempty"`     // As in UserFilter
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_proto_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListUsersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetKeyword() string {
+	if x != nil {
+		return x.Keyword
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *ListUsersRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+func (x *ListUsersRequest) GetEmailContains() string {
+	if x != nil {
+		return x.EmailContains
+	}
+	return ""
+}
+
+type ListUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*UserResponse        `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	TotalSize     int32                  `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`              // Users matching the request, over all pages
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_proto_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListUsersResponse) GetUsers() []*UserResponse {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListUsersResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type ExportUsersRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Format         FileFormat             `protobuf:"varint,1,opt,name=format,proto3,enum=user.FileFormat" json:"format,omitempty"`                  // Defaults to CSV
+	Keyword        string                 `protobuf:"bytes,2,opt,name=keyword,proto3" json:"keyword,omitempty"`                                      // As in UserFilter
+	Roles          []string               `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`                                          // Role names, as in UserFilter
+	OrderBy        string                 `protobuf:"bytes,4,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`                       // As in UserFilter
+	IncludeDeleted bool                   `protobuf:"varint,5,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"` // Also export soft-deleted users
+	EmailContains  string                 `protobuf:"bytes,6,opt,name=email_contains,json=emailContains,proto3" json:"email_contains,omitempty"`     // As in UserFilter
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExportUsersRequest) Reset() {
+	*x = ExportUsersRequest{}
+	mi := &file_proto_user_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUsersRequest) ProtoMessage() {}
+
+func (x *ExportUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUsersRequest.ProtoReflect.Descriptor instead.
+func (*ExportUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ExportUsersRequest) GetFormat() FileFormat {
+	if x != nil {
+		return x.Format
+	}
+	return FileFormat_FILE_FORMAT_UNKNOWN
+}
+
+func (x *ExportUsersRequest) GetKeyword() string {
+	if x != nil {
+		return x.Keyword
+	}
+	return ""
+}
+
+func (x *ExportUsersRequest) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *ExportUsersRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ExportUsersRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+func (x *ExportUsersRequest) GetEmailContains() string {
+	if x != nil {
+		return x.EmailContains
+	}
+	return ""
+}
+
+// A piece of a file; the file is the data of every chunk of the stream, in order
+type FileChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileChunk) Reset() {
+	*x = FileChunk{}
+	mi := &file_proto_user_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileChunk) ProtoMessage() {}
+
+func (x *FileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileChunk.ProtoReflect.Descriptor instead.
+func (*FileChunk) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *FileChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// A piece of an avatar; the first chunk holds the avatar and the others its data, in order
+type AvatarChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Avatar        *Avatar                `protobuf:"bytes,1,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AvatarChunk) Reset() {
+	*x = AvatarChunk{}
+	mi := &file_proto_user_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AvatarChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AvatarChunk) ProtoMessage() {}
+
+func (x *AvatarChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AvatarChunk.ProtoReflect.Descriptor instead.
+func (*AvatarChunk) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AvatarChunk) GetAvatar() *Avatar {
+	if x != nil {
+		return x.Avatar
+	}
+	return nil
+}
+
+func (x *AvatarChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type Avatar struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // image/png, image/jpeg, image/gif or image/webp
+	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`                                 // In bytes
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Avatar) Reset() {
+	*x = Avatar{}
+	mi := &file_proto_user_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Avatar) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Avatar) ProtoMessage() {}
+
+func (x *Avatar) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Avatar.ProtoReflect.Descriptor instead.
+func (*Avatar) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Avatar) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Avatar) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Avatar) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Avatar) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type SearchUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Terms separated by spaces, all of which must hold: role:admin (several role terms
+	// match any of them), name~john (name contains john; a bare word means the same),
+	// created>2024-01-01 (also >=, <, <= and : for that day, with a date or an RFC 3339
+	// time) and deleted:true (include soft-deleted users). Values with spaces are quoted,
+	// as in name~"john doe". At most 500 characters
+	Query         string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	PageSize      int32  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Defaults to 50, at most 1000; not negative
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page
+	OrderBy       string `protobuf:"bytes,4,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`       // As in UserFilter
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersRequest) Reset() {
+	*x = SearchUsersRequest{}
+	mi := &file_proto_user_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersRequest) ProtoMessage() {}
+
+func (x *SearchUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersRequest.ProtoReflect.Descriptor instead.
+func (*SearchUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SearchUsersRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchUsersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchUsersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *SearchUsersRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+type WatchUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserIds       []int32                `protobuf:"varint,1,rep,packed,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`      // Only changes to these users when set; each positive
+	Types         []UserEventType        `protobuf:"varint,2,rep,packed,name=types,proto3,enum=user.UserEventType" json:"types,omitempty"` // Only these kinds of change when set; not UNKNOWN
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchUsersRequest) Reset() {
+	*x = WatchUsersRequest{}
+	mi := &file_proto_user_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchUsersRequest) ProtoMessage() {}
+
+func (x *WatchUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchUsersRequest.ProtoReflect.Descriptor instead.
+func (*WatchUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WatchUsersRequest) GetUserIds() []int32 {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *WatchUsersRequest) GetTypes() []UserEventType {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+type UserEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          UserEventType          `protobuf:"varint,1,opt,name=type,proto3,enum=user.UserEventType" json:"type,omitempty"`
+	User          *UserResponse          `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`           // State after the change
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // When the change was committed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserEvent) Reset() {
+	*x = UserEvent{}
+	mi := &file_proto_user_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserEvent) ProtoMessage() {}
+
+func (x *UserEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserEvent.ProtoReflect.Descriptor instead.
+func (*UserEvent) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UserEvent) GetType() UserEventType {
+	if x != nil {
+		return x.Type
+	}
+	return UserEventType_USER_EVENT_TYPE_UNKNOWN
+}
+
+func (x *UserEvent) GetUser() *UserResponse {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *UserEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type BulkCreateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CreatedCount  int32                  `protobuf:"varint,1,opt,name=created_count,json=createdCount,proto3" json:"created_count,omitempty"`
+	UserIds       []int32                `protobuf:"varint,2,rep,packed,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	Errors        []string               `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkCreateResponse) Reset() {
+	*x = BulkCreateResponse{}
+	mi := &file_proto_user_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCreateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCreateResponse) ProtoMessage() {}
+
+func (x *BulkCreateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCreateResponse.ProtoReflect.Descriptor instead.
+func (*BulkCreateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BulkCreateResponse) GetCreatedCount() int32 {
+	if x != nil {
+		return x.CreatedCount
+	}
+	return 0
+}
+
+func (x *BulkCreateResponse) GetUserIds() []int32 {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *BulkCreateResponse) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type BulkUpdateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UpdatedCount  int32                  `protobuf:"varint,1,opt,name=updated_count,json=updatedCount,proto3" json:"updated_count,omitempty"`
+	UserIds       []int32                `protobuf:"varint,2,rep,packed,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"` // Users updated, in the order their updates were sent
+	Errors        []*BulkUpdateError     `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`                          // One per update rejected
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkUpdateResponse) Reset() {
+	*x = BulkUpdateResponse{}
+	mi := &file_proto_user_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkUpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkUpdateResponse) ProtoMessage() {}
+
+func (x *BulkUpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkUpdateResponse.ProtoReflect.Descriptor instead.
+func (*BulkUpdateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *BulkUpdateResponse) GetUpdatedCount() int32 {
+	if x != nil {
+		return x.UpdatedCount
+	}
+	return 0
+}
+
+func (x *BulkUpdateResponse) GetUserIds() []int32 {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *BulkUpdateResponse) GetErrors() []*BulkUpdateError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type BulkUpdateError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // Position of the update in the stream, from 0
+	Id            int32                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`       // id of the update
+	Code          int32                  `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`   // gRPC status code, as UpdateUser would have failed with
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkUpdateError) Reset() {
+	*x = BulkUpdateError{}
+	mi := &file_proto_user_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkUpdateError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkUpdateError) ProtoMessage() {}
+
+func (x *BulkUpdateError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkUpdateError.ProtoReflect.Descriptor instead.
+func (*BulkUpdateError) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BulkUpdateError) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BulkUpdateError) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BulkUpdateError) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BulkUpdateError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// A piece of a file to import; the file is the data of every message of the stream, in order
+type ImportUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        FileFormat             `protobuf:"varint,1,opt,name=format,proto3,enum=user.FileFormat" json:"format,omitempty"` // Read from the first message; defaults to CSV
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportUsersRequest) Reset() {
+	*x = ImportUsersRequest{}
+	mi := &file_proto_user_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportUsersRequest) ProtoMessage() {}
+
+func (x *ImportUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportUsersRequest.ProtoReflect.Descriptor instead.
+func (*ImportUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ImportUsersRequest) GetFormat() FileFormat {
+	if x != nil {
+		return x.Format
+	}
+	return FileFormat_FILE_FORMAT_UNKNOWN
+}
+
+func (x *ImportUsersRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ImportUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ImportedCount int32                  `protobuf:"varint,1,opt,name=imported_count,json=importedCount,proto3" json:"imported_count,omitempty"`
+	UserIds       []int32                `protobuf:"varint,2,rep,packed,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`         // Users created, in file order
+	SkippedCount  int32                  `protobuf:"varint,3,opt,name=skipped_count,json=skippedCount,proto3" json:"skipped_count,omitempty"` // Soft-deleted users in the file, which aren't imported
+	Errors        []*ImportError         `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`                                  // One per user rejected; any error imports nothing
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportUsersResponse) Reset() {
+	*x = ImportUsersResponse{}
+	mi := &file_proto_user_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportUsersResponse) ProtoMessage() {}
+
+func (x *ImportUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportUsersResponse.ProtoReflect.Descriptor instead.
+func (*ImportUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ImportUsersResponse) GetImportedCount() int32 {
+	if x != nil {
+		return x.ImportedCount
+	}
+	return 0
+}
+
+func (x *ImportUsersResponse) GetUserIds() []int32 {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *ImportUsersResponse) GetSkippedCount() int32 {
+	if x != nil {
+		return x.SkippedCount
+	}
+	return 0
+}
+
+func (x *ImportUsersResponse) GetErrors() []*ImportError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type ImportError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Line          int32                  `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"` // Line of the file the user starts on, from 1
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportError) Reset() {
+	*x = ImportError{}
+	mi := &file_proto_user_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportError) ProtoMessage() {}
+
+func (x *ImportError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportError.ProtoReflect.Descriptor instead.
+func (*ImportError) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ImportError) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *ImportError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// A piece of an avatar upload; the image is the data of every message of the stream, in
+// order, and user_id and content_type are read from the first
+type UploadAvatarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // Optional; must match the type detected from the data
+	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadAvatarRequest) Reset() {
+	*x = UploadAvatarRequest{}
+	mi := &file_proto_user_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadAvatarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadAvatarRequest) ProtoMessage() {}
+
+func (x *UploadAvatarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadAvatarRequest.ProtoReflect.Descriptor instead.
+func (*UploadAvatarRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *UploadAvatarRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UploadAvatarRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *UploadAvatarRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ChatMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Sender, at most 100 characters. Authenticated callers chat as their subject (or
+	// client certificate name) and may leave it empty; otherwise it is required, and the
+	// stream is registered under the first and must keep it.
+	From string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	// User to deliver to, who reads it from the chat history if not connected, or "Server"
+	// for an echo; empty for the room, or else everyone
+	To        string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Message   string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`     // At most 4096 characters
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Set by the server on the messages it delivers
+	Type      MessageType            `protobuf:"varint,5,opt,name=type,proto3,enum=user.MessageType" json:"type,omitempty"`
+	// Room the message is sent to, or that JOIN and LEAVE enter or leave: up to 64
+	// letters, digits, "-", "_" and ".", starting with a letter or digit
+	Room string `protobuf:"bytes,6,opt,name=room,proto3" json:"room,omitempty"`
+	// Set on the messages the server keeps in the chat history, increasing in the order
+	// they were sent
+	Id            int64 `protobuf:"varint,7,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatMessage) Reset() {
+	*x = ChatMessage{}
+	mi := &file_proto_user_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMessage) ProtoMessage() {}
+
+func (x *ChatMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
+func (*ChatMessage) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ChatMessage) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetType() MessageType {
+	if x != nil {
+		return x.Type
+	}
+	return MessageType_MESSAGE_TYPE_UNKNOWN
+}
+
+func (x *ChatMessage) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ChatHistoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Room  string                 `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"` // Messages sent to this room
+	// With user, the direct messages between user and peer, both ways; without room or
+	// peer, the messages sent to everyone
+	Peer string `protobuf:"bytes,2,opt,name=peer,proto3" json:"peer,omitempty"`
+	// Reader of the history: authenticated callers read as themselves, and only admins may
+	// name another user. Required with room or peer when callers aren't authenticated.
+	// Reading a room's history takes being in it, except for admins.
+	User          string `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	Before        int64  `protobuf:"varint,4,opt,name=before,proto3" json:"before,omitempty"` // Only messages with a lower ID when set; not negative
+	Limit         int32  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`   // At most this many, the latest; defaults to 50, not negative
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatHistoryRequest) Reset() {
+	*x = ChatHistoryRequest{}
+	mi := &file_proto_user_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatHistoryRequest) ProtoMessage() {}
+
+func (x *ChatHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ChatHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ChatHistoryRequest) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *ChatHistoryRequest) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+func (x *ChatHistoryRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ChatHistoryRequest) GetBefore() int64 {
+	if x != nil {
+		return x.Before
+	}
+	return 0
+}
+
+func (x *ChatHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ChatHistoryResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Messages []*ChatMessage         `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"` // Oldest first
+	// Older messages remain: ask again with the ID of the first message as before
+	HasMore       bool `protobuf:"varint,2,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatHistoryResponse) Reset() {
+	*x = ChatHistoryResponse{}
+	mi := &file_proto_user_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatHistoryResponse) ProtoMessage() {}
+
+func (x *ChatHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ChatHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ChatHistoryResponse) GetMessages() []*ChatMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *ChatHistoryResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+type RefreshTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshTokenRequest) Reset() {
+	*x = RefreshTokenRequest{}
+	mi := &file_proto_user_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshTokenRequest) ProtoMessage() {}
+
+func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshTokenRequest.ProtoReflect.Descriptor instead.
+func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *RefreshTokenRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type TokenResponse struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken           string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"` // JWT sent as "authorization: Bearer <token>"
+	AccessTokenExpiresAt  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=access_token_expires_at,json=accessTokenExpiresAt,proto3" json:"access_token_expires_at,omitempty"`
+	RefreshToken          string                 `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"` // Opaque, single-use
+	RefreshTokenExpiresAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=refresh_token_expires_at,json=refreshTokenExpiresAt,proto3" json:"refresh_token_expires_at,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *TokenResponse) Reset() {
+	*x = TokenResponse{}
+	mi := &file_proto_user_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenResponse) ProtoMessage() {}
+
+func (x *TokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenResponse.ProtoReflect.Descriptor instead.
+func (*TokenResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *TokenResponse) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *TokenResponse) GetAccessTokenExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AccessTokenExpiresAt
+	}
+	return nil
+}
+
+func (x *TokenResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *TokenResponse) GetRefreshTokenExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RefreshTokenExpiresAt
+	}
+	return nil
+}
+
+type AuditLogRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`       // Only changes to this user when set; not negative
+	Actor         string                 `protobuf:"bytes,2,opt,name=actor,proto3" json:"actor,omitempty"`                        // Only changes made by this caller when set
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"` // Defaults to 50; not negative
+	PageToken     string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditLogRequest) Reset() {
+	*x = AuditLogRequest{}
+	mi := &file_proto_user_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditLogRequest) ProtoMessage() {}
+
+func (x *AuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditLogRequest.ProtoReflect.Descriptor instead.
+func (*AuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *AuditLogRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *AuditLogRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *AuditLogRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *AuditLogRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type AuditEntry struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Actor     string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`   // Subject of the authenticated caller, or "anonymous"
+	Method    string                 `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"` // RPC that made the change, e.g. "UpdateUser"
+	UserId    int32                  `protobuf:"varint,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OldValue  *UserResponse          `protobuf:"bytes,6,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"` // Unset for creations and restores
+	NewValue  *UserResponse          `protobuf:"bytes,7,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"` // Unset for deletions
+	// Fields that differ between old_value and new_value: name, email, role and status, and
+	// deleted_at for deletions and restores; empty for creations
+	ChangedFields []string `protobuf:"bytes,8,rep,name=changed_fields,json=changedFields,proto3" json:"changed_fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEntry) Reset() {
+	*x = AuditEntry{}
+	mi := &file_proto_user_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEntry) ProtoMessage() {}
+
+func (x *AuditEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEntry.ProtoReflect.Descriptor instead.
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *AuditEntry) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AuditEntry) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *AuditEntry) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *AuditEntry) GetOldValue() *UserResponse {
+	if x != nil {
+		return x.OldValue
+	}
+	return nil
+}
+
+func (x *AuditEntry) GetNewValue() *UserResponse {
+	if x != nil {
+		return x.NewValue
+	}
+	return nil
+}
+
+func (x *AuditEntry) GetChangedFields() []string {
+	if x != nil {
+		return x.ChangedFields
+	}
+	return nil
+}
+
+type AuditLogResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*AuditEntry          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditLogResponse) Reset() {
+	*x = AuditLogResponse{}
+	mi := &file_proto_user_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditLogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditLogResponse) ProtoMessage() {}
+
+func (x *AuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditLogResponse.ProtoReflect.Descriptor instead.
+func (*AuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *AuditLogResponse) GetEntries() []*AuditEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *AuditLogResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
 	}
 	return ""
 }
 
-func (x *ChatMessage) GetTimestamp() *timestamppb.Timestamp {
+type UserHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`       // Positive
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"` // Defaults to 50; not negative
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserHistoryRequest) Reset() {
+	*x = UserHistoryRequest{}
+	mi := &file_proto_user_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserHistoryRequest) ProtoMessage() {}
+
+func (x *UserHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[36]
 	if x != nil {
-		return x.Timestamp
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserHistoryRequest.ProtoReflect.Descriptor instead.
+func (*UserHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *UserHistoryRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UserHistoryRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *UserHistoryRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type UserHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*AuditEntry          `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`                                    // Newest first
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserHistoryResponse) Reset() {
+	*x = UserHistoryResponse{}
+	mi := &file_proto_user_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserHistoryResponse) ProtoMessage() {}
+
+func (x *UserHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserHistoryResponse.ProtoReflect.Descriptor instead.
+func (*UserHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *UserHistoryResponse) GetChanges() []*AuditEntry {
+	if x != nil {
+		return x.Changes
 	}
 	return nil
 }
 
-func (x *ChatMessage) GetType() MessageType {
+func (x *UserHistoryResponse) GetNextPageToken() string {
 	if x != nil {
-		return x.Type
+		return x.NextPageToken
 	}
-	return MessageType_MESSAGE_TYPE_UNKNOWN
+	return ""
+}
+
+type SetLogLevelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Level         string                 `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`       // debug, info, warn or error
+	Duration      *durationpb.Duration   `protobuf:"bytes,2,opt,name=duration,proto3" json:"duration,omitempty"` // Revert to the previous level after this long when set
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLogLevelRequest) Reset() {
+	*x = SetLogLevelRequest{}
+	mi := &file_proto_user_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLogLevelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLogLevelRequest) ProtoMessage() {}
+
+func (x *SetLogLevelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLogLevelRequest.ProtoReflect.Descriptor instead.
+func (*SetLogLevelRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *SetLogLevelRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *SetLogLevelRequest) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+type LogLevelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Level         string                 `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	RevertsAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=reverts_at,json=revertsAt,proto3" json:"reverts_at,omitempty"` // Unset unless the level is temporary
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogLevelResponse) Reset() {
+	*x = LogLevelResponse{}
+	mi := &file_proto_user_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogLevelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogLevelResponse) ProtoMessage() {}
+
+func (x *LogLevelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogLevelResponse.ProtoReflect.Descriptor instead.
+func (*LogLevelResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *LogLevelResponse) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *LogLevelResponse) GetRevertsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevertsAt
+	}
+	return nil
+}
+
+type SetMaintenanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`                           // Told to rejected callers
+	RetryAfter    *durationpb.Duration   `protobuf:"bytes,3,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"` // How long callers should wait before retrying; 30s when unset
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMaintenanceRequest) Reset() {
+	*x = SetMaintenanceRequest{}
+	mi := &file_proto_user_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMaintenanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMaintenanceRequest) ProtoMessage() {}
+
+func (x *SetMaintenanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMaintenanceRequest.ProtoReflect.Descriptor instead.
+func (*SetMaintenanceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SetMaintenanceRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *SetMaintenanceRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SetMaintenanceRequest) GetRetryAfter() *durationpb.Duration {
+	if x != nil {
+		return x.RetryAfter
+	}
+	return nil
+}
+
+type MaintenanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	RetryAfter    *durationpb.Duration   `protobuf:"bytes,3,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"`
+	Since         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=since,proto3" json:"since,omitempty"` // When maintenance mode was turned on; unset while off
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MaintenanceResponse) Reset() {
+	*x = MaintenanceResponse{}
+	mi := &file_proto_user_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MaintenanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaintenanceResponse) ProtoMessage() {}
+
+func (x *MaintenanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaintenanceResponse.ProtoReflect.Descriptor instead.
+func (*MaintenanceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *MaintenanceResponse) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *MaintenanceResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *MaintenanceResponse) GetRetryAfter() *durationpb.Duration {
+	if x != nil {
+		return x.RetryAfter
+	}
+	return nil
+}
+
+func (x *MaintenanceResponse) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
 }
 
 var File_proto_user_proto protoreflect.FileDescriptor
 
 const file_proto_user_proto_rawDesc = "" +
 	"\n" +
-	"\x10proto/user.proto\x12\x04user\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\"\x1d\n" +
+	"\x10proto/user.proto\x12\x04user\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1egoogle/protobuf/duration.proto\x1a google/protobuf/field_mask.proto\x1a\x1cgoogle/api/annotations.proto\"\x1d\n" +
 	"\vUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\"\xd2\x01\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\x8e\x03\n" +
 	"\fUserResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
@@ -559,49 +3157,291 @@ const file_proto_user_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"m\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\a \x01(\x03R\aversion\x129\n" +
+	"\n" +
+	"deleted_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12(\n" +
+	"\x06status\x18\t \x01(\x0e2\x10.user.UserStatusR\x06status\x12'\n" +
+	"\trole_type\x18\n" +
+	" \x01(\x0e2\n" +
+	".user.RoleR\broleType\x12\x12\n" +
+	"\x04etag\x18\v \x01(\tR\x04etag\"\xdb\x01\n" +
+	"\x0fUserPreferences\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x16\n" +
+	"\x06locale\x18\x02 \x01(\tR\x06locale\x12\x1a\n" +
+	"\btimezone\x18\x03 \x01(\tR\btimezone\x12@\n" +
+	"\rnotifications\x18\x04 \x01(\v2\x1a.user.NotificationSettingsR\rnotifications\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"e\n" +
+	"\x14NotificationSettings\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\bR\x05email\x12\x12\n" +
+	"\x04push\x18\x02 \x01(\bR\x04push\x12#\n" +
+	"\rweekly_digest\x18\x03 \x01(\bR\fweeklyDigest\"\xe6\x01\n" +
+	"\x18UpdatePreferencesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x16\n" +
+	"\x06locale\x18\x02 \x01(\tR\x06locale\x12\x1a\n" +
+	"\btimezone\x18\x03 \x01(\tR\btimezone\x12@\n" +
+	"\rnotifications\x18\x04 \x01(\v2\x1a.user.NotificationSettingsR\rnotifications\x12;\n" +
+	"\vupdate_mask\x18\x05 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"(\n" +
+	"\x14BatchGetUsersRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x05R\x03ids\"b\n" +
+	"\x15BatchGetUsersResponse\x12(\n" +
+	"\x05users\x18\x01 \x03(\v2\x12.user.UserResponseR\x05users\x12\x1f\n" +
+	"\vmissing_ids\x18\x02 \x03(\x05R\n" +
+	"missingIds\"\x96\x01\n" +
 	"\x11CreateUserRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
 	"\bpassword\x18\x03 \x01(\tR\bpassword\x12\x12\n" +
-	"\x04role\x18\x04 \x01(\tR\x04role\"a\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12'\n" +
+	"\trole_type\x18\x05 \x01(\x0e2\n" +
+	".user.RoleR\broleType\"b\n" +
+	"\x14ValidateUserResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x124\n" +
+	"\n" +
+	"violations\x18\x02 \x03(\v2\x14.user.FieldViolationR\n" +
+	"violations\"H\n" +
+	"\x0eFieldViolation\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\"\xe1\x01\n" +
 	"\x11UpdateUserRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
 	"\x05email\x18\x03 \x01(\tR\x05email\x12\x12\n" +
-	"\x04role\x18\x04 \x01(\tR\x04role\"j\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\x03R\aversion\x12;\n" +
+	"\vupdate_mask\x18\x06 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12'\n" +
+	"\trole_type\x18\a \x01(\x0e2\n" +
+	".user.RoleR\broleType\"\xe2\x03\n" +
 	"\n" +
 	"UserFilter\x12\x18\n" +
 	"\akeyword\x18\x01 \x01(\tR\akeyword\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
 	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x14\n" +
-	"\x05roles\x18\x04 \x03(\tR\x05roles\"l\n" +
+	"\x05roles\x18\x04 \x03(\tR\x05roles\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\x12\x19\n" +
+	"\border_by\x18\a \x01(\tR\aorderBy\x12'\n" +
+	"\x0finclude_deleted\x18\b \x01(\bR\x0eincludeDeleted\x12&\n" +
+	"\x0fresume_after_id\x18\t \x01(\x05R\rresumeAfterId\x12?\n" +
+	"\rcreated_after\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12%\n" +
+	"\x0eemail_contains\x18\f \x01(\tR\remailContains\x12#\n" +
+	"\rname_contains\x18\r \x01(\tR\fnameContains\"\xe9\x01\n" +
+	"\x10ListUsersRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x18\n" +
+	"\akeyword\x18\x03 \x01(\tR\akeyword\x12\x14\n" +
+	"\x05roles\x18\x04 \x03(\tR\x05roles\x12\x19\n" +
+	"\border_by\x18\x05 \x01(\tR\aorderBy\x12'\n" +
+	"\x0finclude_deleted\x18\x06 \x01(\bR\x0eincludeDeleted\x12%\n" +
+	"\x0eemail_contains\x18\a \x01(\tR\remailContains\"\x84\x01\n" +
+	"\x11ListUsersResponse\x12(\n" +
+	"\x05users\x18\x01 \x03(\v2\x12.user.UserResponseR\x05users\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\"\xd9\x01\n" +
+	"\x12ExportUsersRequest\x12(\n" +
+	"\x06format\x18\x01 \x01(\x0e2\x10.user.FileFormatR\x06format\x12\x18\n" +
+	"\akeyword\x18\x02 \x01(\tR\akeyword\x12\x14\n" +
+	"\x05roles\x18\x03 \x03(\tR\x05roles\x12\x19\n" +
+	"\border_by\x18\x04 \x01(\tR\aorderBy\x12'\n" +
+	"\x0finclude_deleted\x18\x05 \x01(\bR\x0eincludeDeleted\x12%\n" +
+	"\x0eemail_contains\x18\x06 \x01(\tR\remailContains\"\x1f\n" +
+	"\tFileChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"G\n" +
+	"\vAvatarChunk\x12$\n" +
+	"\x06avatar\x18\x01 \x01(\v2\f.user.AvatarR\x06avatar\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"\x93\x01\n" +
+	"\x06Avatar\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x81\x01\n" +
+	"\x12SearchUsersRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x19\n" +
+	"\border_by\x18\x04 \x01(\tR\aorderBy\"Y\n" +
+	"\x11WatchUsersRequest\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\x05R\auserIds\x12)\n" +
+	"\x05types\x18\x02 \x03(\x0e2\x13.user.UserEventTypeR\x05types\"\x96\x01\n" +
+	"\tUserEvent\x12'\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x13.user.UserEventTypeR\x04type\x12&\n" +
+	"\x04user\x18\x02 \x01(\v2\x12.user.UserResponseR\x04user\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"l\n" +
 	"\x12BulkCreateResponse\x12#\n" +
 	"\rcreated_count\x18\x01 \x01(\x05R\fcreatedCount\x12\x19\n" +
 	"\buser_ids\x18\x02 \x03(\x05R\auserIds\x12\x16\n" +
-	"\x06errors\x18\x03 \x03(\tR\x06errors\"\xac\x01\n" +
+	"\x06errors\x18\x03 \x03(\tR\x06errors\"\x83\x01\n" +
+	"\x12BulkUpdateResponse\x12#\n" +
+	"\rupdated_count\x18\x01 \x01(\x05R\fupdatedCount\x12\x19\n" +
+	"\buser_ids\x18\x02 \x03(\x05R\auserIds\x12-\n" +
+	"\x06errors\x18\x03 \x03(\v2\x15.user.BulkUpdateErrorR\x06errors\"e\n" +
+	"\x0fBulkUpdateError\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"R\n" +
+	"\x12ImportUsersRequest\x12(\n" +
+	"\x06format\x18\x01 \x01(\x0e2\x10.user.FileFormatR\x06format\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"\xa7\x01\n" +
+	"\x13ImportUsersResponse\x12%\n" +
+	"\x0eimported_count\x18\x01 \x01(\x05R\rimportedCount\x12\x19\n" +
+	"\buser_ids\x18\x02 \x03(\x05R\auserIds\x12#\n" +
+	"\rskipped_count\x18\x03 \x01(\x05R\fskippedCount\x12)\n" +
+	"\x06errors\x18\x04 \x03(\v2\x11.user.ImportErrorR\x06errors\";\n" +
+	"\vImportError\x12\x12\n" +
+	"\x04line\x18\x01 \x01(\x05R\x04line\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"e\n" +
+	"\x13UploadAvatarRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\"\xd0\x01\n" +
 	"\vChatMessage\x12\x12\n" +
 	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
 	"\x02to\x18\x02 \x01(\tR\x02to\x12\x18\n" +
 	"\amessage\x18\x03 \x01(\tR\amessage\x128\n" +
 	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12%\n" +
-	"\x04type\x18\x05 \x01(\x0e2\x11.user.MessageTypeR\x04type*m\n" +
+	"\x04type\x18\x05 \x01(\x0e2\x11.user.MessageTypeR\x04type\x12\x12\n" +
+	"\x04room\x18\x06 \x01(\tR\x04room\x12\x0e\n" +
+	"\x02id\x18\a \x01(\x03R\x02id\"~\n" +
+	"\x12ChatHistoryRequest\x12\x12\n" +
+	"\x04room\x18\x01 \x01(\tR\x04room\x12\x12\n" +
+	"\x04peer\x18\x02 \x01(\tR\x04peer\x12\x12\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\x12\x16\n" +
+	"\x06before\x18\x04 \x01(\x03R\x06before\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"_\n" +
+	"\x13ChatHistoryResponse\x12-\n" +
+	"\bmessages\x18\x01 \x03(\v2\x11.user.ChatMessageR\bmessages\x12\x19\n" +
+	"\bhas_more\x18\x02 \x01(\bR\ahasMore\":\n" +
+	"\x13RefreshTokenRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"\xff\x01\n" +
+	"\rTokenResponse\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12Q\n" +
+	"\x17access_token_expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x14accessTokenExpiresAt\x12#\n" +
+	"\rrefresh_token\x18\x03 \x01(\tR\frefreshToken\x12S\n" +
+	"\x18refresh_token_expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x15refreshTokenExpiresAt\"|\n" +
+	"\x0fAuditLogRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x14\n" +
+	"\x05actor\x18\x02 \x01(\tR\x05actor\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"\xa6\x02\n" +
+	"\n" +
+	"AuditEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x16\n" +
+	"\x06method\x18\x04 \x01(\tR\x06method\x12\x17\n" +
+	"\auser_id\x18\x05 \x01(\x05R\x06userId\x12/\n" +
+	"\told_value\x18\x06 \x01(\v2\x12.user.UserResponseR\boldValue\x12/\n" +
+	"\tnew_value\x18\a \x01(\v2\x12.user.UserResponseR\bnewValue\x12%\n" +
+	"\x0echanged_fields\x18\b \x03(\tR\rchangedFields\"f\n" +
+	"\x10AuditLogResponse\x12*\n" +
+	"\aentries\x18\x01 \x03(\v2\x10.user.AuditEntryR\aentries\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"i\n" +
+	"\x12UserHistoryRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"i\n" +
+	"\x13UserHistoryResponse\x12*\n" +
+	"\achanges\x18\x01 \x03(\v2\x10.user.AuditEntryR\achanges\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"a\n" +
+	"\x12SetLogLevelRequest\x12\x14\n" +
+	"\x05level\x18\x01 \x01(\tR\x05level\x125\n" +
+	"\bduration\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\bduration\"c\n" +
+	"\x10LogLevelResponse\x12\x14\n" +
+	"\x05level\x18\x01 \x01(\tR\x05level\x129\n" +
+	"\n" +
+	"reverts_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\trevertsAt\"\x85\x01\n" +
+	"\x15SetMaintenanceRequest\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12:\n" +
+	"\vretry_after\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"retryAfter\"\xb5\x01\n" +
+	"\x13MaintenanceResponse\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12:\n" +
+	"\vretry_after\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"retryAfter\x120\n" +
+	"\x05since\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05since*H\n" +
+	"\x04Role\x12\x10\n" +
+	"\fROLE_UNKNOWN\x10\x00\x12\r\n" +
+	"\tROLE_USER\x10\x01\x12\x0e\n" +
+	"\n" +
+	"ROLE_ADMIN\x10\x02\x12\x0f\n" +
+	"\vROLE_CUSTOM\x10\x03*X\n" +
+	"\n" +
+	"UserStatus\x12\x17\n" +
+	"\x13USER_STATUS_UNKNOWN\x10\x00\x12\x16\n" +
+	"\x12USER_STATUS_ACTIVE\x10\x01\x12\x19\n" +
+	"\x15USER_STATUS_SUSPENDED\x10\x02*h\n" +
+	"\n" +
+	"FileFormat\x12\x17\n" +
+	"\x13FILE_FORMAT_UNKNOWN\x10\x00\x12\x13\n" +
+	"\x0fFILE_FORMAT_CSV\x10\x01\x12\x16\n" +
+	"\x12FILE_FORMAT_NDJSON\x10\x02\x12\x14\n" +
+	"\x10FILE_FORMAT_JSON\x10\x03*\xa2\x01\n" +
+	"\rUserEventType\x12\x1b\n" +
+	"\x17USER_EVENT_TYPE_UNKNOWN\x10\x00\x12\x1b\n" +
+	"\x17USER_EVENT_TYPE_CREATED\x10\x01\x12\x1b\n" +
+	"\x17USER_EVENT_TYPE_UPDATED\x10\x02\x12\x1b\n" +
+	"\x17USER_EVENT_TYPE_DELETED\x10\x03\x12\x1d\n" +
+	"\x19USER_EVENT_TYPE_UNDELETED\x10\x04*\x9c\x01\n" +
 	"\vMessageType\x12\x18\n" +
 	"\x14MESSAGE_TYPE_UNKNOWN\x10\x00\x12\x15\n" +
 	"\x11MESSAGE_TYPE_TEXT\x10\x01\x12\x15\n" +
 	"\x11MESSAGE_TYPE_FILE\x10\x02\x12\x16\n" +
-	"\x12MESSAGE_TYPE_IMAGE\x10\x032\x9b\x03\n" +
-	"\vUserService\x120\n" +
-	"\aGetUser\x12\x11.user.UserRequest\x1a\x12.user.UserResponse\x129\n" +
+	"\x12MESSAGE_TYPE_IMAGE\x10\x03\x12\x15\n" +
+	"\x11MESSAGE_TYPE_JOIN\x10\x04\x12\x16\n" +
+	"\x12MESSAGE_TYPE_LEAVE\x10\x052\xd5\x11\n" +
+	"\vUserService\x12H\n" +
+	"\aGetUser\x12\x11.user.UserRequest\x1a\x12.user.UserResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/users/{id}\x12d\n" +
+	"\rBatchGetUsers\x12\x1a.user.BatchGetUsersRequest\x1a\x1b.user.BatchGetUsersResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/users:batchGet\x12O\n" +
+	"\n" +
+	"CreateUser\x12\x17.user.CreateUserRequest\x1a\x12.user.UserResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/users\x12b\n" +
+	"\fValidateUser\x12\x17.user.CreateUserRequest\x1a\x1a.user.ValidateUserResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/users:validate\x12T\n" +
 	"\n" +
-	"CreateUser\x12\x17.user.CreateUserRequest\x1a\x12.user.UserResponse\x129\n" +
+	"UpdateUser\x12\x17.user.UpdateUserRequest\x1a\x12.user.UserResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*2\x0e/v1/users/{id}\x12O\n" +
 	"\n" +
-	"UpdateUser\x12\x17.user.UpdateUserRequest\x1a\x12.user.UserResponse\x127\n" +
+	"DeleteUser\x12\x11.user.UserRequest\x1a\x16.google.protobuf.Empty\"\x16\x82\xd3\xe4\x93\x02\x10*\x0e/v1/users/{id}\x12V\n" +
+	"\fUndeleteUser\x12\x11.user.UserRequest\x1a\x12.user.UserResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\"\x17/v1/users/{id}:undelete\x12T\n" +
+	"\vSuspendUser\x12\x11.user.UserRequest\x1a\x12.user.UserResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\"\x16/v1/users/{id}:suspend\x12V\n" +
+	"\fActivateUser\x12\x11.user.UserRequest\x1a\x12.user.UserResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\"\x17/v1/users/{id}:activate\x12^\n" +
+	"\x0eGetPreferences\x12\x11.user.UserRequest\x1a\x15.user.UserPreferences\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/users/{id}/preferences\x12v\n" +
+	"\x11UpdatePreferences\x12\x1e.user.UpdatePreferencesRequest\x1a\x15.user.UserPreferences\"*\x82\xd3\xe4\x93\x02$:\x01*2\x1f/v1/users/{user_id}/preferences\x12H\n" +
+	"\vStreamUsers\x12\x10.user.UserFilter\x1a\x12.user.UserResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/users0\x01\x12T\n" +
+	"\vExportUsers\x12\x18.user.ExportUsersRequest\x1a\x0f.user.FileChunk\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/users:export0\x01\x12R\n" +
+	"\tGetAvatar\x12\x11.user.UserRequest\x1a\x11.user.AvatarChunk\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/users/{id}/avatar0\x01\x12T\n" +
+	"\tListUsers\x12\x16.user.ListUsersRequest\x1a\x17.user.ListUsersResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/users:list\x12Z\n" +
+	"\vSearchUsers\x12\x18.user.SearchUsersRequest\x1a\x17.user.ListUsersResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/users:search\x12Q\n" +
 	"\n" +
-	"DeleteUser\x12\x11.user.UserRequest\x1a\x16.google.protobuf.Empty\x125\n" +
-	"\vStreamUsers\x12\x10.user.UserFilter\x1a\x12.user.UserResponse0\x01\x12B\n" +
-	"\vCreateUsers\x12\x17.user.CreateUserRequest\x1a\x18.user.BulkCreateResponse(\x01\x120\n" +
-	"\x04Chat\x12\x11.user.ChatMessage\x1a\x11.user.ChatMessage(\x010\x01B\x1eZ\x1cexample.com/user/proto;protob\x06proto3"
+	"WatchUsers\x12\x17.user.WatchUsersRequest\x1a\x0f.user.UserEvent\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/users:watch0\x01\x12d\n" +
+	"\vCreateUsers\x12\x17.user.CreateUserRequest\x1a\x18.user.BulkCreateResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/users:batchCreate(\x01\x12h\n" +
+	"\x0fBulkUpdateUsers\x12\x17.user.UpdateUserRequest\x1a\x18.user.BulkUpdateResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/users:batchUpdate(\x01\x12a\n" +
+	"\vImportUsers\x12\x18.user.ImportUsersRequest\x1a\x19.user.ImportUsersResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/users:import(\x01\x12\\\n" +
+	"\fUploadAvatar\x12\x19.user.UploadAvatarRequest\x1a\f.user.Avatar\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/users:uploadAvatar(\x01\x120\n" +
+	"\x04Chat\x12\x11.user.ChatMessage\x1a\x11.user.ChatMessage(\x010\x01\x12_\n" +
+	"\x0eGetChatHistory\x12\x18.user.ChatHistoryRequest\x1a\x19.user.ChatHistoryResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/chat/history\x12S\n" +
+	"\vGetAuditLog\x12\x15.user.AuditLogRequest\x1a\x16.user.AuditLogResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/audit-log\x12j\n" +
+	"\x0eGetUserHistory\x12\x18.user.UserHistoryRequest\x1a\x19.user.UserHistoryResponse\"#\x82\xd3\xe4\x93\x02\x1d\x12\x1b/v1/users/{user_id}/history2\x89\x01\n" +
+	"\vAuthService\x12:\n" +
+	"\vIssueTokens\x12\x16.google.protobuf.Empty\x1a\x13.user.TokenResponse\x12>\n" +
+	"\fRefreshToken\x12\x19.user.RefreshTokenRequest\x1a\x13.user.TokenResponse2\x9d\x02\n" +
+	"\fAdminService\x12=\n" +
+	"\vGetLogLevel\x12\x16.google.protobuf.Empty\x1a\x16.user.LogLevelResponse\x12?\n" +
+	"\vSetLogLevel\x12\x18.user.SetLogLevelRequest\x1a\x16.user.LogLevelResponse\x12C\n" +
+	"\x0eGetMaintenance\x12\x16.google.protobuf.Empty\x1a\x19.user.MaintenanceResponse\x12H\n" +
+	"\x0eSetMaintenance\x12\x1b.user.SetMaintenanceRequest\x1a\x19.user.MaintenanceResponseB\x1eZ\x1cexample.com/user/proto;protob\x06proto3"
 
 var (
 	file_proto_user_proto_rawDescOnce sync.Once
@@ -615,44 +3455,171 @@ func file_proto_user_proto_rawDescGZIP() []byte {
 	return file_proto_user_proto_rawDescData
 }
 
-var file_proto_user_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_user_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_user_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_proto_user_proto_msgTypes = make([]protoimpl.MessageInfo, 42)
 var file_proto_user_proto_goTypes = []any{
-	(MessageType)(0),              // 0: user.MessageType
-	(*UserRequest)(nil),           // 1: user.UserRequest
-	(*UserResponse)(nil),          // 2: user.UserResponse
-	(*CreateUserRequest)(nil),     // 3: user.CreateUserRequest
-	(*UpdateUserRequest)(nil),     // 4: user.UpdateUserRequest
-	(*UserFilter)(nil),            // 5: user.UserFilter
-	(*BulkCreateResponse)(nil),    // 6: user.BulkCreateResponse
-	(*ChatMessage)(nil),           // 7: user.ChatMessage
-	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),         // 9: google.protobuf.Empty
+	(Role)(0),                        // 0: user.Role
+	(UserStatus)(0),                  // 1: user.UserStatus
+	(FileFormat)(0),                  // 2: user.FileFormat
+	(UserEventType)(0),               // 3: user.UserEventType
+	(MessageType)(0),                 // 4: user.MessageType
+	(*UserRequest)(nil),              // 5: user.UserRequest
+	(*UserResponse)(nil),             // 6: user.UserResponse
+	(*UserPreferences)(nil),          // 7: user.UserPreferences
+	(*NotificationSettings)(nil),     // 8: user.NotificationSettings
+	(*UpdatePreferencesRequest)(nil), // 9: user.UpdatePreferencesRequest
+	(*BatchGetUsersRequest)(nil),     // 10: user.BatchGetUsersRequest
+	(*BatchGetUsersResponse)(nil),    // 11: user.BatchGetUsersResponse
+	(*CreateUserRequest)(nil),        // 12: user.CreateUserRequest
+	(*ValidateUserResponse)(nil),     // 13: user.ValidateUserResponse
+	(*FieldViolation)(nil),           // 14: user.FieldViolation
+	(*UpdateUserRequest)(nil),        // 15: user.UpdateUserRequest
+	(*UserFilter)(nil),               // 16: user.UserFilter
+	(*ListUsersRequest)(nil),         // 17: user.ListUsersRequest
+	(*ListUsersResponse)(nil),        // 18: user.ListUsersResponse
+	(*ExportUsersRequest)(nil),       // 19: user.ExportUsersRequest
+	(*FileChunk)(nil),                // 20: user.FileChunk
+	(*AvatarChunk)(nil),              // 21: user.AvatarChunk
+	(*Avatar)(nil),                   // 22: user.Avatar
+	(*SearchUsersRequest)(nil),       // 23: user.SearchUsersRequest
+	(*WatchUsersRequest)(nil),        // 24: user.WatchUsersRequest
+	(*UserEvent)(nil),                // 25: user.UserEvent
+	(*BulkCreateResponse)(nil),       // 26: user.BulkCreateResponse
+	(*BulkUpdateResponse)(nil),       // 27: user.BulkUpdateResponse
+	(*BulkUpdateError)(nil),          // 28: user.BulkUpdateError
+	(*ImportUsersRequest)(nil),       // 29: user.ImportUsersRequest
+	(*ImportUsersResponse)(nil),      // 30: user.ImportUsersResponse
+	(*ImportError)(nil),              // 31: user.ImportError
+	(*UploadAvatarRequest)(nil),      // 32: user.UploadAvatarRequest
+	(*ChatMessage)(nil),              // 33: user.ChatMessage
+	(*ChatHistoryRequest)(nil),       // 34: user.ChatHistoryRequest
+	(*ChatHistoryResponse)(nil),      // 35: user.ChatHistoryResponse
+	(*RefreshTokenRequest)(nil),      // 36: user.RefreshTokenRequest
+	(*TokenResponse)(nil),            // 37: user.TokenResponse
+	(*AuditLogRequest)(nil),          // 38: user.AuditLogRequest
+	(*AuditEntry)(nil),               // 39: user.AuditEntry
+	(*AuditLogResponse)(nil),         // 40: user.AuditLogResponse
+	(*UserHistoryRequest)(nil),       // 41: user.UserHistoryRequest
+	(*UserHistoryResponse)(nil),      // 42: user.UserHistoryResponse
+	(*SetLogLevelRequest)(nil),       // 43: user.SetLogLevelRequest
+	(*LogLevelResponse)(nil),         // 44: user.LogLevelResponse
+	(*SetMaintenanceRequest)(nil),    // 45: user.SetMaintenanceRequest
+	(*MaintenanceResponse)(nil),      // 46: user.MaintenanceResponse
+	(*timestamppb.Timestamp)(nil),    // 47: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),    // 48: google.protobuf.FieldMask
+	(*durationpb.Duration)(nil),      // 49: google.protobuf.Duration
+	(*emptypb.Empty)(nil),            // 50: google.protobuf.Empty
 }
 var file_proto_user_proto_depIdxs = []int32{
-	8,  // 0: user.UserResponse.created_at:type_name -> google.protobuf.Timestamp
-	8,  // 1: user.UserResponse.updated_at:type_name -> google.protobuf.Timestamp
-	8,  // 2: user.ChatMessage.timestamp:type_name -> google.protobuf.Timestamp
-	0,  // 3: user.ChatMessage.type:type_name -> user.MessageType
-	1,  // 4: user.UserService.GetUser:input_type -> user.UserRequest
-	3,  // 5: user.UserService.CreateUser:input_type -> user.CreateUserRequest
-	4,  // 6: user.UserService.UpdateUser:input_type -> user.UpdateUserRequest
-	1,  // 7: user.UserService.DeleteUser:input_type -> user.UserRequest
-	5,  // 8: user.UserService.StreamUsers:input_type -> user.UserFilter
-	3,  // 9: user.UserService.CreateUsers:input_type -> user.CreateUserRequest
-	7,  // 10: user.UserService.Chat:input_type -> user.ChatMessage
-	2,  // 11: user.UserService.GetUser:output_type -> user.UserResponse
-	2,  // 12: user.UserService.CreateUser:output_type -> user.UserResponse
-	2,  // 13: user.UserService.UpdateUser:output_type -> user.UserResponse
-	9,  // 14: user.UserService.DeleteUser:output_type -> google.protobuf.Empty
-	2,  // 15: user.UserService.StreamUsers:output_type -> user.UserResponse
-	6,  // 16: user.UserService.CreateUsers:output_type -> user.BulkCreateResponse
-	7,  // 17: user.UserService.Chat:output_type -> user.ChatMessage
-	11, // [11:18] is the sub-list for method output_type
-	4,  // [4:11] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	47, // 0: user.UserResponse.created_at:type_name -> google.protobuf.Timestamp
+	47, // 1: user.UserResponse.updated_at:type_name -> google.protobuf.Timestamp
+	47, // 2: user.UserResponse.deleted_at:type_name -> google.protobuf.Timestamp
+	1,  // 3: user.UserResponse.status:type_name -> user.UserStatus
+	0,  // 4: user.UserResponse.role_type:type_name -> user.Role
+	8,  // 5: user.UserPreferences.notifications:type_name -> user.NotificationSettings
+	47, // 6: user.UserPreferences.updated_at:type_name -> google.protobuf.Timestamp
+	8,  // 7: user.UpdatePreferencesRequest.notifications:type_name -> user.NotificationSettings
+	48, // 8: user.UpdatePreferencesRequest.update_mask:type_name -> google.protobuf.FieldMask
+	6,  // 9: user.BatchGetUsersResponse.users:type_name -> user.UserResponse
+	0,  // 10: user.CreateUserRequest.role_type:type_name -> user.Role
+	14, // 11: user.ValidateUserResponse.violations:type_name -> user.FieldViolation
+	48, // 12: user.UpdateUserRequest.update_mask:type_name -> google.protobuf.FieldMask
+	0,  // 13: user.UpdateUserRequest.role_type:type_name -> user.Role
+	47, // 14: user.UserFilter.created_after:type_name -> google.protobuf.Timestamp
+	47, // 15: user.UserFilter.created_before:type_name -> google.protobuf.Timestamp
+	6,  // 16: user.ListUsersResponse.users:type_name -> user.UserResponse
+	2,  // 17: user.ExportUsersRequest.format:type_name -> user.FileFormat
+	22, // 18: user.AvatarChunk.avatar:type_name -> user.Avatar
+	47, // 19: user.Avatar.updated_at:type_name -> google.protobuf.Timestamp
+	3,  // 20: user.WatchUsersRequest.types:type_name -> user.UserEventType
+	3,  // 21: user.UserEvent.type:type_name -> user.UserEventType
+	6,  // 22: user.UserEvent.user:type_name -> user.UserResponse
+	47, // 23: user.UserEvent.timestamp:type_name -> google.protobuf.Timestamp
+	28, // 24: user.BulkUpdateResponse.errors:type_name -> user.BulkUpdateError
+	2,  // 25: user.ImportUsersRequest.format:type_name -> user.FileFormat
+	31, // 26: user.ImportUsersResponse.errors:type_name -> user.ImportError
+	47, // 27: user.ChatMessage.timestamp:type_name -> google.protobuf.Timestamp
+	4,  // 28: user.ChatMessage.type:type_name -> user.MessageType
+	33, // 29: user.ChatHistoryResponse.messages:type_name -> user.ChatMessage
+	47, // 30: user.TokenResponse.access_token_expires_at:type_name -> google.protobuf.Timestamp
+	47, // 31: user.TokenResponse.refresh_token_expires_at:type_name -> google.protobuf.Timestamp
+	47, // 32: user.AuditEntry.timestamp:type_name -> google.protobuf.Timestamp
+	6,  // 33: user.AuditEntry.old_value:type_name -> user.UserResponse
+	6,  // 34: user.AuditEntry.new_value:type_name -> user.UserResponse
+	39, // 35: user.AuditLogResponse.entries:type_name -> user.AuditEntry
+	39, // 36: user.UserHistoryResponse.changes:type_name -> user.AuditEntry
+	49, // 37: user.SetLogLevelRequest.duration:type_name -> google.protobuf.Duration
+	47, // 38: user.LogLevelResponse.reverts_at:type_name -> google.protobuf.Timestamp
+	49, // 39: user.SetMaintenanceRequest.retry_after:type_name -> google.protobuf.Duration
+	49, // 40: user.MaintenanceResponse.retry_after:type_name -> google.protobuf.Duration
+	47, // 41: user.MaintenanceResponse.since:type_name -> google.protobuf.Timestamp
+	5,  // 42: user.UserService.GetUser:input_type -> user.UserRequest
+	10, // 43: user.UserService.BatchGetUsers:input_type -> user.BatchGetUsersRequest
+	12, // 44: user.UserService.CreateUser:input_type -> user.CreateUserRequest
+	12, // 45: user.UserService.ValidateUser:input_type -> user.CreateUserRequest
+	15, // 46: user.UserService.UpdateUser:input_type -> user.UpdateUserRequest
+	5,  // 47: user.UserService.DeleteUser:input_type -> user.UserRequest
+	5,  // 48: user.UserService.UndeleteUser:input_type -> user.UserRequest
+	5,  // 49: user.UserService.SuspendUser:input_type -> user.UserRequest
+	5,  // 50: user.UserService.ActivateUser:input_type -> user.UserRequest
+	5,  // 51: user.UserService.GetPreferences:input_type -> user.UserRequest
+	9,  // 52: user.UserService.UpdatePreferences:input_type -> user.UpdatePreferencesRequest
+	16, // 53: user.UserService.StreamUsers:input_type -> user.UserFilter
+	19, // 54: user.UserService.ExportUsers:input_type -> user.ExportUsersRequest
+	5,  // 55: user.UserService.GetAvatar:input_type -> user.UserRequest
+	17, // 56: user.UserService.ListUsers:input_type -> user.ListUsersRequest
+	23, // 57: user.UserService.SearchUsers:input_type -> user.SearchUsersRequest
+	24, // 58: user.UserService.WatchUsers:input_type -> user.WatchUsersRequest
+	12, // 59: user.UserService.CreateUsers:input_type -> user.CreateUserRequest
+	15, // 60: user.UserService.BulkUpdateUsers:input_type -> user.UpdateUserRequest
+	29, // 61: user.UserService.ImportUsers:input_type -> user.ImportUsersRequest
+	32, // 62: user.UserService.UploadAvatar:input_type -> user.UploadAvatarRequest
+	33, // 63: user.UserService.Chat:input_type -> user.ChatMessage
+	34, // 64: user.UserService.GetChatHistory:input_type -> user.ChatHistoryRequest
+	38, // 65: user.UserService.GetAuditLog:input_type -> user.AuditLogRequest
+	41, // 66: user.UserService.GetUserHistory:input_type -> user.UserHistoryRequest
+	50, // 67: user.AuthService.IssueTokens:input_type -> google.protobuf.Empty
+	36, // 68: user.AuthService.RefreshToken:input_type -> user.RefreshTokenRequest
+	50, // 69: user.AdminService.GetLogLevel:input_type -> google.protobuf.Empty
+	43, // 70: user.AdminService.SetLogLevel:input_type -> user.SetLogLevelRequest
+	50, // 71: user.AdminService.GetMaintenance:input_type -> google.protobuf.Empty
+	45, // 72: user.AdminService.SetMaintenance:input_type -> user.SetMaintenanceRequest
+	6,  // 73: user.UserService.GetUser:output_type -> user.UserResponse
+	11, // 74: user.UserService.BatchGetUsers:output_type -> user.BatchGetUsersResponse
+	6,  // 75: user.UserService.CreateUser:output_type -> user.UserResponse
+	13, // 76: user.UserService.ValidateUser:output_type -> user.ValidateUserResponse
+	6,  // 77: user.UserService.UpdateUser:output_type -> user.UserResponse
+	50, // 78: user.UserService.DeleteUser:output_type -> google.protobuf.Empty
+	6,  // 79: user.UserService.UndeleteUser:output_type -> user.UserResponse
+	6,  // 80: user.UserService.SuspendUser:output_type -> user.UserResponse
+	6,  // 81: user.UserService.ActivateUser:output_type -> user.UserResponse
+	7,  // 82: user.UserService.GetPreferences:output_type -> user.UserPreferences
+	7,  // 83: user.UserService.UpdatePreferences:output_type -> user.UserPreferences
+	6,  // 84: user.UserService.StreamUsers:output_type -> user.UserResponse
+	20, // 85: user.UserService.ExportUsers:output_type -> user.FileChunk
+	21, // 86: user.UserService.GetAvatar:output_type -> user.AvatarChunk
+	18, // 87: user.UserService.ListUsers:output_type -> user.ListUsersResponse
+	18, // 88: user.UserService.SearchUsers:output_type -> user.ListUsersResponse
+	25, // 89: user.UserService.WatchUsers:output_type -> user.UserEvent
+	26, // 90: user.UserService.CreateUsers:output_type -> user.BulkCreateResponse
+	27, // 91: user.UserService.BulkUpdateUsers:output_type -> user.BulkUpdateResponse
+	30, // 92: user.UserService.ImportUsers:output_type -> user.ImportUsersResponse
+	22, // 93: user.UserService.UploadAvatar:output_type -> user.Avatar
+	33, // 94: user.UserService.Chat:output_type -> user.ChatMessage
+	35, // 95: user.UserService.GetChatHistory:output_type -> user.ChatHistoryResponse
+	40, // 96: user.UserService.GetAuditLog:output_type -> user.AuditLogResponse
+	42, // 97: user.UserService.GetUserHistory:output_type -> user.UserHistoryResponse
+	37, // 98: user.AuthService.IssueTokens:output_type -> user.TokenResponse
+	37, // 99: user.AuthService.RefreshToken:output_type -> user.TokenResponse
+	44, // 100: user.AdminService.GetLogLevel:output_type -> user.LogLevelResponse
+	44, // 101: user.AdminService.SetLogLevel:output_type -> user.LogLevelResponse
+	46, // 102: user.AdminService.GetMaintenance:output_type -> user.MaintenanceResponse
+	46, // 103: user.AdminService.SetMaintenance:output_type -> user.MaintenanceResponse
+	73, // [73:104] is the sub-list for method output_type
+	42, // [42:73] is the sub-list for method input_type
+	42, // [42:42] is the sub-list for extension type_name
+	42, // [42:42] is the sub-list for extension extendee
+	0,  // [0:42] is the sub-list for field type_name
 }
 
 func init() { file_proto_user_proto_init() }
@@ -665,10 +3632,10 @@ func file_proto_user_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_user_proto_rawDesc), len(file_proto_user_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   7,
+			NumEnums:      5,
+			NumMessages:   42,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   3,
 		},
 		GoTypes:           file_proto_user_proto_goTypes,
 		DependencyIndexes: file_proto_user_proto_depIdxs,